@@ -91,6 +91,12 @@ func (ws *webServer) createGroups() error {
 	}
 	groupsMap["status"] = statusGroup
 
+	ingestGroup, err := groups.NewIngestGroup(ws.facade, ws.apiConfig.IngestMaxPayloadSizeInMB)
+	if err != nil {
+		return err
+	}
+	groupsMap["ingest"] = ingestGroup
+
 	ws.groups = groupsMap
 
 	return nil
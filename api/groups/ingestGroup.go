@@ -0,0 +1,101 @@
+package groups
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/api/shared"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+)
+
+const (
+	payloadPath = "/payload"
+
+	bytesInMB             = 1024 * 1024
+	defaultMaxPayloadSize = 128 * bytesInMB
+
+	// TopicHeader is the header that holds the topic of an ingested payload
+	TopicHeader = "X-Topic"
+	// VersionHeader is the header that holds the version of an ingested payload
+	VersionHeader = "X-Version"
+)
+
+type ingestGroup struct {
+	*baseGroup
+	facade         shared.FacadeHandler
+	maxPayloadSize int64
+}
+
+// NewIngestGroup returns a new instance of ingest group. The payloads larger than the provided size, in MB, are
+// rejected, a zero size uses the default of 128MB
+func NewIngestGroup(facade shared.FacadeHandler, maxPayloadSizeInMB uint64) (*ingestGroup, error) {
+	if check.IfNil(facade) {
+		return nil, fmt.Errorf("%w for ingest group", core.ErrNilFacadeHandler)
+	}
+
+	maxPayloadSize := int64(defaultMaxPayloadSize)
+	if maxPayloadSizeInMB > 0 {
+		maxPayloadSize = int64(maxPayloadSizeInMB * bytesInMB)
+	}
+
+	ig := &ingestGroup{
+		facade:         facade,
+		maxPayloadSize: maxPayloadSize,
+		baseGroup:      &baseGroup{},
+	}
+
+	endpoints := []*shared.EndpointHandlerData{
+		{
+			Path:    payloadPath,
+			Handler: ig.postPayload,
+			Method:  http.MethodPost,
+		},
+	}
+	ig.endpoints = endpoints
+
+	return ig, nil
+}
+
+// postPayload will index the outport payload from the request body, exactly as if it was received on the websocket
+func (ig *ingestGroup) postPayload(c *gin.Context) {
+	topic := c.GetHeader(TopicHeader)
+	if topic == "" {
+		returnStatus(c, nil, http.StatusBadRequest, fmt.Sprintf("missing %s header", TopicHeader), shared.ReturnCodeRequestError)
+		return
+	}
+
+	version, err := strconv.ParseUint(c.GetHeader(VersionHeader), 10, 32)
+	if err != nil {
+		returnStatus(c, nil, http.StatusBadRequest, fmt.Sprintf("invalid %s header: %s", VersionHeader, err.Error()), shared.ReturnCodeRequestError)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, ig.maxPayloadSize))
+	maxBytesErr := &http.MaxBytesError{}
+	if errors.As(err, &maxBytesErr) {
+		returnStatus(c, nil, http.StatusRequestEntityTooLarge, fmt.Sprintf("the payload exceeds the maximum size of %d bytes", maxBytesErr.Limit), shared.ReturnCodeRequestError)
+		return
+	}
+	if err != nil {
+		returnStatus(c, nil, http.StatusBadRequest, err.Error(), shared.ReturnCodeRequestError)
+		return
+	}
+
+	err = ig.facade.ProcessPayload(payload, topic, uint32(version))
+	if err != nil {
+		returnStatus(c, nil, http.StatusInternalServerError, err.Error(), shared.ReturnCodeInternalError)
+		return
+	}
+
+	returnStatus(c, nil, http.StatusOK, "", shared.ReturnCodeSuccess)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ig *ingestGroup) IsInterfaceNil() bool {
+	return ig == nil
+}
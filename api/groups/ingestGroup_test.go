@@ -0,0 +1,124 @@
+package groups
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/multiversx/mx-chain-es-indexer-go/api/shared"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func startIngestGroup(t *testing.T, facade shared.FacadeHandler, maxPayloadSizeInMB uint64) *gin.Engine {
+	ig, err := NewIngestGroup(facade, maxPayloadSizeInMB)
+	require.Nil(t, err)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	ig.RegisterRoutes(engine.Group("/ingest"), config.ApiRoutesConfig{
+		APIPackages: map[string]config.APIPackageConfig{
+			"ingest": {Routes: []config.RouteConfig{{Name: payloadPath, Open: true}}},
+		},
+	})
+
+	return engine
+}
+
+func postPayload(engine *gin.Engine, headers map[string]string, body []byte) (int, *shared.GenericAPIResponse) {
+	req, _ := http.NewRequest(http.MethodPost, "/ingest"+payloadPath, bytes.NewReader(body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp := httptest.NewRecorder()
+	engine.ServeHTTP(resp, req)
+
+	response := &shared.GenericAPIResponse{}
+	_ = json.Unmarshal(resp.Body.Bytes(), response)
+
+	return resp.Code, response
+}
+
+func TestNewIngestGroup(t *testing.T) {
+	t.Parallel()
+
+	ig, err := NewIngestGroup(nil, 0)
+	require.Nil(t, ig)
+	require.True(t, errors.Is(err, core.ErrNilFacadeHandler))
+
+	ig, err = NewIngestGroup(&mock.FacadeStub{}, 0)
+	require.Nil(t, err)
+	require.False(t, ig.IsInterfaceNil())
+	require.Equal(t, int64(defaultMaxPayloadSize), ig.maxPayloadSize)
+
+	ig, _ = NewIngestGroup(&mock.FacadeStub{}, 2)
+	require.Equal(t, int64(2*bytesInMB), ig.maxPayloadSize)
+}
+
+func TestIngestGroup_PostPayload(t *testing.T) {
+	t.Parallel()
+
+	processed := 0
+	errProcess := errors.New("local error")
+	engine := startIngestGroup(t, &mock.FacadeStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			processed++
+			if topic == "failing" {
+				return errProcess
+			}
+
+			require.Equal(t, []byte("payload"), payload)
+			require.Equal(t, "saveBlock", topic)
+			require.Equal(t, uint32(1), version)
+			return nil
+		},
+	}, 0)
+
+	code, response := postPayload(engine, map[string]string{VersionHeader: "1"}, []byte("payload"))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, shared.ReturnCodeRequestError, response.Code)
+
+	code, response = postPayload(engine, map[string]string{TopicHeader: "saveBlock", VersionHeader: "v1"}, []byte("payload"))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, shared.ReturnCodeRequestError, response.Code)
+	require.Equal(t, 0, processed)
+
+	code, response = postPayload(engine, map[string]string{TopicHeader: "failing", VersionHeader: "1"}, []byte("payload"))
+	require.Equal(t, http.StatusInternalServerError, code)
+	require.Equal(t, shared.ReturnCodeInternalError, response.Code)
+	require.Equal(t, errProcess.Error(), response.Error)
+
+	code, response = postPayload(engine, map[string]string{TopicHeader: "saveBlock", VersionHeader: "1"}, []byte("payload"))
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, shared.ReturnCodeSuccess, response.Code)
+	require.Equal(t, 2, processed)
+}
+
+func TestIngestGroup_PostPayloadTooLarge(t *testing.T) {
+	t.Parallel()
+
+	processed := 0
+	engine := startIngestGroup(t, &mock.FacadeStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			processed++
+			return nil
+		},
+	}, 1)
+
+	headers := map[string]string{TopicHeader: "saveBlock", VersionHeader: "1"}
+	code, response := postPayload(engine, headers, make([]byte, bytesInMB+1))
+	require.Equal(t, http.StatusRequestEntityTooLarge, code)
+	require.Equal(t, shared.ReturnCodeRequestError, response.Code)
+	require.Equal(t, 0, processed)
+
+	code, _ = postPayload(engine, headers, make([]byte, bytesInMB))
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 1, processed)
+}
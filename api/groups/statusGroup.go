@@ -13,6 +13,7 @@ import (
 const (
 	metricsPath           = "/metrics"
 	prometheusMetricsPath = "/prometheus-metrics"
+	nonceGapsPath         = "/nonce-gaps"
 )
 
 type statusGroup struct {
@@ -42,6 +43,11 @@ func NewStatusGroup(facade shared.FacadeHandler) (*statusGroup, error) {
 			Handler: sg.getPrometheusMetrics,
 			Method:  http.MethodGet,
 		},
+		{
+			Path:    nonceGapsPath,
+			Handler: sg.getNonceGaps,
+			Method:  http.MethodGet,
+		},
 	}
 	sg.endpoints = endpoints
 
@@ -52,7 +58,7 @@ func NewStatusGroup(facade shared.FacadeHandler) (*statusGroup, error) {
 func (sg *statusGroup) getMetrics(c *gin.Context) {
 	metricsResults := sg.facade.GetMetrics()
 
-	returnStatus(c, gin.H{"metrics": metricsResults}, http.StatusOK, "", "successful")
+	returnStatus(c, gin.H{"metrics": metricsResults}, http.StatusOK, "", shared.ReturnCodeSuccess)
 }
 
 // getPrometheusMetrics will expose proxy metrics in prometheus format
@@ -62,6 +68,13 @@ func (sg *statusGroup) getPrometheusMetrics(c *gin.Context) {
 	c.String(http.StatusOK, metricsResults)
 }
 
+// getNonceGaps will expose the ranges of header nonces that were not indexed yet, per shard
+func (sg *statusGroup) getNonceGaps(c *gin.Context) {
+	nonceGaps := sg.facade.GetNonceGaps()
+
+	returnStatus(c, gin.H{"gaps": nonceGaps}, http.StatusOK, "", shared.ReturnCodeSuccess)
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (sg *statusGroup) IsInterfaceNil() bool {
 	return sg == nil
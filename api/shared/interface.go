@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/multiversx/mx-chain-es-indexer-go/config"
 	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
 )
 
 // GroupHandler defines the actions needed to be performed by a gin API group
@@ -19,6 +20,8 @@ type GroupHandler interface {
 type FacadeHandler interface {
 	GetMetrics() map[string]*request.MetricsResponse
 	GetMetricsForPrometheus() string
+	GetNonceGaps() []*data.NonceGap
+	ProcessPayload(payload []byte, topic string, version uint32) error
 	IsInterfaceNil() bool
 }
 
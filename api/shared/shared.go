@@ -33,3 +33,12 @@ type GenericAPIResponse struct {
 	Error string      `json:"error"`
 	Code  string      `json:"code"`
 }
+
+const (
+	// ReturnCodeSuccess defines a successful request
+	ReturnCodeSuccess = "successful"
+	// ReturnCodeRequestError defines a request which hasn't been executed successfully due to a bad request received
+	ReturnCodeRequestError = "bad_request"
+	// ReturnCodeInternalError defines a request which hasn't been executed successfully due to an internal error
+	ReturnCodeInternalError = "internal_issue"
+)
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+const (
+	maxBulkItemsRetries      = 3
+	bulkItemsRetryBaseDelay  = 200 * time.Millisecond
+	bulkActionUpdate         = "update"
+	bulkActionDelete         = "delete"
+	versionConflictErrorType = "version_conflict_engine_exception"
+	bulkLineSeparator        = "\n"
+)
+
+// bulkItemResult is the outcome of an action of a bulk request
+type bulkItemResult struct {
+	action      string
+	status      int
+	errorType   string
+	description string
+}
+
+func (item bulkItemResult) failed() bool {
+	return item.status >= http.StatusBadRequest
+}
+
+// retryable returns true for the items rejected because the cluster is overloaded and for the scripted upserts that
+// conflicted with a concurrent update of the same document
+func (item bulkItemResult) retryable() bool {
+	switch item.status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusConflict:
+		return item.action == bulkActionUpdate && item.errorType == versionConflictErrorType
+	default:
+		return false
+	}
+}
+
+type bulkSendHandler func(ctx context.Context, body []byte, index string) ([]bulkItemResult, error)
+
+// doBulkRequestWithRetries sends the bulk request and then sends again, with backoff, only the actions that failed with
+// a retryable status. An error is returned as soon as an action fails with a permanent error, or when the retryable
+// actions still fail after the last retry
+func doBulkRequestWithRetries(ctx context.Context, body []byte, index string, sendHandler bulkSendHandler) error {
+	for attempt := 0; ; attempt++ {
+		items, err := sendHandler(ctx, body, index)
+		if err != nil {
+			return err
+		}
+
+		retryableIndices := make([]int, 0)
+		permanentFailures := make([]bulkItemResult, 0)
+		for idx, item := range items {
+			if !item.failed() {
+				continue
+			}
+			if item.retryable() {
+				retryableIndices = append(retryableIndices, idx)
+				continue
+			}
+			permanentFailures = append(permanentFailures, item)
+		}
+
+		if len(permanentFailures) > 0 {
+			return fmt.Errorf("%w: %d of %d actions, %s", dataindexer.ErrBulkItemsRejected,
+				len(permanentFailures), len(items), formatBulkItemsErrors(permanentFailures))
+		}
+		if len(retryableIndices) == 0 {
+			return nil
+		}
+
+		failedItems := selectBulkItems(items, retryableIndices)
+		if attempt == maxBulkItemsRetries {
+			return fmt.Errorf("%w: %d of %d actions after %d retries, %s", dataindexer.ErrBulkItemsRetriesExceeded,
+				len(retryableIndices), len(items), maxBulkItemsRetries, formatBulkItemsErrors(failedItems))
+		}
+
+		body, err = selectBulkActions(body, retryableIndices, len(items))
+		if err != nil {
+			return fmt.Errorf("%w while retrying the failed actions, %s", err, formatBulkItemsErrors(failedItems))
+		}
+
+		log.Debug("retrying the failed actions of a bulk request",
+			"index", index, "failed", len(retryableIndices), "total", len(items), "attempt", attempt+1)
+
+		err = waitBeforeBulkRetry(ctx, attempt)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func waitBeforeBulkRetry(ctx context.Context, attempt int) error {
+	return waitWithContext(ctx, bulkItemsRetryBaseDelay<<attempt)
+}
+
+// selectBulkActions returns a bulk body with the actions found at the provided positions
+func selectBulkActions(body []byte, positions []int, numActions int) ([]byte, error) {
+	actions, err := data.ParseBulkBody(body, "")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", dataindexer.ErrInvalidBulkBody, err.Error())
+	}
+	if len(actions) != numActions {
+		return nil, fmt.Errorf("%w: the body has %d actions and the response %d items", dataindexer.ErrInvalidBulkBody, len(actions), numActions)
+	}
+
+	selected := make([]byte, 0, len(body))
+	for _, position := range positions {
+		selected = append(selected, actions[position].Bytes()...)
+	}
+
+	return selected, nil
+}
+
+func selectBulkItems(items []bulkItemResult, positions []int) []bulkItemResult {
+	selected := make([]bulkItemResult, 0, len(positions))
+	for _, position := range positions {
+		selected = append(selected, items[position])
+	}
+
+	return selected
+}
+
+func formatBulkItemsErrors(items []bulkItemResult) string {
+	errorsString := ""
+	for idx, item := range items {
+		if idx == numOfErrorsToExtractBulkResponse {
+			break
+		}
+		errorsString += item.description + bulkLineSeparator
+	}
+
+	return errorsString
+}
+
+// parseBulkResponseItems decodes the outcome of every action of a bulk request, in the order of the actions
+func parseBulkResponseItems(bodyBytes []byte) ([]bulkItemResult, error) {
+	response := BulkRequestResponse{}
+	err := json.Unmarshal(bodyBytes, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]bulkItemResult, 0, len(response.Items))
+	for _, responseItem := range response.Items {
+		action, selectedItem := responseItem.selectItem()
+		log.Trace("worked on", "index", selectedItem.Index,
+			"_id", selectedItem.ID,
+			"result", selectedItem.Result,
+			"status", selectedItem.Status,
+		)
+
+		items = append(items, bulkItemResult{
+			action:    action,
+			status:    selectedItem.Status,
+			errorType: selectedItem.Error.Type,
+			description: fmt.Sprintf(`{ "index": "%s", "id": "%s", "statusCode": %d, "errorType": "%s", "reason": "%s", "causedBy": { "type": "%s", "reason": "%s", "script_stack":"%s", "script":"%s" }}`,
+				selectedItem.Index, selectedItem.ID, selectedItem.Status, selectedItem.Error.Type, selectedItem.Error.Reason, selectedItem.Error.Cause.Type,
+				selectedItem.Error.Cause.Reason, selectedItem.Error.Cause.ScriptStack, selectedItem.Error.Cause.Script),
+		})
+	}
+
+	return items, nil
+}
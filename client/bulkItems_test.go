@@ -0,0 +1,194 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBulkAction1 = `{ "index" : { "_index":"transactions", "_id" : "t1" } }`
+	testBulkDoc1    = `{"nonce":1}`
+	testBulkAction2 = `{ "update" : { "_index":"tokens", "_id" : "TKN" } }`
+	testBulkDoc2    = `{"script":{"source":"ctx._source.x = 1"},"upsert":{}}`
+	testBulkAction3 = `{ "delete" : { "_index":"accountsesdt", "_id" : "a1" } }`
+)
+
+var testBulkBody = strings.Join([]string{testBulkAction1, testBulkDoc1, testBulkAction2, testBulkDoc2, testBulkAction3}, "\n") + "\n"
+
+func TestSelectBulkActions(t *testing.T) {
+	t.Parallel()
+
+	selected, err := selectBulkActions([]byte(testBulkBody), []int{1, 2}, 3)
+	require.Nil(t, err)
+	require.Equal(t, testBulkAction2+"\n"+testBulkDoc2+"\n"+testBulkAction3+"\n", string(selected))
+
+	selected, err = selectBulkActions([]byte(testBulkBody), []int{0}, 3)
+	require.Nil(t, err)
+	require.Equal(t, testBulkAction1+"\n"+testBulkDoc1+"\n", string(selected))
+
+	_, err = selectBulkActions([]byte(testBulkBody), []int{0}, 2)
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidBulkBody))
+
+	_, err = selectBulkActions([]byte("not json\n"), []int{0}, 1)
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidBulkBody))
+
+	_, err = selectBulkActions([]byte(testBulkAction1), []int{0}, 1)
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidBulkBody))
+}
+
+func TestDoBulkRequestWithRetries_OnlyTheFailedActionsAreRetried(t *testing.T) {
+	t.Parallel()
+
+	sentBodies := make([]string, 0)
+	err := doBulkRequestWithRetries(context.Background(), []byte(testBulkBody), "", func(_ context.Context, body []byte, _ string) ([]bulkItemResult, error) {
+		sentBodies = append(sentBodies, string(body))
+		switch len(sentBodies) {
+		case 1:
+			return []bulkItemResult{
+				{action: "index", status: http.StatusCreated},
+				{action: bulkActionUpdate, status: http.StatusConflict, errorType: versionConflictErrorType},
+				{action: bulkActionDelete, status: http.StatusTooManyRequests},
+			}, nil
+		case 2:
+			return []bulkItemResult{
+				{action: bulkActionUpdate, status: http.StatusOK},
+				{action: bulkActionDelete, status: http.StatusServiceUnavailable},
+			}, nil
+		default:
+			return []bulkItemResult{{action: bulkActionDelete, status: http.StatusOK}}, nil
+		}
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		testBulkBody,
+		testBulkAction2 + "\n" + testBulkDoc2 + "\n" + testBulkAction3 + "\n",
+		testBulkAction3 + "\n",
+	}, sentBodies)
+}
+
+func TestDoBulkRequestWithRetries_PermanentErrorIsNotRetried(t *testing.T) {
+	t.Parallel()
+
+	numCalls := 0
+	err := doBulkRequestWithRetries(context.Background(), []byte(testBulkBody), "", func(_ context.Context, _ []byte, _ string) ([]bulkItemResult, error) {
+		numCalls++
+		return []bulkItemResult{
+			{action: "index", status: http.StatusBadRequest, errorType: "mapper_parsing_exception", description: `{ "index": "transactions", "id": "t1" }`},
+			{action: bulkActionUpdate, status: http.StatusOK},
+			{action: bulkActionDelete, status: http.StatusTooManyRequests},
+		}, nil
+	})
+	require.True(t, errors.Is(err, dataindexer.ErrBulkItemsRejected))
+	require.Contains(t, err.Error(), `"index": "transactions", "id": "t1"`)
+	require.Equal(t, 1, numCalls)
+
+	// a version conflict of an index action is not retried
+	err = doBulkRequestWithRetries(context.Background(), []byte(testBulkAction1+"\n"+testBulkDoc1+"\n"), "", func(_ context.Context, _ []byte, _ string) ([]bulkItemResult, error) {
+		return []bulkItemResult{{action: "index", status: http.StatusConflict, errorType: versionConflictErrorType}}, nil
+	})
+	require.True(t, errors.Is(err, dataindexer.ErrBulkItemsRejected))
+}
+
+func TestDoBulkRequestWithRetries_RequestError(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	err := doBulkRequestWithRetries(context.Background(), []byte(testBulkBody), "", func(_ context.Context, _ []byte, _ string) ([]bulkItemResult, error) {
+		return nil, expectedErr
+	})
+	require.Equal(t, expectedErr, err)
+}
+
+func TestDoBulkRequestWithRetries_RetriesExceeded(t *testing.T) {
+	t.Parallel()
+
+	numCalls := 0
+	err := doBulkRequestWithRetries(context.Background(), []byte(testBulkAction3+"\n"), "", func(_ context.Context, _ []byte, _ string) ([]bulkItemResult, error) {
+		numCalls++
+		return []bulkItemResult{{action: bulkActionDelete, status: http.StatusTooManyRequests, description: "overloaded"}}, nil
+	})
+	require.True(t, errors.Is(err, dataindexer.ErrBulkItemsRetriesExceeded))
+	require.Contains(t, err.Error(), "overloaded")
+	require.Equal(t, maxBulkItemsRetries+1, numCalls)
+}
+
+func TestDoBulkRequestWithRetries_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	numCalls := 0
+	err := doBulkRequestWithRetries(ctx, []byte(testBulkAction3+"\n"), "", func(_ context.Context, _ []byte, _ string) ([]bulkItemResult, error) {
+		numCalls++
+		return []bulkItemResult{{action: bulkActionDelete, status: http.StatusTooManyRequests}}, nil
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 1, numCalls)
+}
+
+func TestElasticClient_DoBulkRequestRetriesTheFailedActions(t *testing.T) {
+	t.Parallel()
+
+	responses := []string{
+		`{"took":1,"errors":true,"items":[` +
+			`{"index":{"_index":"transactions","_id":"t1","status":201,"result":"created"}},` +
+			`{"update":{"_index":"tokens","_id":"TKN","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected"}}},` +
+			`{"delete":{"_index":"accountsesdt","_id":"a1","status":200,"result":"deleted"}}]}`,
+		`{"took":1,"errors":false,"items":[{"update":{"_index":"tokens","_id":"TKN","status":200,"result":"updated"}}]}`,
+	}
+	receivedBodies := make([]string, 0)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receivedBodies = append(receivedBodies, string(body))
+
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(responses[len(receivedBodies)-1]))
+	}))
+	defer ts.Close()
+
+	esClient, _ := NewElasticClient(elasticsearch.Config{
+		Addresses: []string{ts.URL},
+		Logger:    &logging.CustomLogger{},
+	})
+
+	start := time.Now()
+	err := esClient.DoBulkRequest(context.Background(), bytes.NewBufferString(testBulkBody), "")
+	require.Nil(t, err)
+	require.GreaterOrEqual(t, time.Since(start), bulkItemsRetryBaseDelay)
+	require.Equal(t, []string{testBulkBody, testBulkAction2 + "\n" + testBulkDoc2 + "\n"}, receivedBodies)
+}
+
+func TestParseBulkResponseItemsDelete(t *testing.T) {
+	t.Parallel()
+
+	responseBytes := []byte(`{"took":3,"errors":true,"items":[{"delete":{"_index":"accountsesdt","_id":"a1","status":503,"error":{"type":"unavailable_shards_exception","reason":"primary shard is not active"}}}]}`)
+
+	items, err := parseBulkResponseItems(responseBytes)
+	require.Nil(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, bulkActionDelete, items[0].action)
+	require.True(t, items[0].failed())
+	require.True(t, items[0].retryable())
+	require.Contains(t, items[0].description, `"index": "accountsesdt", "id": "a1", "statusCode": 503`)
+
+	items, err = parseBulkResponseItems([]byte(`{"took":3,"errors":false,"items":[{"delete":{"_index":"accountsesdt","_id":"a1","status":200}}]}`))
+	require.Nil(t, err)
+	require.Len(t, items, 1)
+	require.False(t, items[0].failed())
+
+	_, err = parseBulkResponseItems([]byte("not json"))
+	require.NotNil(t, err)
+}
@@ -0,0 +1,250 @@
+package bulksink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const (
+	filePrefix         = "bulk_"
+	fileExtension      = ".ndjson"
+	dirPermissions     = 0755
+	defaultMaxFileSize = 1024 * 1024 * 1024 // 1GB
+	sinkFileNameFormat = "%s%020d%s"
+)
+
+var log = logger.GetOrCreate("indexer/client/bulksink")
+
+// ArgsBulkFileSink holds all the components needed to create a new instance of bulkFileSink
+type ArgsBulkFileSink struct {
+	Path           string
+	MaxFileSize    uint64
+	DatabaseClient elasticproc.DatabaseClientHandler
+}
+
+// BulkTag holds the details of a bulk body, written in the line before the body
+type BulkTag struct {
+	ShardID *uint32  `json:"shardID,omitempty"`
+	Nonce   *uint64  `json:"nonce,omitempty"`
+	Indices []string `json:"indices"`
+	Lines   int      `json:"lines"`
+}
+
+type bulkTagLine struct {
+	Bulk BulkTag `json:"_bulk"`
+}
+
+type bulkFileSink struct {
+	elasticproc.DatabaseClientHandler
+	path        string
+	maxFileSize uint64
+
+	mut             sync.Mutex
+	file            *os.File
+	currentFileSize uint64
+	getTimeHandler  func() time.Time
+}
+
+// NewBulkFileSink will create a new instance of bulkFileSink. Every bulk body is sent to the wrapped database client
+// and, when it succeeds, written in a rotating NDJSON file, preceded by a line holding its tag. All the other requests
+// go directly to the wrapped database client
+func NewBulkFileSink(args ArgsBulkFileSink) (*bulkFileSink, error) {
+	if args.Path == "" {
+		return nil, ErrEmptySinkPath
+	}
+	if check.IfNil(args.DatabaseClient) {
+		return nil, ErrNilDatabaseClient
+	}
+
+	err := os.MkdirAll(args.Path, dirPermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	maxFileSize := args.MaxFileSize
+	if maxFileSize == 0 {
+		maxFileSize = defaultMaxFileSize
+	}
+
+	return &bulkFileSink{
+		DatabaseClientHandler: args.DatabaseClient,
+		path:                  args.Path,
+		maxFileSize:           maxFileSize,
+		getTimeHandler:        time.Now,
+	}, nil
+}
+
+// DoBulkRequest will send the bulk body to the wrapped database client and then will write it in the current file.
+// The body is written only after it was sent, so a bulk request that fails and is retried is written once
+func (bfs *bulkFileSink) DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error {
+	body, err := data.DecompressBulkBody(buff.Bytes())
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidBulkBody, err.Error())
+	}
+
+	tag, err := createBulkTag(ctx, body, index)
+	if err != nil {
+		return err
+	}
+
+	err = bfs.DatabaseClientHandler.DoBulkRequest(ctx, buff, index)
+	if err != nil {
+		return err
+	}
+
+	err = bfs.write(tag, body)
+	if err != nil {
+		return fmt.Errorf("%w while writing the bulk body", err)
+	}
+
+	return nil
+}
+
+func createBulkTag(ctx context.Context, body []byte, defaultIndex string) (*BulkTag, error) {
+	indices, lines, err := extractIndices(body, defaultIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &BulkTag{
+		Indices: indices,
+		Lines:   lines,
+	}
+
+	topic, ok := ctx.Value(request.ContextKey).(string)
+	if ok {
+		_, shardIDStr := request.SplitTopicAndShardID(topic)
+		shardID, errParse := strconv.ParseUint(shardIDStr, 10, 32)
+		if errParse == nil {
+			shardID32 := uint32(shardID)
+			tag.ShardID = &shardID32
+		}
+	}
+
+	nonce, ok := ctx.Value(request.NonceContextKey).(uint64)
+	if ok {
+		tag.Nonce = &nonce
+	}
+
+	return tag, nil
+}
+
+// extractIndices returns the sorted target indices of the actions of the bulk body and the number of non-empty lines
+func extractIndices(body []byte, defaultIndex string) ([]string, int, error) {
+	actions, err := data.ParseBulkBody(body, defaultIndex)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrInvalidBulkBody, err.Error())
+	}
+
+	indicesMap := make(map[string]struct{})
+	numLines := 0
+	for _, action := range actions {
+		indicesMap[action.Index] = struct{}{}
+		numLines += action.NumLines()
+	}
+
+	indices := make([]string, 0, len(indicesMap))
+	for index := range indicesMap {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	return indices, numLines, nil
+}
+
+func (bfs *bulkFileSink) write(tag *BulkTag, body []byte) error {
+	tagBytes, err := json.Marshal(&bulkTagLine{Bulk: *tag})
+	if err != nil {
+		return err
+	}
+
+	entry := make([]byte, 0, len(tagBytes)+len(body)+2)
+	entry = append(entry, tagBytes...)
+	entry = append(entry, '\n')
+	entry = append(entry, body...)
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		entry = append(entry, '\n')
+	}
+
+	bfs.mut.Lock()
+	defer bfs.mut.Unlock()
+
+	if bfs.file == nil || bfs.currentFileSize >= bfs.maxFileSize {
+		err = bfs.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	n, err := bfs.file.Write(entry)
+	bfs.currentFileSize += uint64(n)
+
+	return err
+}
+
+func (bfs *bulkFileSink) rotate() error {
+	err := bfs.closeCurrentFile()
+	if err != nil {
+		log.Warn("bulkFileSink: cannot close bulk file", "error", err)
+	}
+
+	fileName := fmt.Sprintf(sinkFileNameFormat, filePrefix, bfs.getTimeHandler().UnixNano(), fileExtension)
+	file, err := os.Create(filepath.Join(bfs.path, fileName))
+	if err != nil {
+		return err
+	}
+
+	log.Debug("bulkFileSink: writing bulk bodies", "file", fileName)
+
+	bfs.file = file
+	bfs.currentFileSize = 0
+
+	return nil
+}
+
+func (bfs *bulkFileSink) closeCurrentFile() error {
+	if bfs.file == nil {
+		return nil
+	}
+
+	err := bfs.file.Close()
+	bfs.file = nil
+
+	return err
+}
+
+// Close will close the current bulk file and the wrapped database client
+func (bfs *bulkFileSink) Close() error {
+	bfs.mut.Lock()
+	err := bfs.closeCurrentFile()
+	bfs.mut.Unlock()
+	if err != nil {
+		log.Warn("bulkFileSink: cannot close bulk file", "error", err)
+	}
+
+	errClose := elasticproc.CloseDatabaseClient(bfs.DatabaseClientHandler)
+	if errClose != nil {
+		return errClose
+	}
+
+	return err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (bfs *bulkFileSink) IsInterfaceNil() bool {
+	return bfs == nil
+}
@@ -0,0 +1,210 @@
+package bulksink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBulkFileSink(t *testing.T) {
+	t.Parallel()
+
+	sink, err := NewBulkFileSink(ArgsBulkFileSink{DatabaseClient: &mock.DatabaseWriterStub{}})
+	require.Nil(t, sink)
+	require.Equal(t, ErrEmptySinkPath, err)
+
+	sink, err = NewBulkFileSink(ArgsBulkFileSink{Path: t.TempDir()})
+	require.Nil(t, sink)
+	require.Equal(t, ErrNilDatabaseClient, err)
+
+	sink, err = NewBulkFileSink(ArgsBulkFileSink{Path: t.TempDir(), DatabaseClient: &mock.DatabaseWriterStub{}})
+	require.Nil(t, err)
+	require.False(t, sink.IsInterfaceNil())
+}
+
+func TestBulkFileSink_DoBulkRequest(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	forwarded := 0
+	sink, _ := NewBulkFileSink(ArgsBulkFileSink{
+		Path: dir,
+		DatabaseClient: &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				forwarded++
+				return nil
+			},
+		},
+	})
+
+	body := `{ "index" : { "_index":"blocks", "_id" : "h1" } }` + "\n" + `{"nonce":12}` + "\n" +
+		`{ "delete" : { "_index":"values", "_id" : "k" } }` + "\n" +
+		`{ "update" : { "_id" : "t" } }` + "\n" + `{"doc":{"a":1}}` + "\n"
+
+	ctx := context.WithValue(context.Background(), request.NonceContextKey, uint64(12))
+	ctx = context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.BulkTopic, 1))
+	err := sink.DoBulkRequest(ctx, bytes.NewBufferString(body), "tokens")
+	require.Nil(t, err)
+
+	err = sink.DoBulkRequest(context.Background(), bytes.NewBufferString(`{ "index" : { "_index":"rounds", "_id" : "r" } }`+"\n"+`{"round":1}`), "")
+	require.Nil(t, err)
+	require.Nil(t, sink.Close())
+	require.Equal(t, 2, forwarded)
+
+	files, _ := os.ReadDir(dir)
+	require.Len(t, files, 1)
+	written, _ := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	expected := `{"_bulk":{"shardID":1,"nonce":12,"indices":["blocks","tokens","values"],"lines":5}}` + "\n" + body +
+		`{"_bulk":{"indices":["rounds"],"lines":2}}` + "\n" + `{ "index" : { "_index":"rounds", "_id" : "r" } }` + "\n" + `{"round":1}` + "\n"
+	require.Equal(t, expected, string(written))
+}
+
+func TestBulkFileSink_DoBulkRequestCompressedBody(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var forwardedBody []byte
+	sink, _ := NewBulkFileSink(ArgsBulkFileSink{
+		Path: dir,
+		DatabaseClient: &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				forwardedBody = buff.Bytes()
+				return nil
+			},
+		},
+	})
+
+	buffSlice := data.NewCompressedBufferSlice(data.DefaultMaxBulkSize)
+	require.Nil(t, buffSlice.PutData([]byte(`{ "index" : { "_index":"rounds", "_id" : "r" } }`+"\n"), []byte(`{"round":1}`)))
+	compressedBody := buffSlice.Buffers()[0]
+
+	err := sink.DoBulkRequest(context.Background(), compressedBody, "")
+	require.Nil(t, err)
+	require.Nil(t, sink.Close())
+
+	// the wrapped client gets the compressed body and the file holds the uncompressed one
+	require.True(t, data.IsCompressedBulkBody(forwardedBody))
+	files, _ := os.ReadDir(dir)
+	require.Len(t, files, 1)
+	written, _ := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	expected := `{"_bulk":{"indices":["rounds"],"lines":2}}` + "\n" + `{ "index" : { "_index":"rounds", "_id" : "r" } }` + "\n" + `{"round":1}` + "\n"
+	require.Equal(t, expected, string(written))
+}
+
+func TestBulkFileSink_DoBulkRequestRotatesFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sink, _ := NewBulkFileSink(ArgsBulkFileSink{
+		Path:           dir,
+		MaxFileSize:    10,
+		DatabaseClient: &mock.DatabaseWriterStub{},
+	})
+	currentTime := int64(1000)
+	sink.getTimeHandler = func() time.Time {
+		currentTime++
+		return time.Unix(0, currentTime)
+	}
+
+	for i := 0; i < 3; i++ {
+		err := sink.DoBulkRequest(context.Background(), bytes.NewBufferString(`{ "index" : { "_index":"blocks", "_id" : "h" } }`+"\n"+`{}`+"\n"), "")
+		require.Nil(t, err)
+	}
+	require.Nil(t, sink.Close())
+
+	files, _ := os.ReadDir(dir)
+	require.Len(t, files, 3)
+	for _, file := range files {
+		require.True(t, strings.HasPrefix(file.Name(), filePrefix))
+		require.True(t, strings.HasSuffix(file.Name(), fileExtension))
+	}
+}
+
+func TestBulkFileSink_DoBulkRequestInvalidBody(t *testing.T) {
+	t.Parallel()
+
+	sink, _ := NewBulkFileSink(ArgsBulkFileSink{
+		Path: t.TempDir(),
+		DatabaseClient: &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				require.Fail(t, "should not have been called")
+				return nil
+			},
+		},
+	})
+
+	err := sink.DoBulkRequest(context.Background(), bytes.NewBufferString("not json\n"), "")
+	require.True(t, errors.Is(err, ErrInvalidBulkBody))
+}
+
+func TestBulkFileSink_DoBulkRequestFailedShouldNotWriteTheBody(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	expectedErr := errors.New("expected error")
+	numCalls := 0
+	sink, _ := NewBulkFileSink(ArgsBulkFileSink{
+		Path: dir,
+		DatabaseClient: &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				numCalls++
+				if numCalls == 1 {
+					return expectedErr
+				}
+				return nil
+			},
+		},
+	})
+
+	body := `{ "index" : { "_index":"rounds", "_id" : "r" } }` + "\n" + `{"round":1}` + "\n"
+	err := sink.DoBulkRequest(context.Background(), bytes.NewBufferString(body), "")
+	require.Equal(t, expectedErr, err)
+	files, _ := os.ReadDir(dir)
+	require.Empty(t, files)
+
+	// the retried body is written once
+	err = sink.DoBulkRequest(context.Background(), bytes.NewBufferString(body), "")
+	require.Nil(t, err)
+	require.Nil(t, sink.Close())
+
+	files, _ = os.ReadDir(dir)
+	require.Len(t, files, 1)
+	written, _ := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.Equal(t, `{"_bulk":{"indices":["rounds"],"lines":2}}`+"\n"+body, string(written))
+}
+
+// closableClient records whether it was closed
+type closableClient struct {
+	mock.DatabaseWriterStub
+	closed bool
+}
+
+func (cc *closableClient) Close() error {
+	cc.closed = true
+	return nil
+}
+
+func TestBulkFileSink_CloseShouldCloseTheWrappedClient(t *testing.T) {
+	t.Parallel()
+
+	wrappedClient := &closableClient{}
+	sink, _ := NewBulkFileSink(ArgsBulkFileSink{
+		Path:           t.TempDir(),
+		DatabaseClient: wrappedClient,
+	})
+
+	err := sink.DoBulkRequest(context.Background(), bytes.NewBufferString(`{ "index" : { "_index":"rounds", "_id" : "r" } }`+"\n"+`{}`+"\n"), "")
+	require.Nil(t, err)
+	require.Nil(t, sink.Close())
+	require.True(t, wrappedClient.closed)
+}
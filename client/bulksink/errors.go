@@ -0,0 +1,12 @@
+package bulksink
+
+import "errors"
+
+// ErrEmptySinkPath signals that an empty path has been provided for the bulk files
+var ErrEmptySinkPath = errors.New("empty bulk sink path")
+
+// ErrNilDatabaseClient signals that a nil database client has been provided
+var ErrNilDatabaseClient = errors.New("nil database client")
+
+// ErrInvalidBulkBody signals that a bulk body could not be parsed
+var ErrInvalidBulkBody = errors.New("invalid bulk body")
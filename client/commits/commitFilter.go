@@ -0,0 +1,116 @@
+package commits
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+	"github.com/tidwall/gjson"
+)
+
+const commitIDField = "commitId"
+
+// ArgsCommitFilter holds all the components needed to create a new instance of commitFilter
+type ArgsCommitFilter struct {
+	DatabaseClient elasticproc.DatabaseClientHandler
+}
+
+type commitFilter struct {
+	databaseClient elasticproc.DatabaseClientHandler
+}
+
+type responseCommits struct {
+	Docs []struct {
+		ID    string `json:"_id"`
+		Found bool   `json:"found"`
+	} `json:"docs"`
+}
+
+// NewCommitFilter will create a new instance of commitFilter. It is meant for the readers of the indices, which should
+// show only the documents of the blocks that were fully indexed. The indexer itself never reads the indices for its
+// users, its REST API only ingests payloads and reports the status, so the filter is not used in this module and is
+// imported by the services that query the indices. The documents of the accounts, accountsesdt, tokens and esdts
+// indices are not stamped with a commit id, so they are never filtered
+func NewCommitFilter(args ArgsCommitFilter) (*commitFilter, error) {
+	if check.IfNil(args.DatabaseClient) {
+		return nil, ErrNilDatabaseClient
+	}
+
+	return &commitFilter{
+		databaseClient: args.DatabaseClient,
+	}, nil
+}
+
+// CommittedIDs returns the provided commit ids that have a commit record in the commits index
+func (cf *commitFilter) CommittedIDs(ctx context.Context, commitIDs []string) (map[string]struct{}, error) {
+	committed := make(map[string]struct{})
+	if len(commitIDs) == 0 {
+		return committed, nil
+	}
+
+	response := &responseCommits{}
+	err := cf.databaseClient.DoMultiGet(ctx, uniqueIDs(commitIDs), dataindexer.CommitsIndex, false, response)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range response.Docs {
+		if doc.Found {
+			committed[doc.ID] = struct{}{}
+		}
+	}
+
+	return committed, nil
+}
+
+// FilterDocuments returns, in the same order, the provided document sources whose block is committed. The documents
+// are checked after the query, so the readers that paginate, count or aggregate should use CommittedQuery instead. The
+// documents without a commit id were written before the commit records were enabled, or by an operation that is not
+// part of a block, so they are kept
+func (cf *commitFilter) FilterDocuments(ctx context.Context, sources []json.RawMessage) ([]json.RawMessage, error) {
+	commitIDs := make([]string, 0, len(sources))
+	for _, source := range sources {
+		commitID := gjson.GetBytes(source, commitIDField).String()
+		if commitID != "" {
+			commitIDs = append(commitIDs, commitID)
+		}
+	}
+
+	committed, err := cf.CommittedIDs(ctx, commitIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]json.RawMessage, 0, len(sources))
+	for _, source := range sources {
+		commitID := gjson.GetBytes(source, commitIDField).String()
+		_, isCommitted := committed[commitID]
+		if commitID == "" || isCommitted {
+			filtered = append(filtered, source)
+		}
+	}
+
+	return filtered, nil
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, found := seen[id]; found {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	return unique
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (cf *commitFilter) IsInterfaceNil() bool {
+	return cf == nil
+}
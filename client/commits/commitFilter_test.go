@@ -0,0 +1,89 @@
+package commits
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createDatabaseClient(committed map[string]struct{}) *mock.DatabaseWriterStub {
+	return &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			docs := make([]map[string]interface{}, 0, len(ids))
+			for _, id := range ids {
+				_, found := committed[id]
+				docs = append(docs, map[string]interface{}{"_id": id, "found": found})
+			}
+
+			responseBytes, _ := json.Marshal(map[string]interface{}{"docs": docs})
+			return json.Unmarshal(responseBytes, response)
+		},
+	}
+}
+
+func TestNewCommitFilter(t *testing.T) {
+	t.Parallel()
+
+	cf, err := NewCommitFilter(ArgsCommitFilter{})
+	require.Nil(t, cf)
+	require.Equal(t, ErrNilDatabaseClient, err)
+
+	cf, err = NewCommitFilter(ArgsCommitFilter{DatabaseClient: &mock.DatabaseWriterStub{}})
+	require.Nil(t, err)
+	require.False(t, cf.IsInterfaceNil())
+}
+
+func TestCommitFilter_CommittedIDs(t *testing.T) {
+	t.Parallel()
+
+	requestedIDs := make([]string, 0)
+	dbClient := createDatabaseClient(map[string]struct{}{"c1": {}})
+	multiGet := dbClient.DoMultiGetCalled
+	dbClient.DoMultiGetCalled = func(ids []string, index string, withSource bool, response interface{}) error {
+		require.Equal(t, dataindexer.CommitsIndex, index)
+		require.False(t, withSource)
+		requestedIDs = ids
+		return multiGet(ids, index, withSource, response)
+	}
+	cf, _ := NewCommitFilter(ArgsCommitFilter{DatabaseClient: dbClient})
+
+	committed, err := cf.CommittedIDs(context.Background(), []string{"c1", "c2", "c1"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"c1", "c2"}, requestedIDs)
+	require.Equal(t, map[string]struct{}{"c1": {}}, committed)
+}
+
+func TestCommitFilter_FilterDocuments(t *testing.T) {
+	t.Parallel()
+
+	cf, _ := NewCommitFilter(ArgsCommitFilter{DatabaseClient: createDatabaseClient(map[string]struct{}{"c1": {}})})
+
+	sources := []json.RawMessage{
+		json.RawMessage(`{"hash":"tx1","commitId":"c1"}`),
+		json.RawMessage(`{"hash":"tx2","commitId":"c2"}`),
+		json.RawMessage(`{"hash":"tx3"}`),
+	}
+	filtered, err := cf.FilterDocuments(context.Background(), sources)
+	require.Nil(t, err)
+	require.Equal(t, []json.RawMessage{sources[0], sources[2]}, filtered)
+}
+
+func TestCommitFilter_FilterDocumentsRequestError(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	cf, _ := NewCommitFilter(ArgsCommitFilter{DatabaseClient: &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			return expectedErr
+		},
+	}})
+
+	filtered, err := cf.FilterDocuments(context.Background(), []json.RawMessage{json.RawMessage(`{"commitId":"c1"}`)})
+	require.Nil(t, filtered)
+	require.Equal(t, expectedErr, err)
+}
@@ -0,0 +1,44 @@
+package commits
+
+import (
+	"encoding/json"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+)
+
+const matchAllQuery = `{"match_all":{}}`
+
+// CommittedQuery wraps the query of a reader, so it matches only the documents whose block is committed. The commit
+// ids of the blocks of the provided shards that are being written, or were reverted, are excluded with a terms lookup
+// on the pending commits document of every shard, so the pagination, the counts and the aggregations of the reader
+// ignore them, unlike FilterDocuments which checks the documents after the query. The documents without a commit id
+// are matched. An empty query matches all the documents
+func CommittedQuery(query []byte, shardIDs []uint32) ([]byte, error) {
+	if len(query) == 0 {
+		query = []byte(matchAllQuery)
+	}
+	if !json.Valid(query) {
+		return nil, ErrInvalidQuery
+	}
+
+	mustNot := make([]map[string]interface{}, 0, len(shardIDs))
+	for _, shardID := range shardIDs {
+		mustNot = append(mustNot, map[string]interface{}{
+			"terms": map[string]interface{}{
+				commitIDField: map[string]interface{}{
+					"index": dataindexer.CommitsIndex,
+					"id":    elasticproc.PendingCommitsID(shardID),
+					"path":  elasticproc.PendingCommitIDsField,
+				},
+			},
+		})
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":     []json.RawMessage{query},
+			"must_not": mustNot,
+		},
+	})
+}
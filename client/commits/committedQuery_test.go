@@ -0,0 +1,32 @@
+package commits
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommittedQuery(t *testing.T) {
+	t.Parallel()
+
+	query, err := CommittedQuery([]byte(`{"term":{"sender":"erd1"}}`), []uint32{0, 4294967295})
+	require.Nil(t, err)
+	require.JSONEq(t, `{"bool":{
+		"must":[{"term":{"sender":"erd1"}}],
+		"must_not":[
+			{"terms":{"commitId":{"index":"commits","id":"pending-0","path":"pendingCommitIds"}}},
+			{"terms":{"commitId":{"index":"commits","id":"pending-4294967295","path":"pendingCommitIds"}}}
+		]
+	}}`, string(query))
+
+	query, err = CommittedQuery(nil, []uint32{1})
+	require.Nil(t, err)
+	require.JSONEq(t, `{"bool":{
+		"must":[{"match_all":{}}],
+		"must_not":[{"terms":{"commitId":{"index":"commits","id":"pending-1","path":"pendingCommitIds"}}}]
+	}}`, string(query))
+
+	query, err = CommittedQuery([]byte("not json"), []uint32{1})
+	require.Nil(t, query)
+	require.Equal(t, ErrInvalidQuery, err)
+}
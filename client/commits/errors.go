@@ -0,0 +1,9 @@
+package commits
+
+import "errors"
+
+// ErrNilDatabaseClient signals that a nil database client has been provided
+var ErrNilDatabaseClient = errors.New("nil database client")
+
+// ErrInvalidQuery signals that the provided query is not a valid JSON
+var ErrInvalidQuery = errors.New("invalid query")
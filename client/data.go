@@ -11,11 +11,31 @@ var headerContentTypeJSON = []string{"application/json"}
 
 // BulkRequestResponse defines the structure of a bulk request response
 type BulkRequestResponse struct {
-	Errors bool `json:"errors"`
-	Items  []struct {
-		ItemIndex  *Item `json:"index"`
-		ItemUpdate *Item `json:"update"`
-	} `json:"items"`
+	Errors bool               `json:"errors"`
+	Items  []BulkResponseItem `json:"items"`
+}
+
+// BulkResponseItem defines the structure of the outcome of an action from a bulk response
+type BulkResponseItem struct {
+	ItemIndex  *Item `json:"index"`
+	ItemCreate *Item `json:"create"`
+	ItemUpdate *Item `json:"update"`
+	ItemDelete *Item `json:"delete"`
+}
+
+func (bri BulkResponseItem) selectItem() (string, Item) {
+	switch {
+	case bri.ItemIndex != nil:
+		return "index", *bri.ItemIndex
+	case bri.ItemCreate != nil:
+		return "create", *bri.ItemCreate
+	case bri.ItemUpdate != nil:
+		return bulkActionUpdate, *bri.ItemUpdate
+	case bri.ItemDelete != nil:
+		return bulkActionDelete, *bri.ItemDelete
+	default:
+		return "", Item{}
+	}
 }
 
 // Item defines the structure of an item from a bulk response
@@ -33,6 +33,8 @@ type (
 type elasticClient struct {
 	elasticBaseUrl string
 	client         *elasticsearch.Client
+	retryPolicy    *retryPolicy
+	gzipBulkBodies bool
 
 	// countScroll is used to be incremented after each scroll so the scroll duration is different each time,
 	// bypassing any possible caching based on the same request
@@ -53,11 +55,30 @@ func NewElasticClient(cfg elasticsearch.Config) (*elasticClient, error) {
 	ec := &elasticClient{
 		client:         es,
 		elasticBaseUrl: cfg.Addresses[0],
+		retryPolicy:    newDisabledRetryPolicy(),
 	}
 
 	return ec, nil
 }
 
+// EnableRetryPolicy creates the policy used to retry the requests rejected by an overloaded cluster. It should be
+// called before the client is used. Without it, every request is sent once
+func (ec *elasticClient) EnableRetryPolicy(args ArgsRetryPolicy) error {
+	policy, err := NewRetryPolicy(args)
+	if err != nil {
+		return err
+	}
+
+	ec.retryPolicy = policy
+	return nil
+}
+
+// EnableBulkCompression makes the client send the bulk bodies compressed with gzip. It should be called before the
+// client is used
+func (ec *elasticClient) EnableBulkCompression() {
+	ec.gzipBulkBodies = true
+}
+
 // CheckAndCreateTemplate creates an index template if it does not already exist
 func (ec *elasticClient) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
 	if ec.templateExists(templateName) {
@@ -113,7 +134,29 @@ func (ec *elasticClient) CheckAndCreateAlias(alias string, indexName string) err
 
 // DoBulkRequest will do a bulk of request to elastic server
 func (ec *elasticClient) DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error {
-	reader := bytes.NewReader(buff.Bytes())
+	return doBulkRequestWithRetries(ctx, buff.Bytes(), index, ec.sendBulkRequestWithRetryPolicy)
+}
+
+func (ec *elasticClient) sendBulkRequestWithRetryPolicy(ctx context.Context, body []byte, index string) ([]bulkItemResult, error) {
+	var items []bulkItemResult
+	err := ec.retryPolicy.do(ctx, func() error {
+		var errSend error
+		items, errSend = ec.sendBulkRequest(ctx, body, index)
+		return errSend
+	})
+
+	return items, err
+}
+
+func (ec *elasticClient) sendBulkRequest(ctx context.Context, body []byte, index string) ([]bulkItemResult, error) {
+	if data.IsCompressedBulkBody(body) {
+		return ec.sendCompressedBulkRequest(ctx, newCompressedBody(body), index)
+	}
+	if ec.gzipBulkBodies {
+		return ec.sendCompressedBulkRequest(ctx, newGzipBody(body), index)
+	}
+
+	reader := bytes.NewReader(body)
 
 	options := make([]func(*esapi.BulkRequest), 0)
 	if index != "" {
@@ -129,80 +172,118 @@ func (ec *elasticClient) DoBulkRequest(ctx context.Context, buff *bytes.Buffer,
 	if err != nil {
 		log.Warn("elasticClient.DoBulkRequest",
 			"indexer do bulk request no response", err.Error())
-		return err
+		return nil, err
 	}
 
-	return elasticBulkRequestResponseHandler(res)
+	return parseBulkRequestResponse(res)
 }
 
-// DoMultiGet wil do a multi get request to Elasticsearch server
-func (ec *elasticClient) DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, resBody interface{}) error {
-	obj := getDocumentsByIDsQuery(ids, withSource)
-	body, err := encode(obj)
+// sendCompressedBulkRequest sends the bulk body compressed with gzip. The request is built here because the body may be
+// streamed while it is compressed and the transport has to be able to read it again when it retries the request
+func (ec *elasticClient) sendCompressedBulkRequest(ctx context.Context, compressedBody *gzipBody, index string) ([]bulkItemResult, error) {
+	bulkRoute := "/_bulk"
+	if index != "" {
+		bulkRoute = fmt.Sprintf("/%s/_bulk", index)
+	}
+
+	req := newRequest(http.MethodPost, bulkRoute, nil).WithContext(ctx)
+	req.Header[headerContentType] = headerContentTypeJSON
+	req.Header.Set(headerContentEncoding, gzipContentEncoding)
+	req.GetBody = compressedBody.reader
+
+	var err error
+	req.Body, err = compressedBody.reader()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	res, err := ec.client.Mget(
-		&body,
-		ec.client.Mget.WithIndex(index),
-		ec.client.Mget.WithContext(ctx),
-	)
+	res, err := ec.client.Transport.Perform(req)
 	if err != nil {
-		log.Warn("elasticClient.DoMultiGet",
-			"cannot do multi get no response", err.Error())
-		return err
+		log.Warn("elasticClient.DoBulkRequest",
+			"indexer do compressed bulk request no response", err.Error())
+		return nil, err
 	}
 
-	err = parseResponse(res, &resBody, elasticDefaultErrorResponseHandler)
+	return parseBulkRequestResponse(&esapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	})
+}
+
+// DoMultiGet wil do a multi get request to Elasticsearch server
+func (ec *elasticClient) DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, resBody interface{}) error {
+	obj := getDocumentsByIDsQuery(ids, withSource)
+	body, err := encode(obj)
 	if err != nil {
-		log.Warn("elasticClient.DoMultiGet",
-			"error parsing response", err.Error())
 		return err
 	}
 
-	return nil
+	return ec.retryPolicy.do(ctx, func() error {
+		res, errGet := ec.client.Mget(
+			bytes.NewReader(body.Bytes()),
+			ec.client.Mget.WithIndex(index),
+			ec.client.Mget.WithContext(ctx),
+		)
+		if errGet != nil {
+			log.Warn("elasticClient.DoMultiGet",
+				"cannot do multi get no response", errGet.Error())
+			return errGet
+		}
+
+		errGet = parseResponse(res, &resBody, elasticDefaultErrorResponseHandler)
+		if errGet != nil {
+			log.Warn("elasticClient.DoMultiGet",
+				"error parsing response", errGet.Error())
+			return errGet
+		}
+
+		return nil
+	})
 }
 
 // DoQueryRemove will do a query remove to elasticsearch server
 func (ec *elasticClient) DoQueryRemove(ctx context.Context, index string, body *bytes.Buffer) error {
-	err := ec.doRefresh(index)
+	err := ec.doRefresh(ctx, index)
 	if err != nil {
 		log.Warn("elasticClient.doRefresh", "cannot do refresh", err)
 	}
 
-	writeIndex, err := ec.getWriteIndex(index)
+	writeIndex, err := ec.getWriteIndex(ctx, index)
 	if err != nil {
 		log.Warn("elasticClient.getWriteIndex", "cannot do get write index", err)
 		return err
 	}
 
-	res, err := ec.client.DeleteByQuery(
-		[]string{writeIndex},
-		body,
-		ec.client.DeleteByQuery.WithIgnoreUnavailable(true),
-		ec.client.DeleteByQuery.WithConflicts(esConflictsPolicy),
-		ec.client.DeleteByQuery.WithContext(ctx),
-	)
+	return ec.retryPolicy.do(ctx, func() error {
+		res, errRemove := ec.client.DeleteByQuery(
+			[]string{writeIndex},
+			bytes.NewReader(body.Bytes()),
+			ec.client.DeleteByQuery.WithIgnoreUnavailable(true),
+			ec.client.DeleteByQuery.WithConflicts(esConflictsPolicy),
+			ec.client.DeleteByQuery.WithContext(ctx),
+		)
 
-	if err != nil {
-		log.Warn("elasticClient.DoQueryRemove", "cannot do query remove", err)
-		return err
-	}
+		if errRemove != nil {
+			log.Warn("elasticClient.DoQueryRemove", "cannot do query remove", errRemove)
+			return errRemove
+		}
 
-	err = parseResponse(res, nil, elasticDefaultErrorResponseHandler)
-	if err != nil {
-		log.Warn("elasticClient.DoQueryRemove", "error parsing response", err)
-		return err
-	}
+		errRemove = parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+		if errRemove != nil {
+			log.Warn("elasticClient.DoQueryRemove", "error parsing response", errRemove)
+			return errRemove
+		}
 
-	return nil
+		return nil
+	})
 }
 
-func (ec *elasticClient) doRefresh(index string) error {
+func (ec *elasticClient) doRefresh(ctx context.Context, index string) error {
 	res, err := ec.client.Indices.Refresh(
 		ec.client.Indices.Refresh.WithIndex(index),
 		ec.client.Indices.Refresh.WithIgnoreUnavailable(true),
+		ec.client.Indices.Refresh.WithContext(ctx),
 	)
 	if err != nil {
 		return err
@@ -347,9 +428,10 @@ func (ec *elasticClient) createAlias(alias string, index string) error {
 	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
 }
 
-func (ec *elasticClient) getWriteIndex(alias string) (string, error) {
+func (ec *elasticClient) getWriteIndex(ctx context.Context, alias string) (string, error) {
 	res, err := ec.client.Indices.GetAlias(
 		ec.client.Indices.GetAlias.WithIndex(alias),
+		ec.client.Indices.GetAlias.WithContext(ctx),
 	)
 	if err != nil {
 		return "", err
@@ -382,20 +464,21 @@ func (ec *elasticClient) getWriteIndex(alias string) (string, error) {
 
 // UpdateByQuery will update all the documents that match the provided query from the provided index
 func (ec *elasticClient) UpdateByQuery(ctx context.Context, index string, buff *bytes.Buffer) error {
-	reader := bytes.NewReader(buff.Bytes())
-	res, err := ec.client.UpdateByQuery(
-		[]string{index},
-		ec.client.UpdateByQuery.WithBody(reader),
-		ec.client.UpdateByQuery.WithContext(ctx),
-	)
-	if err != nil {
-		return err
-	}
-	if res.IsError() {
-		return fmt.Errorf("%s", res.String())
-	}
+	return ec.retryPolicy.do(ctx, func() error {
+		res, err := ec.client.UpdateByQuery(
+			[]string{index},
+			ec.client.UpdateByQuery.WithBody(bytes.NewReader(buff.Bytes())),
+			ec.client.UpdateByQuery.WithContext(ctx),
+		)
+		if err != nil {
+			return err
+		}
+		if res.IsError() {
+			return newResponseError(res, res.String())
+		}
 
-	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+		return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+	})
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -87,65 +88,38 @@ func elasticDefaultErrorResponseHandler(res *esapi.Response) error {
 		return nil
 	}
 
-	return fmt.Errorf("error while parsing the response: code returned: %v, body: %v, bodyBytes: %v",
-		res.StatusCode, responseBody, string(bodyBytes))
+	return newResponseError(res, fmt.Sprintf("error while parsing the response: code returned: %v, body: %v, bodyBytes: %v",
+		res.StatusCode, responseBody, string(bodyBytes)))
 }
 
-func elasticBulkRequestResponseHandler(res *esapi.Response) error {
-	if res.IsError() {
-		return fmt.Errorf("%s", res.String())
-	}
-
-	bodyBytes, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("%w cannot read elastic response body bytes", err)
+// newResponseError returns the error of a failed response. The requests rejected by an overloaded cluster are signaled
+// with ErrBackOff, so they can be retried
+func newResponseError(res *esapi.Response, message string) error {
+	if res.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w, %s", dataindexer.ErrBackOff, message)
 	}
 
-	return extractErrorFromBulkBodyResponseBytes(bodyBytes)
+	return errors.New(message)
 }
 
-func extractErrorFromBulkBodyResponseBytes(bodyBytes []byte) error {
-	response := BulkRequestResponse{}
-	err := json.Unmarshal(bodyBytes, &response)
-	if err != nil {
-		return err
-	}
-
-	count := 0
-	errorsString := ""
-	for _, item := range response.Items {
-		var selectedItem Item
-
-		switch {
-		case item.ItemIndex != nil:
-			selectedItem = *item.ItemIndex
-		case item.ItemUpdate != nil:
-			selectedItem = *item.ItemUpdate
-		}
-
-		log.Trace("worked on", "index", selectedItem.Index,
-			"_id", selectedItem.ID,
-			"result", selectedItem.Result,
-			"status", selectedItem.Status,
-		)
-
-		if selectedItem.Status < http.StatusBadRequest {
-			continue
+// parseBulkRequestResponse returns the outcome of every action of the bulk request
+func parseBulkRequestResponse(res *esapi.Response) ([]bulkItemResult, error) {
+	defer func() {
+		if res.Body != nil {
+			_ = res.Body.Close()
 		}
+	}()
 
-		count++
-		errorsString += fmt.Sprintf(`{ "index": "%s", "id": "%s", "statusCode": %d, "errorType": "%s", "reason": "%s", "causedBy": { "type": "%s", "reason": "%s", "script_stack":"%s", "script":"%s" }}\n`,
-			selectedItem.Index, selectedItem.ID, selectedItem.Status, selectedItem.Error.Type, selectedItem.Error.Reason, selectedItem.Error.Cause.Type, selectedItem.Error.Cause.Reason, selectedItem.Error.Cause.ScriptStack, selectedItem.Error.Cause.Script)
-
-		if count == numOfErrorsToExtractBulkResponse {
-			break
-		}
+	if res.IsError() {
+		return nil, newResponseError(res, res.String())
 	}
-	if errorsString == "" {
-		return nil
+
+	bodyBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w cannot read elastic response body bytes", err)
 	}
 
-	return fmt.Errorf("%s", errorsString)
+	return parseBulkResponseItems(bodyBytes)
 }
 
 func errIsAlreadyExists(response map[string]interface{}) bool {
@@ -141,16 +141,25 @@ func createMockEsapiResponseWithText(str string) *esapi.Response {
 	}
 }
 
-func TestExtractErrorFromBulkBodyResponseBytesUpdate(t *testing.T) {
+func TestParseBulkResponseItemsUpdate(t *testing.T) {
 	responseBytes := []byte(`{"took":39,"errors":true,"items":[{"update":{"_index":"transactions-000001","_type":"_doc","_id":"76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa","status":409,"error":{"type":"version_conflict_engine_exception","reason":"[76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa]: version conflict, required seqNo [1904], primary term [1]. current document has seqNo [1975] and primary term [1]","index_uuid":"_mEW9HB_QiSbIvkbythJ7Q","shard":"2","index":"transactions-000001"}}}]}`)
 
-	err := extractErrorFromBulkBodyResponseBytes(responseBytes)
-	require.NotNil(t, err)
+	items, err := parseBulkResponseItems(responseBytes)
+	require.Nil(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, bulkActionUpdate, items[0].action)
+	require.True(t, items[0].failed())
+	require.True(t, items[0].retryable())
 }
 
-func TestExtractErrorFromBulkBodyResponseBytesIndex(t *testing.T) {
+func TestParseBulkResponseItemsIndex(t *testing.T) {
 	responseBytes := []byte(`{"took":39,"errors":true,"items":[{"index":{"_index":"transactions-000001","_type":"_doc","_id":"76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa","status":409,"error":{"type":"version_conflict_engine_exception","reason":"[76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa]: version conflict, required seqNo [1904], primary term [1]. current document has seqNo [1975] and primary term [1]","index_uuid":"_mEW9HB_QiSbIvkbythJ7Q","shard":"2","index":"transactions-000001"}}}]}`)
 
-	err := extractErrorFromBulkBodyResponseBytes(responseBytes)
-	require.NotNil(t, err)
+	items, err := parseBulkResponseItems(responseBytes)
+	require.Nil(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "index", items[0].action)
+	require.Equal(t, versionConflictErrorType, items[0].errorType)
+	require.True(t, items[0].failed())
+	require.False(t, items[0].retryable())
 }
@@ -15,16 +15,20 @@ import (
 
 // DoCountRequest will get the number of elements that correspond with the provided query
 func (ec *elasticClient) DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error) {
-	res, err := ec.client.Count(
-		ec.client.Count.WithIndex(index),
-		ec.client.Count.WithBody(bytes.NewBuffer(body)),
-		ec.client.Count.WithContext(ctx),
-	)
-	if err != nil {
-		return 0, err
-	}
+	var bodyBytes []byte
+	err := ec.retryPolicy.do(ctx, func() error {
+		res, errCount := ec.client.Count(
+			ec.client.Count.WithIndex(index),
+			ec.client.Count.WithBody(bytes.NewBuffer(body)),
+			ec.client.Count.WithContext(ctx),
+		)
+		if errCount != nil {
+			return errCount
+		}
 
-	bodyBytes, err := getBytesFromResponse(res)
+		bodyBytes, errCount = getBytesFromResponse(res)
+		return errCount
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -46,7 +50,6 @@ func (ec *elasticClient) DoScrollRequest(
 	res, err := ec.client.Search(
 		ec.client.Search.WithSize(9000),
 		ec.client.Search.WithScroll(10*time.Minute+time.Duration(ec.countScroll)*time.Millisecond),
-		ec.client.Search.WithContext(context.Background()),
 		ec.client.Search.WithIndex(index),
 		ec.client.Search.WithBody(bytes.NewBuffer(body)),
 		ec.client.Search.WithSource(strconv.FormatBool(withSource)),
@@ -67,10 +70,11 @@ func (ec *elasticClient) DoScrollRequest(
 	}
 
 	scrollID := gjson.Get(string(bodyBytes), "_scroll_id")
-	return ec.iterateScroll(scrollID.String(), handlerFunc)
+	return ec.iterateScroll(ctx, scrollID.String(), handlerFunc)
 }
 
 func (ec *elasticClient) iterateScroll(
+	ctx context.Context,
 	scrollID string,
 	handlerFunc func(responseBytes []byte) error,
 ) error {
@@ -85,7 +89,7 @@ func (ec *elasticClient) iterateScroll(
 	}()
 
 	for {
-		scrollBodyBytes, errScroll := ec.getScrollResponse(scrollID)
+		scrollBodyBytes, errScroll := ec.getScrollResponse(ctx, scrollID)
 		if errScroll != nil {
 			return errScroll
 		}
@@ -101,11 +105,12 @@ func (ec *elasticClient) iterateScroll(
 	}
 }
 
-func (ec *elasticClient) getScrollResponse(scrollID string) ([]byte, error) {
+func (ec *elasticClient) getScrollResponse(ctx context.Context, scrollID string) ([]byte, error) {
 	ec.countScroll++
 	res, err := ec.client.Scroll(
 		ec.client.Scroll.WithScrollID(scrollID),
 		ec.client.Scroll.WithScroll(2*time.Minute+time.Duration(ec.countScroll)*time.Millisecond),
+		ec.client.Scroll.WithContext(ctx),
 	)
 	if err != nil {
 		return nil, err
@@ -132,7 +137,7 @@ func (ec *elasticClient) clearScroll(scrollID string) error {
 
 func getBytesFromResponse(res *esapi.Response) ([]byte, error) {
 	if res.IsError() {
-		return nil, fmt.Errorf("error response: %s", res)
+		return nil, newResponseError(res, fmt.Sprintf("error response: %s", res))
 	}
 	defer closeBody(res)
 
@@ -0,0 +1,321 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	elasticsearch8 "github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/bulk"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/conflicts"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+type elasticClientV8 struct {
+	client      *elasticsearch8.TypedClient
+	retryPolicy *retryPolicy
+
+	// countScroll is used to be incremented after each scroll so the scroll duration is different each time,
+	// bypassing any possible caching based on the same request
+	countScroll int
+}
+
+// NewElasticClientV8 will create a new instance of elasticClientV8. It works with Elasticsearch 8.x clusters, using the
+// typed API of the v8 client and the composable index templates
+func NewElasticClientV8(cfg elasticsearch8.Config) (*elasticClientV8, error) {
+	if len(cfg.Addresses) == 0 {
+		return nil, dataindexer.ErrNoElasticUrlProvided
+	}
+
+	es, err := elasticsearch8.NewTypedClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &elasticClientV8{
+		client:      es,
+		retryPolicy: newDisabledRetryPolicy(),
+	}, nil
+}
+
+// EnableRetryPolicy creates the policy used to retry the requests rejected by an overloaded cluster. It should be
+// called before the client is used. Without it, every request is sent once
+func (ec *elasticClientV8) EnableRetryPolicy(args ArgsRetryPolicy) error {
+	policy, err := NewRetryPolicy(args)
+	if err != nil {
+		return err
+	}
+
+	ec.retryPolicy = policy
+	return nil
+}
+
+// CheckAndCreateTemplate creates a composable index template if it does not already exist
+func (ec *elasticClientV8) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
+	exists, err := ec.client.Indices.ExistsIndexTemplate(templateName).Do(context.Background())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = ec.client.Indices.PutIndexTemplate(templateName).Raw(bytes.NewReader(template.Bytes())).Do(context.Background())
+	return err
+}
+
+// CheckAndCreatePolicy creates a new index lifecycle policy if it does not already exist
+func (ec *elasticClientV8) CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error {
+	exists, err := ec.client.Ilm.GetLifecycle().Policy(policyName).IsSuccess(context.Background())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = ec.client.Ilm.PutLifecycle(policyName).Raw(bytes.NewReader(policy.Bytes())).Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("%w: %s", dataindexer.ErrCouldNotCreatePolicy, err.Error())
+	}
+
+	return nil
+}
+
+// CheckAndCreateIndex creates a new index if it does not already exist
+func (ec *elasticClientV8) CheckAndCreateIndex(indexName string) error {
+	exists, err := ec.client.Indices.Exists(indexName).Do(context.Background())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = ec.client.Indices.Create(indexName).Do(context.Background())
+	return err
+}
+
+// PutMappings will put the provided mappings to a given index
+func (ec *elasticClientV8) PutMappings(indexName string, mappings *bytes.Buffer) error {
+	_, err := ec.client.Indices.PutMapping(indexName).Raw(bytes.NewReader(mappings.Bytes())).Do(context.Background())
+	return err
+}
+
+// CheckAndCreateAlias creates a new alias if it does not already exist
+func (ec *elasticClientV8) CheckAndCreateAlias(alias string, indexName string) error {
+	exists, err := ec.client.Indices.ExistsAlias(alias).Do(context.Background())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = ec.client.Indices.PutAlias(indexName, alias).Do(context.Background())
+	return err
+}
+
+// DoBulkRequest will do a bulk of request to elastic server. The compressed bodies are decompressed, the client
+// compresses the requests itself
+func (ec *elasticClientV8) DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error {
+	body, err := data.DecompressBulkBody(buff.Bytes())
+	if err != nil {
+		return fmt.Errorf("%w: %s", dataindexer.ErrInvalidBulkBody, err.Error())
+	}
+
+	return doBulkRequestWithRetries(ctx, body, index, ec.sendBulkRequestWithRetryPolicy)
+}
+
+func (ec *elasticClientV8) sendBulkRequestWithRetryPolicy(ctx context.Context, body []byte, index string) ([]bulkItemResult, error) {
+	var items []bulkItemResult
+	err := ec.retryPolicy.do(ctx, func() error {
+		var errSend error
+		items, errSend = ec.sendBulkRequest(ctx, body, index)
+		return errSend
+	})
+
+	return items, err
+}
+
+func (ec *elasticClientV8) sendBulkRequest(ctx context.Context, body []byte, index string) ([]bulkItemResult, error) {
+	req := ec.client.Bulk().Raw(bytes.NewReader(body))
+	if index != "" {
+		req.Index(index)
+	}
+
+	res, err := req.Do(ctx)
+	if err != nil {
+		log.Warn("elasticClientV8.DoBulkRequest",
+			"indexer do bulk request no response", err.Error())
+		return nil, newResponseErrorV8(err)
+	}
+
+	return extractItemsFromBulkResponse(res), nil
+}
+
+func extractItemsFromBulkResponse(res *bulk.Response) []bulkItemResult {
+	items := make([]bulkItemResult, 0, len(res.Items))
+	for _, item := range res.Items {
+		for action, selectedItem := range item {
+			errorType := ""
+			if selectedItem.Error != nil {
+				errorType = selectedItem.Error.Type
+			}
+
+			items = append(items, bulkItemResult{
+				action:      action.String(),
+				status:      selectedItem.Status,
+				errorType:   errorType,
+				description: formatBulkItemError(selectedItem),
+			})
+		}
+	}
+
+	return items
+}
+
+func formatBulkItemError(item types.ResponseItem) string {
+	errorType, reason, causeType, causeReason := "", "", "", ""
+	if item.Error != nil {
+		errorType = item.Error.Type
+		reason = stringValue(item.Error.Reason)
+		if item.Error.CausedBy != nil {
+			causeType = item.Error.CausedBy.Type
+			causeReason = stringValue(item.Error.CausedBy.Reason)
+		}
+	}
+
+	return fmt.Sprintf(`{ "index": "%s", "id": "%s", "statusCode": %d, "errorType": "%s", "reason": "%s", "causedBy": { "type": "%s", "reason": "%s" }}`,
+		item.Index_, stringValue(item.Id_), item.Status, errorType, reason, causeType, causeReason)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+// DoMultiGet wil do a multi get request to Elasticsearch server
+func (ec *elasticClientV8) DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, resBody interface{}) error {
+	obj := getDocumentsByIDsQuery(ids, withSource)
+	body, err := encode(obj)
+	if err != nil {
+		return err
+	}
+
+	return ec.retryPolicy.do(ctx, func() error {
+		res, errGet := ec.client.Mget().Index(index).Raw(bytes.NewReader(body.Bytes())).Perform(ctx)
+		if errGet != nil {
+			log.Warn("elasticClientV8.DoMultiGet",
+				"cannot do multi get no response", errGet.Error())
+			return errGet
+		}
+
+		errGet = parseHTTPResponse(res, resBody)
+		if errGet != nil {
+			log.Warn("elasticClientV8.DoMultiGet",
+				"error parsing response", errGet.Error())
+			return errGet
+		}
+
+		return nil
+	})
+}
+
+// DoQueryRemove will do a query remove to elasticsearch server
+func (ec *elasticClientV8) DoQueryRemove(ctx context.Context, index string, body *bytes.Buffer) error {
+	_, err := ec.client.Indices.Refresh().Index(index).IgnoreUnavailable(true).Do(ctx)
+	if err != nil {
+		log.Warn("elasticClientV8.doRefresh", "cannot do refresh", err)
+	}
+
+	writeIndex, err := ec.getWriteIndex(ctx, index)
+	if err != nil {
+		log.Warn("elasticClientV8.getWriteIndex", "cannot do get write index", err)
+		return err
+	}
+
+	return ec.retryPolicy.do(ctx, func() error {
+		_, errRemove := ec.client.DeleteByQuery(writeIndex).
+			Raw(bytes.NewReader(body.Bytes())).
+			IgnoreUnavailable(true).
+			Conflicts(conflicts.Proceed).
+			Do(ctx)
+		if errRemove != nil {
+			log.Warn("elasticClientV8.DoQueryRemove", "cannot do query remove", errRemove)
+			return newResponseErrorV8(errRemove)
+		}
+
+		return nil
+	})
+}
+
+func (ec *elasticClientV8) getWriteIndex(ctx context.Context, alias string) (string, error) {
+	indexData, err := ec.client.Indices.GetAlias().Index(alias).Do(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for index, details := range indexData {
+		if len(indexData) == 1 {
+			return index, nil
+		}
+
+		for _, indexAlias := range details.Aliases {
+			if indexAlias.IsWriteIndex != nil && *indexAlias.IsWriteIndex {
+				return index, nil
+			}
+		}
+	}
+
+	return alias, nil
+}
+
+// UpdateByQuery will update all the documents that match the provided query from the provided index
+func (ec *elasticClientV8) UpdateByQuery(ctx context.Context, index string, buff *bytes.Buffer) error {
+	return ec.retryPolicy.do(ctx, func() error {
+		_, err := ec.client.UpdateByQuery(index).Raw(bytes.NewReader(buff.Bytes())).Do(ctx)
+		return newResponseErrorV8(err)
+	})
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ec *elasticClientV8) IsInterfaceNil() bool {
+	return ec == nil
+}
+
+func parseHTTPResponse(res *http.Response, dest interface{}) error {
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("%w, status code: %d, body: %s", dataindexer.ErrBackOff, res.StatusCode, string(bodyBytes))
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("error response, status code: %d, body: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return loadResponseBody(res.Body, dest)
+}
+
+// newResponseErrorV8 signals with ErrBackOff the requests of the typed API rejected by an overloaded cluster, so they
+// can be retried
+func newResponseErrorV8(err error) error {
+	var esErr *types.ElasticsearchError
+	if errors.As(err, &esErr) && esErr.Status == http.StatusTooManyRequests {
+		return fmt.Errorf("%w, %s", dataindexer.ErrBackOff, err.Error())
+	}
+
+	return err
+}
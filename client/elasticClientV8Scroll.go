@@ -0,0 +1,165 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// DoCountRequest will get the number of elements that correspond with the provided query
+func (ec *elasticClientV8) DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error) {
+	var count int64
+	err := ec.retryPolicy.do(ctx, func() error {
+		res, errCount := ec.client.Count().Index(index).Raw(bytes.NewReader(body)).Do(ctx)
+		if errCount != nil {
+			return newResponseErrorV8(errCount)
+		}
+
+		count = res.Count
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+// DoScrollRequest will perform a documents request using scroll api
+func (ec *elasticClientV8) DoScrollRequest(
+	ctx context.Context,
+	index string,
+	body []byte,
+	withSource bool,
+	handlerFunc func(responseBytes []byte) error,
+) error {
+	// the _source flag is a body field in the typed API, so it is added to the provided query
+	query := objectsMap{}
+	if len(body) > 0 {
+		err := json.Unmarshal(body, &query)
+		if err != nil {
+			return err
+		}
+	}
+	query["_source"] = withSource
+
+	queryBuff, err := encode(query)
+	if err != nil {
+		return err
+	}
+
+	ec.countScroll++
+	scrollDuration := 10*time.Minute + time.Duration(ec.countScroll)*time.Millisecond
+	res, err := ec.client.Search().
+		Index(index).
+		Size(9000).
+		Scroll(formatDurationV8(scrollDuration)).
+		Raw(&queryBuff).
+		Perform(ctx)
+	if err != nil {
+		return err
+	}
+
+	bodyBytes, err := getBytesFromHTTPResponse(res)
+	if err != nil {
+		return err
+	}
+
+	err = handlerFunc(bodyBytes)
+	if err != nil {
+		return err
+	}
+
+	scrollID := gjson.Get(string(bodyBytes), "_scroll_id")
+	return ec.iterateScroll(ctx, scrollID.String(), handlerFunc)
+}
+
+func (ec *elasticClientV8) iterateScroll(
+	ctx context.Context,
+	scrollID string,
+	handlerFunc func(responseBytes []byte) error,
+) error {
+	if scrollID == "" {
+		return nil
+	}
+	defer func() {
+		err := ec.clearScroll(scrollID)
+		if err != nil {
+			log.Warn("cannot clear scroll", "error", err)
+		}
+	}()
+
+	for {
+		scrollBodyBytes, errScroll := ec.getScrollResponse(ctx, scrollID)
+		if errScroll != nil {
+			return errScroll
+		}
+
+		numberOfHits := gjson.Get(string(scrollBodyBytes), "hits.hits.#")
+		if numberOfHits.Int() < 1 {
+			return nil
+		}
+		err := handlerFunc(scrollBodyBytes)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (ec *elasticClientV8) getScrollResponse(ctx context.Context, scrollID string) ([]byte, error) {
+	ec.countScroll++
+	scrollDuration := 2*time.Minute + time.Duration(ec.countScroll)*time.Millisecond
+	res, err := ec.client.Scroll().
+		ScrollId(scrollID).
+		Scroll(formatDurationV8(scrollDuration)).
+		Perform(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return getBytesFromHTTPResponse(res)
+}
+
+func (ec *elasticClientV8) clearScroll(scrollID string) error {
+	res, err := ec.client.ClearScroll().ScrollId(scrollID).Perform(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode >= http.StatusBadRequest && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("error response, status code: %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+func getBytesFromHTTPResponse(res *http.Response) ([]byte, error) {
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	bodyBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("error response, status code: %d, body: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return bodyBytes, nil
+}
+
+// formatDurationV8 returns the duration in the time units format of Elasticsearch
+func formatDurationV8(duration time.Duration) string {
+	return strconv.FormatInt(duration.Milliseconds(), 10) + "ms"
+}
@@ -0,0 +1,225 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	elasticsearch8 "github.com/elastic/go-elasticsearch/v8"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	indexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createElasticClientV8(t *testing.T, handler http.HandlerFunc) *elasticClientV8 {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the v8 client checks that the responses are sent by an Elasticsearch cluster
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	esClient, err := NewElasticClientV8(elasticsearch8.Config{
+		Addresses: []string{ts.URL},
+		APIKey:    "api-key",
+		Logger:    &logging.CustomLogger{},
+	})
+	require.Nil(t, err)
+
+	return esClient
+}
+
+func TestNewElasticClientV8(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty url should error", func(t *testing.T) {
+		t.Parallel()
+
+		esClient, err := NewElasticClientV8(elasticsearch8.Config{})
+		require.Nil(t, esClient)
+		require.Equal(t, indexer.ErrNoElasticUrlProvided, err)
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		esClient, err := NewElasticClientV8(elasticsearch8.Config{Addresses: []string{"http://localhost:9200"}})
+		require.Nil(t, err)
+		require.False(t, esClient.IsInterfaceNil())
+	})
+}
+
+func TestElasticClientV8_CheckAndCreateTemplate(t *testing.T) {
+	t.Parallel()
+
+	mut := sync.Mutex{}
+	requests := make([]string, 0)
+	esClient := createElasticClientV8(t, func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mut.Unlock()
+
+		require.Equal(t, "APIKey api-key", r.Header.Get("Authorization"))
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"acknowledged":true}`))
+	})
+
+	err := esClient.CheckAndCreateTemplate("rounds", bytes.NewBufferString(`{"index_patterns":["rounds-*"]}`))
+	require.Nil(t, err)
+	require.Equal(t, []string{"HEAD /_index_template/rounds", "PUT /_index_template/rounds"}, requests)
+}
+
+func TestElasticClientV8_DoBulkRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		esClient := createElasticClientV8(t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/operations/_bulk", r.URL.Path)
+			_, _ = w.Write([]byte(`{"took":1,"errors":false,"items":[{"index":{"_index":"operations","_id":"a","status":201}}]}`))
+		})
+
+		err := esClient.DoBulkRequest(context.Background(), bytes.NewBufferString("{}\n"), "operations")
+		require.Nil(t, err)
+	})
+
+	t.Run("failed items should error", func(t *testing.T) {
+		t.Parallel()
+
+		esClient := createElasticClientV8(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"took":1,"errors":true,"items":[{"index":{"_index":"operations","_id":"a","status":201}},` +
+				`{"update":{"_index":"operations","_id":"b","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`))
+		})
+
+		err := esClient.DoBulkRequest(context.Background(), bytes.NewBufferString("{}\n"), "")
+		require.NotNil(t, err)
+		require.True(t, strings.Contains(err.Error(), `"id": "b"`))
+		require.True(t, strings.Contains(err.Error(), "mapper_parsing_exception"))
+		require.False(t, strings.Contains(err.Error(), `"id": "a"`))
+	})
+}
+
+func TestElasticClientV8_RetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejected bulk request should be retried", func(t *testing.T) {
+		t.Parallel()
+
+		numRequests := 0
+		esClient := createElasticClientV8(t, func(w http.ResponseWriter, r *http.Request) {
+			numRequests++
+			if numRequests == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"error":{"type":"es_rejected_execution_exception","reason":"rejected"},"status":429}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"took":1,"errors":false,"items":[{"index":{"_index":"operations","_id":"a","status":201}}]}`))
+		})
+		err := esClient.EnableRetryPolicy(createMockRetryPolicyArgs())
+		require.Nil(t, err)
+
+		err = esClient.DoBulkRequest(context.Background(), bytes.NewBufferString("{}\n"), "operations")
+		require.Nil(t, err)
+		require.Equal(t, 2, numRequests)
+	})
+
+	t.Run("rejected requests should signal back off without the retry policy", func(t *testing.T) {
+		t.Parallel()
+
+		numRequests := 0
+		esClient := createElasticClientV8(t, func(w http.ResponseWriter, r *http.Request) {
+			numRequests++
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"type":"es_rejected_execution_exception","reason":"rejected"},"status":429}`))
+		})
+
+		_, err := esClient.DoCountRequest(context.Background(), "tokens", []byte(`{"query":{"match_all":{}}}`))
+		require.True(t, errors.Is(err, indexer.ErrBackOff))
+
+		err = esClient.DoMultiGet(context.Background(), []string{"id"}, "tokens", true, &data.ResponseTokens{})
+		require.True(t, errors.Is(err, indexer.ErrBackOff))
+
+		err = esClient.UpdateByQuery(context.Background(), "tokens", bytes.NewBufferString(`{}`))
+		require.True(t, errors.Is(err, indexer.ErrBackOff))
+		require.Equal(t, 3, numRequests)
+	})
+}
+
+func TestElasticClientV8_DoMultiGet(t *testing.T) {
+	t.Parallel()
+
+	esClient := createElasticClientV8(t, func(w http.ResponseWriter, r *http.Request) {
+		jsonFile, err := os.Open("./testsData/response-multi-get.json")
+		require.Nil(t, err)
+
+		byteValue, _ := io.ReadAll(jsonFile)
+		_, _ = w.Write(byteValue)
+	})
+
+	res := &data.ResponseTokens{}
+	err := esClient.DoMultiGet(context.Background(), []string{"id"}, "tokens", true, res)
+	require.Nil(t, err)
+	require.Len(t, res.Docs, 3)
+}
+
+func TestElasticClientV8_DoCountRequest(t *testing.T) {
+	t.Parallel()
+
+	esClient := createElasticClientV8(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/tokens/_count", r.URL.Path)
+		_, _ = w.Write([]byte(`{"count":112,"_shards":{"total":1,"successful":1,"skipped":0,"failed":0}}`))
+	})
+
+	count, err := esClient.DoCountRequest(context.Background(), "tokens", []byte(`{"query":{"match_all":{}}}`))
+	require.Nil(t, err)
+	require.Equal(t, uint64(112), count)
+}
+
+func TestElasticClientV8_DoScrollRequest(t *testing.T) {
+	t.Parallel()
+
+	numScrolls := 0
+	clearedScroll := false
+	esClient := createElasticClientV8(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/accounts/_search":
+			body, _ := io.ReadAll(r.Body)
+			require.True(t, strings.Contains(string(body), `"_source":false`))
+			_, _ = w.Write([]byte(`{"_scroll_id":"scroll","hits":{"hits":[{"_id":"a"}]}}`))
+		case r.URL.Path == "/_search/scroll" && r.Method == http.MethodDelete:
+			clearedScroll = true
+			_, _ = w.Write([]byte(`{"succeeded":true,"num_freed":1}`))
+		case r.URL.Path == "/_search/scroll":
+			numScrolls++
+			if numScrolls > 1 {
+				_, _ = w.Write([]byte(`{"_scroll_id":"scroll","hits":{"hits":[]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"_scroll_id":"scroll","hits":{"hits":[{"_id":"b"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	numHandledResponses := 0
+	err := esClient.DoScrollRequest(context.Background(), "accounts", []byte(`{"query":{"match_all":{}}}`), false, func(_ []byte) error {
+		numHandledResponses++
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, numHandledResponses)
+	require.True(t, clearedScroll)
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"net/http"
@@ -42,6 +43,31 @@ func TestElasticClient_NewClient(t *testing.T) {
 	require.NotNil(t, esClient)
 }
 
+func TestElasticClient_DoBulkRequestFailsOverToTheNextNode(t *testing.T) {
+	t.Parallel()
+
+	stoppedNode := httptest.NewServer(http.NotFoundHandler())
+	stoppedNode.Close()
+
+	numRequests := 0
+	liveNode := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numRequests++
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{"took":1,"errors":false,"items":[]}`))
+	}))
+	defer liveNode.Close()
+
+	esClient, _ := NewElasticClient(elasticsearch.Config{
+		Addresses: []string{stoppedNode.URL, liveNode.URL},
+	})
+
+	for i := 0; i < 4; i++ {
+		err := esClient.DoBulkRequest(context.Background(), bytes.NewBufferString("{}\n"), "")
+		require.Nil(t, err)
+	}
+	require.Equal(t, 4, numRequests)
+}
+
 func TestElasticClient_DoMultiGet(t *testing.T) {
 	handler := http.NotFound
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -95,7 +121,7 @@ func TestElasticClient_GetWriteIndexMultipleIndicesBehind(t *testing.T) {
 		Addresses: []string{ts.URL},
 		Logger:    &logging.CustomLogger{},
 	})
-	res, err := esClient.getWriteIndex("blocks")
+	res, err := esClient.getWriteIndex(context.Background(), "blocks")
 	require.Nil(t, err)
 	require.Equal(t, "blocks-000004", res)
 }
@@ -119,7 +145,7 @@ func TestElasticClient_GetWriteIndexOneIndex(t *testing.T) {
 		Addresses: []string{ts.URL},
 		Logger:    &logging.CustomLogger{},
 	})
-	res, err := esClient.getWriteIndex("delegators")
+	res, err := esClient.getWriteIndex(context.Background(), "delegators")
 	require.Nil(t, err)
 	require.Equal(t, "delegators-000001", res)
 }
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+const (
+	headerContentEncoding = "Content-Encoding"
+	gzipContentEncoding   = "gzip"
+	bulkCompressionLevel  = gzip.DefaultCompression
+)
+
+var gzipWritersPool = sync.Pool{
+	New: func() interface{} {
+		gzipWriter, _ := gzip.NewWriterLevel(nil, bulkCompressionLevel)
+		return gzipWriter
+	},
+}
+
+// gzipBody compresses a bulk body while the transport sends it, so the request does not wait for the whole body to be
+// compressed. It is used for the bodies built uncompressed, like the failed actions sent again, while the bodies of the
+// blocks are compressed as they are built. The compressed bytes are kept next to the body, so the body can be sent
+// again by the transport retries, or read by the request logger, without being compressed again. Both are released
+// when the request completes
+type gzipBody struct {
+	body       []byte
+	mut        sync.Mutex
+	compressed []byte
+}
+
+func newGzipBody(body []byte) *gzipBody {
+	return &gzipBody{
+		body: body,
+	}
+}
+
+// newCompressedBody wraps a bulk body that is already compressed
+func newCompressedBody(compressed []byte) *gzipBody {
+	return &gzipBody{
+		compressed: compressed,
+	}
+}
+
+// reader returns a reader of the compressed body. It can be called multiple times
+func (gb *gzipBody) reader() (io.ReadCloser, error) {
+	gb.mut.Lock()
+	compressed := gb.compressed
+	gb.mut.Unlock()
+
+	if compressed != nil {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	go gb.compress(pipeWriter)
+
+	return pipeReader, nil
+}
+
+// compress writes the compressed body in the pipe, which blocks until the transport reads it. If the transport closes
+// the reader before the end of the body, the compression stops
+func (gb *gzipBody) compress(pipeWriter *io.PipeWriter) {
+	compressed := bytes.NewBuffer(make([]byte, 0, len(gb.body)/4))
+
+	gzipWriter := gzipWritersPool.Get().(*gzip.Writer)
+	gzipWriter.Reset(io.MultiWriter(pipeWriter, compressed))
+	_, err := gzipWriter.Write(gb.body)
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+	gzipWriter.Reset(nil)
+	gzipWritersPool.Put(gzipWriter)
+
+	if err == nil {
+		gb.mut.Lock()
+		gb.compressed = compressed.Bytes()
+		gb.mut.Unlock()
+	}
+
+	_ = pipeWriter.CloseWithError(err)
+}
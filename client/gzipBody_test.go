@@ -0,0 +1,127 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func decompress(t *testing.T, reader io.Reader) string {
+	gzipReader, err := gzip.NewReader(reader)
+	require.Nil(t, err)
+
+	decompressed, err := io.ReadAll(gzipReader)
+	require.Nil(t, err)
+
+	return string(decompressed)
+}
+
+func TestGzipBody_Reader(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat(testBulkBody, 1000)
+	compressedBody := newGzipBody([]byte(body))
+
+	reader, err := compressedBody.reader()
+	require.Nil(t, err)
+	compressed, err := io.ReadAll(reader)
+	require.Nil(t, err)
+	require.Nil(t, reader.Close())
+	require.Less(t, len(compressed), len(body)/10)
+	require.Equal(t, body, decompress(t, bytes.NewReader(compressed)))
+
+	// the compressed bytes are reused
+	reader, err = compressedBody.reader()
+	require.Nil(t, err)
+	require.Equal(t, body, decompress(t, reader))
+}
+
+func TestGzipBody_ReaderClosedBeforeTheEnd(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat(testBulkBody, 1000)
+	compressedBody := newGzipBody([]byte(body))
+
+	reader, _ := compressedBody.reader()
+	_, err := reader.Read(make([]byte, 10))
+	require.Nil(t, err)
+	require.Nil(t, reader.Close())
+
+	// the body is compressed again
+	reader, _ = compressedBody.reader()
+	require.Equal(t, body, decompress(t, reader))
+}
+
+func TestElasticClient_DoBulkRequestCompressed(t *testing.T) {
+	t.Parallel()
+
+	receivedBodies := make([]string, 0)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/transactions/_bulk", r.URL.Path)
+		require.Equal(t, gzipContentEncoding, r.Header.Get(headerContentEncoding))
+		receivedBodies = append(receivedBodies, decompress(t, r.Body))
+
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{"took":1,"errors":false,"items":[{"index":{"_index":"transactions","_id":"t1","status":201}}]}`))
+	}))
+	defer ts.Close()
+
+	esClient, _ := NewElasticClient(elasticsearch.Config{
+		Addresses: []string{ts.URL},
+		Logger:    &logging.CustomLogger{},
+	})
+	esClient.EnableBulkCompression()
+
+	body := testBulkAction1 + "\n" + testBulkDoc1 + "\n"
+	err := esClient.DoBulkRequest(context.Background(), bytes.NewBufferString(body), "transactions")
+	require.Nil(t, err)
+	require.Equal(t, []string{body}, receivedBodies)
+}
+
+func TestElasticClient_DoBulkRequestAlreadyCompressed(t *testing.T) {
+	t.Parallel()
+
+	responses := []string{
+		`{"took":1,"errors":true,"items":[{"index":{"_index":"transactions","_id":"t1","status":201}},{"update":{"_index":"tokens","_id":"TKN","status":429}}]}`,
+		`{"took":1,"errors":false,"items":[{"update":{"_index":"tokens","_id":"TKN","status":200}}]}`,
+	}
+	receivedBodies := make([]string, 0)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, gzipContentEncoding, r.Header.Get(headerContentEncoding))
+		receivedBodies = append(receivedBodies, decompress(t, r.Body))
+
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(responses[len(receivedBodies)-1]))
+	}))
+	defer ts.Close()
+
+	esClient, _ := NewElasticClient(elasticsearch.Config{
+		Addresses: []string{ts.URL},
+		Logger:    &logging.CustomLogger{},
+	})
+	esClient.EnableBulkCompression()
+
+	buffSlice := data.NewCompressedBufferSlice(data.DefaultMaxBulkSize)
+	require.Nil(t, buffSlice.PutData([]byte(testBulkAction1+"\n"), []byte(testBulkDoc1)))
+	require.Nil(t, buffSlice.PutData([]byte(testBulkAction2+"\n"), []byte(testBulkDoc2)))
+
+	err := esClient.DoBulkRequest(context.Background(), buffSlice.Buffers()[0], "")
+	require.Nil(t, err)
+
+	// the compressed body is sent as it is, and the failed action is compressed when it is sent again
+	expectedBodies := []string{
+		testBulkAction1 + "\n" + testBulkDoc1 + "\n" + testBulkAction2 + "\n" + testBulkDoc2 + "\n",
+		testBulkAction2 + "\n" + testBulkDoc2 + "\n",
+	}
+	require.Equal(t, expectedBodies, receivedBodies)
+}
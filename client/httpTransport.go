@@ -0,0 +1,82 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+const headerAuthorization = "Authorization"
+
+// NewHTTPTransport returns a clone of http.DefaultTransport that uses the client certificate and trusts the certificate
+// authorities of the provided TLS config, besides the ones of the system
+func NewHTTPTransport(tlsCfg config.TLSConfig) (*http.Transport, error) {
+	tlsClientConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: tlsCfg.InsecureSkipVerify,
+	}
+
+	if tlsCfg.CACertFile != "" {
+		rootCAs, err := loadCertPool(tlsCfg.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsClientConfig.RootCAs = rootCAs
+	}
+
+	if tlsCfg.CertFile != "" || tlsCfg.KeyFile != "" {
+		if tlsCfg.CertFile == "" || tlsCfg.KeyFile == "" {
+			return nil, dataindexer.ErrIncompleteClientCertificate
+		}
+
+		certificate, err := tls.LoadX509KeyPair(tlsCfg.CertFile, tlsCfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w while loading the client certificate", err)
+		}
+		tlsClientConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if tlsCfg.InsecureSkipVerify {
+		log.Warn("the certificate of the cluster is not verified, this should only be used for testing")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsClientConfig
+
+	return transport, nil
+}
+
+func loadCertPool(caCertFile string) (*x509.CertPool, error) {
+	caCert, err := os.ReadFile(caCertFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w while reading the CA bundle", err)
+	}
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("%w: %s", dataindexer.ErrInvalidCACertificate, caCertFile)
+	}
+
+	return rootCAs, nil
+}
+
+// NewBearerTokenHeader returns the header that authenticates the requests with the provided bearer token. It returns
+// nil when the token is empty
+func NewBearerTokenHeader(token string) http.Header {
+	if token == "" {
+		return nil
+	}
+
+	header := make(http.Header)
+	header.Set(headerAuthorization, "Bearer "+token)
+
+	return header
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEMFile(t *testing.T, blockType string, bytes []byte) string {
+	file, err := os.CreateTemp(t.TempDir(), "*.pem")
+	require.Nil(t, err)
+	defer func() {
+		_ = file.Close()
+	}()
+
+	err = pem.Encode(file, &pem.Block{Type: blockType, Bytes: bytes})
+	require.Nil(t, err)
+
+	return file.Name()
+}
+
+// createClientCertificate returns the files of a self-signed client certificate and of its key
+func createClientCertificate(t *testing.T) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "indexer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	certificate, err := x509.ParseCertificate(certBytes)
+	require.Nil(t, err)
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+
+	return certificate, writePEMFile(t, "CERTIFICATE", certBytes), writePEMFile(t, "EC PRIVATE KEY", keyBytes)
+}
+
+func TestNewHTTPTransport(t *testing.T) {
+	t.Parallel()
+
+	httpTransport, err := NewHTTPTransport(config.TLSConfig{})
+	require.Nil(t, err)
+	require.Nil(t, httpTransport.TLSClientConfig.RootCAs)
+	require.Empty(t, httpTransport.TLSClientConfig.Certificates)
+
+	_, err = NewHTTPTransport(config.TLSConfig{CACertFile: filepath.Join(t.TempDir(), "missing.pem")})
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	invalidCAFile := filepath.Join(t.TempDir(), "invalid.pem")
+	require.Nil(t, os.WriteFile(invalidCAFile, []byte("not a certificate"), 0644))
+	_, err = NewHTTPTransport(config.TLSConfig{CACertFile: invalidCAFile})
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidCACertificate))
+
+	_, certFile, keyFile := createClientCertificate(t)
+	_, err = NewHTTPTransport(config.TLSConfig{CertFile: certFile})
+	require.Equal(t, dataindexer.ErrIncompleteClientCertificate, err)
+
+	_, err = NewHTTPTransport(config.TLSConfig{CertFile: certFile, KeyFile: certFile})
+	require.NotNil(t, err)
+
+	httpTransport, err = NewHTTPTransport(config.TLSConfig{CertFile: certFile, KeyFile: keyFile, InsecureSkipVerify: true})
+	require.Nil(t, err)
+	require.Len(t, httpTransport.TLSClientConfig.Certificates, 1)
+	require.True(t, httpTransport.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestElasticClient_MutualTLSAndBearerToken(t *testing.T) {
+	t.Parallel()
+
+	clientCertificate, certFile, keyFile := createClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCertificate)
+
+	authorizations := make([]string, 0)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get(headerAuthorization))
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	}))
+	ts.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	serverCAFile := writePEMFile(t, "CERTIFICATE", ts.Certificate().Raw)
+
+	// the certificate of the server is not trusted
+	httpTransport, _ := NewHTTPTransport(config.TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	esClient, _ := NewElasticClient(elasticsearch.Config{
+		Addresses:    []string{ts.URL},
+		Transport:    httpTransport,
+		DisableRetry: true,
+	})
+	require.False(t, esClient.indexExists("transactions"))
+
+	// the server requires a client certificate
+	httpTransport, _ = NewHTTPTransport(config.TLSConfig{CACertFile: serverCAFile})
+	esClient, _ = NewElasticClient(elasticsearch.Config{
+		Addresses:    []string{ts.URL},
+		Transport:    httpTransport,
+		DisableRetry: true,
+	})
+	require.False(t, esClient.indexExists("transactions"))
+	require.Empty(t, authorizations)
+
+	httpTransport, _ = NewHTTPTransport(config.TLSConfig{CACertFile: serverCAFile, CertFile: certFile, KeyFile: keyFile})
+	esClient, _ = NewElasticClient(elasticsearch.Config{
+		Addresses:    []string{ts.URL},
+		Transport:    httpTransport,
+		Username:     "user",
+		Password:     "pass",
+		Header:       NewBearerTokenHeader("token"),
+		DisableRetry: true,
+	})
+	require.True(t, esClient.indexExists("transactions"))
+	require.Equal(t, []string{"Bearer token"}, authorizations)
+}
+
+func TestNewBearerTokenHeader(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewBearerTokenHeader(""))
+	require.Equal(t, "Bearer token", NewBearerTokenHeader("token").Get(headerAuthorization))
+}
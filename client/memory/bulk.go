@@ -0,0 +1,297 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/client/memory/painless"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+)
+
+const (
+	opIndex  = "index"
+	opCreate = "create"
+	opUpdate = "update"
+	opDelete = "delete"
+	opNoop   = "noop"
+	opNone   = "none"
+
+	maxBulkErrors = 5
+)
+
+type bulkItemError struct {
+	index     string
+	id        string
+	status    int
+	errorType string
+	reason    string
+}
+
+// DoBulkRequest will apply the index, create, update and delete actions of the provided bulk body. Like the
+// Elasticsearch bulk API, the actions that fail do not stop the other actions and are returned as a single error
+func (mc *memoryClient) DoBulkRequest(_ context.Context, buff *bytes.Buffer, index string) error {
+	body, err := data.DecompressBulkBody(buff.Bytes())
+	if err != nil {
+		return err
+	}
+	lines := bytes.Split(body, []byte("\n"))
+
+	mc.mut.Lock()
+	defer mc.mut.Unlock()
+
+	itemErrors := make([]*bulkItemError, 0)
+	for idx := 0; idx < len(lines); idx++ {
+		if len(bytes.TrimSpace(lines[idx])) == 0 {
+			continue
+		}
+
+		action, err := decodeObject(lines[idx])
+		if err != nil {
+			return fmt.Errorf("%w while decoding the bulk action at line %d", err, idx)
+		}
+
+		op, meta, err := getBulkAction(action)
+		if err != nil {
+			return fmt.Errorf("%w at line %d", err, idx)
+		}
+
+		var body objectsMap
+		if op != opDelete {
+			idx++
+			if idx >= len(lines) || len(bytes.TrimSpace(lines[idx])) == 0 {
+				return fmt.Errorf("missing the body of the %s action at line %d", op, idx-1)
+			}
+			body, err = decodeObject(lines[idx])
+			if err != nil {
+				return fmt.Errorf("%w while decoding the bulk body at line %d", err, idx)
+			}
+		}
+
+		itemErr := mc.applyBulkAction(op, meta, body, index)
+		if itemErr != nil {
+			itemErrors = append(itemErrors, itemErr)
+		}
+	}
+
+	return formatBulkErrors(itemErrors)
+}
+
+func getBulkAction(action objectsMap) (string, objectsMap, error) {
+	if len(action) != 1 {
+		return "", nil, fmt.Errorf("invalid bulk action with %d keys", len(action))
+	}
+
+	for op, value := range action {
+		meta, ok := value.(objectsMap)
+		if !ok {
+			return "", nil, fmt.Errorf("invalid metadata of the %s bulk action", op)
+		}
+
+		switch op {
+		case opIndex, opCreate, opUpdate, opDelete:
+			return op, meta, nil
+		default:
+			return "", nil, fmt.Errorf("unknown bulk action %s", op)
+		}
+	}
+
+	return "", nil, nil
+}
+
+func (mc *memoryClient) applyBulkAction(op string, meta objectsMap, body objectsMap, defaultIndex string) *bulkItemError {
+	index, _ := meta["_index"].(string)
+	if index == "" {
+		index = defaultIndex
+	}
+	id, _ := meta["_id"].(string)
+
+	if index == "" {
+		return &bulkItemError{id: id, status: http.StatusBadRequest, errorType: "action_request_validation_exception", reason: "index is missing"}
+	}
+	indexName := mc.resolveIndex(index)
+	mc.getOrCreateIndex(indexName)
+
+	if id == "" {
+		if op != opIndex && op != opCreate {
+			return &bulkItemError{index: index, status: http.StatusBadRequest, errorType: "action_request_validation_exception", reason: "id is missing"}
+		}
+		mc.idCounter++
+		id = strconv.FormatUint(mc.idCounter, 10)
+	}
+
+	switch op {
+	case opIndex:
+		mc.putDocument(indexName, id, body)
+	case opCreate:
+		_, exists := mc.indices[indexName][id]
+		if exists {
+			return &bulkItemError{index: index, id: id, status: http.StatusConflict, errorType: "version_conflict_engine_exception", reason: "document already exists"}
+		}
+		mc.putDocument(indexName, id, body)
+	case opDelete:
+		mc.deleteDocument(indexName, id)
+	case opUpdate:
+		return mc.applyUpdate(indexName, id, body)
+	}
+
+	return nil
+}
+
+// applyUpdate follows the semantics of the Elasticsearch update API: the script or the partial document is applied
+// on the existing document, while a missing document is created from the upsert, by the script for a scripted upsert
+func (mc *memoryClient) applyUpdate(index string, id string, body objectsMap) *bulkItemError {
+	existing, exists := mc.indices[index][id]
+	scriptRequest, hasScript := body["script"]
+	partialDoc, hasPartialDoc := body["doc"].(objectsMap)
+	upsert, hasUpsert := body["upsert"].(objectsMap)
+
+	if exists {
+		switch {
+		case hasScript:
+			op, source, err := mc.runScript(scriptRequest, existing, opIndex)
+			if err != nil {
+				return scriptError(index, id, err)
+			}
+			mc.applyScriptResult(index, id, op, source)
+		case hasPartialDoc:
+			mc.putDocument(index, id, mergeObjects(existing, partialDoc))
+		default:
+			return &bulkItemError{index: index, id: id, status: http.StatusBadRequest, errorType: "action_request_validation_exception", reason: "script or doc is missing"}
+		}
+		return nil
+	}
+
+	scriptedUpsert, _ := body["scripted_upsert"].(bool)
+	docAsUpsert, _ := body["doc_as_upsert"].(bool)
+	switch {
+	case hasScript && scriptedUpsert:
+		if !hasUpsert {
+			upsert = make(objectsMap)
+		}
+		op, source, err := mc.runScript(scriptRequest, upsert, opCreate)
+		if err != nil {
+			return scriptError(index, id, err)
+		}
+		mc.applyScriptResult(index, id, op, source)
+	case hasUpsert:
+		mc.putDocument(index, id, upsert)
+	case hasPartialDoc && docAsUpsert:
+		mc.putDocument(index, id, partialDoc)
+	default:
+		return &bulkItemError{index: index, id: id, status: http.StatusNotFound, errorType: "document_missing_exception", reason: "document missing"}
+	}
+
+	return nil
+}
+
+func scriptError(index string, id string, err error) *bulkItemError {
+	return &bulkItemError{index: index, id: id, status: http.StatusBadRequest, errorType: "script_exception", reason: err.Error()}
+}
+
+// runScript executes the script on a copy of the source and returns the resulting operation and source
+func (mc *memoryClient) runScript(scriptRequest interface{}, source objectsMap, op string) (string, objectsMap, error) {
+	scriptSource, params, err := parseScriptRequest(scriptRequest)
+	if err != nil {
+		return "", nil, err
+	}
+
+	script, found := mc.scripts[scriptSource]
+	if !found {
+		script, err = painless.Compile(scriptSource)
+		if err != nil {
+			return "", nil, err
+		}
+		mc.scripts[scriptSource] = script
+	}
+
+	ctx := map[string]interface{}{
+		"_source": painless.FromJSON(source),
+		"op":      op,
+	}
+	_, err = script.Execute(map[string]interface{}{
+		"ctx":    ctx,
+		"params": painless.FromJSON(params),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	resultOp, ok := ctx["op"].(string)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid ctx.op %v", ctx["op"])
+	}
+	resultSource, ok := painless.ToJSON(ctx["_source"]).(objectsMap)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid ctx._source")
+	}
+
+	return resultOp, resultSource, nil
+}
+
+func parseScriptRequest(scriptRequest interface{}) (string, objectsMap, error) {
+	switch script := scriptRequest.(type) {
+	case string:
+		return script, make(objectsMap), nil
+	case objectsMap:
+		source, ok := script["source"].(string)
+		if !ok {
+			return "", nil, fmt.Errorf("the script has no source")
+		}
+		params, _ := script["params"].(objectsMap)
+		if params == nil {
+			params = make(objectsMap)
+		}
+		return source, params, nil
+	default:
+		return "", nil, fmt.Errorf("invalid script")
+	}
+}
+
+func (mc *memoryClient) applyScriptResult(index string, id string, op string, source objectsMap) {
+	switch op {
+	case opNoop, opNone:
+	case opDelete:
+		mc.deleteDocument(index, id)
+	default:
+		mc.putDocument(index, id, source)
+	}
+}
+
+// mergeObjects merges the partial document in the existing one, recursively for the nested objects
+func mergeObjects(existing objectsMap, partial objectsMap) objectsMap {
+	merged := copyObject(existing)
+	for key, value := range partial {
+		existingObject, isExistingObject := merged[key].(objectsMap)
+		partialObject, isPartialObject := value.(objectsMap)
+		if isExistingObject && isPartialObject {
+			merged[key] = mergeObjects(existingObject, partialObject)
+			continue
+		}
+		merged[key] = copyValue(value)
+	}
+
+	return merged
+}
+
+func formatBulkErrors(itemErrors []*bulkItemError) error {
+	if len(itemErrors) == 0 {
+		return nil
+	}
+
+	errorsString := ""
+	for idx, itemErr := range itemErrors {
+		if idx == maxBulkErrors {
+			break
+		}
+
+		reason, _ := json.Marshal(itemErr.reason)
+		errorsString += fmt.Sprintf(`{ "index": "%s", "id": "%s", "statusCode": %d, "errorType": "%s", "reason": %s }\n`,
+			itemErr.index, itemErr.id, itemErr.status, itemErr.errorType, reason)
+	}
+
+	return fmt.Errorf("%s", errorsString)
+}
@@ -0,0 +1,509 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/client/memory/painless"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const (
+	scrollPageSize = 9000
+	scrollID       = "memory-scroll"
+)
+
+var log = logger.GetOrCreate("indexer/client/memory")
+
+// ErrUnsupportedQuery signals that the query uses a clause the in-memory client cannot evaluate
+var ErrUnsupportedQuery = errors.New("unsupported query")
+
+type (
+	objectsMap = map[string]interface{}
+	documents  = map[string]objectsMap
+)
+
+type documentKey struct {
+	index string
+	id    string
+}
+
+type memoryClient struct {
+	mut            sync.RWMutex
+	indices        map[string]documents
+	aliases        map[string]string
+	templates      map[string]json.RawMessage
+	policies       map[string]json.RawMessage
+	scripts        map[string]*painless.Script
+	idCounter      uint64
+	maxDocuments   uint64
+	numDocuments   uint64
+	insertionOrder []documentKey
+}
+
+// NewMemoryClient will create a new instance of memoryClient. It keeps all the documents in memory and implements
+// the database client used by the elastic processor, so the whole indexing pipeline can run without a cluster.
+// The painless scripts of the update requests are executed by an interpreter of the subset of the language used by
+// the indexer, while the queries support the match_all, ids, term, terms, match, prefix, exists, range and bool clauses
+func NewMemoryClient() *memoryClient {
+	return &memoryClient{
+		indices:   make(map[string]documents),
+		aliases:   make(map[string]string),
+		templates: make(map[string]json.RawMessage),
+		policies:  make(map[string]json.RawMessage),
+		scripts:   make(map[string]*painless.Script),
+	}
+}
+
+// NewMemoryClientWithMaxDocuments will create a new instance of memoryClient that keeps at most maxDocuments
+// documents. When the limit is exceeded the documents written first are evicted, so a read of an evicted document
+// finds nothing, as it would on an empty database. A zero maxDocuments means no limit
+func NewMemoryClientWithMaxDocuments(maxDocuments uint64) *memoryClient {
+	mc := NewMemoryClient()
+	mc.maxDocuments = maxDocuments
+
+	return mc
+}
+
+// CheckAndCreateTemplate saves the index template if it does not already exist
+func (mc *memoryClient) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
+	mc.mut.Lock()
+	defer mc.mut.Unlock()
+
+	_, exists := mc.templates[templateName]
+	if !exists {
+		mc.templates[templateName] = bufferCopy(template)
+	}
+
+	return nil
+}
+
+// CheckAndCreatePolicy saves the index policy if it does not already exist
+func (mc *memoryClient) CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error {
+	mc.mut.Lock()
+	defer mc.mut.Unlock()
+
+	_, exists := mc.policies[policyName]
+	if !exists {
+		mc.policies[policyName] = bufferCopy(policy)
+	}
+
+	return nil
+}
+
+func bufferCopy(buff *bytes.Buffer) json.RawMessage {
+	if buff == nil {
+		return nil
+	}
+
+	return append(json.RawMessage{}, buff.Bytes()...)
+}
+
+// CheckAndCreateIndex creates a new empty index if it does not already exist
+func (mc *memoryClient) CheckAndCreateIndex(indexName string) error {
+	mc.mut.Lock()
+	defer mc.mut.Unlock()
+
+	mc.getOrCreateIndex(indexName)
+
+	return nil
+}
+
+// CheckAndCreateAlias creates a new alias if it does not already exist
+func (mc *memoryClient) CheckAndCreateAlias(alias string, indexName string) error {
+	mc.mut.Lock()
+	defer mc.mut.Unlock()
+
+	_, exists := mc.aliases[alias]
+	if !exists {
+		mc.aliases[alias] = indexName
+	}
+
+	return nil
+}
+
+// PutMappings does nothing, the documents are not validated against the mappings
+func (mc *memoryClient) PutMappings(_ string, _ *bytes.Buffer) error {
+	return nil
+}
+
+// DoMultiGet will fill the provided response with the documents that have the provided ids, in the format of the
+// Elasticsearch multi get API
+func (mc *memoryClient) DoMultiGet(_ context.Context, ids []string, index string, withSource bool, res interface{}) error {
+	mc.mut.RLock()
+	docs := mc.indices[mc.resolveIndex(index)]
+	responseDocs := make([]objectsMap, 0, len(ids))
+	for _, id := range ids {
+		doc, found := docs[id]
+		responseDoc := objectsMap{
+			"_index": index,
+			"_id":    id,
+			"found":  found,
+		}
+		if found && withSource {
+			responseDoc["_source"] = doc
+		}
+		responseDocs = append(responseDocs, responseDoc)
+	}
+	responseBytes, err := json.Marshal(objectsMap{"docs": responseDocs})
+	mc.mut.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(responseBytes, res)
+}
+
+// DoQueryRemove will remove all the documents that match the provided query
+func (mc *memoryClient) DoQueryRemove(_ context.Context, index string, body *bytes.Buffer) error {
+	request, err := decodeObject(body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	mc.mut.Lock()
+	defer mc.mut.Unlock()
+
+	docs := mc.indices[mc.resolveIndex(index)]
+	ids, err := matchingIDs(docs, request["query"])
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		mc.deleteDocument(mc.resolveIndex(index), id)
+	}
+	log.Trace("memoryClient.DoQueryRemove", "index", index, "removed", len(ids))
+
+	return nil
+}
+
+// UpdateByQuery will run the script of the request on all the documents that match the query of the request
+func (mc *memoryClient) UpdateByQuery(_ context.Context, index string, buff *bytes.Buffer) error {
+	request, err := decodeObject(buff.Bytes())
+	if err != nil {
+		return err
+	}
+
+	mc.mut.Lock()
+	defer mc.mut.Unlock()
+
+	indexName := mc.resolveIndex(index)
+	docs := mc.indices[indexName]
+	ids, err := matchingIDs(docs, request["query"])
+	if err != nil {
+		return err
+	}
+
+	scriptRequest, hasScript := request["script"]
+	if !hasScript {
+		return nil
+	}
+
+	for _, id := range ids {
+		op, source, errScript := mc.runScript(scriptRequest, docs[id], opIndex)
+		if errScript != nil {
+			return fmt.Errorf("%w for document %s", errScript, id)
+		}
+		mc.applyScriptResult(indexName, id, op, source)
+	}
+
+	return nil
+}
+
+// DoCountRequest will return the number of documents that match the provided query
+func (mc *memoryClient) DoCountRequest(_ context.Context, index string, body []byte) (uint64, error) {
+	request, err := decodeObject(body)
+	if err != nil {
+		return 0, err
+	}
+
+	mc.mut.RLock()
+	defer mc.mut.RUnlock()
+
+	ids, err := matchingIDs(mc.indices[mc.resolveIndex(index)], request["query"])
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(ids)), nil
+}
+
+// DoScrollRequest will call the handler with pages of the documents that match the provided query, in the format of
+// the Elasticsearch search API. The documents are sorted by id
+func (mc *memoryClient) DoScrollRequest(
+	ctx context.Context,
+	index string,
+	body []byte,
+	withSource bool,
+	handlerFunc func(responseBytes []byte) error,
+) error {
+	request, err := decodeObject(body)
+	if err != nil {
+		return err
+	}
+
+	pages, err := mc.prepareScrollPages(index, request["query"], withSource)
+	if err != nil {
+		return err
+	}
+
+	// the lock is not held while the handler is called, because the handler can index documents
+	for _, page := range pages {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = handlerFunc(page)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mc *memoryClient) prepareScrollPages(index string, query interface{}, withSource bool) ([][]byte, error) {
+	mc.mut.RLock()
+	defer mc.mut.RUnlock()
+
+	indexName := mc.resolveIndex(index)
+	docs := mc.indices[indexName]
+	ids, err := matchingIDs(docs, query)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([][]byte, 0)
+	for start := 0; start == 0 || start < len(ids); start += scrollPageSize {
+		end := start + scrollPageSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		hits := make([]objectsMap, 0, end-start)
+		for _, id := range ids[start:end] {
+			hit := objectsMap{
+				"_index": indexName,
+				"_id":    id,
+			}
+			if withSource {
+				hit["_source"] = docs[id]
+			}
+			hits = append(hits, hit)
+		}
+
+		page, errMarshal := json.Marshal(objectsMap{
+			"_scroll_id": scrollID,
+			"hits": objectsMap{
+				"total": objectsMap{"value": len(ids), "relation": "eq"},
+				"hits":  hits,
+			},
+		})
+		if errMarshal != nil {
+			return nil, errMarshal
+		}
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+// Indices returns the names of the indices that hold documents, sorted
+func (mc *memoryClient) Indices() []string {
+	mc.mut.RLock()
+	defer mc.mut.RUnlock()
+
+	names := make([]string, 0, len(mc.indices))
+	for name, docs := range mc.indices {
+		if len(docs) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Documents returns a copy of the documents of the provided index or alias, keyed by id
+func (mc *memoryClient) Documents(index string) map[string]map[string]interface{} {
+	mc.mut.RLock()
+	defer mc.mut.RUnlock()
+
+	docs := mc.indices[mc.resolveIndex(index)]
+	result := make(map[string]map[string]interface{}, len(docs))
+	for id, doc := range docs {
+		result[id] = copyObject(doc)
+	}
+
+	return result
+}
+
+// Document returns a copy of the document with the provided id
+func (mc *memoryClient) Document(index string, id string) (map[string]interface{}, bool) {
+	mc.mut.RLock()
+	defer mc.mut.RUnlock()
+
+	doc, found := mc.indices[mc.resolveIndex(index)][id]
+	if !found {
+		return nil, false
+	}
+
+	return copyObject(doc), true
+}
+
+// DumpToDirectory will write the documents of each index in a JSON file named after the index
+func (mc *memoryClient) DumpToDirectory(path string) error {
+	err := os.MkdirAll(path, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range mc.Indices() {
+		docs := mc.Documents(index)
+		docsBytes, errMarshal := json.MarshalIndent(docs, "", "  ")
+		if errMarshal != nil {
+			return errMarshal
+		}
+
+		err = os.WriteFile(filepath.Join(path, index+".json"), docsBytes, 0644)
+		if err != nil {
+			return err
+		}
+		log.Info("dumped index", "index", index, "documents", len(docs), "path", path)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (mc *memoryClient) IsInterfaceNil() bool {
+	return mc == nil
+}
+
+func (mc *memoryClient) resolveIndex(index string) string {
+	indexName, isAlias := mc.aliases[index]
+	if isAlias {
+		return indexName
+	}
+
+	return index
+}
+
+func (mc *memoryClient) getOrCreateIndex(index string) documents {
+	docs, exists := mc.indices[index]
+	if !exists {
+		docs = make(documents)
+		mc.indices[index] = docs
+	}
+
+	return docs
+}
+
+// putDocument saves the document and evicts the documents written first when the limit of documents is exceeded
+func (mc *memoryClient) putDocument(index string, id string, doc objectsMap) {
+	docs := mc.getOrCreateIndex(index)
+	_, exists := docs[id]
+	docs[id] = doc
+	if exists {
+		return
+	}
+
+	mc.numDocuments++
+	if mc.maxDocuments == 0 {
+		return
+	}
+
+	mc.insertionOrder = append(mc.insertionOrder, documentKey{index: index, id: id})
+	mc.evictDocuments()
+}
+
+func (mc *memoryClient) deleteDocument(index string, id string) {
+	docs := mc.indices[index]
+	_, exists := docs[id]
+	if !exists {
+		return
+	}
+
+	delete(docs, id)
+	mc.numDocuments--
+}
+
+func (mc *memoryClient) evictDocuments() {
+	evicted := 0
+	for mc.numDocuments > mc.maxDocuments && evicted < len(mc.insertionOrder) {
+		key := mc.insertionOrder[evicted]
+		mc.deleteDocument(key.index, key.id)
+		evicted++
+	}
+	mc.insertionOrder = mc.insertionOrder[evicted:]
+
+	// the keys of the deleted documents stay in the insertion order until they are reached, so they are dropped
+	// when they outnumber the kept documents
+	if uint64(len(mc.insertionOrder)) > 2*mc.maxDocuments {
+		mc.compactInsertionOrder()
+	}
+}
+
+func (mc *memoryClient) compactInsertionOrder() {
+	seen := make(map[documentKey]struct{}, mc.numDocuments)
+	insertionOrder := make([]documentKey, 0, mc.numDocuments)
+	for _, key := range mc.insertionOrder {
+		_, exists := mc.indices[key.index][key.id]
+		_, isSeen := seen[key]
+		if !exists || isSeen {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		insertionOrder = append(insertionOrder, key)
+	}
+	mc.insertionOrder = insertionOrder
+}
+
+func decodeObject(body []byte) (objectsMap, error) {
+	result := make(objectsMap)
+	if len(bytes.TrimSpace(body)) == 0 {
+		return result, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	err := decoder.Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func copyObject(object objectsMap) objectsMap {
+	copied, _ := copyValue(object).(objectsMap)
+	return copied
+}
+
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case objectsMap:
+		copied := make(objectsMap, len(v))
+		for key, item := range v {
+			copied[key] = copyValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			copied = append(copied, copyValue(item))
+		}
+		return copied
+	default:
+		return v
+	}
+}
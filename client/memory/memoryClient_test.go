@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func doBulk(t *testing.T, mc *memoryClient, lines ...string) error {
+	buff := bytes.NewBuffer(nil)
+	for _, line := range lines {
+		buff.WriteString(line + "\n")
+	}
+
+	return mc.DoBulkRequest(context.Background(), buff, "")
+}
+
+func TestMemoryClient_DoBulkRequestIndexAndDelete(t *testing.T) {
+	t.Parallel()
+
+	mc := NewMemoryClient()
+	err := doBulk(t, mc,
+		`{ "index" : { "_index":"accounts", "_id" : "a" } }`,
+		`{"balance":"10","nonce":1}`,
+		`{ "index" : { "_index":"accounts", "_id" : "b" } }`,
+		`{"balance":"20","nonce":2}`,
+		`{ "delete" : { "_index":"accounts", "_id" : "a" } }`,
+	)
+	require.Nil(t, err)
+
+	_, found := mc.Document("accounts", "a")
+	require.False(t, found)
+	doc, found := mc.Document("accounts", "b")
+	require.True(t, found)
+	require.Equal(t, "20", doc["balance"])
+	require.Equal(t, []string{"accounts"}, mc.Indices())
+}
+
+func TestMemoryClient_MaxDocumentsShouldEvictTheDocumentsWrittenFirst(t *testing.T) {
+	t.Parallel()
+
+	mc := NewMemoryClientWithMaxDocuments(2)
+	err := doBulk(t, mc,
+		`{ "index" : { "_index":"accounts", "_id" : "a" } }`,
+		`{"balance":"10"}`,
+		`{ "index" : { "_index":"tokens", "_id" : "b" } }`,
+		`{"balance":"20"}`,
+		`{ "index" : { "_index":"accounts", "_id" : "a" } }`,
+		`{"balance":"11"}`,
+	)
+	require.Nil(t, err)
+	doc, found := mc.Document("accounts", "a")
+	require.True(t, found)
+	require.Equal(t, "11", doc["balance"])
+
+	err = doBulk(t, mc,
+		`{ "index" : { "_index":"accounts", "_id" : "c" } }`,
+		`{"balance":"30"}`,
+	)
+	require.Nil(t, err)
+	_, found = mc.Document("accounts", "a")
+	require.False(t, found)
+	_, found = mc.Document("tokens", "b")
+	require.True(t, found)
+	_, found = mc.Document("accounts", "c")
+	require.True(t, found)
+
+	// a deleted document frees its place
+	err = doBulk(t, mc,
+		`{ "delete" : { "_index":"tokens", "_id" : "b" } }`,
+		`{ "update" : { "_index":"tokens", "_id" : "d" } }`,
+		`{"doc":{"balance":"40"},"doc_as_upsert":true}`,
+	)
+	require.Nil(t, err)
+	_, found = mc.Document("accounts", "c")
+	require.True(t, found)
+	_, found = mc.Document("tokens", "d")
+	require.True(t, found)
+	require.Equal(t, uint64(2), mc.numDocuments)
+}
+
+func TestMemoryClient_DoBulkRequestScriptedUpsert(t *testing.T) {
+	t.Parallel()
+
+	mc := NewMemoryClient()
+	update := `{"script": {"source": "ctx._source.count += params.count; ctx._source.tag = params.tag","lang": "painless","params": {"count": 2, "tag": "art"}},"upsert": {"count": 2, "tag":"art"}}`
+
+	err := doBulk(t, mc, `{ "update" : {"_index":"tags", "_id" : "YXJ0" } }`, update)
+	require.Nil(t, err)
+	doc, _ := mc.Document("tags", "YXJ0")
+	require.Equal(t, json.Number("2"), doc["count"])
+
+	err = doBulk(t, mc, `{ "update" : {"_index":"tags", "_id" : "YXJ0" } }`, update)
+	require.Nil(t, err)
+	doc, _ = mc.Document("tags", "YXJ0")
+	require.Equal(t, int64(4), doc["count"])
+
+	scriptedUpsert := `{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.doc} else {ctx.op = 'noop'}","lang": "painless","params": {"doc": {"nonce": 5}}},"upsert": {}}`
+	err = doBulk(t, mc, `{ "update" : {"_index":"accounts", "_id" : "a" } }`, scriptedUpsert)
+	require.Nil(t, err)
+	err = doBulk(t, mc,
+		`{ "update" : {"_index":"accounts", "_id" : "a" } }`,
+		`{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.doc} else {ctx.op = 'noop'}","lang": "painless","params": {"doc": {"nonce": 6}}},"upsert": {}}`,
+	)
+	require.Nil(t, err)
+	doc, _ = mc.Document("accounts", "a")
+	require.Equal(t, int64(5), doc["nonce"])
+}
+
+func TestMemoryClient_DoBulkRequestItemErrors(t *testing.T) {
+	t.Parallel()
+
+	mc := NewMemoryClient()
+	err := doBulk(t, mc,
+		`{ "update" : {"_index":"tags", "_id" : "missing" } }`,
+		`{"doc": {"count": 1}}`,
+		`{ "update" : {"_index":"tags", "_id" : "invalid" } }`,
+		`{"script": {"source": "ctx._source.count +=","lang": "painless"},"upsert": {}}`,
+		`{ "index" : { "_index":"tags", "_id" : "ok" } }`,
+		`{"count":1}`,
+	)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "document_missing_exception")
+
+	// the other actions of the bulk request are still applied
+	_, found := mc.Document("tags", "ok")
+	require.True(t, found)
+
+	err = doBulk(t, mc, `{ "index" : { "_index":"tags", "_id" : "ok" } }`)
+	require.NotNil(t, err)
+}
+
+func TestMemoryClient_DoMultiGet(t *testing.T) {
+	t.Parallel()
+
+	mc := NewMemoryClient()
+	_ = mc.CheckAndCreateIndex("tokens-000001")
+	_ = mc.CheckAndCreateAlias("tokens", "tokens-000001")
+	err := doBulk(t, mc, `{ "index" : { "_index":"tokens", "_id" : "TKN-1" } }`, `{"type":"FungibleESDT","currentOwner":"a"}`)
+	require.Nil(t, err)
+
+	res := &data.ResponseTokens{}
+	err = mc.DoMultiGet(context.Background(), []string{"TKN-1", "TKN-2"}, "tokens", true, res)
+	require.Nil(t, err)
+	require.Len(t, res.Docs, 2)
+	require.True(t, res.Docs[0].Found)
+	require.Equal(t, "FungibleESDT", res.Docs[0].Source.Type)
+	require.Equal(t, []string{"tokens-000001"}, mc.Indices())
+	require.False(t, res.Docs[1].Found)
+}
+
+func TestMemoryClient_QueryRequests(t *testing.T) {
+	t.Parallel()
+
+	mc := NewMemoryClient()
+	err := doBulk(t, mc,
+		`{ "index" : { "_index":"accountsesdt", "_id" : "a-TKN-1" } }`,
+		`{"address":"a","token":"TKN-1","balance":"1","timestamp":10}`,
+		`{ "index" : { "_index":"accountsesdt", "_id" : "a-TKN-2" } }`,
+		`{"address":"a","token":"TKN-2","balance":"2","timestamp":20}`,
+		`{ "index" : { "_index":"accountsesdt", "_id" : "b-TKN-1" } }`,
+		`{"address":"b","token":"TKN-1","balance":"3","timestamp":30}`,
+	)
+	require.Nil(t, err)
+
+	count, err := mc.DoCountRequest(context.Background(), "accountsesdt", []byte(`{"query":{"bool":{"must":[{"match":{"address":{"query":"a","operator":"AND"}}}],"must_not":[{"match":{"token":"TKN-2"}}]}}}`))
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), count)
+
+	count, err = mc.DoCountRequest(context.Background(), "accountsesdt", []byte(`{"query":{"range":{"timestamp":{"gte":20}}}}`))
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), count)
+
+	_, err = mc.DoCountRequest(context.Background(), "accountsesdt", []byte(`{"query":{"wildcard":{"token":"TKN*"}}}`))
+	require.True(t, errors.Is(err, ErrUnsupportedQuery))
+
+	err = mc.UpdateByQuery(context.Background(), "accountsesdt", bytes.NewBufferString(`{"query":{"match":{"token":"TKN-1"}},"script":{"source":"ctx._source.frozen = params.frozen","lang":"painless","params":{"frozen":true}}}`))
+	require.Nil(t, err)
+	doc, _ := mc.Document("accountsesdt", "b-TKN-1")
+	require.Equal(t, true, doc["frozen"])
+
+	ids := make([]string, 0)
+	err = mc.DoScrollRequest(context.Background(), "accountsesdt", []byte(`{"query":{"prefix":{"token":{"value":"TKN"}}}}`), false, func(responseBytes []byte) error {
+		response := &data.ResponseScroll{}
+		errUnmarshal := json.Unmarshal(responseBytes, response)
+		require.Nil(t, errUnmarshal)
+		for _, hit := range response.Hits.Hits {
+			ids = append(ids, hit.ID)
+		}
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"a-TKN-1", "a-TKN-2", "b-TKN-1"}, ids)
+
+	err = mc.DoQueryRemove(context.Background(), "accountsesdt", bytes.NewBufferString(`{"query":{"ids":{"values":["a-TKN-1","b-TKN-1"]}}}`))
+	require.Nil(t, err)
+	require.Len(t, mc.Documents("accountsesdt"), 1)
+}
+
+func TestMemoryClient_DumpToDirectory(t *testing.T) {
+	t.Parallel()
+
+	mc := NewMemoryClient()
+	err := doBulk(t, mc, `{ "index" : { "_index":"tags", "_id" : "a" } }`, `{"count":1}`)
+	require.Nil(t, err)
+
+	dir := t.TempDir()
+	err = mc.DumpToDirectory(dir)
+	require.Nil(t, err)
+
+	dumped, err := os.ReadFile(filepath.Join(dir, "tags.json"))
+	require.Nil(t, err)
+	require.JSONEq(t, `{"a":{"count":1}}`, string(dumped))
+}
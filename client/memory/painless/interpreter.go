@@ -0,0 +1,648 @@
+package painless
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// maxSteps limits the number of statements and loop iterations of an execution, so a script that never ends does
+// not block the indexer
+const maxSteps = 10_000_000
+
+type flow int
+
+const (
+	flowNormal flow = iota
+	flowReturn
+	flowBreak
+	flowContinue
+)
+
+type scope struct {
+	vars   map[string]interface{}
+	parent *scope
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{
+		vars:   make(map[string]interface{}),
+		parent: parent,
+	}
+}
+
+func (s *scope) lookup(name string) (interface{}, bool) {
+	for current := s; current != nil; current = current.parent {
+		value, found := current.vars[name]
+		if found {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *scope) assign(name string, value interface{}) bool {
+	for current := s; current != nil; current = current.parent {
+		_, found := current.vars[name]
+		if found {
+			current.vars[name] = value
+			return true
+		}
+	}
+
+	return false
+}
+
+type interpreter struct {
+	steps int
+}
+
+func (in *interpreter) step() error {
+	in.steps++
+	if in.steps > maxSteps {
+		return fmt.Errorf("%w: the script exceeded %d steps", ErrScriptExecution, maxSteps)
+	}
+
+	return nil
+}
+
+func (in *interpreter) exec(stmt interface{}, sc *scope) (flow, interface{}, error) {
+	err := in.step()
+	if err != nil {
+		return flowNormal, nil, err
+	}
+
+	switch s := stmt.(type) {
+	case *blockStmt:
+		return in.execBlock(s, newScope(sc))
+	case *exprStmt:
+		_, err = in.eval(s.expr, sc)
+		return flowNormal, nil, err
+	case *declStmt:
+		return flowNormal, nil, in.execDeclaration(s, sc)
+	case *ifStmt:
+		cond, errCond := in.evalCondition(s.cond, sc)
+		if errCond != nil {
+			return flowNormal, nil, errCond
+		}
+		if cond {
+			return in.exec(s.then, sc)
+		}
+		if s.elseStmt != nil {
+			return in.exec(s.elseStmt, sc)
+		}
+		return flowNormal, nil, nil
+	case *forStmt:
+		return in.execFor(s, newScope(sc))
+	case *forEachStmt:
+		return in.execForEach(s, sc)
+	case *whileStmt:
+		return in.execLoop(s.cond, nil, s.body, sc)
+	case *returnStmt:
+		if s.value == nil {
+			return flowReturn, nil, nil
+		}
+		value, errValue := in.eval(s.value, sc)
+		return flowReturn, value, errValue
+	case *breakStmt:
+		return flowBreak, nil, nil
+	case *continueStmt:
+		return flowContinue, nil, nil
+	default:
+		return flowNormal, nil, fmt.Errorf("%w: unknown statement %T", ErrScriptExecution, stmt)
+	}
+}
+
+func (in *interpreter) execBlock(block *blockStmt, sc *scope) (flow, interface{}, error) {
+	for _, stmt := range block.stmts {
+		result, value, err := in.exec(stmt, sc)
+		if err != nil || result != flowNormal {
+			return result, value, err
+		}
+	}
+
+	return flowNormal, nil, nil
+}
+
+func (in *interpreter) execDeclaration(decl *declStmt, sc *scope) error {
+	value := defaultValue(decl.typeName)
+	if decl.value != nil {
+		var err error
+		value, err = in.eval(decl.value, sc)
+		if err != nil {
+			return err
+		}
+	}
+	sc.vars[decl.name] = value
+
+	return nil
+}
+
+func defaultValue(typeName string) interface{} {
+	switch typeName {
+	case "int", "long", "short", "byte":
+		return int64(0)
+	case "double", "float":
+		return float64(0)
+	case "boolean":
+		return false
+	default:
+		return nil
+	}
+}
+
+func (in *interpreter) execFor(s *forStmt, sc *scope) (flow, interface{}, error) {
+	if s.init != nil {
+		_, _, err := in.exec(s.init, sc)
+		if err != nil {
+			return flowNormal, nil, err
+		}
+	}
+
+	return in.execLoop(s.cond, s.update, s.body, sc)
+}
+
+func (in *interpreter) execLoop(cond interface{}, update interface{}, body interface{}, sc *scope) (flow, interface{}, error) {
+	for {
+		if cond != nil {
+			ok, err := in.evalCondition(cond, sc)
+			if err != nil || !ok {
+				return flowNormal, nil, err
+			}
+		}
+
+		result, value, err := in.exec(body, sc)
+		if err != nil {
+			return flowNormal, nil, err
+		}
+		switch result {
+		case flowReturn:
+			return result, value, nil
+		case flowBreak:
+			return flowNormal, nil, nil
+		}
+
+		if update != nil {
+			_, err = in.eval(update, sc)
+			if err != nil {
+				return flowNormal, nil, err
+			}
+		}
+	}
+}
+
+func (in *interpreter) execForEach(s *forEachStmt, sc *scope) (flow, interface{}, error) {
+	iterable, err := in.eval(s.iterable, sc)
+	if err != nil {
+		return flowNormal, nil, err
+	}
+
+	var items []interface{}
+	switch v := iterable.(type) {
+	case *List:
+		items = append(items, v.Items...)
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			items = append(items, key)
+		}
+	default:
+		return flowNormal, nil, fmt.Errorf("%w: cannot iterate over %s", ErrScriptExecution, typeName(iterable))
+	}
+
+	for _, item := range items {
+		loopScope := newScope(sc)
+		loopScope.vars[s.name] = item
+
+		result, value, errExec := in.exec(s.body, loopScope)
+		if errExec != nil {
+			return flowNormal, nil, errExec
+		}
+		switch result {
+		case flowReturn:
+			return result, value, nil
+		case flowBreak:
+			return flowNormal, nil, nil
+		}
+	}
+
+	return flowNormal, nil, nil
+}
+
+func (in *interpreter) evalCondition(expr interface{}, sc *scope) (bool, error) {
+	value, err := in.eval(expr, sc)
+	if err != nil {
+		return false, err
+	}
+
+	cond, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: cannot cast %s to boolean", ErrScriptExecution, typeName(value))
+	}
+
+	return cond, nil
+}
+
+func (in *interpreter) eval(expr interface{}, sc *scope) (interface{}, error) {
+	switch e := expr.(type) {
+	case *literalExpr:
+		return e.value, nil
+	case *identExpr:
+		value, found := sc.lookup(e.name)
+		if !found {
+			return nil, fmt.Errorf("%w: variable %s is not defined", ErrScriptExecution, e.name)
+		}
+		return value, nil
+	case *memberExpr:
+		object, err := in.eval(e.object, sc)
+		if err != nil {
+			return nil, err
+		}
+		return getMember(object, e.name)
+	case *indexExpr:
+		return in.evalIndex(e, sc)
+	case *callExpr:
+		return in.evalCall(e, sc)
+	case *newExpr:
+		return in.evalNew(e, sc)
+	case *listExpr:
+		list := &List{Items: make([]interface{}, 0, len(e.items))}
+		for _, item := range e.items {
+			value, err := in.eval(item, sc)
+			if err != nil {
+				return nil, err
+			}
+			list.Items = append(list.Items, value)
+		}
+		return list, nil
+	case *mapExpr:
+		return in.evalMap(e, sc)
+	case *unaryExpr:
+		return in.evalUnary(e, sc)
+	case *binaryExpr:
+		return in.evalBinary(e, sc)
+	case *ternaryExpr:
+		cond, err := in.evalCondition(e.cond, sc)
+		if err != nil {
+			return nil, err
+		}
+		if cond {
+			return in.eval(e.whenTrue, sc)
+		}
+		return in.eval(e.whenFalse, sc)
+	case *assignExpr:
+		return in.evalAssign(e, sc)
+	case *incDecExpr:
+		return in.evalIncDec(e, sc)
+	case *lambdaExpr:
+		return &lambda{expr: e, closure: sc}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown expression %T", ErrScriptExecution, expr)
+	}
+}
+
+func getMember(object interface{}, name string) (interface{}, error) {
+	switch v := object.(type) {
+	case map[string]interface{}:
+		return v[name], nil
+	case *List:
+		if name == "length" {
+			return int64(len(v.Items)), nil
+		}
+	case nil:
+		return nil, fmt.Errorf("%w: cannot access field %s of null", ErrScriptExecution, name)
+	}
+
+	return nil, fmt.Errorf("%w: %s has no field %s", ErrScriptExecution, typeName(object), name)
+}
+
+func (in *interpreter) evalIndex(e *indexExpr, sc *scope) (interface{}, error) {
+	object, err := in.eval(e.object, sc)
+	if err != nil {
+		return nil, err
+	}
+	index, err := in.eval(e.index, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := object.(type) {
+	case map[string]interface{}:
+		return v[toString(index)], nil
+	case *List:
+		position, errPosition := listPosition(v, index)
+		if errPosition != nil {
+			return nil, errPosition
+		}
+		return v.Items[position], nil
+	default:
+		return nil, fmt.Errorf("%w: cannot index %s", ErrScriptExecution, typeName(object))
+	}
+}
+
+func listPosition(list *List, index interface{}) (int, error) {
+	position, ok := index.(int64)
+	if !ok {
+		return 0, fmt.Errorf("%w: invalid list index %s", ErrScriptExecution, typeName(index))
+	}
+	if position < 0 || position >= int64(len(list.Items)) {
+		return 0, fmt.Errorf("%w: index %d out of bounds for length %d", ErrScriptExecution, position, len(list.Items))
+	}
+
+	return int(position), nil
+}
+
+func (in *interpreter) evalMap(e *mapExpr, sc *scope) (interface{}, error) {
+	result := make(map[string]interface{}, len(e.keys))
+	for idx := range e.keys {
+		key, err := in.eval(e.keys[idx], sc)
+		if err != nil {
+			return nil, err
+		}
+		value, err := in.eval(e.values[idx], sc)
+		if err != nil {
+			return nil, err
+		}
+		result[toString(key)] = value
+	}
+
+	return result, nil
+}
+
+func (in *interpreter) evalNew(e *newExpr, sc *scope) (interface{}, error) {
+	args, err := in.evalArgs(e.args, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.typeName {
+	case "HashMap", "LinkedHashMap", "TreeMap":
+		return make(map[string]interface{}), nil
+	case "ArrayList", "LinkedList":
+		return &List{Items: make([]interface{}, 0)}, nil
+	case "BigInteger":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%w: BigInteger expects one argument", ErrScriptExecution)
+		}
+		value, ok := toBigInt(args[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot create BigInteger from %s", ErrScriptExecution, toString(args[0]))
+		}
+		return new(big.Int).Set(value), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %s", ErrScriptExecution, e.typeName)
+	}
+}
+
+func (in *interpreter) evalArgs(exprs []interface{}, sc *scope) ([]interface{}, error) {
+	args := make([]interface{}, 0, len(exprs))
+	for _, expr := range exprs {
+		value, err := in.eval(expr, sc)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+
+	return args, nil
+}
+
+func (in *interpreter) evalUnary(e *unaryExpr, sc *scope) (interface{}, error) {
+	if e.op == "!" {
+		value, err := in.evalCondition(e.operand, sc)
+		return !value, err
+	}
+
+	value, err := in.eval(e.operand, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case int64:
+		if e.op == "-" {
+			return -v, nil
+		}
+		return v, nil
+	case float64:
+		if e.op == "-" {
+			return -v, nil
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("%w: cannot apply %s to %s", ErrScriptExecution, e.op, typeName(value))
+	}
+}
+
+func (in *interpreter) evalBinary(e *binaryExpr, sc *scope) (interface{}, error) {
+	switch e.op {
+	case "&&", "||":
+		left, err := in.evalCondition(e.left, sc)
+		if err != nil {
+			return nil, err
+		}
+		if (e.op == "&&" && !left) || (e.op == "||" && left) {
+			return left, nil
+		}
+		return in.evalCondition(e.right, sc)
+	}
+
+	left, err := in.eval(e.left, sc)
+	if err != nil {
+		return nil, err
+	}
+	right, err := in.eval(e.right, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyBinary(e.op, left, right)
+}
+
+func applyBinary(op string, left, right interface{}) (interface{}, error) {
+	switch op {
+	case "==":
+		return equalValues(left, right), nil
+	case "!=":
+		return !equalValues(left, right), nil
+	case "<", "<=", ">", ">=":
+		cmp, err := compareValues(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return compareResult(op, cmp), nil
+	case "+":
+		_, leftIsString := left.(string)
+		_, rightIsString := right.(string)
+		if leftIsString || rightIsString {
+			return toString(left) + toString(right), nil
+		}
+	}
+
+	return applyArithmetic(op, left, right)
+}
+
+func compareResult(op string, cmp int) bool {
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func applyArithmetic(op string, left, right interface{}) (interface{}, error) {
+	leftInt, leftIsInt := left.(int64)
+	rightInt, rightIsInt := right.(int64)
+	if leftIsInt && rightIsInt {
+		switch op {
+		case "+":
+			return leftInt + rightInt, nil
+		case "-":
+			return leftInt - rightInt, nil
+		case "*":
+			return leftInt * rightInt, nil
+		case "/", "%":
+			if rightInt == 0 {
+				return nil, fmt.Errorf("%w: division by zero", ErrScriptExecution)
+			}
+			if op == "/" {
+				return leftInt / rightInt, nil
+			}
+			return leftInt % rightInt, nil
+		}
+	}
+
+	leftFloat, leftIsNumber := toFloat(left)
+	rightFloat, rightIsNumber := toFloat(right)
+	if !leftIsNumber || !rightIsNumber {
+		return nil, fmt.Errorf("%w: cannot apply %s to %s and %s", ErrScriptExecution, op, typeName(left), typeName(right))
+	}
+
+	switch op {
+	case "+":
+		return leftFloat + rightFloat, nil
+	case "-":
+		return leftFloat - rightFloat, nil
+	case "*":
+		return leftFloat * rightFloat, nil
+	case "/":
+		return leftFloat / rightFloat, nil
+	default:
+		return nil, fmt.Errorf("%w: cannot apply %s to %s and %s", ErrScriptExecution, op, typeName(left), typeName(right))
+	}
+}
+
+func (in *interpreter) evalAssign(e *assignExpr, sc *scope) (interface{}, error) {
+	value, err := in.eval(e.value, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.op != "=" {
+		current, errCurrent := in.eval(e.target, sc)
+		if errCurrent != nil {
+			return nil, errCurrent
+		}
+		value, err = applyBinary(e.op[:1], current, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return value, in.store(e.target, value, sc)
+}
+
+func (in *interpreter) evalIncDec(e *incDecExpr, sc *scope) (interface{}, error) {
+	current, err := in.eval(e.target, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := applyArithmetic(e.op[:1], current, int64(1))
+	if err != nil {
+		return nil, err
+	}
+
+	err = in.store(e.target, updated, sc)
+	if err != nil {
+		return nil, err
+	}
+	if e.prefix {
+		return updated, nil
+	}
+
+	return current, nil
+}
+
+func (in *interpreter) store(target interface{}, value interface{}, sc *scope) error {
+	switch t := target.(type) {
+	case *identExpr:
+		if !sc.assign(t.name, value) {
+			return fmt.Errorf("%w: variable %s is not defined", ErrScriptExecution, t.name)
+		}
+		return nil
+	case *memberExpr:
+		object, err := in.eval(t.object, sc)
+		if err != nil {
+			return err
+		}
+		m, ok := object.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%w: cannot set field %s of %s", ErrScriptExecution, t.name, typeName(object))
+		}
+		m[t.name] = value
+		return nil
+	case *indexExpr:
+		object, err := in.eval(t.object, sc)
+		if err != nil {
+			return err
+		}
+		index, err := in.eval(t.index, sc)
+		if err != nil {
+			return err
+		}
+		switch v := object.(type) {
+		case map[string]interface{}:
+			v[toString(index)] = value
+			return nil
+		case *List:
+			position, errPosition := listPosition(v, index)
+			if errPosition != nil {
+				return errPosition
+			}
+			v.Items[position] = value
+			return nil
+		default:
+			return fmt.Errorf("%w: cannot index %s", ErrScriptExecution, typeName(object))
+		}
+	default:
+		return fmt.Errorf("%w: invalid assignment target", ErrScriptExecution)
+	}
+}
+
+func (in *interpreter) callLambda(value interface{}, args ...interface{}) (interface{}, error) {
+	fn, ok := value.(*lambda)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a lambda", ErrScriptExecution, typeName(value))
+	}
+	if len(fn.expr.params) != len(args) {
+		return nil, fmt.Errorf("%w: the lambda expects %d arguments, got %d", ErrScriptExecution, len(fn.expr.params), len(args))
+	}
+
+	sc := newScope(fn.closure)
+	for idx, name := range fn.expr.params {
+		sc.vars[name] = args[idx]
+	}
+
+	block, isBlock := fn.expr.body.(*blockStmt)
+	if !isBlock {
+		return in.eval(fn.expr.body, sc)
+	}
+
+	_, result, err := in.execBlock(block, sc)
+	return result, err
+}
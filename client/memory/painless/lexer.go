@@ -0,0 +1,120 @@
+package painless
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// the punctuation is matched longest first
+var punctuation = []string{
+	"->", "==", "!=", "<=", ">=", "&&", "||", "+=", "-=", "*=", "/=", "%=", "++", "--",
+	"{", "}", "(", ")", "[", "]", ";", ",", ".", "=", "<", ">", "+", "-", "*", "/", "%", "!", "?", ":",
+}
+
+func tokenize(source string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(source)
+
+	for pos := 0; pos < len(runes); {
+		r := runes[pos]
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+		case r == '/' && pos+1 < len(runes) && runes[pos+1] == '*':
+			end := pos + 2
+			for end+1 < len(runes) && !(runes[end] == '*' && runes[end+1] == '/') {
+				end++
+			}
+			if end+1 >= len(runes) {
+				return nil, fmt.Errorf("%w: unterminated comment at %d", ErrInvalidScript, pos)
+			}
+			pos = end + 2
+		case unicode.IsLetter(r) || r == '_':
+			start := pos
+			for pos < len(runes) && (unicode.IsLetter(runes[pos]) || unicode.IsDigit(runes[pos]) || runes[pos] == '_') {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:pos]), pos: start})
+		case unicode.IsDigit(r):
+			start := pos
+			for pos < len(runes) && (unicode.IsDigit(runes[pos]) || runes[pos] == '.') {
+				pos++
+			}
+			text := string(runes[start:pos])
+			// the Java type suffixes are ignored
+			if pos < len(runes) && strings.ContainsRune("lLdDfF", runes[pos]) {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, pos: start})
+		case r == '\'' || r == '"':
+			text, next, err := readString(runes, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: pos})
+			pos = next
+		default:
+			punct := matchPunctuation(runes[pos:])
+			if punct == "" {
+				return nil, fmt.Errorf("%w: unexpected character %q at %d", ErrInvalidScript, r, pos)
+			}
+			tokens = append(tokens, token{kind: tokenPunct, text: punct, pos: pos})
+			pos += len(punct)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func readString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	builder := strings.Builder{}
+	for pos := start + 1; pos < len(runes); pos++ {
+		r := runes[pos]
+		switch {
+		case r == quote:
+			return builder.String(), pos + 1, nil
+		case r == '\\' && pos+1 < len(runes):
+			pos++
+			switch runes[pos] {
+			case 'n':
+				builder.WriteRune('\n')
+			case 't':
+				builder.WriteRune('\t')
+			default:
+				builder.WriteRune(runes[pos])
+			}
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: unterminated string at %d", ErrInvalidScript, start)
+}
+
+func matchPunctuation(runes []rune) string {
+	for _, punct := range punctuation {
+		if len(runes) >= len(punct) && string(runes[:len(punct)]) == punct {
+			return punct
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,446 @@
+package painless
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+func (in *interpreter) evalCall(e *callExpr, sc *scope) (interface{}, error) {
+	object, err := in.eval(e.object, sc)
+	if err != nil {
+		return nil, err
+	}
+	args, err := in.evalArgs(e.args, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := object.(type) {
+	case nil:
+		return nil, fmt.Errorf("%w: cannot call %s on null", ErrScriptExecution, e.method)
+	case map[string]interface{}:
+		return in.callMapMethod(v, e.method, args)
+	case *List:
+		return in.callListMethod(v, e.method, args)
+	case *iterator:
+		return callIteratorMethod(v, e.method, args)
+	case string:
+		return callStringMethod(v, e.method, args)
+	case *big.Int:
+		return callBigIntMethod(v, e.method, args)
+	default:
+		return callObjectMethod(object, e.method, args)
+	}
+}
+
+func checkArgs(method string, args []interface{}, expected int) error {
+	if len(args) != expected {
+		return fmt.Errorf("%w: %s expects %d arguments, got %d", ErrScriptExecution, method, expected, len(args))
+	}
+
+	return nil
+}
+
+func unknownMethod(object interface{}, method string) error {
+	return fmt.Errorf("%w: unknown method %s of %s", ErrScriptExecution, method, typeName(object))
+}
+
+func (in *interpreter) callMapMethod(m map[string]interface{}, method string, args []interface{}) (interface{}, error) {
+	switch method {
+	case "containsKey", "get", "remove":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		key := toString(args[0])
+		value, found := m[key]
+		if method == "containsKey" {
+			return found, nil
+		}
+		if method == "remove" {
+			delete(m, key)
+		}
+		return value, nil
+	case "getOrDefault":
+		err := checkArgs(method, args, 2)
+		if err != nil {
+			return nil, err
+		}
+		value, found := m[toString(args[0])]
+		if !found {
+			return args[1], nil
+		}
+		return value, nil
+	case "put":
+		err := checkArgs(method, args, 2)
+		if err != nil {
+			return nil, err
+		}
+		key := toString(args[0])
+		previous := m[key]
+		m[key] = args[1]
+		return previous, nil
+	case "putAll":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		other, ok := args[0].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: putAll expects a map", ErrScriptExecution)
+		}
+		for key, value := range other {
+			m[key] = value
+		}
+		return nil, nil
+	case "isEmpty":
+		return len(m) == 0, nil
+	case "size":
+		return int64(len(m)), nil
+	case "clear":
+		for key := range m {
+			delete(m, key)
+		}
+		return nil, nil
+	case "keySet":
+		keys := &List{}
+		for _, key := range sortedKeys(m) {
+			keys.Items = append(keys.Items, key)
+		}
+		return keys, nil
+	case "values":
+		values := &List{}
+		for _, key := range sortedKeys(m) {
+			values.Items = append(values.Items, m[key])
+		}
+		return values, nil
+	case "forEach":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedKeys(m) {
+			_, err = in.callLambda(args[0], key, m[key])
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case "equals":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return equalValues(m, args[0]), nil
+	default:
+		return nil, unknownMethod(m, method)
+	}
+}
+
+func (in *interpreter) callListMethod(list *List, method string, args []interface{}) (interface{}, error) {
+	switch method {
+	case "add":
+		switch len(args) {
+		case 1:
+			list.Items = append(list.Items, args[0])
+			return true, nil
+		case 2:
+			position, ok := args[0].(int64)
+			if !ok || position < 0 || position > int64(len(list.Items)) {
+				return nil, fmt.Errorf("%w: invalid list index %s", ErrScriptExecution, toString(args[0]))
+			}
+			list.Items = append(list.Items[:position], append([]interface{}{args[1]}, list.Items[position:]...)...)
+			return nil, nil
+		default:
+			return nil, checkArgs(method, args, 1)
+		}
+	case "addAll":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		other, ok := args[0].(*List)
+		if !ok {
+			return nil, fmt.Errorf("%w: addAll expects a list", ErrScriptExecution)
+		}
+		list.Items = append(list.Items, other.Items...)
+		return true, nil
+	case "get":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		position, err := listPosition(list, args[0])
+		if err != nil {
+			return nil, err
+		}
+		return list.Items[position], nil
+	case "set":
+		err := checkArgs(method, args, 2)
+		if err != nil {
+			return nil, err
+		}
+		position, err := listPosition(list, args[0])
+		if err != nil {
+			return nil, err
+		}
+		previous := list.Items[position]
+		list.Items[position] = args[1]
+		return previous, nil
+	case "remove":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return removeFromList(list, args[0])
+	case "removeIf":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return in.removeIf(list, args[0])
+	case "contains":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return indexOf(list, args[0]) >= 0, nil
+	case "indexOf":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return int64(indexOf(list, args[0])), nil
+	case "size", "getLength":
+		return int64(len(list.Items)), nil
+	case "isEmpty":
+		return len(list.Items) == 0, nil
+	case "clear":
+		list.Items = list.Items[:0]
+		return nil, nil
+	case "iterator":
+		return &iterator{list: list}, nil
+	case "forEach":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range append([]interface{}{}, list.Items...) {
+			_, err = in.callLambda(args[0], item)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case "equals":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return equalValues(list, args[0]), nil
+	default:
+		return nil, unknownMethod(list, method)
+	}
+}
+
+func indexOf(list *List, value interface{}) int {
+	for idx, item := range list.Items {
+		if equalValues(item, value) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// removeFromList removes the element at the provided position, for an integer argument, or the first element equal
+// to the argument otherwise, like the two remove methods of a Java list
+func removeFromList(list *List, arg interface{}) (interface{}, error) {
+	if _, isPosition := arg.(int64); isPosition {
+		position, err := listPosition(list, arg)
+		if err != nil {
+			return nil, err
+		}
+		removed := list.Items[position]
+		list.Items = append(list.Items[:position], list.Items[position+1:]...)
+		return removed, nil
+	}
+
+	position := indexOf(list, arg)
+	if position < 0 {
+		return false, nil
+	}
+	list.Items = append(list.Items[:position], list.Items[position+1:]...)
+
+	return true, nil
+}
+
+func (in *interpreter) removeIf(list *List, predicate interface{}) (interface{}, error) {
+	kept := make([]interface{}, 0, len(list.Items))
+	for _, item := range list.Items {
+		result, err := in.callLambda(predicate, item)
+		if err != nil {
+			return nil, err
+		}
+		remove, ok := result.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: the removeIf predicate returned %s", ErrScriptExecution, typeName(result))
+		}
+		if !remove {
+			kept = append(kept, item)
+		}
+	}
+
+	removedAny := len(kept) != len(list.Items)
+	list.Items = kept
+
+	return removedAny, nil
+}
+
+func callIteratorMethod(it *iterator, method string, args []interface{}) (interface{}, error) {
+	err := checkArgs(method, args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	switch method {
+	case "hasNext":
+		return it.next < len(it.list.Items), nil
+	case "next":
+		if it.next >= len(it.list.Items) {
+			return nil, fmt.Errorf("%w: the iterator has no more elements", ErrScriptExecution)
+		}
+		value := it.list.Items[it.next]
+		it.next++
+		it.removed = false
+		return value, nil
+	case "remove":
+		if it.next == 0 || it.removed {
+			return nil, fmt.Errorf("%w: illegal iterator state", ErrScriptExecution)
+		}
+		it.next--
+		it.list.Items = append(it.list.Items[:it.next], it.list.Items[it.next+1:]...)
+		it.removed = true
+		return nil, nil
+	default:
+		return nil, unknownMethod(it, method)
+	}
+}
+
+func callStringMethod(s string, method string, args []interface{}) (interface{}, error) {
+	switch method {
+	case "isEmpty":
+		return len(s) == 0, nil
+	case "length":
+		return int64(len(s)), nil
+	case "toString", "trim", "toLowerCase", "toUpperCase":
+		switch method {
+		case "trim":
+			return strings.TrimSpace(s), nil
+		case "toLowerCase":
+			return strings.ToLower(s), nil
+		case "toUpperCase":
+			return strings.ToUpper(s), nil
+		}
+		return s, nil
+	}
+
+	err := checkArgs(method, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	switch method {
+	case "equals":
+		return equalValues(s, args[0]), nil
+	case "contains":
+		return strings.Contains(s, toString(args[0])), nil
+	case "startsWith":
+		return strings.HasPrefix(s, toString(args[0])), nil
+	case "endsWith":
+		return strings.HasSuffix(s, toString(args[0])), nil
+	case "indexOf":
+		return int64(strings.Index(s, toString(args[0]))), nil
+	case "compareTo":
+		cmp, errCompare := compareValues(s, args[0])
+		return int64(cmp), errCompare
+	default:
+		return nil, unknownMethod(s, method)
+	}
+}
+
+func callBigIntMethod(value *big.Int, method string, args []interface{}) (interface{}, error) {
+	switch method {
+	case "toString":
+		return value.String(), nil
+	case "signum":
+		return int64(value.Sign()), nil
+	case "negate":
+		return new(big.Int).Neg(value), nil
+	case "abs":
+		return new(big.Int).Abs(value), nil
+	case "longValue":
+		return value.Int64(), nil
+	}
+
+	err := checkArgs(method, args, 1)
+	if err != nil {
+		return nil, err
+	}
+	other, ok := toBigInt(args[0])
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects a BigInteger", ErrScriptExecution, method)
+	}
+
+	switch method {
+	case "add":
+		return new(big.Int).Add(value, other), nil
+	case "subtract":
+		return new(big.Int).Sub(value, other), nil
+	case "multiply":
+		return new(big.Int).Mul(value, other), nil
+	case "divide":
+		if other.Sign() == 0 {
+			return nil, fmt.Errorf("%w: division by zero", ErrScriptExecution)
+		}
+		return new(big.Int).Quo(value, other), nil
+	case "compareTo":
+		return int64(value.Cmp(other)), nil
+	case "equals":
+		return value.Cmp(other) == 0, nil
+	default:
+		return nil, unknownMethod(value, method)
+	}
+}
+
+func callObjectMethod(object interface{}, method string, args []interface{}) (interface{}, error) {
+	switch method {
+	case "toString":
+		return toString(object), nil
+	case "equals":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return equalValues(object, args[0]), nil
+	case "compareTo":
+		err := checkArgs(method, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		cmp, err := compareValues(object, args[0])
+		return int64(cmp), err
+	case "intValue", "longValue":
+		value, ok := toInt(object)
+		if ok {
+			return value, nil
+		}
+	case "doubleValue":
+		value, ok := toFloat(object)
+		if ok {
+			return value, nil
+		}
+	}
+
+	return nil, unknownMethod(object, method)
+}
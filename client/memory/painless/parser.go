@@ -0,0 +1,842 @@
+package painless
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	blockStmt struct {
+		stmts []interface{}
+	}
+	ifStmt struct {
+		cond     interface{}
+		then     interface{}
+		elseStmt interface{}
+	}
+	forStmt struct {
+		init   interface{}
+		cond   interface{}
+		update interface{}
+		body   interface{}
+	}
+	forEachStmt struct {
+		name     string
+		iterable interface{}
+		body     interface{}
+	}
+	whileStmt struct {
+		cond interface{}
+		body interface{}
+	}
+	returnStmt struct {
+		value interface{}
+	}
+	breakStmt    struct{}
+	continueStmt struct{}
+	declStmt     struct {
+		typeName string
+		name     string
+		value    interface{}
+	}
+	exprStmt struct {
+		expr interface{}
+	}
+)
+
+type (
+	literalExpr struct {
+		value interface{}
+	}
+	identExpr struct {
+		name string
+	}
+	memberExpr struct {
+		object interface{}
+		name   string
+	}
+	indexExpr struct {
+		object interface{}
+		index  interface{}
+	}
+	callExpr struct {
+		object interface{}
+		method string
+		args   []interface{}
+	}
+	newExpr struct {
+		typeName string
+		args     []interface{}
+	}
+	listExpr struct {
+		items []interface{}
+	}
+	mapExpr struct {
+		keys   []interface{}
+		values []interface{}
+	}
+	unaryExpr struct {
+		op      string
+		operand interface{}
+	}
+	binaryExpr struct {
+		op    string
+		left  interface{}
+		right interface{}
+	}
+	ternaryExpr struct {
+		cond      interface{}
+		whenTrue  interface{}
+		whenFalse interface{}
+	}
+	assignExpr struct {
+		op     string
+		target interface{}
+		value  interface{}
+	}
+	incDecExpr struct {
+		op     string
+		target interface{}
+		prefix bool
+	}
+	lambdaExpr struct {
+		params []string
+		body   interface{}
+	}
+)
+
+var keywords = map[string]struct{}{
+	"if": {}, "else": {}, "for": {}, "while": {}, "return": {}, "break": {}, "continue": {},
+	"new": {}, "true": {}, "false": {}, "null": {}, "instanceof": {},
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func parse(source string) (*blockStmt, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	program := &blockStmt{}
+	for p.peek().kind != tokenEOF {
+		stmt, errParse := p.parseStatement()
+		if errParse != nil {
+			return nil, errParse
+		}
+		program.stmts = append(program.stmts, stmt)
+	}
+
+	return program, nil
+}
+
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	tok := p.peek()
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) isPunct(text string) bool {
+	tok := p.peek()
+	return tok.kind == tokenPunct && tok.text == text
+}
+
+func (p *parser) isKeyword(text string) bool {
+	tok := p.peek()
+	return tok.kind == tokenIdent && tok.text == text
+}
+
+func (p *parser) expectPunct(text string) error {
+	tok := p.next()
+	if tok.kind != tokenPunct || tok.text != text {
+		return p.errorAt(tok, fmt.Sprintf("expected %q", text))
+	}
+
+	return nil
+}
+
+func (p *parser) expectIdent() (string, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return "", p.errorAt(tok, "expected identifier")
+	}
+
+	return tok.text, nil
+}
+
+func (p *parser) errorAt(tok token, message string) error {
+	found := tok.text
+	if tok.kind == tokenEOF {
+		found = "end of script"
+	}
+
+	return fmt.Errorf("%w: %s at %d, found %q", ErrInvalidScript, message, tok.pos, found)
+}
+
+// the semicolons are optional, the statements are also delimited by the tokens that cannot continue an expression
+func (p *parser) skipSemicolons() {
+	for p.isPunct(";") {
+		p.next()
+	}
+}
+
+func (p *parser) atStatementEnd() bool {
+	tok := p.peek()
+	return tok.kind == tokenEOF || (tok.kind == tokenPunct && (tok.text == ";" || tok.text == "}"))
+}
+
+func (p *parser) isDeclarationStart() bool {
+	first, second := p.peek(), p.peekAt(1)
+	if first.kind != tokenIdent || second.kind != tokenIdent {
+		return false
+	}
+	_, isKeyword := keywords[first.text]
+
+	return !isKeyword
+}
+
+func (p *parser) parseStatement() (interface{}, error) {
+	switch {
+	case p.isPunct("{"):
+		return p.parseBlock()
+	case p.isPunct(";"):
+		p.next()
+		return &blockStmt{}, nil
+	case p.isKeyword("if"):
+		return p.parseIf()
+	case p.isKeyword("for"):
+		return p.parseFor()
+	case p.isKeyword("while"):
+		return p.parseWhile()
+	case p.isKeyword("return"):
+		p.next()
+		stmt := &returnStmt{}
+		if !p.atStatementEnd() {
+			value, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			stmt.value = value
+		}
+		p.skipSemicolons()
+		return stmt, nil
+	case p.isKeyword("break"):
+		p.next()
+		p.skipSemicolons()
+		return &breakStmt{}, nil
+	case p.isKeyword("continue"):
+		p.next()
+		p.skipSemicolons()
+		return &continueStmt{}, nil
+	case p.isDeclarationStart():
+		stmt, err := p.parseDeclaration()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSemicolons()
+		return stmt, nil
+	default:
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSemicolons()
+		return &exprStmt{expr: expr}, nil
+	}
+}
+
+func (p *parser) parseBlock() (*blockStmt, error) {
+	err := p.expectPunct("{")
+	if err != nil {
+		return nil, err
+	}
+
+	block := &blockStmt{}
+	for !p.isPunct("}") {
+		if p.peek().kind == tokenEOF {
+			return nil, p.errorAt(p.peek(), `expected "}"`)
+		}
+
+		stmt, errParse := p.parseStatement()
+		if errParse != nil {
+			return nil, errParse
+		}
+		block.stmts = append(block.stmts, stmt)
+	}
+	p.next()
+
+	return block, nil
+}
+
+func (p *parser) parseDeclaration() (*declStmt, error) {
+	typeName := p.next().text
+	name, err := p.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &declStmt{typeName: typeName, name: name}
+	if p.isPunct("=") {
+		p.next()
+		stmt.value, err = p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) parseCondition() (interface{}, error) {
+	err := p.expectPunct("(")
+	if err != nil {
+		return nil, err
+	}
+
+	cond, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	return cond, p.expectPunct(")")
+}
+
+func (p *parser) parseIf() (interface{}, error) {
+	p.next()
+	cond, err := p.parseCondition()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &ifStmt{cond: cond}
+	stmt.then, err = p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isKeyword("else") {
+		p.next()
+		stmt.elseStmt, err = p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) parseWhile() (interface{}, error) {
+	p.next()
+	cond, err := p.parseCondition()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	return &whileStmt{cond: cond, body: body}, nil
+}
+
+func (p *parser) parseFor() (interface{}, error) {
+	p.next()
+	err := p.expectPunct("(")
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isDeclarationStart() && p.peekAt(2).kind == tokenPunct && p.peekAt(2).text == ":" {
+		return p.parseForEach()
+	}
+
+	stmt := &forStmt{}
+	if !p.isPunct(";") {
+		if p.isDeclarationStart() {
+			stmt.init, err = p.parseDeclaration()
+		} else {
+			var expr interface{}
+			expr, err = p.parseExpr()
+			stmt.init = &exprStmt{expr: expr}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = p.expectPunct(";")
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.isPunct(";") {
+		stmt.cond, err = p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = p.expectPunct(";")
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.isPunct(")") {
+		stmt.update, err = p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = p.expectPunct(")")
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.body, err = p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) parseForEach() (interface{}, error) {
+	p.next()
+	name := p.next().text
+	p.next()
+
+	iterable, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expectPunct(")")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	return &forEachStmt{name: name, iterable: iterable, body: body}, nil
+}
+
+func (p *parser) parseExpr() (interface{}, error) {
+	return p.parseAssignment()
+}
+
+func (p *parser) parseAssignment() (interface{}, error) {
+	left, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	if tok.kind != tokenPunct {
+		return left, nil
+	}
+
+	switch tok.text {
+	case "=", "+=", "-=", "*=", "/=", "%=":
+		if !isAssignable(left) {
+			return nil, p.errorAt(tok, "invalid assignment target")
+		}
+		p.next()
+
+		value, errValue := p.parseAssignment()
+		if errValue != nil {
+			return nil, errValue
+		}
+
+		return &assignExpr{op: tok.text, target: left, value: value}, nil
+	default:
+		return left, nil
+	}
+}
+
+func isAssignable(expr interface{}) bool {
+	switch expr.(type) {
+	case *identExpr, *memberExpr, *indexExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *parser) parseTernary() (interface{}, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.isPunct("?") {
+		return cond, nil
+	}
+	p.next()
+
+	whenTrue, err := p.parseAssignment()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expectPunct(":")
+	if err != nil {
+		return nil, err
+	}
+	whenFalse, err := p.parseAssignment()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ternaryExpr{cond: cond, whenTrue: whenTrue, whenFalse: whenFalse}, nil
+}
+
+// binaryLevels holds the binary operators, from the lowest precedence to the highest one
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (interface{}, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.kind != tokenPunct || !containsString(binaryLevels[level], tok.text) {
+			return left, nil
+		}
+		p.next()
+
+		right, errRight := p.parseBinary(level + 1)
+		if errRight != nil {
+			return nil, errRight
+		}
+		left = &binaryExpr{op: tok.text, left: left, right: right}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *parser) parseUnary() (interface{}, error) {
+	tok := p.peek()
+	if tok.kind != tokenPunct {
+		return p.parsePostfix()
+	}
+
+	switch tok.text {
+	case "!", "-", "+":
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: tok.text, operand: operand}, nil
+	case "++", "--":
+		p.next()
+		target, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if !isAssignable(target) {
+			return nil, p.errorAt(tok, "invalid increment target")
+		}
+		return &incDecExpr{op: tok.text, target: target, prefix: true}, nil
+	default:
+		return p.parsePostfix()
+	}
+}
+
+func (p *parser) parsePostfix() (interface{}, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.isPunct("."):
+			p.next()
+			name, errName := p.expectIdent()
+			if errName != nil {
+				return nil, errName
+			}
+			if !p.isPunct("(") {
+				expr = &memberExpr{object: expr, name: name}
+				continue
+			}
+
+			args, errArgs := p.parseArguments()
+			if errArgs != nil {
+				return nil, errArgs
+			}
+			expr = &callExpr{object: expr, method: name, args: args}
+		case p.isPunct("["):
+			p.next()
+			index, errIndex := p.parseExpr()
+			if errIndex != nil {
+				return nil, errIndex
+			}
+			errIndex = p.expectPunct("]")
+			if errIndex != nil {
+				return nil, errIndex
+			}
+			expr = &indexExpr{object: expr, index: index}
+		case p.isPunct("++") || p.isPunct("--"):
+			tok := p.next()
+			if !isAssignable(expr) {
+				return nil, p.errorAt(tok, "invalid increment target")
+			}
+			expr = &incDecExpr{op: tok.text, target: expr}
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *parser) parseArguments() ([]interface{}, error) {
+	err := p.expectPunct("(")
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, 0)
+	for !p.isPunct(")") {
+		arg, errArg := p.parseExpr()
+		if errArg != nil {
+			return nil, errArg
+		}
+		args = append(args, arg)
+
+		if !p.isPunct(",") {
+			break
+		}
+		p.next()
+	}
+
+	return args, p.expectPunct(")")
+}
+
+func (p *parser) parsePrimary() (interface{}, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokenNumber:
+		p.next()
+		return parseNumber(tok)
+	case tokenString:
+		p.next()
+		return &literalExpr{value: tok.text}, nil
+	case tokenIdent:
+		return p.parseIdentifier()
+	case tokenPunct:
+		switch tok.text {
+		case "(":
+			if p.isLambdaStart() {
+				return p.parseLambda()
+			}
+			p.next()
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expectPunct(")")
+		case "[":
+			return p.parseCollection()
+		}
+	}
+
+	return nil, p.errorAt(tok, "unexpected token")
+}
+
+func parseNumber(tok token) (interface{}, error) {
+	if strings.Contains(tok.text, ".") {
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid number %s at %d", ErrInvalidScript, tok.text, tok.pos)
+		}
+		return &literalExpr{value: value}, nil
+	}
+
+	value, err := strconv.ParseInt(tok.text, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid number %s at %d", ErrInvalidScript, tok.text, tok.pos)
+	}
+
+	return &literalExpr{value: value}, nil
+}
+
+func (p *parser) parseIdentifier() (interface{}, error) {
+	tok := p.next()
+	switch tok.text {
+	case "true":
+		return &literalExpr{value: true}, nil
+	case "false":
+		return &literalExpr{value: false}, nil
+	case "null":
+		return &literalExpr{value: nil}, nil
+	case "new":
+		typeName, err := p.expectIdent()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		return &newExpr{typeName: typeName, args: args}, nil
+	}
+
+	if p.isPunct("->") {
+		p.next()
+		body, err := p.parseLambdaBody()
+		if err != nil {
+			return nil, err
+		}
+		return &lambdaExpr{params: []string{tok.text}, body: body}, nil
+	}
+
+	return &identExpr{name: tok.text}, nil
+}
+
+// isLambdaStart checks if the parenthesis starts the parameters of a lambda, like (key, value) -> or (def x) ->
+func (p *parser) isLambdaStart() bool {
+	for offset := 1; ; offset++ {
+		tok := p.peekAt(offset)
+		switch {
+		case tok.kind == tokenIdent || (tok.kind == tokenPunct && tok.text == ","):
+			continue
+		case tok.kind == tokenPunct && tok.text == ")":
+			next := p.peekAt(offset + 1)
+			return next.kind == tokenPunct && next.text == "->"
+		default:
+			return false
+		}
+	}
+}
+
+func (p *parser) parseLambda() (interface{}, error) {
+	p.next()
+	params := make([]string, 0)
+	for !p.isPunct(")") {
+		tok := p.next()
+		if tok.kind != tokenIdent {
+			continue
+		}
+		// a typed parameter, like def x, keeps only the name
+		if p.peek().kind == tokenIdent {
+			continue
+		}
+		params = append(params, tok.text)
+	}
+	p.next()
+
+	err := p.expectPunct("->")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.parseLambdaBody()
+	if err != nil {
+		return nil, err
+	}
+
+	return &lambdaExpr{params: params, body: body}, nil
+}
+
+func (p *parser) parseLambdaBody() (interface{}, error) {
+	if p.isPunct("{") {
+		return p.parseBlock()
+	}
+
+	return p.parseAssignment()
+}
+
+// parseCollection parses the list literals, like [a, b], and the map literals, like [:] or ['a': 1]
+func (p *parser) parseCollection() (interface{}, error) {
+	p.next()
+	if p.isPunct(":") {
+		p.next()
+		return &mapExpr{}, p.expectPunct("]")
+	}
+
+	list := &listExpr{}
+	var mapLiteral *mapExpr
+	for !p.isPunct("]") {
+		item, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.isPunct(":") || mapLiteral != nil {
+			if mapLiteral == nil {
+				if len(list.items) > 0 {
+					return nil, p.errorAt(p.peek(), "mixed list and map literal")
+				}
+				mapLiteral = &mapExpr{}
+			}
+			err = p.expectPunct(":")
+			if err != nil {
+				return nil, err
+			}
+			value, errValue := p.parseTernary()
+			if errValue != nil {
+				return nil, errValue
+			}
+			mapLiteral.keys = append(mapLiteral.keys, item)
+			mapLiteral.values = append(mapLiteral.values, value)
+		} else {
+			list.items = append(list.items, item)
+		}
+
+		if !p.isPunct(",") {
+			break
+		}
+		p.next()
+	}
+
+	err := p.expectPunct("]")
+	if err != nil {
+		return nil, err
+	}
+	if mapLiteral != nil {
+		return mapLiteral, nil
+	}
+
+	return list, nil
+}
@@ -0,0 +1,550 @@
+package painless
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/accounts"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/logsevents"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/tokeninfo"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/transactions"
+	"github.com/stretchr/testify/require"
+)
+
+type tokensSerializer interface {
+	SerializeTokens(tokens []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, index string) error
+	SerializeRolesData(tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties, buffSlice *data.BufferSlice, index string) error
+	SerializeDelegators(delegators map[string]*data.Delegator, buffSlice *data.BufferSlice, index string) error
+}
+
+type accountsESDTSerializer interface {
+	SerializeAccountsESDT(accounts map[string]*data.AccountInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, index string) error
+	SerializeTypeForProvidedIDs(ids []string, tokenType string, buffSlice *data.BufferSlice, index string) error
+}
+
+// repoScriptTest runs the script of the update action written by the indexer on an existing document. Without an
+// existing document the script runs on the upsert document, as the scripted upserts do
+type repoScriptTest struct {
+	name           string
+	body           []byte
+	source         string
+	expectedOp     string
+	expectedSource string
+}
+
+func runRepoScriptTests(t *testing.T, tests []repoScriptTest) {
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			op, source := executeUpdateAction(t, tt.body, tt.source)
+			require.Equal(t, tt.expectedOp, op)
+			require.JSONEq(t, tt.expectedSource, source)
+		})
+	}
+}
+
+func executeUpdateAction(t *testing.T, body []byte, existingSource string) (string, string) {
+	actions, err := data.ParseBulkBody(body, "")
+	require.Nil(t, err)
+	require.Len(t, actions, 1)
+
+	request := struct {
+		Script struct {
+			Source string                 `json:"source"`
+			Params map[string]interface{} `json:"params"`
+		} `json:"script"`
+		ScriptedUpsert bool                   `json:"scripted_upsert"`
+		Upsert         map[string]interface{} `json:"upsert"`
+	}{}
+	decodeJSON(t, actions[0].DocumentLine, &request)
+
+	op := "index"
+	source := request.Upsert
+	if existingSource == "" {
+		require.True(t, request.ScriptedUpsert, "the script runs on a missing document only for the scripted upserts")
+		op = "create"
+	} else {
+		source = make(map[string]interface{})
+		decodeJSON(t, []byte(existingSource), &source)
+	}
+
+	script, err := Compile(request.Script.Source)
+	require.Nil(t, err)
+
+	ctx := map[string]interface{}{
+		"_source": FromJSON(source),
+		"op":      op,
+	}
+	_, err = script.Execute(map[string]interface{}{
+		"ctx":    ctx,
+		"params": FromJSON(request.Script.Params),
+	})
+	require.Nil(t, err)
+
+	resultSource, err := json.Marshal(ToJSON(ctx["_source"]))
+	require.Nil(t, err)
+
+	return ctx["op"].(string), string(resultSource)
+}
+
+// decodeJSON decodes the numbers as the memory client does. Only the first value is decoded, as Elasticsearch does
+func decodeJSON(t *testing.T, jsonBytes []byte, dest interface{}) {
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+	require.Nil(t, decoder.Decode(dest))
+}
+
+func createLogsAndEventsProcessor(t *testing.T) tokensSerializer {
+	balanceConverter, _ := converters.NewBalanceConverter(18)
+	processor, err := logsevents.NewLogsAndEventsProcessor(logsevents.ArgsLogsAndEventsProcessor{
+		PubKeyConverter:  &mock.PubkeyConverterMock{},
+		Marshalizer:      &mock.MarshalizerMock{},
+		BalanceConverter: balanceConverter,
+		Hasher:           &mock.HasherMock{},
+	})
+	require.Nil(t, err)
+
+	return processor
+}
+
+func serializeToken(t *testing.T, token *data.TokenInfo, nftUpdate *data.NFTDataUpdate) []byte {
+	tokens := make([]*data.TokenInfo, 0)
+	if token != nil {
+		tokens = append(tokens, token)
+	}
+	nftUpdates := make([]*data.NFTDataUpdate, 0)
+	if nftUpdate != nil {
+		nftUpdates = append(nftUpdates, nftUpdate)
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := createLogsAndEventsProcessor(t).SerializeTokens(tokens, nftUpdates, buffSlice, "tokens")
+	require.Nil(t, err)
+
+	return buffSlice.Buffers()[0].Bytes()
+}
+
+func serializeRolesAndProperties(t *testing.T, tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties) []byte {
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := createLogsAndEventsProcessor(t).SerializeRolesData(tokenRolesAndProperties, buffSlice, "tokens")
+	require.Nil(t, err)
+
+	return buffSlice.Buffers()[0].Bytes()
+}
+
+func TestScript_ExecuteTokensScripts(t *testing.T) {
+	t.Parallel()
+
+	issuedToken := &data.TokenInfo{
+		Name:         "Token",
+		Ticker:       "TKN",
+		Token:        "TKN-abcd",
+		Issuer:       "erd1issuer",
+		CurrentOwner: "erd1issuer",
+		Type:         core.FungibleESDT,
+		Timestamp:    100,
+	}
+	transferredToken := &data.TokenInfo{
+		Token:             "TKN-abcd",
+		CurrentOwner:      "erd1bob",
+		TransferOwnership: true,
+		OwnersHistory:     []*data.OwnerData{{Address: "erd1bob", Timestamp: 200}},
+	}
+
+	setRole := tokeninfo.NewTokenRolesAndProperties()
+	setRole.AddRole("TKN-abcd", "erd1bob", core.ESDTRoleLocalMint, true)
+	unsetRole := tokeninfo.NewTokenRolesAndProperties()
+	unsetRole.AddRole("TKN-abcd", "erd1alice", core.ESDTRoleLocalMint, false)
+	properties := tokeninfo.NewTokenRolesAndProperties()
+	properties.AddProperties("TKN-abcd", map[string]bool{"canMint": true})
+
+	runRepoScriptTests(t, []repoScriptTest{
+		{
+			name:           "issue should keep the roles",
+			body:           serializeToken(t, issuedToken, nil),
+			source:         `{"token":"TKN-abcd","roles":{"ESDTRoleLocalMint":["erd1alice"]}}`,
+			expectedOp:     "index",
+			expectedSource: `{"name":"Token","ticker":"TKN","token":"TKN-abcd","issuer":"erd1issuer","currentOwner":"erd1issuer","numDecimals":0,"type":"FungibleESDT","timestamp":100,"roles":{"ESDTRoleLocalMint":["erd1alice"]}}`,
+		},
+		{
+			name:           "issue without roles should not change the token",
+			body:           serializeToken(t, issuedToken, nil),
+			source:         `{"token":"TKN-abcd","type":"FungibleESDT"}`,
+			expectedOp:     "index",
+			expectedSource: `{"token":"TKN-abcd","type":"FungibleESDT"}`,
+		},
+		{
+			name:           "transfer ownership should add the owner in the history",
+			body:           serializeToken(t, transferredToken, nil),
+			source:         `{"token":"TKN-abcd","currentOwner":"erd1alice","ownersHistory":[{"address":"erd1alice","timestamp":100}]}`,
+			expectedOp:     "index",
+			expectedSource: `{"token":"TKN-abcd","currentOwner":"erd1bob","ownersHistory":[{"address":"erd1alice","timestamp":100},{"address":"erd1bob","timestamp":200}]}`,
+		},
+		{
+			name:           "transfer ownership should create the owners history",
+			body:           serializeToken(t, transferredToken, nil),
+			source:         `{"token":"TKN-abcd","currentOwner":"erd1alice"}`,
+			expectedOp:     "index",
+			expectedSource: `{"token":"TKN-abcd","currentOwner":"erd1bob","ownersHistory":[{"address":"erd1bob","timestamp":200}]}`,
+		},
+		{
+			name:           "change to dynamic should change the type",
+			body:           serializeToken(t, &data.TokenInfo{Token: "NFT-abcd", Type: core.DynamicNFTESDT, Timestamp: 300, ChangeToDynamic: true}, nil),
+			source:         `{"token":"NFT-abcd","type":"NonFungibleESDT"}`,
+			expectedOp:     "index",
+			expectedSource: `{"token":"NFT-abcd","type":"DynamicNonFungibleESDT","changedToDynamicTimestamp":300}`,
+		},
+		{
+			name:           "set role should create the roles",
+			body:           serializeRolesAndProperties(t, setRole),
+			source:         `{"token":"TKN-abcd"}`,
+			expectedOp:     "index",
+			expectedSource: `{"token":"TKN-abcd","roles":{"ESDTRoleLocalMint":["erd1bob"]}}`,
+		},
+		{
+			name:           "set role should add the address once",
+			body:           serializeRolesAndProperties(t, setRole),
+			source:         `{"token":"TKN-abcd","roles":{"ESDTRoleLocalMint":["erd1alice","erd1bob"]}}`,
+			expectedOp:     "index",
+			expectedSource: `{"token":"TKN-abcd","roles":{"ESDTRoleLocalMint":["erd1alice","erd1bob"]}}`,
+		},
+		{
+			name:           "unset role should remove the empty role",
+			body:           serializeRolesAndProperties(t, unsetRole),
+			source:         `{"token":"TKN-abcd","roles":{"ESDTRoleLocalMint":["erd1alice"],"ESDTRoleLocalBurn":["erd1alice"]}}`,
+			expectedOp:     "index",
+			expectedSource: `{"token":"TKN-abcd","roles":{"ESDTRoleLocalBurn":["erd1alice"]}}`,
+		},
+		{
+			name:           "properties should be merged",
+			body:           serializeRolesAndProperties(t, properties),
+			source:         `{"token":"TKN-abcd","properties":{"canBurn":true,"canMint":false}}`,
+			expectedOp:     "index",
+			expectedSource: `{"token":"TKN-abcd","properties":{"canBurn":true,"canMint":true}}`,
+		},
+		{
+			name:           "freeze should set frozen",
+			body:           serializeToken(t, nil, &data.NFTDataUpdate{Identifier: "NFT-abcd-01", Freeze: true}),
+			source:         `{"identifier":"NFT-abcd-01"}`,
+			expectedOp:     "index",
+			expectedSource: `{"identifier":"NFT-abcd-01","frozen":true}`,
+		},
+		{
+			name:           "unpause should reset paused",
+			body:           serializeToken(t, nil, &data.NFTDataUpdate{Identifier: "TKN-abcd", UnPause: true}),
+			source:         `{"token":"TKN-abcd","paused":true}`,
+			expectedOp:     "index",
+			expectedSource: `{"token":"TKN-abcd","paused":false}`,
+		},
+		{
+			name:           "new attributes should replace the metadata and the tags",
+			body:           serializeToken(t, nil, &data.NFTDataUpdate{Identifier: "NFT-abcd-01", NewAttributes: []byte("tags:art,music;metadata:meta")}),
+			source:         `{"identifier":"NFT-abcd-01","data":{"attributes":"b2xk","tags":["old"]}}`,
+			expectedOp:     "index",
+			expectedSource: `{"identifier":"NFT-abcd-01","data":{"attributes":"dGFnczphcnQsbXVzaWM7bWV0YWRhdGE6bWV0YQ==","metadata":"meta","tags":["art","music"]}}`,
+		},
+		{
+			name:           "new uris should be added once",
+			body:           serializeToken(t, nil, &data.NFTDataUpdate{Identifier: "NFT-abcd-01", URIsToAdd: [][]byte{[]byte("uri1"), []byte("uri2")}}),
+			source:         `{"identifier":"NFT-abcd-01","data":{"uris":["dXJpMQ=="]}}`,
+			expectedOp:     "index",
+			expectedSource: `{"identifier":"NFT-abcd-01","data":{"uris":["dXJpMQ==","dXJpMg=="],"nonEmptyURIs":true}}`,
+		},
+		{
+			name:           "new royalties should be set on the metadata",
+			body:           serializeToken(t, nil, &data.NFTDataUpdate{Identifier: "NFT-abcd-01", NewRoyalties: core.OptionalUint32{Value: 500, HasValue: true}}),
+			source:         `{"identifier":"NFT-abcd-01","data":{"royalties":100}}`,
+			expectedOp:     "index",
+			expectedSource: `{"identifier":"NFT-abcd-01","data":{"royalties":500}}`,
+		},
+		{
+			name:           "new creator should be set on the metadata",
+			body:           serializeToken(t, nil, &data.NFTDataUpdate{Identifier: "NFT-abcd-01", NewCreator: "erd1bob"}),
+			source:         `{"identifier":"NFT-abcd-01","data":{"creator":"erd1alice"}}`,
+			expectedOp:     "index",
+			expectedSource: `{"identifier":"NFT-abcd-01","data":{"creator":"erd1bob"}}`,
+		},
+		{
+			name:           "recreated metadata should replace the data",
+			body:           serializeToken(t, nil, &data.NFTDataUpdate{Identifier: "NFT-abcd-01", NewMetaData: &data.TokenMetaData{Name: "new", Royalties: 10}}),
+			source:         `{"identifier":"NFT-abcd-01","data":{"name":"old","creator":"erd1alice"}}`,
+			expectedOp:     "index",
+			expectedSource: `{"identifier":"NFT-abcd-01","data":{"name":"new","royalties":10,"nonEmptyURIs":false,"whiteListedStorage":false}}`,
+		},
+	})
+}
+
+func createAccountsProcessor(t *testing.T) accountsESDTSerializer {
+	balanceConverter, _ := converters.NewBalanceConverter(18)
+	processor, err := accounts.NewAccountsProcessor(&mock.PubkeyConverterMock{}, balanceConverter)
+	require.Nil(t, err)
+
+	return processor
+}
+
+func serializeAccountESDT(t *testing.T, account *data.AccountInfo, nftUpdate *data.NFTDataUpdate) []byte {
+	accountsESDT := make(map[string]*data.AccountInfo)
+	if account != nil {
+		accountsESDT[account.Address] = account
+	}
+	nftUpdates := make([]*data.NFTDataUpdate, 0)
+	if nftUpdate != nil {
+		nftUpdates = append(nftUpdates, nftUpdate)
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := createAccountsProcessor(t).SerializeAccountsESDT(accountsESDT, nftUpdates, buffSlice, "accountsesdt")
+	require.Nil(t, err)
+
+	return buffSlice.Buffers()[0].Bytes()
+}
+
+func TestScript_ExecuteAccountsESDTScripts(t *testing.T) {
+	t.Parallel()
+
+	account := &data.AccountInfo{
+		Address:         "erd1alice",
+		Balance:         "1000",
+		BalanceNum:      1e-15,
+		TokenName:       "TKN-abcd",
+		TokenIdentifier: "TKN-abcd",
+		Timestamp:       100,
+	}
+	emptyAccount := &data.AccountInfo{
+		Address:   "erd1alice",
+		Balance:   "0",
+		TokenName: "TKN-abcd",
+		Timestamp: 100,
+	}
+	accountJSON := `{"address":"erd1alice","balance":"1000","balanceNum":1e-15,"token":"TKN-abcd","identifier":"TKN-abcd","timestamp":100,"shardID":0}`
+
+	typeBuffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := createAccountsProcessor(t).SerializeTypeForProvidedIDs([]string{"erd1alice-NFT-abcd-01"}, core.DynamicNFTESDT, typeBuffSlice, "accountsesdt")
+	require.Nil(t, err)
+	typeBody := typeBuffSlice.Buffers()[0].Bytes()
+
+	runRepoScriptTests(t, []repoScriptTest{
+		{
+			name:           "new balance should create the document",
+			body:           serializeAccountESDT(t, account, nil),
+			expectedOp:     "create",
+			expectedSource: accountJSON,
+		},
+		{
+			name:           "newer balance should update the fields",
+			body:           serializeAccountESDT(t, account, nil),
+			source:         `{"address":"erd1alice","balance":"10","balanceNum":1e-17,"token":"TKN-abcd","type":"FungibleESDT","timestamp":50}`,
+			expectedOp:     "index",
+			expectedSource: `{"address":"erd1alice","balance":"1000","balanceNum":1e-15,"token":"TKN-abcd","identifier":"TKN-abcd","type":"FungibleESDT","timestamp":100,"shardID":0}`,
+		},
+		{
+			name:           "older balance should not change the document",
+			body:           serializeAccountESDT(t, account, nil),
+			source:         `{"address":"erd1alice","balance":"10","timestamp":150}`,
+			expectedOp:     "index",
+			expectedSource: `{"address":"erd1alice","balance":"10","timestamp":150}`,
+		},
+		{
+			name:           "zero balance of a missing document should do nothing",
+			body:           serializeAccountESDT(t, emptyAccount, nil),
+			expectedOp:     "noop",
+			expectedSource: `{}`,
+		},
+		{
+			name:           "zero balance should delete an older document",
+			body:           serializeAccountESDT(t, emptyAccount, nil),
+			source:         `{"address":"erd1alice","balance":"10","timestamp":50}`,
+			expectedOp:     "delete",
+			expectedSource: `{"address":"erd1alice","balance":"10","timestamp":50}`,
+		},
+		{
+			name:           "zero balance should not delete a newer document",
+			body:           serializeAccountESDT(t, emptyAccount, nil),
+			source:         `{"address":"erd1alice","balance":"10","timestamp":150}`,
+			expectedOp:     "index",
+			expectedSource: `{"address":"erd1alice","balance":"10","timestamp":150}`,
+		},
+		{
+			name:           "type of a missing document should do nothing",
+			body:           typeBody,
+			expectedOp:     "noop",
+			expectedSource: `{}`,
+		},
+		{
+			name:           "type should be set",
+			body:           typeBody,
+			source:         `{"address":"erd1alice","type":"NonFungibleESDT"}`,
+			expectedOp:     "index",
+			expectedSource: `{"address":"erd1alice","type":"DynamicNonFungibleESDT"}`,
+		},
+		{
+			name:           "new attributes should remove the metadata and the tags",
+			body:           serializeAccountESDT(t, nil, &data.NFTDataUpdate{Identifier: "NFT-abcd-01", Address: "erd1alice", NewAttributes: []byte("attr")}),
+			source:         `{"address":"erd1alice","data":{"attributes":"b2xk","metadata":"meta","tags":["old"]}}`,
+			expectedOp:     "index",
+			expectedSource: `{"address":"erd1alice","data":{"attributes":"YXR0cg=="}}`,
+		},
+	})
+}
+
+func serializeDelegator(t *testing.T, delegator *data.Delegator) []byte {
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err := createLogsAndEventsProcessor(t).SerializeDelegators(map[string]*data.Delegator{delegator.Address: delegator}, buffSlice, "delegators")
+	require.Nil(t, err)
+
+	return buffSlice.Buffers()[0].Bytes()
+}
+
+func TestScript_ExecuteDelegatorsScripts(t *testing.T) {
+	t.Parallel()
+
+	delegator := &data.Delegator{
+		Address:        "erd1alice",
+		Contract:       "erd1contract",
+		ActiveStake:    "100",
+		ActiveStakeNum: 1e-16,
+		Timestamp:      100,
+		TimestampMs:    100000,
+	}
+	unDelegate := *delegator
+	unDelegate.UnDelegateInfo = &data.UnDelegate{Timestamp: 100, TimestampMs: 100000, ID: "2", Value: "50", ValueNum: 5e-17}
+	withdrawal := *delegator
+	withdrawal.WithdrawFundIDs = []string{"1"}
+
+	delegatorJSON := `{"address":"erd1alice","contract":"erd1contract","timestamp":100,"timestampMs":100000,"activeStake":"100","activeStakeNum":1e-16}`
+	oldDelegatorFields := `"address":"erd1alice","contract":"erd1contract","timestamp":50,"timestampMs":50000,"activeStake":"150","activeStakeNum":1.5e-16`
+	newDelegatorFields := `"address":"erd1alice","contract":"erd1contract","timestamp":100,"timestampMs":100000,"activeStake":"100","activeStakeNum":1e-16`
+	oldUnDelegate := `{"timestamp":50,"timestampMs":50000,"id":"1","value":"10","valueNum":1e-17}`
+	newUnDelegate := `{"timestamp":100,"timestampMs":100000,"id":"2","value":"50","valueNum":5e-17}`
+
+	runRepoScriptTests(t, []repoScriptTest{
+		{
+			name:           "delegate should create the delegator",
+			body:           serializeDelegator(t, delegator),
+			expectedOp:     "create",
+			expectedSource: delegatorJSON,
+		},
+		{
+			name:           "delegate should update the active stake",
+			body:           serializeDelegator(t, delegator),
+			source:         `{` + oldDelegatorFields + `,"unDelegateInfo":[` + oldUnDelegate + `]}`,
+			expectedOp:     "index",
+			expectedSource: `{` + newDelegatorFields + `,"unDelegateInfo":[` + oldUnDelegate + `]}`,
+		},
+		{
+			name:           "undelegate should create the delegator",
+			body:           serializeDelegator(t, &unDelegate),
+			expectedOp:     "create",
+			expectedSource: delegatorJSON,
+		},
+		{
+			name:           "undelegate should create the undelegate info",
+			body:           serializeDelegator(t, &unDelegate),
+			source:         `{` + oldDelegatorFields + `}`,
+			expectedOp:     "index",
+			expectedSource: `{` + newDelegatorFields + `,"unDelegateInfo":[` + newUnDelegate + `]}`,
+		},
+		{
+			name:           "undelegate should add the undelegate info",
+			body:           serializeDelegator(t, &unDelegate),
+			source:         `{` + oldDelegatorFields + `,"unDelegateInfo":[` + oldUnDelegate + `]}`,
+			expectedOp:     "index",
+			expectedSource: `{` + newDelegatorFields + `,"unDelegateInfo":[` + oldUnDelegate + `,` + newUnDelegate + `]}`,
+		},
+		{
+			name:           "withdrawal should remove the withdrawn undelegate info",
+			body:           serializeDelegator(t, &withdrawal),
+			source:         `{` + oldDelegatorFields + `,"unDelegateInfo":[` + oldUnDelegate + `,` + newUnDelegate + `]}`,
+			expectedOp:     "index",
+			expectedSource: `{` + newDelegatorFields + `,"unDelegateInfo":[` + newUnDelegate + `]}`,
+		},
+		{
+			name:           "withdrawal of the last undelegate info should remove the list",
+			body:           serializeDelegator(t, &withdrawal),
+			source:         `{` + oldDelegatorFields + `,"unDelegateInfo":[` + oldUnDelegate + `]}`,
+			expectedOp:     "index",
+			expectedSource: `{` + newDelegatorFields + `}`,
+		},
+		{
+			name:           "withdrawal without undelegate info should not change the delegator",
+			body:           serializeDelegator(t, &withdrawal),
+			source:         `{` + oldDelegatorFields + `}`,
+			expectedOp:     "index",
+			expectedSource: `{` + oldDelegatorFields + `}`,
+		},
+	})
+}
+
+func serializeFeeData(t *testing.T, feeData *data.FeeData) []byte {
+	balanceConverter, _ := converters.NewBalanceConverter(18)
+	processor, err := transactions.NewTransactionsProcessor(&transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: mock.NewPubkeyConverterMock(32),
+		Hasher:                 &mock.HasherMock{},
+		Marshalizer:            &mock.MarshalizerMock{},
+		BalanceConverter:       balanceConverter,
+	})
+	require.Nil(t, err)
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	err = processor.SerializeTransactionsFeeData(map[string]*data.FeeData{"txHash": feeData}, buffSlice, "transactions")
+	require.Nil(t, err)
+
+	return buffSlice.Buffers()[0].Bytes()
+}
+
+func TestScript_ExecuteTransactionsFeeScripts(t *testing.T) {
+	t.Parallel()
+
+	newFee := serializeFeeData(t, &data.FeeData{Fee: "60000", FeeNum: 6e-14, GasUsed: 600})
+	refund := serializeFeeData(t, &data.FeeData{Fee: "40000", FeeNum: 4e-14, GasRefunded: 400})
+
+	runRepoScriptTests(t, []repoScriptTest{
+		{
+			name:           "fee of a missing transaction should do nothing",
+			body:           newFee,
+			expectedOp:     "noop",
+			expectedSource: `{}`,
+		},
+		{
+			name:           "fee should be replaced",
+			body:           newFee,
+			source:         `{"hash":"txHash","fee":"100000","feeNum":1e-13,"gasUsed":1000}`,
+			expectedOp:     "index",
+			expectedSource: `{"hash":"txHash","fee":"60000","feeNum":6e-14,"gasUsed":600}`,
+		},
+		{
+			name:           "refund of a missing transaction should do nothing",
+			body:           refund,
+			expectedOp:     "noop",
+			expectedSource: `{}`,
+		},
+		{
+			name:           "first refund should be subtracted from the initial paid fee",
+			body:           refund,
+			source:         `{"hash":"txHash","initialPaidFee":"120000","fee":"100000","feeNum":1e-13,"gasUsed":1000}`,
+			expectedOp:     "index",
+			expectedSource: `{"hash":"txHash","initialPaidFee":"120000","fee":"80000","feeNum":6e-14,"gasUsed":600,"hadRefund":true}`,
+		},
+		{
+			name:           "next refund should be subtracted from the fee",
+			body:           refund,
+			source:         `{"hash":"txHash","initialPaidFee":"120000","fee":"80000","feeNum":8e-14,"gasUsed":800,"hadRefund":true}`,
+			expectedOp:     "index",
+			expectedSource: `{"hash":"txHash","initialPaidFee":"120000","fee":"40000","feeNum":4e-14,"gasUsed":400,"hadRefund":true}`,
+		},
+		{
+			name:           "refund without the initial paid fee should not change the transaction",
+			body:           refund,
+			source:         `{"hash":"txHash","fee":"100000","feeNum":1e-13,"gasUsed":1000}`,
+			expectedOp:     "index",
+			expectedSource: `{"hash":"txHash","fee":"100000","feeNum":1e-13,"gasUsed":1000}`,
+		},
+	})
+}
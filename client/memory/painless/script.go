@@ -0,0 +1,41 @@
+package painless
+
+import (
+	"errors"
+)
+
+// ErrInvalidScript signals that the source of a script cannot be parsed
+var ErrInvalidScript = errors.New("invalid painless script")
+
+// ErrScriptExecution signals that a script failed while it was executed
+var ErrScriptExecution = errors.New("painless script execution failed")
+
+// Script is a compiled painless script. Only the subset of the language used by the indexer scripts is supported:
+// the statements, the operators, the lambdas and the most common methods of the maps, lists, strings and BigInteger
+type Script struct {
+	program *blockStmt
+}
+
+// Compile will parse the provided source
+func Compile(source string) (*Script, error) {
+	program, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Script{program: program}, nil
+}
+
+// Execute will run the script with the provided variables, usually ctx and params. The variables have to be converted
+// with FromJSON and are changed in place by the script. The value of the return statement, if any, is returned
+func (s *Script) Execute(variables map[string]interface{}) (interface{}, error) {
+	sc := newScope(nil)
+	for name, value := range variables {
+		sc.vars[name] = value
+	}
+
+	in := &interpreter{}
+	_, result, err := in.execBlock(s.program, sc)
+
+	return result, err
+}
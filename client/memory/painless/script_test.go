@@ -0,0 +1,123 @@
+package painless
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/stretchr/testify/require"
+)
+
+func executeOnSource(t *testing.T, source string, doc map[string]interface{}, params map[string]interface{}) map[string]interface{} {
+	script, err := Compile(converters.FormatPainlessSource(source))
+	require.Nil(t, err)
+
+	ctx := map[string]interface{}{
+		"_source": FromJSON(doc),
+		"op":      "index",
+	}
+	_, err = script.Execute(map[string]interface{}{
+		"ctx":    ctx,
+		"params": FromJSON(params),
+	})
+	require.Nil(t, err)
+
+	return ctx
+}
+
+func TestCompile_InvalidScript(t *testing.T) {
+	t.Parallel()
+
+	_, err := Compile("if (ctx._source.a == 1 {")
+	require.True(t, errors.Is(err, ErrInvalidScript))
+
+	_, err = Compile("ctx._source.a = 'unterminated")
+	require.True(t, errors.Is(err, ErrInvalidScript))
+}
+
+func TestScript_ExecuteIncrementAndAssign(t *testing.T) {
+	t.Parallel()
+
+	ctx := executeOnSource(t, `
+		ctx._source.count += params.count;
+		ctx._source.tag = params.tag
+`, map[string]interface{}{"count": 2, "tag": "old"}, map[string]interface{}{"count": 3, "tag": "new"})
+
+	source := ToJSON(ctx["_source"]).(map[string]interface{})
+	require.Equal(t, int64(5), source["count"])
+	require.Equal(t, "new", source["tag"])
+}
+
+func TestScript_ExecuteConditionsAndOp(t *testing.T) {
+	t.Parallel()
+
+	source := `
+		if (ctx._source.containsKey('timestamp')) {
+			if (ctx._source.timestamp > params.timestamp) {
+				ctx.op = 'noop';
+				return
+			}
+		}
+		ctx._source.timestamp = params.timestamp
+`
+	ctx := executeOnSource(t, source, map[string]interface{}{"timestamp": 10}, map[string]interface{}{"timestamp": 5})
+	require.Equal(t, "noop", ctx["op"])
+
+	ctx = executeOnSource(t, source, map[string]interface{}{"timestamp": 10}, map[string]interface{}{"timestamp": 15})
+	require.Equal(t, "index", ctx["op"])
+	require.Equal(t, int64(15), ToJSON(ctx["_source"]).(map[string]interface{})["timestamp"])
+}
+
+func TestScript_ExecuteListsAndLoops(t *testing.T) {
+	t.Parallel()
+
+	ctx := executeOnSource(t, `
+		if (!ctx._source.containsKey('roles')) {
+			ctx._source.roles = new ArrayList();
+		}
+		for (int i = 0; i < params.roles.length; i++) {
+			if (!ctx._source.roles.contains(params.roles[i])) {
+				ctx._source.roles.add(params.roles[i]);
+			}
+		}
+		ctx._source.roles.removeIf(role -> role == 'burn');
+		Iterator it = ctx._source.roles.iterator();
+		while (it.hasNext()) {
+			if (it.next() == 'mint') {
+				it.remove();
+			}
+		}
+`, map[string]interface{}{"roles": []interface{}{"burn", "create"}}, map[string]interface{}{"roles": []interface{}{"create", "mint", "transfer"}})
+
+	source := ToJSON(ctx["_source"]).(map[string]interface{})
+	require.Equal(t, []interface{}{"create", "transfer"}, source["roles"])
+}
+
+func TestScript_ExecuteBigInteger(t *testing.T) {
+	t.Parallel()
+
+	ctx := executeOnSource(t, `
+		BigInteger balance = new BigInteger(ctx._source.balance);
+		ctx._source.balance = balance.add(new BigInteger(params.value)).toString();
+`, map[string]interface{}{"balance": "18446744073709551616"}, map[string]interface{}{"value": "1"})
+
+	expected := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(1))
+	require.Equal(t, expected.String(), ToJSON(ctx["_source"]).(map[string]interface{})["balance"])
+}
+
+func TestScript_ExecuteErrors(t *testing.T) {
+	t.Parallel()
+
+	script, err := Compile("ctx._source.missing.field = 1")
+	require.Nil(t, err)
+	_, err = script.Execute(map[string]interface{}{
+		"ctx": map[string]interface{}{"_source": map[string]interface{}{}},
+	})
+	require.True(t, errors.Is(err, ErrScriptExecution))
+
+	script, err = Compile("while (true) {}")
+	require.Nil(t, err)
+	_, err = script.Execute(map[string]interface{}{})
+	require.True(t, errors.Is(err, ErrScriptExecution))
+}
@@ -0,0 +1,250 @@
+package painless
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxSafeInteger is the biggest integer that a float64 holds without losing precision
+const maxSafeInteger = 1 << 53
+
+// List is the mutable list the scripts work with, so the changes made through a variable are seen by all the
+// references of the list
+type List struct {
+	Items []interface{}
+}
+
+type iterator struct {
+	list    *List
+	next    int
+	removed bool
+}
+
+type lambda struct {
+	expr    *lambdaExpr
+	closure *scope
+}
+
+// FromJSON converts a value decoded from JSON in a value the scripts can work with. The value is copied, so the
+// changes made by the scripts are not seen by the provided value
+func FromJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = FromJSON(item)
+		}
+		return converted
+	case []interface{}:
+		list := &List{Items: make([]interface{}, 0, len(v))}
+		for _, item := range v {
+			list.Items = append(list.Items, FromJSON(item))
+		}
+		return list
+	case json.Number:
+		intValue, err := v.Int64()
+		if err == nil {
+			return intValue
+		}
+		floatValue, _ := v.Float64()
+		return floatValue
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < maxSafeInteger {
+			return int64(v)
+		}
+		return v
+	case int:
+		return int64(v)
+	default:
+		return v
+	}
+}
+
+// ToJSON converts a value the scripts work with in a value that can be encoded in JSON
+func ToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = ToJSON(item)
+		}
+		return converted
+	case *List:
+		items := make([]interface{}, 0, len(v.Items))
+		for _, item := range v.Items {
+			items = append(items, ToJSON(item))
+		}
+		return items
+	case *big.Int:
+		return json.Number(v.String())
+	default:
+		return v
+	}
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case int64:
+		return "long"
+	case float64:
+		return "double"
+	case string:
+		return "String"
+	case *big.Int:
+		return "BigInteger"
+	case map[string]interface{}:
+		return "HashMap"
+	case *List:
+		return "ArrayList"
+	case *iterator:
+		return "Iterator"
+	case *lambda:
+		return "lambda"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *big.Int:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		encoded, _ := json.Marshal(ToJSON(v))
+		return string(encoded)
+	}
+}
+
+func toInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func isNumber(value interface{}) bool {
+	_, ok := toFloat(value)
+	return ok
+}
+
+func toBigInt(value interface{}) (*big.Int, bool) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v, true
+	case int64:
+		return big.NewInt(v), true
+	case string:
+		return new(big.Int).SetString(v, 10)
+	default:
+		return nil, false
+	}
+}
+
+func equalValues(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+
+	leftInt, leftIsInt := left.(int64)
+	rightInt, rightIsInt := right.(int64)
+	if leftIsInt && rightIsInt {
+		return leftInt == rightInt
+	}
+	if isNumber(left) && isNumber(right) {
+		leftFloat, _ := toFloat(left)
+		rightFloat, _ := toFloat(right)
+		return leftFloat == rightFloat
+	}
+
+	leftBig, leftIsBig := left.(*big.Int)
+	rightBig, rightIsBig := right.(*big.Int)
+	if leftIsBig && rightIsBig {
+		return leftBig.Cmp(rightBig) == 0
+	}
+
+	return reflect.DeepEqual(ToJSON(left), ToJSON(right))
+}
+
+func compareValues(left, right interface{}) (int, error) {
+	leftInt, leftIsInt := left.(int64)
+	rightInt, rightIsInt := right.(int64)
+	switch {
+	case leftIsInt && rightIsInt:
+		return compareOrdered(leftInt, rightInt), nil
+	case isNumber(left) && isNumber(right):
+		leftFloat, _ := toFloat(left)
+		rightFloat, _ := toFloat(right)
+		return compareOrdered(leftFloat, rightFloat), nil
+	}
+
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+	if leftIsString && rightIsString {
+		return strings.Compare(leftString, rightString), nil
+	}
+
+	leftBig, leftIsBig := toBigInt(left)
+	rightBig, rightIsBig := toBigInt(right)
+	_, isBigInt := left.(*big.Int)
+	if leftIsBig && rightIsBig && isBigInt {
+		return leftBig.Cmp(rightBig), nil
+	}
+
+	return 0, fmt.Errorf("%w: cannot compare %s with %s", ErrScriptExecution, typeName(left), typeName(right))
+}
+
+func compareOrdered[T int64 | float64](left, right T) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,325 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// matchingIDs returns the sorted ids of the documents that match the query. A missing query matches all the documents
+func matchingIDs(docs documents, query interface{}) ([]string, error) {
+	ids := make([]string, 0)
+	for id, doc := range docs {
+		matches := true
+		if query != nil {
+			var err error
+			matches, err = matchesQuery(query, id, doc)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if matches {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+func matchesQuery(query interface{}, id string, doc objectsMap) (bool, error) {
+	clauses, ok := query.(objectsMap)
+	if !ok {
+		return false, fmt.Errorf("%w: the query is not an object", ErrUnsupportedQuery)
+	}
+
+	// an object with more clauses is not valid for Elasticsearch, all the clauses have to match here
+	for clauseType, clause := range clauses {
+		matches, err := matchesClause(clauseType, clause, id, doc)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchesClause(clauseType string, clause interface{}, id string, doc objectsMap) (bool, error) {
+	switch clauseType {
+	case "match_all":
+		return true, nil
+	case "match_none":
+		return false, nil
+	case "bool":
+		return matchesBool(clause, id, doc)
+	case "ids":
+		values, _ := asObject(clause)["values"].([]interface{})
+		for _, value := range values {
+			if fmt.Sprint(value) == id {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "exists":
+		field, _ := asObject(clause)["field"].(string)
+		return len(fieldValues(doc, field)) > 0, nil
+	case "term", "match", "match_phrase":
+		return matchesFieldClause(clause, []string{"value", "query"}, func(docValue interface{}, queryValue interface{}) bool {
+			return valuesEqual(docValue, queryValue)
+		})(doc)
+	case "terms":
+		for field, values := range asObject(clause) {
+			queryValues, ok := values.([]interface{})
+			if !ok {
+				return false, fmt.Errorf("%w: the terms of %s are not a list", ErrUnsupportedQuery, field)
+			}
+			for _, docValue := range fieldValues(doc, field) {
+				for _, queryValue := range queryValues {
+					if valuesEqual(docValue, queryValue) {
+						return true, nil
+					}
+				}
+			}
+		}
+		return false, nil
+	case "prefix":
+		return matchesFieldClause(clause, []string{"value"}, func(docValue interface{}, queryValue interface{}) bool {
+			return strings.HasPrefix(canonicalValue(docValue), fmt.Sprint(queryValue))
+		})(doc)
+	case "range":
+		return matchesRange(clause, doc)
+	default:
+		return false, fmt.Errorf("%w: %s", ErrUnsupportedQuery, clauseType)
+	}
+}
+
+// matchesFieldClause handles the clauses like {"field": value} or {"field": {"value": value}}
+func matchesFieldClause(clause interface{}, valueKeys []string, matchFunc func(docValue interface{}, queryValue interface{}) bool) func(doc objectsMap) (bool, error) {
+	return func(doc objectsMap) (bool, error) {
+		for field, query := range asObject(clause) {
+			queryValue := query
+			if queryObject, isObject := query.(objectsMap); isObject {
+				queryValue = nil
+				for _, key := range valueKeys {
+					value, found := queryObject[key]
+					if found {
+						queryValue = value
+						break
+					}
+				}
+			}
+
+			matches := false
+			for _, docValue := range fieldValues(doc, field) {
+				if matchFunc(docValue, queryValue) {
+					matches = true
+					break
+				}
+			}
+			if !matches {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+}
+
+func matchesBool(clause interface{}, id string, doc objectsMap) (bool, error) {
+	boolQuery := asObject(clause)
+
+	for _, occur := range []string{"must", "filter"} {
+		for _, subQuery := range asList(boolQuery[occur]) {
+			matches, err := matchesQuery(subQuery, id, doc)
+			if err != nil || !matches {
+				return false, err
+			}
+		}
+	}
+
+	for _, subQuery := range asList(boolQuery["must_not"]) {
+		matches, err := matchesQuery(subQuery, id, doc)
+		if err != nil || matches {
+			return false, err
+		}
+	}
+
+	shouldQueries := asList(boolQuery["should"])
+	// without must or filter clauses at least one should clause has to match
+	hasRequiredClauses := len(asList(boolQuery["must"])) > 0 || len(asList(boolQuery["filter"])) > 0
+	if len(shouldQueries) == 0 || hasRequiredClauses {
+		return true, nil
+	}
+
+	for _, subQuery := range shouldQueries {
+		matches, err := matchesQuery(subQuery, id, doc)
+		if err != nil {
+			return false, err
+		}
+		if matches {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func matchesRange(clause interface{}, doc objectsMap) (bool, error) {
+	for field, bounds := range asObject(clause) {
+		matches := false
+		for _, docValue := range fieldValues(doc, field) {
+			inRange, err := valueInRange(docValue, asObject(bounds))
+			if err != nil {
+				return false, err
+			}
+			if inRange {
+				matches = true
+				break
+			}
+		}
+		if !matches {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func valueInRange(docValue interface{}, bounds objectsMap) (bool, error) {
+	for operator, bound := range bounds {
+		cmp := compareCanonical(docValue, bound)
+		switch operator {
+		case "gt":
+			if cmp <= 0 {
+				return false, nil
+			}
+		case "gte":
+			if cmp < 0 {
+				return false, nil
+			}
+		case "lt":
+			if cmp >= 0 {
+				return false, nil
+			}
+		case "lte":
+			if cmp > 0 {
+				return false, nil
+			}
+		case "format", "boost":
+		default:
+			return false, fmt.Errorf("%w: range operator %s", ErrUnsupportedQuery, operator)
+		}
+	}
+
+	return true, nil
+}
+
+// fieldValues returns the values of the field, following the dotted path through the nested objects and the arrays
+func fieldValues(value interface{}, field string) []interface{} {
+	if field == "" {
+		switch v := value.(type) {
+		case nil:
+			return nil
+		case []interface{}:
+			values := make([]interface{}, 0, len(v))
+			for _, item := range v {
+				values = append(values, fieldValues(item, "")...)
+			}
+			return values
+		default:
+			return []interface{}{v}
+		}
+	}
+
+	switch v := value.(type) {
+	case objectsMap:
+		// the field names can also contain dots
+		fieldValue, found := v[field]
+		if found {
+			return fieldValues(fieldValue, "")
+		}
+
+		name, rest, hasRest := strings.Cut(field, ".")
+		if !hasRest {
+			return nil
+		}
+		return fieldValues(v[name], rest)
+	case []interface{}:
+		values := make([]interface{}, 0)
+		for _, item := range v {
+			values = append(values, fieldValues(item, field)...)
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// valuesEqual compares the values like the keyword and numeric fields are compared, without any text analysis. The
+// numbers and the strings that hold numbers are compared by value
+func valuesEqual(docValue interface{}, queryValue interface{}) bool {
+	return canonicalValue(docValue) == canonicalValue(queryValue)
+}
+
+func canonicalValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		number, isNumber := parseNumber(v)
+		if isNumber {
+			return number.String()
+		}
+		return v
+	case json.Number:
+		number, isNumber := parseNumber(v.String())
+		if isNumber {
+			return number.String()
+		}
+		return v.String()
+	case float64:
+		return canonicalValue(strconv.FormatFloat(v, 'f', -1, 64))
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func parseNumber(value string) (*big.Float, bool) {
+	if value == "" {
+		return nil, false
+	}
+
+	number, ok := new(big.Float).SetPrec(256).SetString(value)
+	return number, ok
+}
+
+func compareCanonical(docValue interface{}, bound interface{}) int {
+	docNumber, docIsNumber := parseNumber(canonicalValue(docValue))
+	boundNumber, boundIsNumber := parseNumber(canonicalValue(bound))
+	if docIsNumber && boundIsNumber {
+		return docNumber.Cmp(boundNumber)
+	}
+
+	return strings.Compare(canonicalValue(docValue), canonicalValue(bound))
+}
+
+func asObject(value interface{}) objectsMap {
+	object, _ := value.(objectsMap)
+	return object
+}
+
+func asList(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
@@ -0,0 +1,223 @@
+package mirror
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+)
+
+// ClusterHealth holds the health of a mirror cluster
+type ClusterHealth struct {
+	Name            string `json:"name"`
+	Healthy         bool   `json:"healthy"`
+	OutOfSync       bool   `json:"outOfSync"`
+	PendingRequests int    `json:"pendingRequests"`
+	PendingBytes    uint64 `json:"pendingBytes"`
+	LastError       string `json:"lastError,omitempty"`
+}
+
+// operation is a write request that can be sent to any of the clusters
+type operation struct {
+	name string
+	size uint64
+	do   func(ctx context.Context, client elasticproc.DatabaseClientHandler) error
+}
+
+// cluster is a mirror cluster together with the writes it has not received yet
+type cluster struct {
+	name          string
+	client        elasticproc.DatabaseClientHandler
+	maxBufferSize uint64
+	statusMetrics core.StatusMetricsHandler
+	newWrites     chan struct{}
+
+	mut          sync.Mutex
+	healthy      bool
+	outOfSync    bool
+	pending      []*operation
+	pendingBytes uint64
+	lastError    string
+}
+
+func newCluster(name string, client elasticproc.DatabaseClientHandler, maxBufferSize uint64, statusMetrics core.StatusMetricsHandler) *cluster {
+	return &cluster{
+		name:          name,
+		client:        client,
+		maxBufferSize: maxBufferSize,
+		statusMetrics: statusMetrics,
+		newWrites:     make(chan struct{}, 1),
+		healthy:       true,
+	}
+}
+
+// write queues the operation for the cluster, without waiting for it to be sent. The queued writes are sent by the
+// goroutine of the cluster in the same order as they were received by the primary cluster
+func (c *cluster) write(op *operation) {
+	c.mut.Lock()
+	c.bufferUnprotected(op)
+	c.mut.Unlock()
+
+	c.reportHealth()
+
+	select {
+	case c.newWrites <- struct{}{}:
+	default:
+	}
+}
+
+func (c *cluster) bufferUnprotected(op *operation) {
+	if c.outOfSync {
+		return
+	}
+
+	if c.pendingBytes+op.size > c.maxBufferSize {
+		log.Error("the writes buffered for the mirror cluster exceeded the maximum size and were dropped, "+
+			"no more writes are sent to the cluster until it is reindexed and the indexer is restarted",
+			"cluster", c.name, "pending requests", len(c.pending), "pending bytes", c.pendingBytes)
+		c.outOfSync = true
+		c.pending = nil
+		c.pendingBytes = 0
+		return
+	}
+
+	c.pending = append(c.pending, op)
+	c.pendingBytes += op.size
+}
+
+// run sends the queued writes until the mirror client is closed. The new writes are sent as soon as they are queued,
+// while a cluster that fell behind is retried only at the catch-up interval. When the mirror client is closed, the
+// queued writes are flushed until the context is cancelled
+func (c *cluster) run(ctx context.Context, closing <-chan struct{}, catchUpInterval time.Duration) {
+	ticker := time.NewTicker(catchUpInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closing:
+			c.flush(ctx, ticker.C)
+			return
+		case <-c.newWrites:
+			if c.isHealthy() {
+				c.sendPending(ctx)
+			}
+		case <-ticker.C:
+			c.sendPending(ctx)
+		}
+	}
+}
+
+// flush sends the queued writes, retrying them at the catch-up interval, until the cluster is up-to-date or the
+// context is cancelled
+func (c *cluster) flush(ctx context.Context, catchUp <-chan time.Time) {
+	for {
+		c.sendPending(ctx)
+		if c.numPending() == 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-catchUp:
+		}
+	}
+}
+
+func (c *cluster) numPending() int {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return len(c.pending)
+}
+
+func (c *cluster) isHealthy() bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return c.healthy
+}
+
+// sendPending sends the queued writes, in order, until one of them fails or the cluster is up-to-date. The lock is
+// released while a request is in flight, so the new writes are queued instead of waiting for it
+func (c *cluster) sendPending(ctx context.Context) {
+	defer c.reportHealth()
+
+	for {
+		c.mut.Lock()
+		if c.outOfSync {
+			c.mut.Unlock()
+			return
+		}
+		if len(c.pending) == 0 {
+			if !c.healthy {
+				log.Info("mirror cluster caught up", "cluster", c.name)
+			}
+			c.healthy = true
+			c.mut.Unlock()
+			return
+		}
+		op := c.pending[0]
+		c.mut.Unlock()
+
+		err := op.do(ctx, c.client)
+		if ctx.Err() != nil {
+			return
+		}
+
+		c.mut.Lock()
+		if err != nil {
+			if c.healthy {
+				log.Warn("mirror cluster fell behind, the writes are buffered until it catches up",
+					"cluster", c.name, "request", op.name, "error", err.Error())
+			} else {
+				log.Debug("mirror cluster is still behind", "cluster", c.name, "request", op.name, "error", err.Error())
+			}
+			c.healthy = false
+			c.lastError = err.Error()
+			c.mut.Unlock()
+			return
+		}
+
+		// the buffer could have been dropped while the request was in flight
+		if len(c.pending) > 0 && c.pending[0] == op {
+			c.pending[0] = nil
+			c.pending = c.pending[1:]
+			c.pendingBytes -= op.size
+		}
+		c.mut.Unlock()
+	}
+}
+
+func (c *cluster) health() ClusterHealth {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return ClusterHealth{
+		Name:            c.name,
+		Healthy:         c.healthy,
+		OutOfSync:       c.outOfSync,
+		PendingRequests: len(c.pending),
+		PendingBytes:    c.pendingBytes,
+		LastError:       c.lastError,
+	}
+}
+
+func (c *cluster) reportHealth() {
+	if check.IfNil(c.statusMetrics) {
+		return
+	}
+
+	health := c.health()
+	c.statusMetrics.SetMirrorClusterHealth(metrics.ArgsMirrorClusterHealth{
+		Cluster:         health.Name,
+		Healthy:         health.Healthy,
+		OutOfSync:       health.OutOfSync,
+		PendingRequests: uint64(health.PendingRequests),
+		PendingBytes:    health.PendingBytes,
+	})
+}
@@ -0,0 +1,12 @@
+package mirror
+
+import "errors"
+
+// ErrNilDatabaseClient signals that a nil database client has been provided
+var ErrNilDatabaseClient = errors.New("nil database client")
+
+// ErrNoMirrorClusters signals that no cluster has been provided to mirror the writes to
+var ErrNoMirrorClusters = errors.New("no mirror clusters")
+
+// ErrInvalidCatchUpInterval signals that an invalid catch-up interval has been provided
+var ErrInvalidCatchUpInterval = errors.New("invalid catch-up interval")
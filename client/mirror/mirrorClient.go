@@ -0,0 +1,296 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const (
+	defaultMaxBufferSize = 256 * 1024 * 1024 // 256MB
+	defaultFlushTimeout  = 30 * time.Second
+)
+
+var log = logger.GetOrCreate("indexer/client/mirror")
+
+// ArgsMirrorClient holds all the components needed to create a new instance of mirrorClient
+type ArgsMirrorClient struct {
+	Primary         elasticproc.DatabaseClientHandler
+	Mirrors         map[string]elasticproc.DatabaseClientHandler
+	MaxBufferSize   uint64
+	CatchUpInterval time.Duration
+	FlushTimeout    time.Duration
+	StatusMetrics   core.StatusMetricsHandler
+}
+
+type mirrorClient struct {
+	elasticproc.DatabaseClientHandler
+	clusters     []*cluster
+	cancel       context.CancelFunc
+	closing      chan struct{}
+	flushTimeout time.Duration
+	wg           sync.WaitGroup
+	closeOnce    sync.Once
+}
+
+// NewMirrorClient will create a new instance of mirrorClient. Every write is sent to the primary database client and,
+// when it succeeds, it is queued for all the mirror clusters. Every mirror cluster has its own goroutine that sends
+// the queued writes in order, so a slow or unavailable mirror cluster never delays the primary cluster. The reads are
+// only sent to the primary database client. The health of the mirror clusters is reported in the status metrics. A
+// mirror cluster whose queued writes exceed the maximum buffer size is out of sync: its queued writes are dropped and
+// no more writes are sent to it, until it is reindexed and the indexer is restarted
+func NewMirrorClient(args ArgsMirrorClient) (*mirrorClient, error) {
+	if check.IfNil(args.Primary) {
+		return nil, ErrNilDatabaseClient
+	}
+	if len(args.Mirrors) == 0 {
+		return nil, ErrNoMirrorClusters
+	}
+	if args.CatchUpInterval <= 0 {
+		return nil, ErrInvalidCatchUpInterval
+	}
+
+	maxBufferSize := args.MaxBufferSize
+	if maxBufferSize == 0 {
+		maxBufferSize = defaultMaxBufferSize
+	}
+	flushTimeout := args.FlushTimeout
+	if flushTimeout <= 0 {
+		flushTimeout = defaultFlushTimeout
+	}
+
+	clusters := make([]*cluster, 0, len(args.Mirrors))
+	for name, client := range args.Mirrors {
+		if check.IfNil(client) {
+			return nil, ErrNilDatabaseClient
+		}
+		clusters = append(clusters, newCluster(name, client, maxBufferSize, args.StatusMetrics))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mc := &mirrorClient{
+		DatabaseClientHandler: args.Primary,
+		clusters:              clusters,
+		cancel:                cancel,
+		closing:               make(chan struct{}),
+		flushTimeout:          flushTimeout,
+	}
+	for _, c := range clusters {
+		c.reportHealth()
+
+		mc.wg.Add(1)
+		go func(c *cluster) {
+			defer mc.wg.Done()
+			c.run(ctx, mc.closing, args.CatchUpInterval)
+		}(c)
+	}
+
+	return mc, nil
+}
+
+// mirror queues the operation for all the mirror clusters. The operation is sent later by the goroutine of every
+// cluster, with the context of the mirror client, so it is not bound to the context of the request that was sent to
+// the primary cluster. The duration of the mirrored requests is bounded by the timeouts of the mirror clients
+func (mc *mirrorClient) mirror(op *operation) {
+	for _, c := range mc.clusters {
+		c.write(op)
+	}
+}
+
+// DoBulkRequest will send the bulk request to the primary cluster and then to the mirror clusters
+func (mc *mirrorClient) DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error {
+	body := copyBytes(buff)
+	err := mc.DatabaseClientHandler.DoBulkRequest(ctx, buff, index)
+	if err != nil {
+		return err
+	}
+
+	mc.mirror(&operation{
+		name: "bulk",
+		size: uint64(len(body)),
+		do: func(ctx context.Context, client elasticproc.DatabaseClientHandler) error {
+			return client.DoBulkRequest(ctx, bytes.NewBuffer(body), index)
+		},
+	})
+
+	return nil
+}
+
+// DoQueryRemove will send the delete by query request to the primary cluster and then to the mirror clusters
+func (mc *mirrorClient) DoQueryRemove(ctx context.Context, index string, buff *bytes.Buffer) error {
+	body := copyBytes(buff)
+	err := mc.DatabaseClientHandler.DoQueryRemove(ctx, index, buff)
+	if err != nil {
+		return err
+	}
+
+	mc.mirror(&operation{
+		name: "delete by query",
+		size: uint64(len(body)),
+		do: func(ctx context.Context, client elasticproc.DatabaseClientHandler) error {
+			return client.DoQueryRemove(ctx, index, bytes.NewBuffer(body))
+		},
+	})
+
+	return nil
+}
+
+// UpdateByQuery will send the update by query request to the primary cluster and then to the mirror clusters
+func (mc *mirrorClient) UpdateByQuery(ctx context.Context, index string, buff *bytes.Buffer) error {
+	body := copyBytes(buff)
+	err := mc.DatabaseClientHandler.UpdateByQuery(ctx, index, buff)
+	if err != nil {
+		return err
+	}
+
+	mc.mirror(&operation{
+		name: "update by query",
+		size: uint64(len(body)),
+		do: func(ctx context.Context, client elasticproc.DatabaseClientHandler) error {
+			return client.UpdateByQuery(ctx, index, bytes.NewBuffer(body))
+		},
+	})
+
+	return nil
+}
+
+// PutMappings will put the mappings in the primary cluster and then in the mirror clusters
+func (mc *mirrorClient) PutMappings(indexName string, mappings *bytes.Buffer) error {
+	body := copyBytes(mappings)
+	err := mc.DatabaseClientHandler.PutMappings(indexName, mappings)
+	if err != nil {
+		return err
+	}
+
+	mc.mirror(&operation{
+		name: "put mappings",
+		size: uint64(len(body)),
+		do: func(_ context.Context, client elasticproc.DatabaseClientHandler) error {
+			return client.PutMappings(indexName, bytes.NewBuffer(body))
+		},
+	})
+
+	return nil
+}
+
+// CheckAndCreateIndex will create the index in the primary cluster and then in the mirror clusters
+func (mc *mirrorClient) CheckAndCreateIndex(index string) error {
+	err := mc.DatabaseClientHandler.CheckAndCreateIndex(index)
+	if err != nil {
+		return err
+	}
+
+	mc.mirror(&operation{
+		name: "create index",
+		do: func(_ context.Context, client elasticproc.DatabaseClientHandler) error {
+			return client.CheckAndCreateIndex(index)
+		},
+	})
+
+	return nil
+}
+
+// CheckAndCreateAlias will create the alias in the primary cluster and then in the mirror clusters
+func (mc *mirrorClient) CheckAndCreateAlias(alias string, index string) error {
+	err := mc.DatabaseClientHandler.CheckAndCreateAlias(alias, index)
+	if err != nil {
+		return err
+	}
+
+	mc.mirror(&operation{
+		name: "create alias",
+		do: func(_ context.Context, client elasticproc.DatabaseClientHandler) error {
+			return client.CheckAndCreateAlias(alias, index)
+		},
+	})
+
+	return nil
+}
+
+// CheckAndCreateTemplate will create the template in the primary cluster and then in the mirror clusters
+func (mc *mirrorClient) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
+	body := copyBytes(template)
+	err := mc.DatabaseClientHandler.CheckAndCreateTemplate(templateName, template)
+	if err != nil {
+		return err
+	}
+
+	mc.mirror(&operation{
+		name: "create template",
+		size: uint64(len(body)),
+		do: func(_ context.Context, client elasticproc.DatabaseClientHandler) error {
+			return client.CheckAndCreateTemplate(templateName, bytes.NewBuffer(body))
+		},
+	})
+
+	return nil
+}
+
+// CheckAndCreatePolicy will create the policy in the primary cluster and then in the mirror clusters
+func (mc *mirrorClient) CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error {
+	body := copyBytes(policy)
+	err := mc.DatabaseClientHandler.CheckAndCreatePolicy(policyName, policy)
+	if err != nil {
+		return err
+	}
+
+	mc.mirror(&operation{
+		name: "create policy",
+		size: uint64(len(body)),
+		do: func(_ context.Context, client elasticproc.DatabaseClientHandler) error {
+			return client.CheckAndCreatePolicy(policyName, bytes.NewBuffer(body))
+		},
+	})
+
+	return nil
+}
+
+// GetClustersHealth returns the health of every mirror cluster
+func (mc *mirrorClient) GetClustersHealth() []ClusterHealth {
+	health := make([]ClusterHealth, 0, len(mc.clusters))
+	for _, c := range mc.clusters {
+		health = append(health, c.health())
+	}
+
+	return health
+}
+
+// Close will flush the queued writes to the mirror clusters and then will stop their goroutines. The writes that
+// are not sent within the flush timeout are lost, and the mirror clusters that did not receive them are out of sync
+func (mc *mirrorClient) Close() error {
+	mc.closeOnce.Do(func() {
+		close(mc.closing)
+		timer := time.AfterFunc(mc.flushTimeout, mc.cancel)
+		mc.wg.Wait()
+		timer.Stop()
+		mc.cancel()
+
+		for _, health := range mc.GetClustersHealth() {
+			if health.PendingRequests > 0 {
+				log.Error("the queued writes could not be sent to the mirror cluster on close, the cluster has to be reindexed",
+					"cluster", health.Name, "pending requests", health.PendingRequests, "last error", health.LastError)
+			}
+		}
+	})
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (mc *mirrorClient) IsInterfaceNil() bool {
+	return mc == nil
+}
+
+func copyBytes(buff *bytes.Buffer) []byte {
+	if buff == nil {
+		return nil
+	}
+
+	return append([]byte(nil), buff.Bytes()...)
+}
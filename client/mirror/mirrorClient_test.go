@@ -0,0 +1,324 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingCluster struct {
+	mut      sync.Mutex
+	bodies   []string
+	failWith error
+}
+
+func (rc *recordingCluster) setError(err error) {
+	rc.mut.Lock()
+	rc.failWith = err
+	rc.mut.Unlock()
+}
+
+func (rc *recordingCluster) getBodies() []string {
+	rc.mut.Lock()
+	defer rc.mut.Unlock()
+
+	return append([]string(nil), rc.bodies...)
+}
+
+func (rc *recordingCluster) record(buff *bytes.Buffer) error {
+	rc.mut.Lock()
+	defer rc.mut.Unlock()
+
+	if rc.failWith != nil {
+		return rc.failWith
+	}
+	rc.bodies = append(rc.bodies, buff.String())
+
+	return nil
+}
+
+func (rc *recordingCluster) stub() *mock.DatabaseWriterStub {
+	return &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, _ string) error {
+			return rc.record(buff)
+		},
+		DoQueryRemoveCalled: func(_ string, body *bytes.Buffer) error {
+			return rc.record(body)
+		},
+		UpdateByQueryCalled: func(_ string, body *bytes.Buffer) error {
+			return rc.record(body)
+		},
+	}
+}
+
+func TestNewMirrorClient(t *testing.T) {
+	t.Parallel()
+
+	mirrors := map[string]elasticproc.DatabaseClientHandler{"m": &mock.DatabaseWriterStub{}}
+
+	mc, err := NewMirrorClient(ArgsMirrorClient{Mirrors: mirrors, CatchUpInterval: time.Second})
+	require.Nil(t, mc)
+	require.Equal(t, ErrNilDatabaseClient, err)
+
+	_, err = NewMirrorClient(ArgsMirrorClient{Primary: &mock.DatabaseWriterStub{}, CatchUpInterval: time.Second})
+	require.Equal(t, ErrNoMirrorClusters, err)
+
+	_, err = NewMirrorClient(ArgsMirrorClient{Primary: &mock.DatabaseWriterStub{}, Mirrors: map[string]elasticproc.DatabaseClientHandler{"m": nil}, CatchUpInterval: time.Second})
+	require.Equal(t, ErrNilDatabaseClient, err)
+
+	_, err = NewMirrorClient(ArgsMirrorClient{Primary: &mock.DatabaseWriterStub{}, Mirrors: mirrors})
+	require.Equal(t, ErrInvalidCatchUpInterval, err)
+
+	mc, err = NewMirrorClient(ArgsMirrorClient{Primary: &mock.DatabaseWriterStub{}, Mirrors: mirrors, CatchUpInterval: time.Second})
+	require.Nil(t, err)
+	require.False(t, mc.IsInterfaceNil())
+	require.Nil(t, mc.Close())
+	require.Nil(t, mc.Close())
+}
+
+func TestMirrorClient_WritesAreSentToAllClusters(t *testing.T) {
+	t.Parallel()
+
+	primary, mirror1, mirror2 := &recordingCluster{}, &recordingCluster{}, &recordingCluster{}
+	multiGetCalls := 0
+	primaryStub := primary.stub()
+	primaryStub.DoMultiGetCalled = func(_ []string, _ string, _ bool, _ interface{}) error {
+		multiGetCalls++
+		return nil
+	}
+	mirror1Stub := mirror1.stub()
+	mirror1Stub.DoMultiGetCalled = func(_ []string, _ string, _ bool, _ interface{}) error {
+		require.Fail(t, "the reads should only be sent to the primary cluster")
+		return nil
+	}
+
+	mc, _ := NewMirrorClient(ArgsMirrorClient{
+		Primary:         primaryStub,
+		Mirrors:         map[string]elasticproc.DatabaseClientHandler{"m1": mirror1Stub, "m2": mirror2.stub()},
+		CatchUpInterval: time.Hour,
+	})
+	defer func() {
+		_ = mc.Close()
+	}()
+
+	err := mc.DoBulkRequest(context.Background(), bytes.NewBufferString("bulk"), "")
+	require.Nil(t, err)
+	err = mc.DoQueryRemove(context.Background(), "blocks", bytes.NewBufferString("remove"))
+	require.Nil(t, err)
+	err = mc.UpdateByQuery(context.Background(), "tokens", bytes.NewBufferString("update"))
+	require.Nil(t, err)
+	err = mc.DoMultiGet(context.Background(), []string{"id"}, "tokens", true, nil)
+	require.Nil(t, err)
+
+	expected := []string{"bulk", "remove", "update"}
+	require.Equal(t, expected, primary.getBodies())
+	require.Eventually(t, func() bool {
+		return len(mirror1.getBodies()) == len(expected) && len(mirror2.getBodies()) == len(expected)
+	}, time.Second, time.Millisecond)
+	require.Equal(t, expected, mirror1.getBodies())
+	require.Equal(t, expected, mirror2.getBodies())
+	require.Equal(t, 1, multiGetCalls)
+}
+
+func TestMirrorClient_PrimaryErrorIsNotMirrored(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("primary error")
+	primary, mirror := &recordingCluster{failWith: expectedErr}, &recordingCluster{}
+	mc, _ := NewMirrorClient(ArgsMirrorClient{
+		Primary:         primary.stub(),
+		Mirrors:         map[string]elasticproc.DatabaseClientHandler{"m": mirror.stub()},
+		CatchUpInterval: time.Hour,
+	})
+	defer func() {
+		_ = mc.Close()
+	}()
+
+	err := mc.DoBulkRequest(context.Background(), bytes.NewBufferString("bulk"), "")
+	require.Equal(t, expectedErr, err)
+	require.Empty(t, mirror.getBodies())
+}
+
+func TestMirrorClient_ClusterBehindCatchesUp(t *testing.T) {
+	t.Parallel()
+
+	primary, mirror := &recordingCluster{}, &recordingCluster{failWith: errors.New("unavailable")}
+	mc, _ := NewMirrorClient(ArgsMirrorClient{
+		Primary:         primary.stub(),
+		Mirrors:         map[string]elasticproc.DatabaseClientHandler{"m": mirror.stub()},
+		CatchUpInterval: 10 * time.Millisecond,
+	})
+	defer func() {
+		_ = mc.Close()
+	}()
+
+	err := mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b1"), "")
+	require.Nil(t, err)
+	err = mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b2"), "")
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		return !mc.GetClustersHealth()[0].Healthy
+	}, time.Second, time.Millisecond)
+	health := mc.GetClustersHealth()
+	require.Equal(t, []ClusterHealth{
+		{Name: "m", Healthy: false, PendingRequests: 2, PendingBytes: 4, LastError: "unavailable"},
+	}, health)
+
+	mirror.setError(nil)
+	require.Eventually(t, func() bool {
+		return mc.GetClustersHealth()[0].Healthy
+	}, time.Second, 5*time.Millisecond)
+
+	err = mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b3"), "")
+	require.Nil(t, err)
+	require.Eventually(t, func() bool {
+		return len(mirror.getBodies()) == 3
+	}, time.Second, time.Millisecond)
+	require.Equal(t, []string{"b1", "b2", "b3"}, mirror.getBodies())
+	require.Equal(t, primary.getBodies(), mirror.getBodies())
+	require.Equal(t, 0, mc.GetClustersHealth()[0].PendingRequests)
+}
+
+func TestMirrorClient_BufferOverflowMarksClusterOutOfSync(t *testing.T) {
+	t.Parallel()
+
+	mirror := &recordingCluster{failWith: errors.New("unavailable")}
+	mc, _ := NewMirrorClient(ArgsMirrorClient{
+		Primary:         &mock.DatabaseWriterStub{},
+		Mirrors:         map[string]elasticproc.DatabaseClientHandler{"m": mirror.stub()},
+		MaxBufferSize:   5,
+		CatchUpInterval: 10 * time.Millisecond,
+	})
+	defer func() {
+		_ = mc.Close()
+	}()
+
+	_ = mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b1"), "")
+	_ = mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b2"), "")
+	require.False(t, mc.GetClustersHealth()[0].OutOfSync)
+
+	_ = mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b3"), "")
+	health := mc.GetClustersHealth()[0]
+	require.True(t, health.OutOfSync)
+	require.Equal(t, 0, health.PendingRequests)
+	require.Equal(t, uint64(0), health.PendingBytes)
+
+	// the writes are not sent to the cluster anymore, even after it is available again
+	mirror.setError(nil)
+	_ = mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b4"), "")
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, mirror.getBodies())
+	require.Equal(t, 0, mc.GetClustersHealth()[0].PendingRequests)
+}
+
+func TestMirrorClient_CloseShouldFlushTheQueuedWrites(t *testing.T) {
+	t.Parallel()
+
+	mirror := &recordingCluster{failWith: errors.New("unavailable")}
+	mc, _ := NewMirrorClient(ArgsMirrorClient{
+		Primary:         &mock.DatabaseWriterStub{},
+		Mirrors:         map[string]elasticproc.DatabaseClientHandler{"m": mirror.stub()},
+		CatchUpInterval: 10 * time.Millisecond,
+	})
+
+	_ = mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b1"), "")
+	_ = mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b2"), "")
+	require.Eventually(t, func() bool {
+		return !mc.GetClustersHealth()[0].Healthy
+	}, time.Second, time.Millisecond)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		mirror.setError(nil)
+	}()
+	require.Nil(t, mc.Close())
+	require.Equal(t, []string{"b1", "b2"}, mirror.getBodies())
+	require.Equal(t, 0, mc.GetClustersHealth()[0].PendingRequests)
+}
+
+func TestMirrorClient_CloseShouldStopFlushingAfterTheFlushTimeout(t *testing.T) {
+	t.Parallel()
+
+	mirror := &recordingCluster{failWith: errors.New("unavailable")}
+	mc, _ := NewMirrorClient(ArgsMirrorClient{
+		Primary:         &mock.DatabaseWriterStub{},
+		Mirrors:         map[string]elasticproc.DatabaseClientHandler{"m": mirror.stub()},
+		CatchUpInterval: 10 * time.Millisecond,
+		FlushTimeout:    50 * time.Millisecond,
+	})
+
+	_ = mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b1"), "")
+
+	start := time.Now()
+	require.Nil(t, mc.Close())
+	require.Less(t, time.Since(start), time.Second)
+	require.Empty(t, mirror.getBodies())
+	require.Equal(t, 1, mc.GetClustersHealth()[0].PendingRequests)
+}
+
+func TestMirrorClient_SlowClusterShouldNotDelayThePrimaryCluster(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	mirrorStub := &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(_ *bytes.Buffer, _ string) error {
+			<-release
+			return nil
+		},
+	}
+	mc, _ := NewMirrorClient(ArgsMirrorClient{
+		Primary:         &mock.DatabaseWriterStub{},
+		Mirrors:         map[string]elasticproc.DatabaseClientHandler{"m": mirrorStub},
+		CatchUpInterval: time.Hour,
+	})
+
+	for i := 0; i < 3; i++ {
+		err := mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b"), "")
+		require.Nil(t, err)
+	}
+	require.Equal(t, 3, mc.GetClustersHealth()[0].PendingRequests)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return mc.GetClustersHealth()[0].PendingRequests == 0
+	}, time.Second, time.Millisecond)
+	require.Nil(t, mc.Close())
+}
+
+func TestMirrorClient_ShouldReportTheHealthInTheStatusMetrics(t *testing.T) {
+	t.Parallel()
+
+	statusMetrics := metrics.NewStatusMetrics()
+	mirror := &recordingCluster{failWith: errors.New("unavailable")}
+	mc, _ := NewMirrorClient(ArgsMirrorClient{
+		Primary:         &mock.DatabaseWriterStub{},
+		Mirrors:         map[string]elasticproc.DatabaseClientHandler{"m": mirror.stub()},
+		CatchUpInterval: time.Hour,
+		FlushTimeout:    time.Millisecond,
+		StatusMetrics:   statusMetrics,
+	})
+	defer func() {
+		_ = mc.Close()
+	}()
+	require.Contains(t, statusMetrics.GetMetricsForPrometheus(), `mirror_clusters{operation="healthy",cluster="m"} 1`)
+
+	_ = mc.DoBulkRequest(context.Background(), bytes.NewBufferString("b1"), "")
+	require.Eventually(t, func() bool {
+		return strings.Contains(statusMetrics.GetMetricsForPrometheus(), `mirror_clusters{operation="healthy",cluster="m"} 0`)
+	}, time.Second, time.Millisecond)
+
+	prometheusMetrics := statusMetrics.GetMetricsForPrometheus()
+	require.Contains(t, prometheusMetrics, `mirror_clusters{operation="out_of_sync",cluster="m"} 0`)
+	require.Contains(t, prometheusMetrics, `mirror_clusters{operation="pending_requests",cluster="m"} 1`)
+	require.Contains(t, prometheusMetrics, `mirror_clusters{operation="pending_bytes",cluster="m"} 2`)
+}
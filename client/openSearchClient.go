@@ -0,0 +1,167 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+const (
+	openSearchDistribution     = "opensearch"
+	minOpenSearchMajorVersion  = 1
+	maxOpenSearchMajorVersion  = 2
+	openSearchISMPoliciesRoute = "/_plugins/_ism/policies/%s"
+)
+
+type clusterInfo struct {
+	Version struct {
+		Distribution string `json:"distribution"`
+		Number       string `json:"number"`
+	} `json:"version"`
+}
+
+type openSearchClient struct {
+	*elasticClient
+	version string
+}
+
+// NewOpenSearchClient will create a new instance of openSearchClient. The OpenSearch API is compatible with the
+// Elasticsearch 7.10 one, so the Elasticsearch client is used for all the requests, except the ones that handle the
+// Index State Management policies and the aliases. The version of the cluster is checked when the client is created
+func NewOpenSearchClient(cfg elasticsearch.Config) (*openSearchClient, error) {
+	ec, err := NewElasticClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := getOpenSearchVersion(ec.client)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("connected to OpenSearch cluster", "version", version)
+
+	return &openSearchClient{
+		elasticClient: ec,
+		version:       version,
+	}, nil
+}
+
+func getOpenSearchVersion(client *elasticsearch.Client) (string, error) {
+	res, err := client.Info()
+	if err != nil {
+		return "", err
+	}
+
+	info := &clusterInfo{}
+	err = parseResponse(res, info, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		return "", err
+	}
+
+	if info.Version.Distribution != openSearchDistribution {
+		return "", fmt.Errorf("%w, distribution: %s, version: %s", dataindexer.ErrNotOpenSearchCluster, info.Version.Distribution, info.Version.Number)
+	}
+
+	majorVersion, err := strconv.Atoi(strings.Split(info.Version.Number, ".")[0])
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", dataindexer.ErrUnsupportedOpenSearchVersion, info.Version.Number)
+	}
+	if majorVersion < minOpenSearchMajorVersion || majorVersion > maxOpenSearchMajorVersion {
+		return "", fmt.Errorf("%w: %s", dataindexer.ErrUnsupportedOpenSearchVersion, info.Version.Number)
+	}
+
+	return info.Version.Number, nil
+}
+
+// Version returns the version of the OpenSearch cluster
+func (osc *openSearchClient) Version() string {
+	return osc.version
+}
+
+// CheckAndCreatePolicy creates a new Index State Management policy if it does not already exist
+func (osc *openSearchClient) CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error {
+	exists, err := osc.policyExists(policyName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	req := newRequest(http.MethodPut, fmt.Sprintf(openSearchISMPoliciesRoute, policyName), policy)
+	req.Header[headerContentType] = headerContentTypeJSON
+	res, err := osc.client.Transport.Perform(req)
+	if err != nil {
+		return err
+	}
+
+	response := &esapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+	err = parseResponse(response, nil, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		return fmt.Errorf("%w: %s", dataindexer.ErrCouldNotCreatePolicy, err.Error())
+	}
+
+	return nil
+}
+
+func (osc *openSearchClient) policyExists(policyName string) (bool, error) {
+	req := newRequest(http.MethodGet, fmt.Sprintf(openSearchISMPoliciesRoute, policyName), nil)
+	res, err := osc.client.Transport.Perform(req)
+	if err != nil {
+		return false, err
+	}
+
+	response := &esapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}
+	defer func() {
+		_ = loadResponseBody(response.Body, nil)
+		_ = response.Body.Close()
+	}()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("cannot check the policy %s, status code: %d", policyName, response.StatusCode)
+	}
+}
+
+// CheckAndCreateAlias creates a new alias if it does not already exist. The index is set as the write index of the
+// alias, so the alias keeps pointing to the old indices after a rollover
+func (osc *openSearchClient) CheckAndCreateAlias(alias string, indexName string) error {
+	if osc.aliasExists(alias) {
+		return nil
+	}
+
+	res, err := osc.client.Indices.PutAlias(
+		[]string{indexName},
+		alias,
+		osc.client.Indices.PutAlias.WithBody(strings.NewReader(`{"is_write_index": true}`)),
+	)
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (osc *openSearchClient) IsInterfaceNil() bool {
+	return osc == nil
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	indexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createOpenSearchServer(t *testing.T, infoResponse string, handler http.HandlerFunc) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(infoResponse))
+			return
+		}
+
+		handler(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestNewOpenSearchClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("elasticsearch cluster should error", func(t *testing.T) {
+		t.Parallel()
+
+		ts := createOpenSearchServer(t, `{"version":{"number":"7.10.2"}}`, http.NotFound)
+		osClient, err := NewOpenSearchClient(elasticsearch.Config{Addresses: []string{ts.URL}})
+		require.Nil(t, osClient)
+		require.True(t, errors.Is(err, indexer.ErrNotOpenSearchCluster))
+	})
+
+	t.Run("unsupported version should error", func(t *testing.T) {
+		t.Parallel()
+
+		ts := createOpenSearchServer(t, `{"version":{"distribution":"opensearch","number":"3.0.0"}}`, http.NotFound)
+		osClient, err := NewOpenSearchClient(elasticsearch.Config{Addresses: []string{ts.URL}})
+		require.Nil(t, osClient)
+		require.True(t, errors.Is(err, indexer.ErrUnsupportedOpenSearchVersion))
+	})
+
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		ts := createOpenSearchServer(t, `{"version":{"distribution":"opensearch","number":"2.11.1"}}`, http.NotFound)
+		osClient, err := NewOpenSearchClient(elasticsearch.Config{Addresses: []string{ts.URL}, Logger: &logging.CustomLogger{}})
+		require.Nil(t, err)
+		require.False(t, osClient.IsInterfaceNil())
+		require.Equal(t, "2.11.1", osClient.Version())
+	})
+}
+
+func TestOpenSearchClient_CheckAndCreatePolicy(t *testing.T) {
+	t.Parallel()
+
+	createdPolicies := make(map[string]string)
+	ts := createOpenSearchServer(t, `{"version":{"distribution":"opensearch","number":"2.11.1"}}`, func(w http.ResponseWriter, r *http.Request) {
+		policyName := r.URL.Path[len("/_plugins/_ism/policies/"):]
+		switch r.Method {
+		case http.MethodGet:
+			_, found := createdPolicies[policyName]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"type":"status_exception"},"status":404}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			createdPolicies[policyName] = string(body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"_id":"` + policyName + `"}`))
+		}
+	})
+
+	osClient, _ := NewOpenSearchClient(elasticsearch.Config{Addresses: []string{ts.URL}, Logger: &logging.CustomLogger{}})
+
+	err := osClient.CheckAndCreatePolicy("rounds_policy", bytes.NewBufferString(`{"policy":{}}`))
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"rounds_policy": `{"policy":{}}`}, createdPolicies)
+
+	// an existing policy should not be overwritten
+	err = osClient.CheckAndCreatePolicy("rounds_policy", bytes.NewBufferString(`{"policy":{"changed":true}}`))
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"rounds_policy": `{"policy":{}}`}, createdPolicies)
+}
+
+func TestOpenSearchClient_CheckAndCreateAliasShouldSetWriteIndex(t *testing.T) {
+	t.Parallel()
+
+	var aliasBody string
+	ts := createOpenSearchServer(t, `{"version":{"distribution":"opensearch","number":"1.3.0"}}`, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		require.Equal(t, "/rounds-000001/_aliases/rounds", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		aliasBody = string(body)
+		_, _ = w.Write([]byte(`{"acknowledged":true}`))
+	})
+
+	osClient, _ := NewOpenSearchClient(elasticsearch.Config{Addresses: []string{ts.URL}, Logger: &logging.CustomLogger{}})
+	err := osClient.CheckAndCreateAlias("rounds", "rounds-000001")
+	require.Nil(t, err)
+	require.Equal(t, `{"is_write_index": true}`, aliasBody)
+}
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+// ArgsRetryPolicy holds the arguments needed to create a retry policy
+type ArgsRetryPolicy struct {
+	InitialInterval     time.Duration
+	MaxInterval         time.Duration
+	MaxElapsedTime      time.Duration
+	FailureThreshold    uint32
+	OpenCircuitDuration time.Duration
+	StatusMetrics       core.StatusMetricsHandler
+}
+
+// retryPolicy retries the requests rejected by an overloaded cluster with an exponential backoff and jitter, until the
+// max elapsed time is reached. After FailureThreshold consecutive rejected requests the circuit opens and the requests
+// fail fast until OpenCircuitDuration elapses. Then a single request is let through: the circuit closes if it is not
+// rejected and opens again otherwise
+type retryPolicy struct {
+	initialInterval     time.Duration
+	maxInterval         time.Duration
+	maxElapsedTime      time.Duration
+	failureThreshold    uint32
+	openCircuitDuration time.Duration
+	statusMetrics       core.StatusMetricsHandler
+
+	mut                 sync.Mutex
+	consecutiveFailures uint32
+	openUntil           time.Time
+	probing             bool
+}
+
+// NewRetryPolicy will create a new instance of retryPolicy. The status metrics handler is optional
+func NewRetryPolicy(args ArgsRetryPolicy) (*retryPolicy, error) {
+	if args.InitialInterval <= 0 || args.MaxInterval < args.InitialInterval {
+		return nil, fmt.Errorf("%w: the initial interval should be positive and lower than the max interval", dataindexer.ErrInvalidRetryPolicy)
+	}
+	if args.FailureThreshold > 0 && args.OpenCircuitDuration <= 0 {
+		return nil, fmt.Errorf("%w: the open circuit duration should be positive", dataindexer.ErrInvalidRetryPolicy)
+	}
+
+	return &retryPolicy{
+		initialInterval:     args.InitialInterval,
+		maxInterval:         args.MaxInterval,
+		maxElapsedTime:      args.MaxElapsedTime,
+		failureThreshold:    args.FailureThreshold,
+		openCircuitDuration: args.OpenCircuitDuration,
+		statusMetrics:       args.StatusMetrics,
+	}, nil
+}
+
+// newDisabledRetryPolicy returns a policy that sends every request once and never opens the circuit
+func newDisabledRetryPolicy() *retryPolicy {
+	return &retryPolicy{}
+}
+
+// do calls the operation until it is not rejected by the cluster, or the max elapsed time is reached. The last error
+// is returned
+func (rp *retryPolicy) do(ctx context.Context, operation func() error) error {
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		err := rp.allowRequest()
+		if err != nil {
+			rp.addMetric(ctx, request.CircuitOpenTopic, 0, 0)
+			return err
+		}
+
+		err = operation()
+		if !isBackOffError(err) {
+			rp.recordSuccess()
+			return err
+		}
+		rp.recordFailure()
+
+		delay := rp.computeDelay(attempt)
+		elapsed := time.Since(start)
+		if elapsed+delay > rp.maxElapsedTime {
+			if attempt > 0 {
+				return fmt.Errorf("%w, gave up after %d attempts in %s", err, attempt+1, elapsed.Truncate(time.Millisecond))
+			}
+			return err
+		}
+
+		log.Debug("the cluster rejected the request, retrying", "attempt", attempt+1, "delay", delay, "error", err.Error())
+		rp.addMetric(ctx, request.BackOffTopic, http.StatusTooManyRequests, delay)
+
+		err = waitWithContext(ctx, delay)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func isBackOffError(err error) bool {
+	return errors.Is(err, dataindexer.ErrBackOff)
+}
+
+// computeDelay doubles the initial interval with every attempt, up to the max interval, and picks a random delay between
+// half of it and all of it, so the clients rejected at the same time do not retry at the same time
+func (rp *retryPolicy) computeDelay(attempt int) time.Duration {
+	delay := rp.maxInterval
+	if attempt < 32 && rp.initialInterval<<attempt < rp.maxInterval {
+		delay = rp.initialInterval << attempt
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+func (rp *retryPolicy) allowRequest() error {
+	rp.mut.Lock()
+	defer rp.mut.Unlock()
+
+	if rp.openUntil.IsZero() {
+		return nil
+	}
+	if rp.probing || time.Now().Before(rp.openUntil) {
+		return dataindexer.ErrCircuitBreakerOpen
+	}
+
+	rp.probing = true
+	return nil
+}
+
+func (rp *retryPolicy) recordSuccess() {
+	rp.mut.Lock()
+	defer rp.mut.Unlock()
+
+	if !rp.openUntil.IsZero() {
+		log.Info("the cluster accepts the requests again, the circuit breaker is closed")
+	}
+
+	rp.consecutiveFailures = 0
+	rp.openUntil = time.Time{}
+	rp.probing = false
+}
+
+func (rp *retryPolicy) recordFailure() {
+	if rp.failureThreshold == 0 {
+		return
+	}
+
+	rp.mut.Lock()
+	defer rp.mut.Unlock()
+
+	rp.consecutiveFailures++
+	if !rp.probing && rp.consecutiveFailures < rp.failureThreshold {
+		return
+	}
+
+	rp.probing = false
+	rp.openUntil = time.Now().Add(rp.openCircuitDuration)
+	log.Warn("the cluster keeps rejecting the requests, the circuit breaker is open",
+		"consecutive failures", rp.consecutiveFailures, "duration", rp.openCircuitDuration)
+}
+
+// addMetric records the event under the provided topic, for the shard of the request found in the context
+func (rp *retryPolicy) addMetric(ctx context.Context, topic string, statusCode int, duration time.Duration) {
+	if check.IfNil(rp.statusMetrics) {
+		return
+	}
+
+	valueFromCtx := ctx.Value(request.ContextKey)
+	if valueFromCtx != nil {
+		_, shardIDStr := request.SplitTopicAndShardID(fmt.Sprintf("%s", valueFromCtx))
+		shardID, err := strconv.ParseUint(shardIDStr, 10, 32)
+		if err == nil {
+			topic = request.ExtendTopicWithShardID(topic, uint32(shardID))
+		}
+	}
+
+	rp.statusMetrics.AddIndexingData(metrics.ArgsAddIndexingData{
+		StatusCode: statusCode,
+		GotError:   true,
+		Topic:      topic,
+		Duration:   duration,
+	})
+}
+
+func waitWithContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
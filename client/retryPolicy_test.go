@@ -0,0 +1,236 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockRetryPolicyArgs() ArgsRetryPolicy {
+	return ArgsRetryPolicy{
+		InitialInterval:     time.Millisecond,
+		MaxInterval:         4 * time.Millisecond,
+		MaxElapsedTime:      time.Second,
+		FailureThreshold:    0,
+		OpenCircuitDuration: 0,
+	}
+}
+
+func backOffError() error {
+	return fmt.Errorf("%w, rejected", dataindexer.ErrBackOff)
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	args := createMockRetryPolicyArgs()
+	args.InitialInterval = 0
+	rp, err := NewRetryPolicy(args)
+	require.Nil(t, rp)
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidRetryPolicy))
+
+	args = createMockRetryPolicyArgs()
+	args.MaxInterval = args.InitialInterval / 2
+	_, err = NewRetryPolicy(args)
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidRetryPolicy))
+
+	args = createMockRetryPolicyArgs()
+	args.FailureThreshold = 3
+	_, err = NewRetryPolicy(args)
+	require.True(t, errors.Is(err, dataindexer.ErrInvalidRetryPolicy))
+
+	args.OpenCircuitDuration = time.Second
+	rp, err = NewRetryPolicy(args)
+	require.Nil(t, err)
+	require.NotNil(t, rp)
+}
+
+func TestRetryPolicy_ComputeDelay(t *testing.T) {
+	t.Parallel()
+
+	rp, _ := NewRetryPolicy(ArgsRetryPolicy{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second})
+	for attempt, maxDelay := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		maxDelay *= time.Millisecond
+		for i := 0; i < 10; i++ {
+			delay := rp.computeDelay(attempt)
+			require.GreaterOrEqual(t, delay, maxDelay/2)
+			require.LessOrEqual(t, delay, maxDelay)
+		}
+	}
+
+	require.LessOrEqual(t, rp.computeDelay(100), time.Second)
+}
+
+func TestRetryPolicy_RetriesOnlyTheBackOffErrors(t *testing.T) {
+	t.Parallel()
+
+	statusMetrics := metrics.NewStatusMetrics()
+	args := createMockRetryPolicyArgs()
+	args.StatusMetrics = statusMetrics
+	rp, _ := NewRetryPolicy(args)
+
+	ctx := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.BulkTopic, 1))
+	numCalls := 0
+	err := rp.do(ctx, func() error {
+		numCalls++
+		if numCalls < 3 {
+			return backOffError()
+		}
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 3, numCalls)
+
+	backOffMetrics := statusMetrics.GetMetrics()["req_back_off_1"]
+	require.NotNil(t, backOffMetrics)
+	require.Equal(t, uint64(2), backOffMetrics.OperationsCount)
+	require.Equal(t, uint64(2), backOffMetrics.ErrorsCount[http.StatusTooManyRequests])
+
+	expectedErr := errors.New("expected error")
+	numCalls = 0
+	err = rp.do(ctx, func() error {
+		numCalls++
+		return expectedErr
+	})
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, 1, numCalls)
+}
+
+func TestRetryPolicy_MaxElapsedTime(t *testing.T) {
+	t.Parallel()
+
+	args := createMockRetryPolicyArgs()
+	args.MaxElapsedTime = 20 * time.Millisecond
+	rp, _ := NewRetryPolicy(args)
+
+	start := time.Now()
+	numCalls := 0
+	err := rp.do(context.Background(), func() error {
+		numCalls++
+		return backOffError()
+	})
+	require.True(t, errors.Is(err, dataindexer.ErrBackOff))
+	require.Contains(t, err.Error(), "gave up")
+	require.Greater(t, numCalls, 1)
+	require.Less(t, time.Since(start), time.Second)
+
+	rp = newDisabledRetryPolicy()
+	numCalls = 0
+	err = rp.do(context.Background(), func() error {
+		numCalls++
+		return backOffError()
+	})
+	require.True(t, errors.Is(err, dataindexer.ErrBackOff))
+	require.Equal(t, 1, numCalls)
+}
+
+func TestRetryPolicy_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	rp, _ := NewRetryPolicy(createMockRetryPolicyArgs())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	numCalls := 0
+	err := rp.do(ctx, func() error {
+		numCalls++
+		return backOffError()
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 1, numCalls)
+}
+
+func TestRetryPolicy_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	statusMetrics := metrics.NewStatusMetrics()
+	args := createMockRetryPolicyArgs()
+	args.FailureThreshold = 3
+	args.OpenCircuitDuration = 50 * time.Millisecond
+	args.StatusMetrics = statusMetrics
+	rp, _ := NewRetryPolicy(args)
+
+	numCalls := 0
+	rejectAll := func() error {
+		numCalls++
+		return backOffError()
+	}
+
+	// the circuit opens after the third rejected attempt and the request fails without waiting for the max elapsed time
+	err := rp.do(context.Background(), rejectAll)
+	require.Equal(t, dataindexer.ErrCircuitBreakerOpen, err)
+	require.Equal(t, 3, numCalls)
+
+	err = rp.do(context.Background(), rejectAll)
+	require.Equal(t, dataindexer.ErrCircuitBreakerOpen, err)
+	require.Equal(t, 3, numCalls)
+	require.Equal(t, uint64(2), statusMetrics.GetMetrics()[request.CircuitOpenTopic].OperationsCount)
+
+	// after the open duration a single request is let through and, as it is rejected, the circuit opens again
+	time.Sleep(args.OpenCircuitDuration)
+	err = rp.do(context.Background(), rejectAll)
+	require.Equal(t, dataindexer.ErrCircuitBreakerOpen, err)
+	require.Equal(t, 4, numCalls)
+
+	// a request that is not rejected closes the circuit
+	time.Sleep(args.OpenCircuitDuration)
+	err = rp.do(context.Background(), func() error {
+		numCalls++
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 5, numCalls)
+
+	err = rp.do(context.Background(), func() error {
+		return nil
+	})
+	require.Nil(t, err)
+}
+
+func TestElasticClient_RetryPolicyRetriesTheRejectedRequests(t *testing.T) {
+	t.Parallel()
+
+	numRequests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numRequests++
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		if numRequests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"type":"es_rejected_execution_exception"},"status":429}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"took":1,"errors":false,"items":[{"index":{"_index":"transactions","_id":"t1","status":201}}]}`))
+	}))
+	defer ts.Close()
+
+	esClient, _ := NewElasticClient(elasticsearch.Config{
+		Addresses: []string{ts.URL},
+		Logger:    &logging.CustomLogger{},
+	})
+
+	body := testBulkAction1 + "\n" + testBulkDoc1 + "\n"
+	err := esClient.DoBulkRequest(context.Background(), bytes.NewBufferString(body), "")
+	require.True(t, errors.Is(err, dataindexer.ErrBackOff))
+	require.Equal(t, 1, numRequests)
+
+	err = esClient.EnableRetryPolicy(createMockRetryPolicyArgs())
+	require.Nil(t, err)
+
+	numRequests = 0
+	err = esClient.DoBulkRequest(context.Background(), bytes.NewBufferString(body), "")
+	require.Nil(t, err)
+	require.Equal(t, 2, numRequests)
+}
@@ -0,0 +1,9 @@
+package timeout
+
+import "errors"
+
+// ErrNilDatabaseClient signals that a nil database client has been provided
+var ErrNilDatabaseClient = errors.New("nil database client")
+
+// ErrRequestTimeout signals that a request did not complete before its timeout
+var ErrRequestTimeout = errors.New("request timeout")
@@ -0,0 +1,131 @@
+package timeout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+)
+
+// ArgsTimeoutClient holds all the components needed to create a new instance of timeoutClient. A zero timeout
+// disables the timeout of the operation
+type ArgsTimeoutClient struct {
+	DatabaseClient       elasticproc.DatabaseClientHandler
+	BulkTimeout          time.Duration
+	MultiGetTimeout      time.Duration
+	ScrollTimeout        time.Duration
+	DeleteByQueryTimeout time.Duration
+	UpdateByQueryTimeout time.Duration
+}
+
+type timeoutClient struct {
+	elasticproc.DatabaseClientHandler
+	bulkTimeout          time.Duration
+	multiGetTimeout      time.Duration
+	scrollTimeout        time.Duration
+	deleteByQueryTimeout time.Duration
+	updateByQueryTimeout time.Duration
+}
+
+// NewTimeoutClient will create a new instance of timeoutClient. Every request sent through it is cancelled when its
+// operation does not complete in the configured timeout. The timeout covers the whole operation, including the retries
+// of the database client and, for the scroll requests, all the pages. The count requests use the multi-get timeout
+func NewTimeoutClient(args ArgsTimeoutClient) (*timeoutClient, error) {
+	if check.IfNil(args.DatabaseClient) {
+		return nil, ErrNilDatabaseClient
+	}
+
+	return &timeoutClient{
+		DatabaseClientHandler: args.DatabaseClient,
+		bulkTimeout:           args.BulkTimeout,
+		multiGetTimeout:       args.MultiGetTimeout,
+		scrollTimeout:         args.ScrollTimeout,
+		deleteByQueryTimeout:  args.DeleteByQueryTimeout,
+		updateByQueryTimeout:  args.UpdateByQueryTimeout,
+	}, nil
+}
+
+// DoBulkRequest will send the bulk request with the bulk timeout
+func (tc *timeoutClient) DoBulkRequest(ctx context.Context, buff *bytes.Buffer, index string) error {
+	return doWithTimeout(ctx, tc.bulkTimeout, "bulk", func(ctx context.Context) error {
+		return tc.DatabaseClientHandler.DoBulkRequest(ctx, buff, index)
+	})
+}
+
+// DoMultiGet will send the multi-get request with the multi-get timeout
+func (tc *timeoutClient) DoMultiGet(ctx context.Context, ids []string, index string, withSource bool, res interface{}) error {
+	return doWithTimeout(ctx, tc.multiGetTimeout, "multi-get", func(ctx context.Context) error {
+		return tc.DatabaseClientHandler.DoMultiGet(ctx, ids, index, withSource, res)
+	})
+}
+
+// DoCountRequest will send the count request with the multi-get timeout
+func (tc *timeoutClient) DoCountRequest(ctx context.Context, index string, body []byte) (uint64, error) {
+	var count uint64
+	err := doWithTimeout(ctx, tc.multiGetTimeout, "count", func(ctx context.Context) error {
+		var errCount error
+		count, errCount = tc.DatabaseClientHandler.DoCountRequest(ctx, index, body)
+		return errCount
+	})
+
+	return count, err
+}
+
+// DoScrollRequest will send the scroll requests with the scroll timeout
+func (tc *timeoutClient) DoScrollRequest(
+	ctx context.Context,
+	index string,
+	body []byte,
+	withSource bool,
+	handlerFunc func(responseBytes []byte) error,
+) error {
+	return doWithTimeout(ctx, tc.scrollTimeout, "scroll", func(ctx context.Context) error {
+		return tc.DatabaseClientHandler.DoScrollRequest(ctx, index, body, withSource, handlerFunc)
+	})
+}
+
+// DoQueryRemove will send the delete by query request with the delete by query timeout
+func (tc *timeoutClient) DoQueryRemove(ctx context.Context, index string, buff *bytes.Buffer) error {
+	return doWithTimeout(ctx, tc.deleteByQueryTimeout, "delete by query", func(ctx context.Context) error {
+		return tc.DatabaseClientHandler.DoQueryRemove(ctx, index, buff)
+	})
+}
+
+// UpdateByQuery will send the update by query request with the update by query timeout
+func (tc *timeoutClient) UpdateByQuery(ctx context.Context, index string, buff *bytes.Buffer) error {
+	return doWithTimeout(ctx, tc.updateByQueryTimeout, "update by query", func(ctx context.Context) error {
+		return tc.DatabaseClientHandler.UpdateByQuery(ctx, index, buff)
+	})
+}
+
+// doWithTimeout calls the operation with a context that expires after the timeout. The error of an operation that
+// timed out wraps ErrRequestTimeout, while the cancellation of the parent context is returned as it is
+func doWithTimeout(ctx context.Context, timeout time.Duration, operation string, handler func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return handler(ctx)
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := handler(ctxWithTimeout)
+	if err == nil || ctx.Err() != nil || !errors.Is(ctxWithTimeout.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	return fmt.Errorf("%w: the %s request did not complete in %s: %s", ErrRequestTimeout, operation, timeout, err.Error())
+}
+
+// Close will close the wrapped database client
+func (tc *timeoutClient) Close() error {
+	return elasticproc.CloseDatabaseClient(tc.DatabaseClientHandler)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (tc *timeoutClient) IsInterfaceNil() bool {
+	return tc == nil
+}
@@ -0,0 +1,121 @@
+package timeout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingClient blocks every bulk and scroll request until its context is done
+type blockingClient struct {
+	mock.DatabaseWriterStub
+}
+
+func (bc *blockingClient) DoBulkRequest(ctx context.Context, _ *bytes.Buffer, _ string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (bc *blockingClient) DoScrollRequest(ctx context.Context, _ string, _ []byte, _ bool, _ func(responseBytes []byte) error) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestNewTimeoutClient(t *testing.T) {
+	t.Parallel()
+
+	tc, err := NewTimeoutClient(ArgsTimeoutClient{})
+	require.Nil(t, tc)
+	require.Equal(t, ErrNilDatabaseClient, err)
+
+	tc, err = NewTimeoutClient(ArgsTimeoutClient{DatabaseClient: &mock.DatabaseWriterStub{}})
+	require.Nil(t, err)
+	require.False(t, tc.IsInterfaceNil())
+}
+
+func TestTimeoutClient_RequestTimesOut(t *testing.T) {
+	t.Parallel()
+
+	tc, _ := NewTimeoutClient(ArgsTimeoutClient{
+		DatabaseClient: &blockingClient{},
+		BulkTimeout:    10 * time.Millisecond,
+	})
+
+	err := tc.DoBulkRequest(context.Background(), bytes.NewBufferString("{}\n"), "")
+	require.True(t, errors.Is(err, ErrRequestTimeout))
+}
+
+func TestTimeoutClient_ParentCancellation(t *testing.T) {
+	t.Parallel()
+
+	tc, _ := NewTimeoutClient(ArgsTimeoutClient{
+		DatabaseClient: &blockingClient{},
+		ScrollTimeout:  time.Minute,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	err := tc.DoScrollRequest(ctx, "", nil, false, nil)
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestDoWithTimeout_DisabledTimeout(t *testing.T) {
+	t.Parallel()
+
+	deadlineSet := true
+	err := doWithTimeout(context.Background(), 0, "multi-get", func(ctx context.Context) error {
+		_, deadlineSet = ctx.Deadline()
+		return nil
+	})
+	require.Nil(t, err)
+	require.False(t, deadlineSet)
+}
+
+func TestTimeoutClient_FailedRequestBeforeTheTimeout(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	tc, _ := NewTimeoutClient(ArgsTimeoutClient{
+		DatabaseClient: &mock.DatabaseWriterStub{
+			UpdateByQueryCalled: func(_ string, _ *bytes.Buffer) error {
+				return expectedErr
+			},
+		},
+		UpdateByQueryTimeout: time.Minute,
+	})
+
+	err := tc.UpdateByQuery(context.Background(), "delegators", bytes.NewBufferString("{}"))
+	require.Equal(t, expectedErr, err)
+}
+
+// closableClient records whether it was closed
+type closableClient struct {
+	mock.DatabaseWriterStub
+	closed bool
+}
+
+func (cc *closableClient) Close() error {
+	cc.closed = true
+	return nil
+}
+
+func TestTimeoutClient_CloseShouldCloseTheWrappedClient(t *testing.T) {
+	t.Parallel()
+
+	tc, _ := NewTimeoutClient(ArgsTimeoutClient{DatabaseClient: &mock.DatabaseWriterStub{}})
+	require.Nil(t, tc.Close())
+
+	wrappedClient := &closableClient{}
+	tc, _ = NewTimeoutClient(ArgsTimeoutClient{DatabaseClient: wrappedClient})
+	require.Nil(t, tc.Close())
+	require.True(t, wrappedClient.closed)
+}
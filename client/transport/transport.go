@@ -3,7 +3,9 @@ package transport
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
@@ -14,20 +16,41 @@ import (
 
 var errNilRequest = errors.New("nil request")
 
+type countingReadCloser struct {
+	io.ReadCloser
+	count int64
+}
+
+// Read counts the bytes read from the wrapped reader
+func (crc *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := crc.ReadCloser.Read(p)
+	atomic.AddInt64(&crc.count, int64(n))
+
+	return n, err
+}
+
+func (crc *countingReadCloser) numBytes() int64 {
+	return atomic.LoadInt64(&crc.count)
+}
+
 type metricsTransport struct {
 	statusMetrics core.StatusMetricsHandler
 	transport     http.RoundTripper
 }
 
-// NewMetricsTransport will create a new instance of metricsTransport
-func NewMetricsTransport(statusMetrics core.StatusMetricsHandler) (*metricsTransport, error) {
+// NewMetricsTransport will create a new instance of metricsTransport, which wraps the provided transport. When the
+// transport is nil, http.DefaultTransport is used
+func NewMetricsTransport(statusMetrics core.StatusMetricsHandler, transport http.RoundTripper) (*metricsTransport, error) {
 	if check.IfNil(statusMetrics) {
 		return nil, core.ErrNilMetricsHandler
 	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
 
 	return &metricsTransport{
 		statusMetrics: statusMetrics,
-		transport:     http.DefaultTransport,
+		transport:     transport,
 	}, nil
 }
 
@@ -41,6 +64,14 @@ func (m *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	startTime := time.Now()
 	size := req.ContentLength
 
+	// the length of a streamed body, like a compressed bulk body, is only known after it was sent
+	var streamedBody *countingReadCloser
+	if size <= 0 && req.Body != nil && req.Body != http.NoBody {
+		streamedBody = &countingReadCloser{ReadCloser: req.Body}
+		req = req.Clone(req.Context())
+		req.Body = streamedBody
+	}
+
 	var statusCode int
 	resp, err := m.transport.RoundTrip(req)
 	if err == nil {
@@ -48,6 +79,9 @@ func (m *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 
 	duration := time.Since(startTime)
+	if streamedBody != nil {
+		size = streamedBody.numBytes()
+	}
 
 	valueFromCtx := req.Context().Value(request.ContextKey)
 	if valueFromCtx == nil {
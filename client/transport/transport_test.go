@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/multiversx/mx-chain-es-indexer-go/core"
@@ -17,19 +19,19 @@ import (
 func TestNewMetricsTransport(t *testing.T) {
 	t.Parallel()
 
-	transportHandler, err := NewMetricsTransport(nil)
+	transportHandler, err := NewMetricsTransport(nil, nil)
 	require.Nil(t, transportHandler)
 	require.Equal(t, core.ErrNilMetricsHandler, err)
 
 	metricsHandler := metrics.NewStatusMetrics()
-	transportHandler, err = NewMetricsTransport(metricsHandler)
+	transportHandler, err = NewMetricsTransport(metricsHandler, nil)
 	require.Nil(t, err)
 	require.NotNil(t, transportHandler)
 }
 
 func TestMetricsTransport_NilRequest(t *testing.T) {
 	metricsHandler := metrics.NewStatusMetrics()
-	transportHandler, _ := NewMetricsTransport(metricsHandler)
+	transportHandler, _ := NewMetricsTransport(metricsHandler, nil)
 
 	_, err := transportHandler.RoundTrip(nil)
 	require.Equal(t, errNilRequest, err)
@@ -39,7 +41,7 @@ func TestMetricsTransport_RoundTripNilResponseShouldWork(t *testing.T) {
 	t.Parallel()
 
 	metricsHandler := metrics.NewStatusMetrics()
-	transportHandler, _ := NewMetricsTransport(metricsHandler)
+	transportHandler, _ := NewMetricsTransport(metricsHandler, nil)
 
 	testErr := errors.New("test")
 	transportHandler.transport = &mock.TransportMock{
@@ -63,7 +65,7 @@ func TestMetricsTransport_RoundTrip(t *testing.T) {
 	t.Parallel()
 
 	metricsHandler := metrics.NewStatusMetrics()
-	transportHandler, _ := NewMetricsTransport(metricsHandler)
+	transportHandler, _ := NewMetricsTransport(metricsHandler, nil)
 
 	transportHandler.transport = &mock.TransportMock{
 		Response: &http.Response{
@@ -88,7 +90,7 @@ func TestMetricsTransport_RoundTripNoValueInContextShouldNotAddMetrics(t *testin
 	t.Parallel()
 
 	metricsHandler := metrics.NewStatusMetrics()
-	transportHandler, _ := NewMetricsTransport(metricsHandler)
+	transportHandler, _ := NewMetricsTransport(metricsHandler, nil)
 
 	transportHandler.transport = &mock.TransportMock{
 		Response: &http.Response{
@@ -103,3 +105,33 @@ func TestMetricsTransport_RoundTripNoValueInContextShouldNotAddMetrics(t *testin
 	metricsMap := metricsHandler.GetMetrics()
 	require.Len(t, metricsMap, 0)
 }
+
+func TestMetricsTransport_RoundTripStreamedBody(t *testing.T) {
+	t.Parallel()
+
+	metricsHandler := metrics.NewStatusMetrics()
+	transportHandler, _ := NewMetricsTransport(metricsHandler, nil)
+
+	transportHandler.transport = &readBodyTransport{}
+
+	testTopic := "test"
+	contextWithValue := context.WithValue(context.Background(), request.ContextKey, testTopic)
+	req, _ := http.NewRequestWithContext(contextWithValue, http.MethodPost, "dummy", io.NopCloser(strings.NewReader("streamed")))
+	require.Equal(t, int64(0), req.ContentLength)
+
+	_, _ = transportHandler.RoundTrip(req)
+
+	metricsMap := metricsHandler.GetMetrics()
+	require.Equal(t, uint64(8), metricsMap[testTopic].TotalData)
+}
+
+type readBodyTransport struct{}
+
+func (rbt *readBodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	_, err := io.Copy(io.Discard, req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
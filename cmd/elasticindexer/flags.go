@@ -52,4 +52,62 @@ var (
 		Name:  "disable-ansi-color",
 		Usage: "Boolean option for disabling ANSI colors in the logging system.",
 	}
+
+	// dryRun defines a flag that keeps the indexed documents in memory instead of writing them to the database
+	dryRun = cli.BoolFlag{
+		Name: "dry-run",
+		Usage: "Boolean option for running the indexer without a database. If set, the documents are kept in memory " +
+			"and are written as JSON files in the dry-run output directory when the indexer is closed.",
+	}
+	// dryRunOutput defines a flag for the directory where the documents of a dry run are written
+	dryRunOutput = cli.StringFlag{
+		Name:  "dry-run-output",
+		Usage: "The `" + filePathPlaceholder + "` to the directory where the documents of a dry run are written, one JSON file per index",
+		Value: "./dry-run",
+	}
+
+	// replayPath defines a flag for the path of the recorded payloads that should be replayed
+	replayPath = cli.StringFlag{
+		Name:  "path",
+		Usage: "The `" + filePathPlaceholder + "` to a recorded file or to a directory containing recorded files",
+		Value: "./recorded-payloads",
+	}
+	// replayFromTimestamp defines a flag for the lower bound of the replayed time range
+	replayFromTimestamp = cli.Uint64Flag{
+		Name:  "from-timestamp-ms",
+		Usage: "Only the payloads recorded at or after this unix timestamp in milliseconds will be replayed. 0 means no lower bound",
+	}
+	// replayToTimestamp defines a flag for the upper bound of the replayed time range
+	replayToTimestamp = cli.Uint64Flag{
+		Name:  "to-timestamp-ms",
+		Usage: "Only the payloads recorded at or before this unix timestamp in milliseconds will be replayed. 0 means no upper bound",
+	}
+	// replayContinueOnError defines a flag that tells the replay to continue if a payload cannot be indexed
+	replayContinueOnError = cli.BoolFlag{
+		Name:  "continue-on-error",
+		Usage: "Boolean option for continuing the replay if a payload cannot be indexed. If not set, the replay stops at the first error.",
+	}
+
+	// deadLetterPath defines a flag for the directory of the dead-letter store
+	deadLetterPath = cli.StringFlag{
+		Name:  "path",
+		Usage: "The `" + filePathPlaceholder + "` to the dead-letter directory. If not set, the path from the preferences configuration file is used",
+	}
+	// deadLetterIDs defines a flag for the dead-letter entries that should be retried
+	deadLetterIDs = cli.StringSliceFlag{
+		Name:  "id",
+		Usage: "The id of a dead-letter entry that should be retried. It can be provided multiple times. If not set, all the entries are retried",
+	}
+
+	// ingestPath defines a flag for the directory of serialized outport payloads that should be indexed
+	ingestPath = cli.StringFlag{
+		Name:  "path",
+		Usage: "The `" + filePathPlaceholder + "` to a directory containing serialized outport payloads",
+		Value: "./outport-blocks",
+	}
+	// ingestContinueOnError defines a flag that tells the ingestion to continue if a file cannot be indexed
+	ingestContinueOnError = cli.BoolFlag{
+		Name:  "continue-on-error",
+		Usage: "Boolean option for continuing the ingestion if a file cannot be read or indexed. If not set, the ingestion stops at the first error.",
+	}
 )
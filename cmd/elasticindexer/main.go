@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -12,8 +14,14 @@ import (
 	"github.com/multiversx/mx-chain-core-go/core/closing"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	indexerCore "github.com/multiversx/mx-chain-es-indexer-go/core"
 	"github.com/multiversx/mx-chain-es-indexer-go/factory"
 	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/deadletter"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+	indexerFactory "github.com/multiversx/mx-chain-es-indexer-go/process/factory"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/recorder"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/wsindexer"
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-logger-go/file"
@@ -21,8 +29,9 @@ import (
 )
 
 var (
-	log          = logger.GetOrCreate("indexer")
-	helpTemplate = `NAME:
+	log            = logger.GetOrCreate("indexer")
+	errDryRunRetry = errors.New("the dead-letter entries cannot be retried in dry-run mode, because the retried entries are removed from the store")
+	helpTemplate   = `NAME:
    {{.Name}} - {{.Usage}}
 USAGE:
    {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}
@@ -64,6 +73,8 @@ func main() {
 		logLevel,
 		logSaveFile,
 		disableAnsiColor,
+		dryRun,
+		dryRunOutput,
 	}
 	app.Authors = []cli.Author{
 		{
@@ -74,6 +85,52 @@ func main() {
 
 	app.Version = version
 	app.Action = startIndexer
+	app.Commands = []cli.Command{
+		{
+			Name:  "replay",
+			Usage: "Feeds recorded payloads back to the indexer, without a node attached",
+			Flags: []cli.Flag{
+				replayPath,
+				replayFromTimestamp,
+				replayToTimestamp,
+				replayContinueOnError,
+			},
+			Action: replayPayloads,
+		},
+		{
+			Name: "index-dir",
+			Usage: "Indexes a directory of serialized outport payloads, without a node attached. The files are " +
+				"serialized with the data-marshaller-type from the preferences file. The files whose names start with " +
+				"\"settings\" hold the outport config, the ones whose names start with \"revert\" hold the data of a " +
+				"reverted block and all the other files hold an outport block",
+			Flags: []cli.Flag{
+				ingestPath,
+				ingestContinueOnError,
+			},
+			Action: indexDirectory,
+		},
+		{
+			Name:  "dead-letters",
+			Usage: "Inspects and retries the payloads saved in the dead-letter store",
+			Subcommands: []cli.Command{
+				{
+					Name:   "list",
+					Usage:  "Lists the saved payloads, oldest first",
+					Flags:  []cli.Flag{deadLetterPath},
+					Action: listDeadLetters,
+				},
+				{
+					Name:  "retry",
+					Usage: "Feeds the saved payloads back to the indexer and removes the ones that are indexed",
+					Flags: []cli.Flag{
+						deadLetterPath,
+						deadLetterIDs,
+					},
+					Action: retryDeadLetters,
+				},
+			},
+		},
+	}
 
 	err := app.Run(os.Args)
 	if err != nil {
@@ -83,28 +140,20 @@ func main() {
 }
 
 func startIndexer(ctx *cli.Context) error {
-	cfg, err := loadMainConfig(ctx.GlobalString(configurationFile.Name))
-	if err != nil {
-		return fmt.Errorf("%w while loading the config file", err)
-	}
-
-	clusterCfg, err := loadClusterConfig(ctx.GlobalString(configurationPreferencesFile.Name))
-	if err != nil {
-		return fmt.Errorf("%w while loading the preferences config file", err)
-	}
-
-	fileLogging, err := initializeLogger(ctx, cfg)
+	configs, fileLogging, err := loadIndexerConfigs(ctx)
 	if err != nil {
-		return fmt.Errorf("%w while initializing the logger", err)
-	}
-
-	epochsCfg, err := loadEpochsConfig(ctx.GlobalString(configurationEnableEpochsFile.Name))
-	if err != nil {
-		return fmt.Errorf("%w while loading the enable epochs config file", err)
+		return err
 	}
+	defer closeFileLogging(fileLogging)
 
+	cfg, clusterCfg, epochsCfg := configs.cfg, configs.clusterCfg, configs.epochsCfg
 	statusMetrics := metrics.NewStatusMetrics()
-	wsHost, err := factory.CreateWsIndexer(cfg, clusterCfg, epochsCfg, statusMetrics, ctx.App.Version)
+	nonceGapDetector := dataindexer.NewNonceGapDetector(statusMetrics)
+	databaseClient := createDryRunClient(ctx, clusterCfg.Config.BulkSink)
+	// the root context of all the requests sent to the database, cancelled at the user's signal
+	indexerCtx, cancelIndexer := context.WithCancel(context.Background())
+	defer cancelIndexer()
+	wsHost, err := factory.CreateWsIndexer(indexerCtx, cfg, clusterCfg, epochsCfg, statusMetrics, nonceGapDetector, databaseClient, ctx.App.Version)
 	if err != nil {
 		return fmt.Errorf("%w while creating the indexer", err)
 	}
@@ -114,7 +163,7 @@ func startIndexer(ctx *cli.Context) error {
 		return fmt.Errorf("%w while loading the api config file", err)
 	}
 
-	webServer, err := factory.CreateWebServer(apiConfig, statusMetrics)
+	webServer, err := factory.CreateWebServer(apiConfig, statusMetrics, nonceGapDetector, wsHost)
 	if err != nil {
 		return fmt.Errorf("%w while creating the web server", err)
 	}
@@ -134,23 +183,267 @@ func startIndexer(ctx *cli.Context) error {
 	}
 
 	log.Info("closing app at user's signal")
+	// the in-flight requests are cancelled, so the payload that is being indexed does not delay the shutdown. It is not
+	// acknowledged, so it is sent again after the restart
+	cancelIndexer()
+	// the web server is closed first because it can also feed payloads to the indexer
+	err = webServer.Close()
+	if err != nil {
+		log.Error("cannot close web server", "error", err)
+	}
+
 	err = wsHost.Close()
 	if err != nil {
 		log.Error("cannot close ws indexer", "error", err)
 	}
+	dumpDryRunDocuments(ctx, databaseClient)
 
-	err = webServer.Close()
+	return nil
+}
+
+func replayPayloads(ctx *cli.Context) error {
+	replayCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	indexer, _, cleanup, err := createPayloadIndexer(replayCtx, ctx)
 	if err != nil {
-		log.Error("cannot close web server", "error", err)
+		return err
 	}
 
-	if !check.IfNilReflect(fileLogging) {
-		err = fileLogging.Close()
-		log.LogIfError(err)
+	payloadsReplayer, err := recorder.NewReplayer(recorder.ArgsReplayer{
+		Path:            ctx.String(replayPath.Name),
+		FromTimestampMs: ctx.Uint64(replayFromTimestamp.Name),
+		ToTimestampMs:   ctx.Uint64(replayToTimestamp.Name),
+		ContinueOnError: ctx.Bool(replayContinueOnError.Name),
+		PayloadHandler:  indexer,
+	})
+	if err != nil {
+		cleanup()
+		return fmt.Errorf("%w while creating the replayer", err)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-interrupt:
+			log.Info("stopping replay at user's signal")
+			cancel()
+		case <-replayCtx.Done():
+		}
+	}()
+
+	stats, errReplay := payloadsReplayer.Replay(replayCtx)
+	cancel()
+	if stats != nil {
+		log.Info("replay finished",
+			"files", stats.NumFiles,
+			"payloads", stats.NumFrames,
+			"skipped", stats.NumSkipped,
+			"errors", stats.NumErrors,
+		)
+	}
+
+	cleanup()
+
+	return errReplay
+}
+
+func indexDirectory(ctx *cli.Context) error {
+	ingestCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	indexer, clusterCfg, cleanup, err := createPayloadIndexer(ingestCtx, ctx)
+	if err != nil {
+		return err
+	}
+
+	dirIngester, err := factory.CreateDirIngester(clusterCfg, ctx.String(ingestPath.Name), ctx.Bool(ingestContinueOnError.Name), indexer)
+	if err != nil {
+		cleanup()
+		return fmt.Errorf("%w while creating the directory ingester", err)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-interrupt:
+			log.Info("stopping directory indexing at user's signal")
+			cancel()
+		case <-ingestCtx.Done():
+		}
+	}()
+
+	stats, errIngest := dirIngester.Ingest(ingestCtx)
+	cancel()
+	if stats != nil {
+		log.Info("directory indexing finished",
+			"files", stats.NumFiles,
+			"skipped", stats.NumSkipped,
+			"errors", stats.NumErrors,
+		)
+	}
+
+	cleanup()
+
+	return errIngest
+}
+
+func listDeadLetters(ctx *cli.Context) error {
+	clusterCfg, err := loadClusterConfig(ctx.GlobalString(configurationPreferencesFile.Name))
+	if err != nil {
+		return fmt.Errorf("%w while loading the preferences config file", err)
+	}
+
+	path := getDeadLetterPath(ctx, clusterCfg)
+	entries, err := deadletter.ReadEntries(path)
+	if err != nil {
+		return fmt.Errorf("%w while reading the dead-letter entries", err)
 	}
+
+	for _, entry := range entries {
+		fmt.Printf("%s\ttopic=%s\tversion=%d\tshard=%d\ttimestampMs=%d\terror=%s\n",
+			entry.ID, entry.Topic, entry.Version, entry.ShardID, entry.TimestampMs, entry.Error)
+	}
+	log.Info("dead-letter entries", "path", path, "num entries", len(entries))
+
 	return nil
 }
 
+func retryDeadLetters(ctx *cli.Context) error {
+	if ctx.GlobalBool(dryRun.Name) {
+		return errDryRunRetry
+	}
+
+	indexer, clusterCfg, cleanup, err := createPayloadIndexer(context.Background(), ctx)
+	if err != nil {
+		return err
+	}
+
+	path := getDeadLetterPath(ctx, clusterCfg)
+	stats, errRetry := deadletter.Retry(path, ctx.StringSlice(deadLetterIDs.Name), indexer)
+	if stats != nil {
+		log.Info("dead-letter retry finished",
+			"retried", stats.NumRetried,
+			"failed", stats.NumFailed,
+		)
+	}
+
+	cleanup()
+
+	return errRetry
+}
+
+// indexerConfigs holds the config files needed to create an indexer
+type indexerConfigs struct {
+	cfg        config.Config
+	clusterCfg config.ClusterConfig
+	epochsCfg  config.EnableEpochsConfig
+}
+
+// loadIndexerConfigs loads the config files needed to create an indexer and initializes the logger
+func loadIndexerConfigs(ctx *cli.Context) (*indexerConfigs, closing.Closer, error) {
+	cfg, err := loadMainConfig(ctx.GlobalString(configurationFile.Name))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w while loading the config file", err)
+	}
+
+	clusterCfg, err := loadClusterConfig(ctx.GlobalString(configurationPreferencesFile.Name))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w while loading the preferences config file", err)
+	}
+
+	fileLogging, err := initializeLogger(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w while initializing the logger", err)
+	}
+
+	epochsCfg, err := loadEpochsConfig(ctx.GlobalString(configurationEnableEpochsFile.Name))
+	if err != nil {
+		closeFileLogging(fileLogging)
+		return nil, nil, fmt.Errorf("%w while loading the enable epochs config file", err)
+	}
+
+	return &indexerConfigs{
+		cfg:        cfg,
+		clusterCfg: clusterCfg,
+		epochsCfg:  epochsCfg,
+	}, fileLogging, nil
+}
+
+// createPayloadIndexer creates the indexer of the commands that feed it payloads without a node attached, after it
+// loads the config files and initializes the logger. The requests sent to the database are interrupted when the
+// provided context is cancelled. The returned cleanup closes the indexer, writes the documents of the dry run and
+// closes the logger
+func createPayloadIndexer(indexerCtx context.Context, ctx *cli.Context) (indexerCore.PayloadHandler, config.ClusterConfig, func(), error) {
+	configs, fileLogging, err := loadIndexerConfigs(ctx)
+	if err != nil {
+		return nil, config.ClusterConfig{}, nil, err
+	}
+
+	statusMetrics := metrics.NewStatusMetrics()
+	nonceGapDetector := dataindexer.NewNonceGapDetector(statusMetrics)
+	databaseClient := createDryRunClient(ctx, configs.clusterCfg.Config.BulkSink)
+	indexer, err := factory.CreatePayloadIndexer(indexerCtx, configs.cfg, configs.clusterCfg, configs.epochsCfg, statusMetrics, nonceGapDetector, databaseClient, ctx.App.Version)
+	if err != nil {
+		closeFileLogging(fileLogging)
+		return nil, config.ClusterConfig{}, nil, fmt.Errorf("%w while creating the indexer", err)
+	}
+
+	cleanup := func() {
+		errClose := indexer.Close()
+		if errClose != nil {
+			log.Error("cannot close indexer", "error", errClose)
+		}
+		dumpDryRunDocuments(ctx, databaseClient)
+		closeFileLogging(fileLogging)
+	}
+
+	return indexer, configs.clusterCfg, cleanup, nil
+}
+
+func closeFileLogging(fileLogging closing.Closer) {
+	if check.IfNilReflect(fileLogging) {
+		return
+	}
+
+	err := fileLogging.Close()
+	log.LogIfError(err)
+}
+
+// createDryRunClient returns the in-memory database client if the dry run is enabled, nil otherwise. It keeps at most
+// max-documents-in-memory documents from the bulk-sink section
+func createDryRunClient(ctx *cli.Context, sinkCfg config.BulkSinkConfig) elasticproc.DatabaseClientHandler {
+	if !ctx.GlobalBool(dryRun.Name) {
+		return nil
+	}
+
+	log.Info("dry run: the documents are kept in memory and are not written to the database")
+	return indexerFactory.CreateMemoryClient(sinkCfg)
+}
+
+func dumpDryRunDocuments(ctx *cli.Context, databaseClient elasticproc.DatabaseClientHandler) {
+	dumper, ok := databaseClient.(interface{ DumpToDirectory(path string) error })
+	if !ok {
+		return
+	}
+
+	path := ctx.GlobalString(dryRunOutput.Name)
+	err := dumper.DumpToDirectory(path)
+	if err != nil {
+		log.Error("cannot write the documents of the dry run", "path", path, "error", err)
+		return
+	}
+	log.Info("dry run: the documents were written", "path", path)
+}
+
+func getDeadLetterPath(ctx *cli.Context, clusterCfg config.ClusterConfig) string {
+	if ctx.IsSet(deadLetterPath.Name) {
+		return ctx.String(deadLetterPath.Name)
+	}
+
+	return clusterCfg.Config.DeadLetter.Path
+}
+
 func requestSettings(host wsindexer.WSClient, retryDuration time.Duration, close chan os.Signal) bool {
 	timer := time.NewTimer(0)
 	defer timer.Stop()
@@ -36,28 +36,129 @@ type ClusterConfig struct {
 	Config struct {
 		DisabledIndices []string `toml:"disabled-indices"`
 		WebSocket       struct {
-			URL                string `toml:"url"`
-			Mode               string `toml:"mode"`
-			DataMarshallerType string `toml:"data-marshaller-type"`
-			RetryDurationInSec uint32 `toml:"retry-duration-in-seconds"`
-			BlockingAckOnError bool   `toml:"blocking-ack-on-error"`
-			WithAcknowledge    bool   `toml:"with-acknowledge"`
-			AckTimeoutInSec    uint32 `toml:"acknowledge-timeout-in-seconds"`
+			URL                string   `toml:"url"`
+			URLs               []string `toml:"urls"`
+			Mode               string   `toml:"mode"`
+			DataMarshallerType string   `toml:"data-marshaller-type"`
+			RetryDurationInSec uint32   `toml:"retry-duration-in-seconds"`
+			BlockingAckOnError bool     `toml:"blocking-ack-on-error"`
+			WithAcknowledge    bool     `toml:"with-acknowledge"`
+			AckTimeoutInSec    uint32   `toml:"acknowledge-timeout-in-seconds"`
 		} `toml:"web-socket"`
+		Spool struct {
+			Enabled            bool   `toml:"enabled"`
+			Path               string `toml:"path"`
+			MaxSizeInMB        uint64 `toml:"max-size-in-mb"`
+			RetryDurationInSec uint32 `toml:"retry-duration-in-seconds"`
+			MaxAttempts        uint32 `toml:"max-attempts"`
+		} `toml:"spool"`
+		PayloadsRecorder struct {
+			Enabled         bool   `toml:"enabled"`
+			Path            string `toml:"path"`
+			MaxFileSizeInMB uint64 `toml:"max-file-size-in-mb"`
+		} `toml:"payloads-recorder"`
+		DeadLetter struct {
+			Enabled bool   `toml:"enabled"`
+			Path    string `toml:"path"`
+		} `toml:"dead-letter"`
+		BulkSink       BulkSinkConfig `toml:"bulk-sink"`
 		ElasticCluster struct {
-			UseKibana                 bool   `toml:"use-kibana"`
-			URL                       string `toml:"url"`
-			UserName                  string `toml:"username"`
-			Password                  string `toml:"password"`
-			BulkRequestMaxSizeInBytes int    `toml:"bulk-request-max-size-in-bytes"`
+			Backend                   string            `toml:"backend"`
+			UseKibana                 bool              `toml:"use-kibana"`
+			URL                       string            `toml:"url"`
+			URLs                      []string          `toml:"urls"`
+			Sniffing                  SniffingConfig    `toml:"sniffing"`
+			UserName                  string            `toml:"username"`
+			Password                  string            `toml:"password"`
+			APIKey                    string            `toml:"api-key"`
+			BearerToken               string            `toml:"bearer-token"`
+			TLS                       TLSConfig         `toml:"tls"`
+			BulkRequestMaxSizeInBytes int               `toml:"bulk-request-max-size-in-bytes"`
+			BulkRequestWorkers        int               `toml:"bulk-request-workers"`
+			CompressBulkRequests      bool              `toml:"compress-bulk-requests"`
+			ISM                       ISMConfig         `toml:"ism"`
+			RetryPolicy               RetryPolicyConfig `toml:"retry-policy"`
+			Timeouts                  TimeoutsConfig    `toml:"timeouts"`
+			Mirror                    MirrorConfig      `toml:"mirror"`
 		} `toml:"elastic-cluster"`
 	} `toml:"config"`
 }
 
+// ISMConfig holds the configuration for the OpenSearch Index State Management policies
+type ISMConfig struct {
+	Enabled             bool     `toml:"enabled"`
+	Indices             []string `toml:"indices"`
+	RolloverMinSize     string   `toml:"rollover-min-size"`
+	RolloverMinIndexAge string   `toml:"rollover-min-index-age"`
+}
+
+// RetryPolicyConfig holds the configuration of the retries of the requests rejected by an overloaded cluster
+type RetryPolicyConfig struct {
+	Enabled                             bool   `toml:"enabled"`
+	InitialIntervalInMs                 uint64 `toml:"initial-interval-in-ms"`
+	MaxIntervalInMs                     uint64 `toml:"max-interval-in-ms"`
+	MaxElapsedTimeInSeconds             uint64 `toml:"max-elapsed-time-in-seconds"`
+	CircuitBreakerFailureThreshold      uint32 `toml:"circuit-breaker-failure-threshold"`
+	CircuitBreakerOpenDurationInSeconds uint64 `toml:"circuit-breaker-open-duration-in-seconds"`
+}
+
+// TimeoutsConfig holds the maximum durations of the requests sent to the cluster, for each operation
+type TimeoutsConfig struct {
+	BulkInSeconds          uint32 `toml:"bulk-in-seconds"`
+	MultiGetInSeconds      uint32 `toml:"multi-get-in-seconds"`
+	ScrollInSeconds        uint32 `toml:"scroll-in-seconds"`
+	DeleteByQueryInSeconds uint32 `toml:"delete-by-query-in-seconds"`
+	UpdateByQueryInSeconds uint32 `toml:"update-by-query-in-seconds"`
+}
+
+// SniffingConfig holds the configuration of the discovery of the nodes of a cluster
+type SniffingConfig struct {
+	Enabled           bool   `toml:"enabled"`
+	IntervalInSeconds uint32 `toml:"interval-in-seconds"`
+}
+
+// MirrorConfig holds the configuration of the clusters that receive a copy of every write
+type MirrorConfig struct {
+	Enabled                  bool                  `toml:"enabled"`
+	MaxBufferSizeInMB        uint64                `toml:"max-buffer-size-in-mb"`
+	CatchUpIntervalInSeconds uint32                `toml:"catch-up-interval-in-seconds"`
+	FlushTimeoutInSeconds    uint32                `toml:"flush-timeout-in-seconds"`
+	Clusters                 []MirrorClusterConfig `toml:"clusters"`
+}
+
+// MirrorClusterConfig holds the connection details of a mirror cluster
+type MirrorClusterConfig struct {
+	URL         string    `toml:"url"`
+	URLs        []string  `toml:"urls"`
+	UserName    string    `toml:"username"`
+	Password    string    `toml:"password"`
+	APIKey      string    `toml:"api-key"`
+	BearerToken string    `toml:"bearer-token"`
+	TLS         TLSConfig `toml:"tls"`
+}
+
+// TLSConfig holds the certificates used by the HTTPS connections to a cluster
+type TLSConfig struct {
+	CACertFile         string `toml:"ca-cert-file"`
+	CertFile           string `toml:"cert-file"`
+	KeyFile            string `toml:"key-file"`
+	InsecureSkipVerify bool   `toml:"insecure-skip-verify"`
+}
+
+// BulkSinkConfig holds the configuration for the NDJSON files where the bulk bodies are written
+type BulkSinkConfig struct {
+	Enabled              bool   `toml:"enabled"`
+	Path                 string `toml:"path"`
+	MaxFileSizeInMB      uint64 `toml:"max-file-size-in-mb"`
+	ReplaceDatabase      bool   `toml:"replace-database"`
+	MaxDocumentsInMemory uint64 `toml:"max-documents-in-memory"`
+}
+
 // ApiRoutesConfig holds the configuration related to Rest API routes
 type ApiRoutesConfig struct {
-	RestApiInterface string                      `toml:"rest-api-interface"`
-	APIPackages      map[string]APIPackageConfig `toml:"api-packages"`
+	RestApiInterface         string                      `toml:"rest-api-interface"`
+	IngestMaxPayloadSizeInMB uint64                      `toml:"ingest-max-payload-size-in-mb"`
+	APIPackages              map[string]APIPackageConfig `toml:"api-packages"`
 }
 
 // APIPackageConfig holds the configuration for the routes of each package
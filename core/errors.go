@@ -7,3 +7,9 @@ var ErrNilMetricsHandler = errors.New("nil metrics handler")
 
 // ErrNilFacadeHandler signal that a nil facade handler has been provided
 var ErrNilFacadeHandler = errors.New("nil facade handler")
+
+// ErrNilNonceGapsHandler signals that a nil nonce gaps handler has been provided
+var ErrNilNonceGapsHandler = errors.New("nil nonce gaps handler")
+
+// ErrNilPayloadHandler signals that a nil payload handler has been provided
+var ErrNilPayloadHandler = errors.New("nil payload handler")
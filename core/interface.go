@@ -2,17 +2,34 @@ package core
 
 import (
 	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
 	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
 )
 
 // StatusMetricsHandler defines the behavior of a component that handles status metrics
 type StatusMetricsHandler interface {
 	AddIndexingData(args metrics.ArgsAddIndexingData)
+	AddNonceGap(shardID uint32, numMissingNonces uint64)
+	AddPayloadVersion(args metrics.ArgsAddPayloadVersion)
+	SetMirrorClusterHealth(args metrics.ArgsMirrorClusterHealth)
 	GetMetrics() map[string]*request.MetricsResponse
 	GetMetricsForPrometheus() string
 	IsInterfaceNil() bool
 }
 
+// NonceGapsHandler defines the behavior of a component that provides the nonce gaps that are still open
+type NonceGapsHandler interface {
+	GetOpenGaps() []*data.NonceGap
+	IsInterfaceNil() bool
+}
+
+// PayloadHandler defines the behavior of a component that indexes the outport payloads
+type PayloadHandler interface {
+	ProcessPayload(payload []byte, topic string, version uint32) error
+	Close() error
+	IsInterfaceNil() bool
+}
+
 // WebServerHandler defines the behavior of a component that handles the web server
 type WebServerHandler interface {
 	StartHttpServer() error
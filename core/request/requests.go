@@ -14,7 +14,9 @@ const (
 	noShardID = "#"
 	// ContextKey the key for the value that will be added in the context
 	ContextKey StringKeyType = "key"
-	separator  string        = "_"
+	// NonceContextKey the key for the nonce of the block whose data is sent, added in the context of the bulk requests
+	NonceContextKey StringKeyType = "nonce"
+	separator       string        = "_"
 	// RemoveTopic is the identifier for the remove requests metrics
 	RemoveTopic string = "req_remove"
 	// GetTopic is the identifier for the get requests metrics
@@ -25,6 +27,10 @@ const (
 	UpdateTopic string = "req_update"
 	// ScrollTopic is the identifier for the scroll requests metrics
 	ScrollTopic string = "req_scroll"
+	// BackOffTopic is the identifier for the metrics of the requests retried because the cluster rejected them
+	BackOffTopic string = "req_back_off"
+	// CircuitOpenTopic is the identifier for the metrics of the requests not sent because the circuit breaker is open
+	CircuitOpenTopic string = "req_circuit_open"
 )
 
 // MetricsResponse defines the response for status metrics endpoint
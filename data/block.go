@@ -10,6 +10,7 @@ import (
 //	plus some extra information for ease of search and filter
 type Block struct {
 	UUID                  string                 `json:"uuid"`
+	CommitID              string                 `json:"commitId,omitempty"`
 	Nonce                 uint64                 `json:"nonce"`
 	Round                 uint64                 `json:"round"`
 	Epoch                 uint32                 `json:"epoch"`
@@ -52,6 +53,18 @@ type Block struct {
 	ProposerBlsKey        string                 `json:"proposerBlsKey,omitempty"`
 }
 
+// ResponseBlocks is the structure for the blocks multi get response
+type ResponseBlocks struct {
+	Docs []ResponseBlockDB `json:"docs"`
+}
+
+// ResponseBlockDB is the structure for the block response
+type ResponseBlockDB struct {
+	Found  bool   `json:"found"`
+	ID     string `json:"_id"`
+	Source Block  `json:"_source"`
+}
+
 // MiniBlocksDetails is a structure that hold information about mini-blocks execution details
 type MiniBlocksDetails struct {
 	IndexFirstProcessedTx    int32    `json:"firstProcessedTx"`
@@ -114,4 +127,5 @@ type Miniblock struct {
 	Timestamp                   uint64 `json:"timestamp"`
 	TimestampMs                 uint64 `json:"timestampMs,omitempty"`
 	Reserved                    []byte `json:"reserved,omitempty"`
+	CommitID                    string `json:"commitId,omitempty"`
 }
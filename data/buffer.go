@@ -1,15 +1,30 @@
 package data
 
-import "bytes"
+import (
+	"bytes"
+	"compress/gzip"
+	"sync"
+)
 
 // DefaultMaxBulkSize is the constant for the maximum size of one bulk request that is sent to the Elasticsearch database
 const DefaultMaxBulkSize = 4194304 // 4MB
 
+var gzipWritersPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
 // BufferSlice extend structure bytes.Buffer with new methods
 type BufferSlice struct {
 	buffSlice         []*bytes.Buffer
 	bulkSizeThreshold int
 	idx               int
+
+	// the uncompressed size of the current buffer, the threshold applies to it
+	currentSize int
+	compress    bool
+	gzipWriter  *gzip.Writer
 }
 
 // NewBufferSlice will create a new buffer
@@ -25,23 +40,31 @@ func NewBufferSlice(bulkSizeThreshold int) *BufferSlice {
 	}
 }
 
+// NewCompressedBufferSlice will create a new buffer whose data is compressed with gzip as it is put. The threshold
+// applies to the uncompressed size of the buffers, so they hold the same actions as the ones of NewBufferSlice
+func NewCompressedBufferSlice(bulkSizeThreshold int) *BufferSlice {
+	bs := NewBufferSlice(bulkSizeThreshold)
+	bs.compress = true
+
+	return bs
+}
+
 // PutData will put meta bytes and serializeData in buffer
 func (bs *BufferSlice) PutData(meta []byte, serializedData []byte) error {
-	if len(bs.buffSlice) == 0 {
-		bs.buffSlice = append(bs.buffSlice, &bytes.Buffer{})
+	if len(bs.buffSlice) == 0 || bs.aNewElementIsNeeded(meta, serializedData) {
+		bs.addBuffer()
 	}
 
 	currentBuff := bs.buffSlice[bs.idx]
 
-	if bs.aNewElementIsNeeded(meta, serializedData) {
-		currentBuff = &bytes.Buffer{}
-		bs.buffSlice = append(bs.buffSlice, currentBuff)
-		bs.idx++
-	}
-
 	if len(serializedData) > 0 {
 		serializedData = append(serializedData, "\n"...)
 	}
+	bs.currentSize += len(meta) + len(serializedData)
+
+	if bs.compress {
+		return bs.writeCompressed(meta, serializedData)
+	}
 
 	currentBuff.Grow(len(meta) + len(serializedData))
 	_, err := currentBuff.Write(meta)
@@ -56,15 +79,55 @@ func (bs *BufferSlice) PutData(meta []byte, serializedData []byte) error {
 	return nil
 }
 
-// Buffers will return the slice of buffers
+func (bs *BufferSlice) addBuffer() {
+	bs.closeGzipWriter()
+
+	bs.buffSlice = append(bs.buffSlice, &bytes.Buffer{})
+	bs.idx = len(bs.buffSlice) - 1
+	bs.currentSize = 0
+}
+
+func (bs *BufferSlice) writeCompressed(meta []byte, serializedData []byte) error {
+	if bs.gzipWriter == nil {
+		bs.gzipWriter = gzipWritersPool.Get().(*gzip.Writer)
+		bs.gzipWriter.Reset(bs.buffSlice[bs.idx])
+	}
+
+	_, err := bs.gzipWriter.Write(meta)
+	if err != nil {
+		return err
+	}
+	_, err = bs.gzipWriter.Write(serializedData)
+
+	return err
+}
+
+// closeGzipWriter writes the end of the compressed stream of the current buffer. The data put afterwards goes in a
+// new buffer
+func (bs *BufferSlice) closeGzipWriter() {
+	if bs.gzipWriter == nil {
+		return
+	}
+
+	// the writer only fails if the underlying buffer fails, which never happens
+	_ = bs.gzipWriter.Close()
+	bs.gzipWriter.Reset(nil)
+	gzipWritersPool.Put(bs.gzipWriter)
+	bs.gzipWriter = nil
+	// the buffer is full, so the next data starts a new buffer
+	bs.currentSize = bs.bulkSizeThreshold
+}
+
+// Buffers will return the slice of buffers. The compressed buffers are complete gzip streams, so no more data is put
+// in them afterwards
 func (bs *BufferSlice) Buffers() []*bytes.Buffer {
+	bs.closeGzipWriter()
+
 	return bs.buffSlice
 }
 
 func (bs *BufferSlice) aNewElementIsNeeded(meta []byte, serializedData []byte) bool {
-	currentBuff := bs.buffSlice[bs.idx]
-
-	buffLenWithCurrentAcc := currentBuff.Len() + len(meta) + len(serializedData)
+	buffLenWithCurrentAcc := bs.currentSize + len(meta) + len(serializedData)
 
-	return buffLenWithCurrentAcc > bs.bulkSizeThreshold && currentBuff.Len() != 0
+	return buffLenWithCurrentAcc > bs.bulkSizeThreshold && bs.currentSize != 0
 }
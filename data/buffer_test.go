@@ -2,6 +2,7 @@ package data
 
 import (
 	"crypto/rand"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -49,6 +50,44 @@ func TestBufferSlice_PutDataShouldWorkNilSerializedDataSize1(t *testing.T) {
 	require.Equal(t, "my dataserialized\n", returnedBuffSlice[0].String())
 }
 
+func TestCompressedBufferSlice_PutDataShouldCompressAsTheDataIsPut(t *testing.T) {
+	t.Parallel()
+
+	meta := []byte(`{ "index" : { "_index":"transactions", "_id" : "t1" } }` + "\n")
+	serializedData := []byte(`{"nonce":1,"data":"` + strings.Repeat("a", 1000) + `"}`)
+	uncompressedSize := len(meta) + len(serializedData) + 1
+
+	buffSlice := NewCompressedBufferSlice(2 * uncompressedSize)
+	for i := 0; i < 3; i++ {
+		err := buffSlice.PutData(meta, append([]byte(nil), serializedData...))
+		require.Nil(t, err)
+	}
+
+	// the threshold applies to the uncompressed size
+	returnedBuffSlice := buffSlice.Buffers()
+	require.Equal(t, 2, len(returnedBuffSlice))
+
+	expectedAction := string(meta) + string(serializedData) + "\n"
+	for idx, expectedBody := range []string{expectedAction + expectedAction, expectedAction} {
+		compressedBody := returnedBuffSlice[idx].Bytes()
+		require.True(t, IsCompressedBulkBody(compressedBody))
+		require.Less(t, len(compressedBody), len(expectedBody))
+
+		body, err := DecompressBulkBody(compressedBody)
+		require.Nil(t, err)
+		require.Equal(t, expectedBody, string(body))
+	}
+
+	// the data put after the buffers were returned goes in a new buffer
+	err := buffSlice.PutData(meta, append([]byte(nil), serializedData...))
+	require.Nil(t, err)
+	returnedBuffSlice = buffSlice.Buffers()
+	require.Equal(t, 3, len(returnedBuffSlice))
+	body, err := DecompressBulkBody(returnedBuffSlice[2].Bytes())
+	require.Nil(t, err)
+	require.Equal(t, expectedAction, string(body))
+}
+
 func generateRandomBytes(n int) []byte {
 	b := make([]byte, n)
 	_, _ = rand.Read(b)
@@ -0,0 +1,139 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	bulkLineSeparator = "\n"
+	bulkDeleteAction  = "delete"
+)
+
+var (
+	errCannotDecodeBulkAction = errors.New("cannot decode the action")
+	gzipMagicBytes            = []byte{0x1f, 0x8b}
+)
+
+// IsCompressedBulkBody returns true if the bulk body was compressed with gzip, as the ones of NewCompressedBufferSlice.
+// The uncompressed bodies always start with the JSON of an action
+func IsCompressedBulkBody(body []byte) bool {
+	return bytes.HasPrefix(body, gzipMagicBytes)
+}
+
+// DecompressBulkBody returns the uncompressed bulk body. The bodies that are not compressed are returned as they are
+func DecompressBulkBody(body []byte) ([]byte, error) {
+	if !IsCompressedBulkBody(body) {
+		return body, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	return io.ReadAll(reader)
+}
+
+// BulkAction holds an action of a bulk body, with the line of its document. The delete actions have no document line
+type BulkAction struct {
+	Operation    string
+	Index        string
+	ID           string
+	ActionLine   []byte
+	DocumentLine []byte
+}
+
+// NumLines returns the number of lines of the action in the bulk body
+func (ba *BulkAction) NumLines() int {
+	if ba.DocumentLine == nil {
+		return 1
+	}
+
+	return 2
+}
+
+// Bytes returns the lines of the action, each one followed by a new line, so they can be sent in another bulk body
+func (ba *BulkAction) Bytes() []byte {
+	result := make([]byte, 0, len(ba.ActionLine)+len(ba.DocumentLine)+2)
+	result = append(result, ba.ActionLine...)
+	result = append(result, bulkLineSeparator...)
+	if ba.DocumentLine != nil {
+		result = append(result, ba.DocumentLine...)
+		result = append(result, bulkLineSeparator...)
+	}
+
+	return result
+}
+
+// ParseBulkBody splits the provided bulk body in its actions. The empty lines are skipped and the actions without an
+// index get the default index. A compressed body is decompressed first, otherwise the returned lines share the memory
+// of the body
+func ParseBulkBody(body []byte, defaultIndex string) ([]*BulkAction, error) {
+	body, err := DecompressBulkBody(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decompressing the bulk body", err)
+	}
+
+	actions := make([]*BulkAction, 0)
+
+	var lastAction *BulkAction
+	for _, line := range bytes.Split(body, []byte(bulkLineSeparator)) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if lastAction != nil {
+			lastAction.DocumentLine = line
+			lastAction = nil
+			continue
+		}
+
+		action, err := parseBulkActionLine(line, defaultIndex)
+		if err != nil {
+			return nil, fmt.Errorf("%w at position %d", err, len(actions))
+		}
+		actions = append(actions, action)
+
+		if action.Operation != bulkDeleteAction {
+			lastAction = action
+		}
+	}
+
+	if lastAction != nil {
+		return nil, fmt.Errorf("missing the document of the action at position %d", len(actions)-1)
+	}
+
+	return actions, nil
+}
+
+func parseBulkActionLine(line []byte, defaultIndex string) (*BulkAction, error) {
+	actionMap := make(map[string]struct {
+		Index string `json:"_index"`
+		ID    string `json:"_id"`
+	})
+	err := json.Unmarshal(line, &actionMap)
+	if err != nil || len(actionMap) != 1 {
+		return nil, errCannotDecodeBulkAction
+	}
+
+	action := &BulkAction{
+		ActionLine: line,
+	}
+	for operation, meta := range actionMap {
+		action.Operation = operation
+		action.Index = meta.Index
+		action.ID = meta.ID
+	}
+	if action.Index == "" {
+		action.Index = defaultIndex
+	}
+
+	return action, nil
+}
@@ -0,0 +1,70 @@
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBulkBody(t *testing.T) {
+	t.Parallel()
+
+	indexAction := `{ "index" : { "_index":"transactions", "_id" : "t1" } }`
+	indexDocument := `{"nonce":1}`
+	updateAction := `{ "update" : { "_id" : "TKN" } }`
+	updateDocument := `{"script":{"source":"ctx._source.x = 1"},"upsert":{}}`
+	deleteAction := `{ "delete" : { "_index":"accountsesdt", "_id" : "a1" } }`
+	body := strings.Join([]string{indexAction, indexDocument, "", updateAction, updateDocument, deleteAction}, "\n") + "\n"
+
+	actions, err := ParseBulkBody([]byte(body), "tokens")
+	require.Nil(t, err)
+	require.Len(t, actions, 3)
+
+	require.Equal(t, "index", actions[0].Operation)
+	require.Equal(t, "transactions", actions[0].Index)
+	require.Equal(t, "t1", actions[0].ID)
+	require.Equal(t, 2, actions[0].NumLines())
+	require.Equal(t, indexAction+"\n"+indexDocument+"\n", string(actions[0].Bytes()))
+
+	require.Equal(t, "update", actions[1].Operation)
+	require.Equal(t, "tokens", actions[1].Index)
+	require.Equal(t, updateDocument, string(actions[1].DocumentLine))
+
+	require.Equal(t, "delete", actions[2].Operation)
+	require.Nil(t, actions[2].DocumentLine)
+	require.Equal(t, 1, actions[2].NumLines())
+	require.Equal(t, deleteAction+"\n", string(actions[2].Bytes()))
+}
+
+func TestParseBulkBody_InvalidBodyShouldErr(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseBulkBody([]byte("not json\n"), "")
+	require.ErrorContains(t, err, "cannot decode the action at position 0")
+
+	_, err = ParseBulkBody([]byte(`{"index":{},"delete":{}}`), "")
+	require.ErrorContains(t, err, "cannot decode the action at position 0")
+
+	_, err = ParseBulkBody([]byte(`{ "index" : { "_index":"transactions", "_id" : "t1" } }`+"\n"), "")
+	require.ErrorContains(t, err, "missing the document of the action at position 0")
+}
+
+func TestParseBulkBody_CompressedBody(t *testing.T) {
+	t.Parallel()
+
+	buffSlice := NewCompressedBufferSlice(DefaultMaxBulkSize)
+	require.Nil(t, buffSlice.PutData([]byte(`{ "index" : { "_id" : "t1" } }`+"\n"), []byte(`{"nonce":1}`)))
+	require.Nil(t, buffSlice.PutData([]byte(`{ "delete" : { "_index":"accountsesdt", "_id" : "a1" } }`+"\n"), nil))
+
+	actions, err := ParseBulkBody(buffSlice.Buffers()[0].Bytes(), "transactions")
+	require.Nil(t, err)
+	require.Len(t, actions, 2)
+	require.Equal(t, "transactions", actions[0].Index)
+	require.Equal(t, `{"nonce":1}`, string(actions[0].DocumentLine))
+	require.Equal(t, "delete", actions[1].Operation)
+
+	body, err := DecompressBulkBody([]byte(`{ "delete" : {} }`))
+	require.Nil(t, err)
+	require.Equal(t, `{ "delete" : {} }`, string(body))
+}
@@ -53,3 +53,43 @@ type KeyValueObj struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 }
+
+// ShardBlockInfo is the dto for the per shard block markers that are stored in the values index
+type ShardBlockInfo struct {
+	Key         string `json:"key"`
+	ShardID     uint32 `json:"shardId"`
+	HeaderHash  string `json:"headerHash"`
+	Nonce       uint64 `json:"nonce"`
+	Round       uint64 `json:"round"`
+	TimestampMs uint64 `json:"timestampMs,omitempty"`
+}
+
+// BlockCommit is the dto for the commit record of a block. It is written after all the documents of the block, so the
+// documents stamped with its commit id are complete once it exists
+type BlockCommit struct {
+	CommitID    string `json:"commitId"`
+	ShardID     uint32 `json:"shardId"`
+	Nonce       uint64 `json:"nonce"`
+	Round       uint64 `json:"round"`
+	TimestampMs uint64 `json:"timestampMs,omitempty"`
+}
+
+// ResponseCommits is the structure for the commit records response
+type ResponseCommits struct {
+	Docs []ResponseCommitDB `json:"docs"`
+}
+
+// ResponseCommitDB is the structure for the commit record response
+type ResponseCommitDB struct {
+	Found  bool        `json:"found"`
+	ID     string      `json:"_id"`
+	Source BlockCommit `json:"_source"`
+}
+
+// NonceGap is the dto for a range of header nonces of a shard that were never indexed
+type NonceGap struct {
+	ShardID      uint32 `json:"shardId"`
+	FromNonce    uint64 `json:"fromNonce"`
+	ToNonce      uint64 `json:"toNonce"`
+	DetectedAtMs uint64 `json:"detectedAtMs"`
+}
@@ -3,6 +3,7 @@ package data
 // LogEvent is the dto for the log event structure
 type LogEvent struct {
 	UUID           string   `json:"uuid"`
+	CommitID       string   `json:"commitId,omitempty"`
 	ID             string   `json:"-"`
 	TxHash         string   `json:"txHash"`
 	OriginalTxHash string   `json:"originalTxHash,omitempty"`
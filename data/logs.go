@@ -8,6 +8,7 @@ import (
 // Logs holds all the fields needed for a logs structure
 type Logs struct {
 	UUID           string   `json:"uuid"`
+	CommitID       string   `json:"commitId,omitempty"`
 	ID             string   `json:"-"`
 	OriginalTxHash string   `json:"originalTxHash,omitempty"`
 	Address        string   `json:"address"`
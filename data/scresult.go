@@ -3,6 +3,7 @@ package data
 // ScResult is a structure containing all the fields that need to be saved for a smart contract result
 type ScResult struct {
 	UUID               string    `json:"uuid"`
+	CommitID           string    `json:"commitId,omitempty"`
 	Hash               string    `json:"-"`
 	MBHash             string    `json:"miniBlockHash,omitempty"`
 	Nonce              uint64    `json:"nonce"`
@@ -5,6 +5,7 @@ package data
 // plus some extra information for ease of search and filter
 type Transaction struct {
 	UUID                 string      `json:"uuid"`
+	CommitID             string      `json:"commitId,omitempty"`
 	MBHash               string      `json:"miniBlockHash"`
 	Nonce                uint64      `json:"nonce"`
 	Round                uint64      `json:"round"`
@@ -65,6 +66,7 @@ type Receipt struct {
 	TxHash      string `json:"txHash"`
 	Timestamp   uint64 `json:"timestamp"`
 	TimestampMs uint64 `json:"timestampMs,omitempty"`
+	CommitID    string `json:"commitId,omitempty"`
 }
 
 // PreparedResults is the DTO that holds all the results after processing
@@ -4,20 +4,35 @@ import (
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-es-indexer-go/core"
 	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
 )
 
 type metricsFacade struct {
-	statusMetrics core.StatusMetricsHandler
+	statusMetrics    core.StatusMetricsHandler
+	nonceGapsHandler core.NonceGapsHandler
+	payloadHandler   core.PayloadHandler
 }
 
 // NewMetricsFacade will create a new instance of metricsFacade
-func NewMetricsFacade(statusMetrics core.StatusMetricsHandler) (*metricsFacade, error) {
+func NewMetricsFacade(
+	statusMetrics core.StatusMetricsHandler,
+	nonceGapsHandler core.NonceGapsHandler,
+	payloadHandler core.PayloadHandler,
+) (*metricsFacade, error) {
 	if check.IfNil(statusMetrics) {
 		return nil, core.ErrNilMetricsHandler
 	}
+	if check.IfNil(nonceGapsHandler) {
+		return nil, core.ErrNilNonceGapsHandler
+	}
+	if check.IfNil(payloadHandler) {
+		return nil, core.ErrNilPayloadHandler
+	}
 
 	return &metricsFacade{
-		statusMetrics: statusMetrics,
+		statusMetrics:    statusMetrics,
+		nonceGapsHandler: nonceGapsHandler,
+		payloadHandler:   payloadHandler,
 	}, nil
 }
 
@@ -31,6 +46,16 @@ func (mf *metricsFacade) GetMetricsForPrometheus() string {
 	return mf.statusMetrics.GetMetricsForPrometheus()
 }
 
+// GetNonceGaps will return the nonce gaps that are still open
+func (mf *metricsFacade) GetNonceGaps() []*data.NonceGap {
+	return mf.nonceGapsHandler.GetOpenGaps()
+}
+
+// ProcessPayload will index the provided outport payload
+func (mf *metricsFacade) ProcessPayload(payload []byte, topic string, version uint32) error {
+	return mf.payloadHandler.ProcessPayload(payload, topic, version)
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (mf *metricsFacade) IsInterfaceNil() bool {
 	return mf == nil
@@ -0,0 +1,13 @@
+package factory
+
+import (
+	"context"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/process/offline"
+)
+
+// DirIngester defines what a component that indexes a directory of outport payloads should be able to do
+type DirIngester interface {
+	Ingest(ctx context.Context) (*offline.IngestStats, error)
+	IsInterfaceNil() bool
+}
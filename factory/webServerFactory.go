@@ -8,8 +8,13 @@ import (
 )
 
 // CreateWebServer will create a new instance of core.WebServerHandler
-func CreateWebServer(apiConfig config.ApiRoutesConfig, statusMetricsHandler core.StatusMetricsHandler) (core.WebServerHandler, error) {
-	metricsFacade, err := facade.NewMetricsFacade(statusMetricsHandler)
+func CreateWebServer(
+	apiConfig config.ApiRoutesConfig,
+	statusMetricsHandler core.StatusMetricsHandler,
+	nonceGapsHandler core.NonceGapsHandler,
+	payloadHandler core.PayloadHandler,
+) (core.WebServerHandler, error) {
+	metricsFacade, err := facade.NewMetricsFacade(statusMetricsHandler, nonceGapsHandler, payloadHandler)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,9 @@
 package factory
 
 import (
+	"context"
+	"time"
+
 	"github.com/multiversx/mx-chain-communication-go/websocket/data"
 	factoryHost "github.com/multiversx/mx-chain-communication-go/websocket/factory"
 	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
@@ -9,19 +12,31 @@ import (
 	factoryMarshaller "github.com/multiversx/mx-chain-core-go/marshal/factory"
 	"github.com/multiversx/mx-chain-es-indexer-go/config"
 	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/deadletter"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/factory"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/offline"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/recorder"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/spool"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/wsindexer"
 	logger "github.com/multiversx/mx-chain-logger-go"
 )
 
+const bytesInMB = 1024 * 1024
+
 var log = logger.GetOrCreate("elasticindexer")
 
-// CreateWsIndexer will create a new instance of wsindexer.WSClient
+// CreateWsIndexer will create a new instance of wsindexer.WSClient. The requests sent to the database are interrupted
+// when the provided context is cancelled
 func CreateWsIndexer(
+	ctx context.Context,
 	cfg config.Config,
 	clusterCfg config.ClusterConfig,
 	epochsCfg config.EnableEpochsConfig,
 	statusMetrics core.StatusMetricsHandler,
+	nonceGapDetector dataindexer.NonceGapDetector,
+	databaseClient elasticproc.DatabaseClientHandler,
 	version string,
 ) (wsindexer.WSClient, error) {
 	wsMarshaller, err := factoryMarshaller.NewMarshalizer(clusterCfg.Config.WebSocket.DataMarshallerType)
@@ -29,40 +44,123 @@ func CreateWsIndexer(
 		return nil, err
 	}
 
-	dataIndexer, err := createDataIndexer(cfg, clusterCfg, epochsCfg, wsMarshaller, statusMetrics, version)
+	indexer, err := CreatePayloadIndexer(ctx, cfg, clusterCfg, epochsCfg, statusMetrics, nonceGapDetector, databaseClient, version)
 	if err != nil {
 		return nil, err
 	}
 
-	args := wsindexer.ArgsIndexer{
+	payloadHandler, err := createPayloadHandler(clusterCfg, wsMarshaller, indexer)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := getWebSocketURLs(clusterCfg)
+	hosts := make([]wsindexer.WSHost, 0, len(urls))
+	for _, url := range urls {
+		host, errCreate := createWsHost(clusterCfg, url, wsMarshaller)
+		if errCreate != nil {
+			closeHosts(hosts)
+			return nil, errCreate
+		}
+		hosts = append(hosts, host)
+	}
+
+	return wsindexer.NewMultiHost(wsindexer.ArgsMultiHost{
+		Hosts:          hosts,
+		PayloadHandler: payloadHandler,
+	})
+}
+
+func getWebSocketURLs(clusterCfg config.ClusterConfig) []string {
+	if len(clusterCfg.Config.WebSocket.URLs) > 0 {
+		return clusterCfg.Config.WebSocket.URLs
+	}
+
+	return []string{clusterCfg.Config.WebSocket.URL}
+}
+
+func closeHosts(hosts []wsindexer.WSHost) {
+	for _, host := range hosts {
+		err := host.Close()
+		if err != nil {
+			log.Warn("cannot close websocket host", "error", err)
+		}
+	}
+}
+
+// CreatePayloadIndexer will create a new instance of core.PayloadHandler that indexes the received payloads
+// without being attached to a websocket host. A non-nil database client replaces the backend from the cluster config.
+// The requests sent to the database are interrupted when the provided context is cancelled
+func CreatePayloadIndexer(
+	ctx context.Context,
+	cfg config.Config,
+	clusterCfg config.ClusterConfig,
+	epochsCfg config.EnableEpochsConfig,
+	statusMetrics core.StatusMetricsHandler,
+	nonceGapDetector dataindexer.NonceGapDetector,
+	databaseClient elasticproc.DatabaseClientHandler,
+	version string,
+) (core.PayloadHandler, error) {
+	wsMarshaller, err := factoryMarshaller.NewMarshalizer(clusterCfg.Config.WebSocket.DataMarshallerType)
+	if err != nil {
+		return nil, err
+	}
+
+	dataIndexer, err := createDataIndexer(ctx, cfg, clusterCfg, epochsCfg, wsMarshaller, statusMetrics, nonceGapDetector, databaseClient, version)
+	if err != nil {
+		return nil, err
+	}
+
+	indexer, err := wsindexer.NewIndexer(wsindexer.ArgsIndexer{
 		Marshaller:    wsMarshaller,
 		DataIndexer:   dataIndexer,
 		StatusMetrics: statusMetrics,
-	}
-	indexer, err := wsindexer.NewIndexer(args)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	host, err := createWsHost(clusterCfg, wsMarshaller)
+	return wsindexer.NewDispatcher(wsindexer.ArgsDispatcher{
+		Marshaller:     wsMarshaller,
+		PayloadHandler: indexer,
+	})
+}
+
+// CreateDirIngester will create a new component that indexes a directory of serialized outport payloads
+func CreateDirIngester(
+	clusterCfg config.ClusterConfig,
+	path string,
+	continueOnError bool,
+	payloadHandler core.PayloadHandler,
+) (DirIngester, error) {
+	wsMarshaller, err := factoryMarshaller.NewMarshalizer(clusterCfg.Config.WebSocket.DataMarshallerType)
 	if err != nil {
 		return nil, err
 	}
 
-	err = host.SetPayloadHandler(indexer)
+	blockContainer, err := factory.CreateBlockCreatorsContainer()
 	if err != nil {
 		return nil, err
 	}
 
-	return host, nil
+	return offline.NewDirIngester(offline.ArgsDirIngester{
+		Path:            path,
+		Marshaller:      wsMarshaller,
+		BlockContainer:  blockContainer,
+		PayloadHandler:  payloadHandler,
+		ContinueOnError: continueOnError,
+	})
 }
 
 func createDataIndexer(
+	ctx context.Context,
 	cfg config.Config,
 	clusterCfg config.ClusterConfig,
 	enableEpochsCfg config.EnableEpochsConfig,
 	wsMarshaller marshal.Marshalizer,
 	statusMetrics core.StatusMetricsHandler,
+	nonceGapDetector dataindexer.NonceGapDetector,
+	databaseClient elasticproc.DatabaseClientHandler,
 	version string,
 ) (wsindexer.DataIndexer, error) {
 	marshaller, err := factoryMarshaller.NewMarshalizer(cfg.Config.Marshaller.Type)
@@ -83,12 +181,19 @@ func createDataIndexer(
 	}
 
 	return factory.NewIndexer(factory.ArgsIndexerFactory{
+		Context:                  ctx,
 		UseKibana:                clusterCfg.Config.ElasticCluster.UseKibana,
 		Denomination:             cfg.Config.Economics.Denomination,
 		BulkRequestMaxSize:       clusterCfg.Config.ElasticCluster.BulkRequestMaxSizeInBytes,
+		BulkRequestWorkers:       clusterCfg.Config.ElasticCluster.BulkRequestWorkers,
 		Url:                      clusterCfg.Config.ElasticCluster.URL,
+		Urls:                     clusterCfg.Config.ElasticCluster.URLs,
+		SniffingConfig:           clusterCfg.Config.ElasticCluster.Sniffing,
 		UserName:                 clusterCfg.Config.ElasticCluster.UserName,
 		Password:                 clusterCfg.Config.ElasticCluster.Password,
+		APIKey:                   clusterCfg.Config.ElasticCluster.APIKey,
+		BearerToken:              clusterCfg.Config.ElasticCluster.BearerToken,
+		TLSConfig:                clusterCfg.Config.ElasticCluster.TLS,
 		EnabledIndexes:           prepareIndices(cfg.Config.AvailableIndices, clusterCfg.Config.DisabledIndices),
 		Marshalizer:              marshaller,
 		Hasher:                   hasher,
@@ -96,8 +201,17 @@ func createDataIndexer(
 		ValidatorPubkeyConverter: validatorPubkeyConverter,
 		HeaderMarshaller:         wsMarshaller,
 		StatusMetrics:            statusMetrics,
+		NonceGapDetector:         nonceGapDetector,
 		Version:                  version,
 		EnableEpochsConfig:       enableEpochsCfg,
+		Backend:                  clusterCfg.Config.ElasticCluster.Backend,
+		ISMConfig:                clusterCfg.Config.ElasticCluster.ISM,
+		DatabaseClient:           databaseClient,
+		BulkSinkConfig:           clusterCfg.Config.BulkSink,
+		MirrorConfig:             clusterCfg.Config.ElasticCluster.Mirror,
+		RetryPolicyConfig:        clusterCfg.Config.ElasticCluster.RetryPolicy,
+		CompressBulkRequests:     clusterCfg.Config.ElasticCluster.CompressBulkRequests,
+		TimeoutsConfig:           clusterCfg.Config.ElasticCluster.Timeouts,
 	})
 }
 
@@ -120,10 +234,96 @@ func prepareIndices(availableIndices, disabledIndices []string) []string {
 	return indices
 }
 
-func createWsHost(clusterCfg config.ClusterConfig, wsMarshaller marshal.Marshalizer) (factoryHost.FullDuplexHost, error) {
+// deadLetterStoreHandler defines the behavior of the dead-letter store, which wraps the indexer or receives the
+// payloads the spool gives up on
+type deadLetterStoreHandler interface {
+	core.PayloadHandler
+	SavePayload(payload []byte, topic string, version uint32, processingErr error) error
+}
+
+// createPayloadHandler chains, from the indexer outwards, the dead-letter store or the spool, and the recorder. The
+// recorder is at ingress, so a payload is recorded once even if the spool retries it. With the spool enabled, the
+// dead-letter store only receives the payloads that failed the maximum number of attempts
+func createPayloadHandler(
+	clusterCfg config.ClusterConfig,
+	wsMarshaller marshal.Marshalizer,
+	indexer core.PayloadHandler,
+) (core.PayloadHandler, error) {
+	deadLetterStore, err := createDeadLetterStore(clusterCfg, wsMarshaller, indexer)
+	if err != nil {
+		return nil, err
+	}
+
+	var payloadHandler core.PayloadHandler = indexer
+	spoolCfg := clusterCfg.Config.Spool
+	switch {
+	case spoolCfg.Enabled:
+		var deadLetterHandler spool.DeadLetterHandler
+		if deadLetterStore != nil {
+			deadLetterHandler = deadLetterStore
+		}
+		payloadHandler, err = spool.NewDiskSpool(spool.ArgsDiskSpool{
+			Path:              spoolCfg.Path,
+			MaxSizeInBytes:    spoolCfg.MaxSizeInMB * bytesInMB,
+			RetryDuration:     time.Duration(spoolCfg.RetryDurationInSec) * time.Second,
+			MaxAttempts:       spoolCfg.MaxAttempts,
+			Marshaller:        wsMarshaller,
+			PayloadHandler:    indexer,
+			DeadLetterHandler: deadLetterHandler,
+		})
+		if err != nil {
+			return nil, err
+		}
+	case deadLetterStore != nil:
+		payloadHandler = deadLetterStore
+	}
+
+	recorderCfg := clusterCfg.Config.PayloadsRecorder
+	if !recorderCfg.Enabled {
+		return payloadHandler, nil
+	}
+
+	return recorder.NewPayloadRecorder(recorder.ArgsPayloadRecorder{
+		Path:           recorderCfg.Path,
+		MaxFileSize:    recorderCfg.MaxFileSizeInMB * bytesInMB,
+		PayloadHandler: payloadHandler,
+	})
+}
+
+// createDeadLetterStore returns nil when the dead-letter store is disabled, or when the failed payloads are sent again
+// by the node, without the spool
+func createDeadLetterStore(
+	clusterCfg config.ClusterConfig,
+	wsMarshaller marshal.Marshalizer,
+	indexer core.PayloadHandler,
+) (deadLetterStoreHandler, error) {
+	deadLetterCfg := clusterCfg.Config.DeadLetter
+	if !deadLetterCfg.Enabled {
+		return nil, nil
+	}
+
+	if clusterCfg.Config.WebSocket.BlockingAckOnError && !clusterCfg.Config.Spool.Enabled {
+		log.Warn("the dead-letter store is not used because the failed payloads are sent again by the node",
+			"blocking-ack-on-error", clusterCfg.Config.WebSocket.BlockingAckOnError)
+		return nil, nil
+	}
+	if clusterCfg.Config.Spool.Enabled && clusterCfg.Config.Spool.MaxAttempts == 0 {
+		log.Warn("the dead-letter store is not used because the spool retries the failed payloads until they are indexed",
+			"max-attempts", clusterCfg.Config.Spool.MaxAttempts)
+		return nil, nil
+	}
+
+	return deadletter.NewDeadLetterStore(deadletter.ArgsDeadLetterStore{
+		Path:           deadLetterCfg.Path,
+		Marshaller:     wsMarshaller,
+		PayloadHandler: indexer,
+	})
+}
+
+func createWsHost(clusterCfg config.ClusterConfig, url string, wsMarshaller marshal.Marshalizer) (factoryHost.FullDuplexHost, error) {
 	return factoryHost.CreateWebSocketHost(factoryHost.ArgsWebSocketHost{
 		WebSocketConfig: data.WebSocketConfig{
-			URL:                     clusterCfg.Config.WebSocket.URL,
+			URL:                     url,
 			WithAcknowledge:         clusterCfg.Config.WebSocket.WithAcknowledge,
 			Mode:                    clusterCfg.Config.WebSocket.Mode,
 			RetryDurationInSec:      int(clusterCfg.Config.WebSocket.RetryDurationInSec),
@@ -1,8 +1,17 @@
 package factory
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/recorder"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,3 +30,42 @@ func TestPrepareIndices(t *testing.T) {
 	res = prepareIndices(available, disabled)
 	require.Equal(t, []string{"index1", "index2"}, res)
 }
+
+func TestCreatePayloadHandler_SpoolRetriesShouldBeRecordedOnce(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	clusterCfg := config.ClusterConfig{}
+	clusterCfg.Config.Spool.Enabled = true
+	clusterCfg.Config.Spool.Path = filepath.Join(dir, "spool")
+	clusterCfg.Config.Spool.RetryDurationInSec = 1
+	clusterCfg.Config.PayloadsRecorder.Enabled = true
+	clusterCfg.Config.PayloadsRecorder.Path = filepath.Join(dir, "records")
+
+	numCalls := int32(0)
+	indexer := &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			if atomic.AddInt32(&numCalls, 1) == 1 {
+				return errors.New("local error")
+			}
+			return nil
+		},
+	}
+	payloadHandler, err := createPayloadHandler(clusterCfg, &marshal.JsonMarshalizer{}, indexer)
+	require.Nil(t, err)
+
+	err = payloadHandler.ProcessPayload([]byte("payload"), "SaveBlock", 1)
+	require.Nil(t, err)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&numCalls) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Nil(t, payloadHandler.Close())
+
+	replayer, _ := recorder.NewReplayer(recorder.ArgsReplayer{
+		Path:           clusterCfg.Config.PayloadsRecorder.Path,
+		PayloadHandler: &mock.PayloadHandlerStub{},
+	})
+	stats, err := replayer.Replay(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, 1, stats.NumFrames)
+}
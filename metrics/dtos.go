@@ -10,3 +10,19 @@ type ArgsAddIndexingData struct {
 	Topic      string
 	Duration   time.Duration
 }
+
+// ArgsMirrorClusterHealth holds the health of a cluster that receives a copy of every write
+type ArgsMirrorClusterHealth struct {
+	Cluster         string
+	Healthy         bool
+	OutOfSync       bool
+	PendingRequests uint64
+	PendingBytes    uint64
+}
+
+// ArgsAddPayloadVersion holds the topic and the version of a received payload
+type ArgsAddPayloadVersion struct {
+	Topic    string
+	Version  uint32
+	GotError bool
+}
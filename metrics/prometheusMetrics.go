@@ -13,6 +13,9 @@ const (
 	operationName = "operation"
 	shardIDName   = "shardID"
 	errorCodeName = "errorCode"
+	clusterName   = "cluster"
+	topicName     = "topic"
+	versionName   = "version"
 )
 
 func counterMetric(metricName, operation string, shardIDStr string, count uint64) string {
@@ -84,3 +87,59 @@ func promMetricAsString(metric *dto.MetricFamily) string {
 
 	return out.String() + "\n"
 }
+
+func clusterGaugeMetric(metricName, operation string, cluster string, value uint64) string {
+	metricFamily := &dto.MetricFamily{
+		Name: proto.String(metricName),
+		Type: dto.MetricType_GAUGE.Enum(),
+		Metric: []*dto.Metric{
+			{
+				Label: []*dto.LabelPair{
+					{
+						Name:  proto.String(operationName),
+						Value: proto.String(operation),
+					},
+					{
+						Name:  proto.String(clusterName),
+						Value: proto.String(cluster),
+					},
+				},
+				Gauge: &dto.Gauge{
+					Value: proto.Float64(float64(value)),
+				},
+			},
+		},
+	}
+
+	return promMetricAsString(metricFamily)
+}
+
+func versionCounterMetric(metricName, operation string, topic string, versionStr string, count uint64) string {
+	metricFamily := &dto.MetricFamily{
+		Name: proto.String(metricName),
+		Type: dto.MetricType_COUNTER.Enum(),
+		Metric: []*dto.Metric{
+			{
+				Label: []*dto.LabelPair{
+					{
+						Name:  proto.String(operationName),
+						Value: proto.String(operation),
+					},
+					{
+						Name:  proto.String(topicName),
+						Value: proto.String(topic),
+					},
+					{
+						Name:  proto.String(versionName),
+						Value: proto.String(versionStr),
+					},
+				},
+				Counter: &dto.Counter{
+					Value: proto.Float64(float64(count)),
+				},
+			},
+		},
+	}
+
+	return promMetricAsString(metricFamily)
+}
@@ -3,6 +3,7 @@ package metrics
 import (
 	"bytes"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"unicode"
@@ -16,17 +17,47 @@ const (
 	totalTime      = "total_time"
 	totalData      = "total_data"
 	requestsErrors = "requests_errors"
+	nonceGaps      = "nonce_gaps"
+	gapsCount      = "gaps_count"
+	missingNonces  = "missing_nonces"
+	mirrorClusters = "mirror_clusters"
+	healthy        = "healthy"
+	outOfSync      = "out_of_sync"
+	pendingReqs    = "pending_requests"
+	pendingBytes   = "pending_bytes"
+	payloadVersion = "payload_versions"
 )
 
+type nonceGapsMetrics struct {
+	gapsCount     uint64
+	missingNonces uint64
+}
+
+type payloadVersionKey struct {
+	topic   string
+	version uint32
+}
+
+type payloadVersionMetrics struct {
+	operationsCount uint64
+	errorsCount     uint64
+}
+
 type statusMetrics struct {
-	metrics map[string]*request.MetricsResponse
-	mut     sync.RWMutex
+	metrics         map[string]*request.MetricsResponse
+	nonceGaps       map[uint32]*nonceGapsMetrics
+	mirrorClusters  map[string]ArgsMirrorClusterHealth
+	payloadVersions map[payloadVersionKey]*payloadVersionMetrics
+	mut             sync.RWMutex
 }
 
 // NewStatusMetrics will return an instance of the statusMetrics
 func NewStatusMetrics() *statusMetrics {
 	return &statusMetrics{
-		metrics: make(map[string]*request.MetricsResponse),
+		metrics:         make(map[string]*request.MetricsResponse),
+		nonceGaps:       make(map[uint32]*nonceGapsMetrics),
+		mirrorClusters:  make(map[string]ArgsMirrorClusterHealth),
+		payloadVersions: make(map[payloadVersionKey]*payloadVersionMetrics),
 	}
 }
 
@@ -56,6 +87,48 @@ func (sm *statusMetrics) AddIndexingData(args ArgsAddIndexingData) {
 	}
 }
 
+// AddNonceGap will count a nonce gap detected for the provided shard, together with the number of nonces it misses
+func (sm *statusMetrics) AddNonceGap(shardID uint32, numMissingNonces uint64) {
+	sm.mut.Lock()
+	defer sm.mut.Unlock()
+
+	_, found := sm.nonceGaps[shardID]
+	if !found {
+		sm.nonceGaps[shardID] = &nonceGapsMetrics{}
+	}
+
+	sm.nonceGaps[shardID].gapsCount++
+	sm.nonceGaps[shardID].missingNonces += numMissingNonces
+}
+
+// AddPayloadVersion will count a payload received for the provided topic and version. The payloads are counted apart
+// from the indexing data of the topics, so they are not counted twice in the topic metrics
+func (sm *statusMetrics) AddPayloadVersion(args ArgsAddPayloadVersion) {
+	sm.mut.Lock()
+	defer sm.mut.Unlock()
+
+	key := payloadVersionKey{
+		topic:   camelToSnake(args.Topic),
+		version: args.Version,
+	}
+	_, found := sm.payloadVersions[key]
+	if !found {
+		sm.payloadVersions[key] = &payloadVersionMetrics{}
+	}
+
+	sm.payloadVersions[key].operationsCount++
+	if args.GotError {
+		sm.payloadVersions[key].errorsCount++
+	}
+}
+
+// SetMirrorClusterHealth will replace the health of the provided mirror cluster
+func (sm *statusMetrics) SetMirrorClusterHealth(args ArgsMirrorClusterHealth) {
+	sm.mut.Lock()
+	sm.mirrorClusters[args.Cluster] = args
+	sm.mut.Unlock()
+}
+
 // GetMetrics returns the metrics map
 func (sm *statusMetrics) GetMetrics() map[string]*request.MetricsResponse {
 	sm.mut.RLock()
@@ -68,6 +141,18 @@ func (sm *statusMetrics) GetMetrics() map[string]*request.MetricsResponse {
 func (sm *statusMetrics) GetMetricsForPrometheus() string {
 	sm.mut.RLock()
 	metrics := sm.getAllUnprotected()
+	gapsMetrics := make(map[uint32]nonceGapsMetrics, len(sm.nonceGaps))
+	for shardID, shardGapsMetrics := range sm.nonceGaps {
+		gapsMetrics[shardID] = *shardGapsMetrics
+	}
+	clustersHealth := make([]ArgsMirrorClusterHealth, 0, len(sm.mirrorClusters))
+	for _, clusterHealth := range sm.mirrorClusters {
+		clustersHealth = append(clustersHealth, clusterHealth)
+	}
+	versionsMetrics := make(map[payloadVersionKey]payloadVersionMetrics, len(sm.payloadVersions))
+	for key, versionMetrics := range sm.payloadVersions {
+		versionsMetrics[key] = *versionMetrics
+	}
 	sm.mut.RUnlock()
 
 	stringBuilder := strings.Builder{}
@@ -81,6 +166,25 @@ func (sm *statusMetrics) GetMetricsForPrometheus() string {
 		stringBuilder.WriteString(errorsMetric(topic, requestsErrors, shardIDStr, metricsData.ErrorsCount))
 	}
 
+	for shardID, shardGapsMetrics := range gapsMetrics {
+		shardIDStr := strconv.FormatUint(uint64(shardID), 10)
+		stringBuilder.WriteString(counterMetric(nonceGaps, gapsCount, shardIDStr, shardGapsMetrics.gapsCount))
+		stringBuilder.WriteString(counterMetric(nonceGaps, missingNonces, shardIDStr, shardGapsMetrics.missingNonces))
+	}
+
+	for _, clusterHealth := range clustersHealth {
+		stringBuilder.WriteString(clusterGaugeMetric(mirrorClusters, healthy, clusterHealth.Cluster, boolToUint64(clusterHealth.Healthy)))
+		stringBuilder.WriteString(clusterGaugeMetric(mirrorClusters, outOfSync, clusterHealth.Cluster, boolToUint64(clusterHealth.OutOfSync)))
+		stringBuilder.WriteString(clusterGaugeMetric(mirrorClusters, pendingReqs, clusterHealth.Cluster, clusterHealth.PendingRequests))
+		stringBuilder.WriteString(clusterGaugeMetric(mirrorClusters, pendingBytes, clusterHealth.Cluster, clusterHealth.PendingBytes))
+	}
+
+	for key, versionMetrics := range versionsMetrics {
+		versionStr := strconv.FormatUint(uint64(key.version), 10)
+		stringBuilder.WriteString(versionCounterMetric(payloadVersion, operationCount, key.topic, versionStr, versionMetrics.operationsCount))
+		stringBuilder.WriteString(versionCounterMetric(payloadVersion, errorsCount, key.topic, versionStr, versionMetrics.errorsCount))
+	}
+
 	promMetricsOutput := stringBuilder.String()
 
 	return promMetricsOutput
@@ -100,6 +204,14 @@ func (sm *statusMetrics) IsInterfaceNil() bool {
 	return sm == nil
 }
 
+func boolToUint64(value bool) uint64 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
+
 func camelToSnake(camelStr string) string {
 	var snakeBuf bytes.Buffer
 
@@ -60,6 +60,41 @@ test1{operation="requests_errors",shardID="0",errorCode="400"} 1
 `, prometheusMetrics)
 }
 
+func TestStatusMetrics_AddNonceGap(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	statusMetricsHandler.AddNonceGap(1, 3)
+	statusMetricsHandler.AddNonceGap(1, 2)
+
+	require.Empty(t, statusMetricsHandler.GetMetrics())
+	require.Equal(t, `# TYPE nonce_gaps counter
+nonce_gaps{operation="gaps_count",shardID="1"} 2
+
+# TYPE nonce_gaps counter
+nonce_gaps{operation="missing_nonces",shardID="1"} 5
+
+`, statusMetricsHandler.GetMetricsForPrometheus())
+}
+
+func TestStatusMetrics_AddPayloadVersion(t *testing.T) {
+	t.Parallel()
+
+	statusMetricsHandler := NewStatusMetrics()
+	statusMetricsHandler.AddPayloadVersion(ArgsAddPayloadVersion{Topic: outport.TopicSaveBlock, Version: 2})
+	statusMetricsHandler.AddPayloadVersion(ArgsAddPayloadVersion{Topic: outport.TopicSaveBlock, Version: 2, GotError: true})
+
+	// the payload versions are not counted in the metrics of the topics
+	require.Empty(t, statusMetricsHandler.GetMetrics())
+	require.Equal(t, `# TYPE payload_versions counter
+payload_versions{operation="operations_count",topic="save_block",version="2"} 2
+
+# TYPE payload_versions counter
+payload_versions{operation="errors_count",topic="save_block",version="2"} 1
+
+`, statusMetricsHandler.GetMetricsForPrometheus())
+}
+
 func TestCamelCaseToSnakeCase(t *testing.T) {
 	t.Parallel()
 
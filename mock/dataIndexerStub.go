@@ -0,0 +1,102 @@
+package mock
+
+import "github.com/multiversx/mx-chain-core-go/data/outport"
+
+// DataIndexerStub -
+type DataIndexerStub struct {
+	SaveBlockCalled             func(outportBlock *outport.OutportBlock) error
+	RevertIndexedBlockCalled    func(blockData *outport.BlockData) error
+	SaveRoundsInfoCalled        func(roundsInfos *outport.RoundsInfo) error
+	SaveValidatorsPubKeysCalled func(validatorsPubKeys *outport.ValidatorsPubKeys) error
+	SaveValidatorsRatingCalled  func(ratingData *outport.ValidatorsRating) error
+	SaveAccountsCalled          func(accountsData *outport.Accounts) error
+	FinalizedBlockCalled        func(finalizedBlock *outport.FinalizedBlock) error
+	SetCurrentSettingsCalled    func(settings outport.OutportConfig) error
+	CloseCalled                 func() error
+}
+
+// SaveBlock -
+func (dis *DataIndexerStub) SaveBlock(outportBlock *outport.OutportBlock) error {
+	if dis.SaveBlockCalled != nil {
+		return dis.SaveBlockCalled(outportBlock)
+	}
+
+	return nil
+}
+
+// RevertIndexedBlock -
+func (dis *DataIndexerStub) RevertIndexedBlock(blockData *outport.BlockData) error {
+	if dis.RevertIndexedBlockCalled != nil {
+		return dis.RevertIndexedBlockCalled(blockData)
+	}
+
+	return nil
+}
+
+// SaveRoundsInfo -
+func (dis *DataIndexerStub) SaveRoundsInfo(roundsInfos *outport.RoundsInfo) error {
+	if dis.SaveRoundsInfoCalled != nil {
+		return dis.SaveRoundsInfoCalled(roundsInfos)
+	}
+
+	return nil
+}
+
+// SaveValidatorsPubKeys -
+func (dis *DataIndexerStub) SaveValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error {
+	if dis.SaveValidatorsPubKeysCalled != nil {
+		return dis.SaveValidatorsPubKeysCalled(validatorsPubKeys)
+	}
+
+	return nil
+}
+
+// SaveValidatorsRating -
+func (dis *DataIndexerStub) SaveValidatorsRating(ratingData *outport.ValidatorsRating) error {
+	if dis.SaveValidatorsRatingCalled != nil {
+		return dis.SaveValidatorsRatingCalled(ratingData)
+	}
+
+	return nil
+}
+
+// SaveAccounts -
+func (dis *DataIndexerStub) SaveAccounts(accountsData *outport.Accounts) error {
+	if dis.SaveAccountsCalled != nil {
+		return dis.SaveAccountsCalled(accountsData)
+	}
+
+	return nil
+}
+
+// FinalizedBlock -
+func (dis *DataIndexerStub) FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error {
+	if dis.FinalizedBlockCalled != nil {
+		return dis.FinalizedBlockCalled(finalizedBlock)
+	}
+
+	return nil
+}
+
+// SetCurrentSettings -
+func (dis *DataIndexerStub) SetCurrentSettings(settings outport.OutportConfig) error {
+	if dis.SetCurrentSettingsCalled != nil {
+		return dis.SetCurrentSettingsCalled(settings)
+	}
+
+	return nil
+}
+
+// Close -
+func (dis *DataIndexerStub) Close() error {
+	if dis.CloseCalled != nil {
+		return dis.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (dis *DataIndexerStub) IsInterfaceNil() bool {
+	return dis == nil
+}
@@ -0,0 +1,20 @@
+package mock
+
+// DeadLetterHandlerStub -
+type DeadLetterHandlerStub struct {
+	SavePayloadCalled func(payload []byte, topic string, version uint32, processingErr error) error
+}
+
+// SavePayload -
+func (dlhs *DeadLetterHandlerStub) SavePayload(payload []byte, topic string, version uint32, processingErr error) error {
+	if dlhs.SavePayloadCalled != nil {
+		return dlhs.SavePayloadCalled(payload, topic, version, processingErr)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (dlhs *DeadLetterHandlerStub) IsInterfaceNil() bool {
+	return dlhs == nil
+}
@@ -4,6 +4,7 @@ import (
 	coreData "github.com/multiversx/mx-chain-core-go/data"
 	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
 )
 
 // ElasticProcessorStub -
@@ -19,6 +20,15 @@ type ElasticProcessorStub struct {
 	SaveShardValidatorsPubKeysCalled func(validators *outport.ValidatorsPubKeys) error
 	SaveAccountsCalled               func(accountsData *outport.Accounts) error
 	RemoveAccountsESDTCalled         func(shardID uint32, timestampMS uint64) error
+	SaveFinalizedBlockCalled         func(finalizedBlock *outport.FinalizedBlock) error
+	SavePendingCommitCalled          func(header coreData.HeaderHandler, headerHash []byte) error
+	SaveCommitCalled                 func(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error
+	SaveCheckpointCalled             func(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error
+	RevertCheckpointCalled           func(header coreData.HeaderHandler, headerHash []byte) error
+	GetCheckpointsCalled             func() (map[uint32]*data.ShardBlockInfo, error)
+	SaveNonceGapsCalled              func(shardID uint32, removedGaps []*data.NonceGap, addedGaps []*data.NonceGap) error
+	GetNonceGapsCalled               func() ([]*data.NonceGap, error)
+	CloseCalled                      func() error
 }
 
 // RemoveAccountsESDT -
@@ -111,11 +121,92 @@ func (eim *ElasticProcessorStub) SaveAccounts(accounts *outport.Accounts) error
 	return nil
 }
 
+// SaveFinalizedBlock -
+func (eim *ElasticProcessorStub) SaveFinalizedBlock(finalizedBlock *outport.FinalizedBlock) error {
+	if eim.SaveFinalizedBlockCalled != nil {
+		return eim.SaveFinalizedBlockCalled(finalizedBlock)
+	}
+
+	return nil
+}
+
+// SavePendingCommit -
+func (eim *ElasticProcessorStub) SavePendingCommit(header coreData.HeaderHandler, headerHash []byte) error {
+	if eim.SavePendingCommitCalled != nil {
+		return eim.SavePendingCommitCalled(header, headerHash)
+	}
+
+	return nil
+}
+
+// SaveCommit -
+func (eim *ElasticProcessorStub) SaveCommit(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error {
+	if eim.SaveCommitCalled != nil {
+		return eim.SaveCommitCalled(header, headerHash, timestampMs)
+	}
+
+	return nil
+}
+
+// SaveCheckpoint -
+func (eim *ElasticProcessorStub) SaveCheckpoint(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error {
+	if eim.SaveCheckpointCalled != nil {
+		return eim.SaveCheckpointCalled(header, headerHash, timestampMs)
+	}
+
+	return nil
+}
+
+// RevertCheckpoint -
+func (eim *ElasticProcessorStub) RevertCheckpoint(header coreData.HeaderHandler, headerHash []byte) error {
+	if eim.RevertCheckpointCalled != nil {
+		return eim.RevertCheckpointCalled(header, headerHash)
+	}
+
+	return nil
+}
+
+// GetCheckpoints -
+func (eim *ElasticProcessorStub) GetCheckpoints() (map[uint32]*data.ShardBlockInfo, error) {
+	if eim.GetCheckpointsCalled != nil {
+		return eim.GetCheckpointsCalled()
+	}
+
+	return make(map[uint32]*data.ShardBlockInfo), nil
+}
+
+// SaveNonceGaps -
+func (eim *ElasticProcessorStub) SaveNonceGaps(shardID uint32, removedGaps []*data.NonceGap, addedGaps []*data.NonceGap) error {
+	if eim.SaveNonceGapsCalled != nil {
+		return eim.SaveNonceGapsCalled(shardID, removedGaps, addedGaps)
+	}
+
+	return nil
+}
+
+// GetNonceGaps -
+func (eim *ElasticProcessorStub) GetNonceGaps() ([]*data.NonceGap, error) {
+	if eim.GetNonceGapsCalled != nil {
+		return eim.GetNonceGapsCalled()
+	}
+
+	return make([]*data.NonceGap, 0), nil
+}
+
 // SetOutportConfig -
 func (eim *ElasticProcessorStub) SetOutportConfig(_ outport.OutportConfig) error {
 	return nil
 }
 
+// Close -
+func (eim *ElasticProcessorStub) Close() error {
+	if eim.CloseCalled != nil {
+		return eim.CloseCalled()
+	}
+
+	return nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (eim *ElasticProcessorStub) IsInterfaceNil() bool {
 	return eim == nil
@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+)
+
+// FacadeStub -
+type FacadeStub struct {
+	GetMetricsCalled              func() map[string]*request.MetricsResponse
+	GetMetricsForPrometheusCalled func() string
+	GetNonceGapsCalled            func() []*data.NonceGap
+	ProcessPayloadCalled          func(payload []byte, topic string, version uint32) error
+}
+
+// GetMetrics -
+func (fs *FacadeStub) GetMetrics() map[string]*request.MetricsResponse {
+	if fs.GetMetricsCalled != nil {
+		return fs.GetMetricsCalled()
+	}
+	return nil
+}
+
+// GetMetricsForPrometheus -
+func (fs *FacadeStub) GetMetricsForPrometheus() string {
+	if fs.GetMetricsForPrometheusCalled != nil {
+		return fs.GetMetricsForPrometheusCalled()
+	}
+	return ""
+}
+
+// GetNonceGaps -
+func (fs *FacadeStub) GetNonceGaps() []*data.NonceGap {
+	if fs.GetNonceGapsCalled != nil {
+		return fs.GetNonceGapsCalled()
+	}
+	return nil
+}
+
+// ProcessPayload -
+func (fs *FacadeStub) ProcessPayload(payload []byte, topic string, version uint32) error {
+	if fs.ProcessPayloadCalled != nil {
+		return fs.ProcessPayloadCalled(payload, topic, version)
+	}
+	return nil
+}
+
+// IsInterfaceNil -
+func (fs *FacadeStub) IsInterfaceNil() bool {
+	return fs == nil
+}
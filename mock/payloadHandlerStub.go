@@ -0,0 +1,30 @@
+package mock
+
+// PayloadHandlerStub -
+type PayloadHandlerStub struct {
+	ProcessPayloadCalled func(payload []byte, topic string, version uint32) error
+	CloseCalled          func() error
+}
+
+// ProcessPayload -
+func (phs *PayloadHandlerStub) ProcessPayload(payload []byte, topic string, version uint32) error {
+	if phs.ProcessPayloadCalled != nil {
+		return phs.ProcessPayloadCalled(payload, topic, version)
+	}
+
+	return nil
+}
+
+// Close -
+func (phs *PayloadHandlerStub) Close() error {
+	if phs.CloseCalled != nil {
+		return phs.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (phs *PayloadHandlerStub) IsInterfaceNil() bool {
+	return phs == nil
+}
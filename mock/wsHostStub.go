@@ -0,0 +1,42 @@
+package mock
+
+import "github.com/multiversx/mx-chain-communication-go/websocket"
+
+// WSHostStub -
+type WSHostStub struct {
+	SendCalled              func(payload []byte, topic string) error
+	SetPayloadHandlerCalled func(handler websocket.PayloadHandler) error
+	CloseCalled             func() error
+}
+
+// Send -
+func (w *WSHostStub) Send(payload []byte, topic string) error {
+	if w.SendCalled != nil {
+		return w.SendCalled(payload, topic)
+	}
+
+	return nil
+}
+
+// SetPayloadHandler -
+func (w *WSHostStub) SetPayloadHandler(handler websocket.PayloadHandler) error {
+	if w.SetPayloadHandlerCalled != nil {
+		return w.SetPayloadHandlerCalled(handler)
+	}
+
+	return nil
+}
+
+// Close -
+func (w *WSHostStub) Close() error {
+	if w.CloseCalled != nil {
+		return w.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (w *WSHostStub) IsInterfaceNil() bool {
+	return w == nil
+}
@@ -49,6 +49,10 @@ const (
 	ValuesIndex = "values"
 	// EventsIndex is the Elasticsearch index for log events
 	EventsIndex = "events"
+	// NonceGapsIndex is the Elasticsearch index for the ranges of header nonces that were never indexed
+	NonceGapsIndex = "noncegaps"
+	// CommitsIndex is the Elasticsearch index for the commit records of the fully indexed blocks
+	CommitsIndex = "commits"
 
 	// TransactionsPolicy is the Elasticsearch policy for the transactions
 	TransactionsPolicy = "transactions_policy"
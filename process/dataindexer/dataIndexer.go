@@ -1,6 +1,7 @@
 package dataindexer
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 	"github.com/multiversx/mx-chain-core-go/marshal"
+	indexerData "github.com/multiversx/mx-chain-es-indexer-go/data"
 	logger "github.com/multiversx/mx-chain-logger-go"
 )
 
@@ -18,30 +20,44 @@ var log = logger.GetOrCreate("dataindexer")
 
 // ArgDataIndexer is a structure that is used to store all the components that are needed to create an indexer
 type ArgDataIndexer struct {
+	Context          context.Context
 	HeaderMarshaller marshal.Marshalizer
 	ElasticProcessor ElasticProcessor
 	BlockContainer   BlockContainerHandler
+	NonceGapDetector NonceGapDetector
 }
 
 type dataIndexer struct {
+	ctx              context.Context
 	elasticProcessor ElasticProcessor
 	headerMarshaller marshal.Marshalizer
 	blockContainer   BlockContainerHandler
+	nonceGapDetector NonceGapDetector
 }
 
-// NewDataIndexer will create a new data indexer
+// NewDataIndexer will create a new data indexer. Once the provided context is cancelled, the blocks are no longer
+// indexed or reverted. A nil context is never cancelled
 func NewDataIndexer(arguments ArgDataIndexer) (*dataIndexer, error) {
 	err := checkIndexerArgs(arguments)
 	if err != nil {
 		return nil, err
 	}
 
+	ctx := arguments.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	dataIndexerObj := &dataIndexer{
+		ctx:              ctx,
 		elasticProcessor: arguments.ElasticProcessor,
 		headerMarshaller: arguments.HeaderMarshaller,
 		blockContainer:   arguments.BlockContainer,
+		nonceGapDetector: arguments.NonceGapDetector,
 	}
 
+	dataIndexerObj.initNonceGapDetector()
+
 	return dataIndexerObj, nil
 }
 
@@ -55,10 +71,43 @@ func checkIndexerArgs(arguments ArgDataIndexer) error {
 	if check.IfNilReflect(arguments.BlockContainer) {
 		return ErrNilBlockContainerHandler
 	}
+	if check.IfNil(arguments.NonceGapDetector) {
+		return ErrNilNonceGapDetector
+	}
 
 	return nil
 }
 
+// initNonceGapDetector will load the last indexed nonce of every shard and the open nonce gaps, so the gaps that
+// happen while the indexer is stopped are detected as well
+func (di *dataIndexer) initNonceGapDetector() {
+	checkpoints, err := di.elasticProcessor.GetCheckpoints()
+	if err != nil {
+		log.Warn("dataIndexer: cannot load the indexing checkpoints", "error", err)
+		return
+	}
+
+	openGaps, err := di.elasticProcessor.GetNonceGaps()
+	if err != nil {
+		log.Warn("dataIndexer: cannot load the nonce gaps", "error", err)
+		return
+	}
+
+	lastNonces := make(map[uint32]uint64, len(checkpoints))
+	for shardID, checkpoint := range checkpoints {
+		lastNonces[shardID] = checkpoint.Nonce
+		log.Info("indexing checkpoint",
+			"shardID", checkpoint.ShardID,
+			"nonce", checkpoint.Nonce,
+			"round", checkpoint.Round,
+			"hash", checkpoint.HeaderHash,
+			"timestampMs", checkpoint.TimestampMs,
+		)
+	}
+
+	di.nonceGapDetector.Initialize(lastNonces, openGaps)
+}
+
 func (di *dataIndexer) getHeaderFromBytes(headerType core.HeaderType, headerBytes []byte) (header data.HeaderHandler, err error) {
 	creator, err := di.blockContainer.Get(headerType)
 	if err != nil {
@@ -70,6 +119,11 @@ func (di *dataIndexer) getHeaderFromBytes(headerType core.HeaderType, headerByte
 
 // SaveBlock saves the block info in the queue to be sent to elastic
 func (di *dataIndexer) SaveBlock(outportBlock *outport.OutportBlock) error {
+	err := di.ctx.Err()
+	if err != nil {
+		return fmt.Errorf("%w, the block is not indexed", err)
+	}
+
 	header, err := di.getHeaderFromBytes(core.HeaderType(outportBlock.BlockData.HeaderType), outportBlock.BlockData.HeaderBytes)
 	if err != nil {
 		return err
@@ -93,7 +147,44 @@ func (di *dataIndexer) SaveBlock(outportBlock *outport.OutportBlock) error {
 		outportBlock.TransactionPool = &outport.TransactionPool{}
 	}
 
-	return di.saveBlockData(outportBlock, header)
+	err = di.elasticProcessor.SavePendingCommit(header, headerHash)
+	if err != nil {
+		return fmt.Errorf("%w when saving pending commit, block hash %s, nonce %d",
+			err, hex.EncodeToString(headerHash), headerNonce)
+	}
+
+	err = di.saveBlockData(outportBlock, header)
+	if err != nil {
+		return err
+	}
+
+	err = di.elasticProcessor.SaveCommit(header, headerHash, outportBlock.BlockData.GetTimestampMs())
+	if err != nil {
+		return fmt.Errorf("%w when saving commit, block hash %s, nonce %d",
+			err, hex.EncodeToString(headerHash), headerNonce)
+	}
+
+	err = di.elasticProcessor.SaveCheckpoint(header, headerHash, outportBlock.BlockData.GetTimestampMs())
+	if err != nil {
+		return fmt.Errorf("%w when saving checkpoint, block hash %s, nonce %d",
+			err, hex.EncodeToString(headerHash), headerNonce)
+	}
+
+	err = di.processNonceGaps(shardID, headerNonce)
+	if err != nil {
+		return fmt.Errorf("%w when saving nonce gaps, block hash %s, nonce %d",
+			err, hex.EncodeToString(headerHash), headerNonce)
+	}
+
+	return nil
+}
+
+// processNonceGaps will save the changes of the nonce gaps of a shard. The nonce gap detector keeps the changes only
+// if they were saved, so the block is retried with the same detector state when the gaps cannot be saved
+func (di *dataIndexer) processNonceGaps(shardID uint32, nonce uint64) error {
+	return di.nonceGapDetector.ProcessNonce(shardID, nonce, func(removedGaps []*indexerData.NonceGap, addedGaps []*indexerData.NonceGap) error {
+		return di.elasticProcessor.SaveNonceGaps(shardID, removedGaps, addedGaps)
+	})
 }
 
 func (di *dataIndexer) saveBlockData(outportBlock *outport.OutportBlock, header data.HeaderHandler) error {
@@ -130,18 +221,28 @@ func (di *dataIndexer) saveBlockData(outportBlock *outport.OutportBlock, header
 	return nil
 }
 
-// Close will stop goroutine that index data in database
+// Close will close the processor, which releases the database client
 func (di *dataIndexer) Close() error {
-	return nil
+	return di.elasticProcessor.Close()
 }
 
-// RevertIndexedBlock will remove from database block and miniblocks
+// RevertIndexedBlock will move back the checkpoint of the block's shard and will remove from database block and miniblocks
 func (di *dataIndexer) RevertIndexedBlock(blockData *outport.BlockData) error {
+	err := di.ctx.Err()
+	if err != nil {
+		return fmt.Errorf("%w, the block is not reverted", err)
+	}
+
 	header, err := di.getHeaderFromBytes(core.HeaderType(blockData.HeaderType), blockData.HeaderBytes)
 	if err != nil {
 		return err
 	}
 
+	err = di.elasticProcessor.RevertCheckpoint(header, blockData.HeaderHash)
+	if err != nil {
+		return err
+	}
+
 	err = di.elasticProcessor.RemoveHeader(header)
 	if err != nil {
 		return err
@@ -180,9 +281,19 @@ func (di *dataIndexer) SaveAccounts(accounts *outport.Accounts) error {
 	return di.elasticProcessor.SaveAccounts(accounts)
 }
 
-// FinalizedBlock returns nil
-func (di *dataIndexer) FinalizedBlock(_ *outport.FinalizedBlock) error {
-	return nil
+// FinalizedBlock will mark the provided block as final
+func (di *dataIndexer) FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error {
+	return di.elasticProcessor.SaveFinalizedBlock(finalizedBlock)
+}
+
+// GetCheckpoints returns the last indexed block of every shard
+func (di *dataIndexer) GetCheckpoints() (map[uint32]*indexerData.ShardBlockInfo, error) {
+	return di.elasticProcessor.GetCheckpoints()
+}
+
+// GetNonceGaps returns the nonce gaps that are still open
+func (di *dataIndexer) GetNonceGaps() []*indexerData.NonceGap {
+	return di.nonceGapDetector.GetOpenGaps()
 }
 
 // GetMarshaller return the marshaller
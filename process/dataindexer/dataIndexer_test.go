@@ -1,6 +1,8 @@
 package dataindexer
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/multiversx/mx-chain-core-go/core"
@@ -8,6 +10,8 @@ import (
 	coreData "github.com/multiversx/mx-chain-core-go/data"
 	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	indexerData "github.com/multiversx/mx-chain-es-indexer-go/data"
 	"github.com/multiversx/mx-chain-es-indexer-go/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -17,6 +21,7 @@ func NewDataIndexerArguments() ArgDataIndexer {
 		ElasticProcessor: &mock.ElasticProcessorStub{},
 		HeaderMarshaller: &mock.MarshalizerMock{},
 		BlockContainer:   &mock.BlockContainerStub{},
+		NonceGapDetector: NewNonceGapDetector(nil),
 	}
 }
 
@@ -70,6 +75,10 @@ func TestDataIndexer_SaveBlock(t *testing.T) {
 			countMap[2]++
 			return nil
 		},
+		SaveCheckpointCalled: func(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error {
+			countMap[3]++
+			return nil
+		},
 	}
 	ei, _ := NewDataIndexer(arguments)
 
@@ -85,6 +94,199 @@ func TestDataIndexer_SaveBlock(t *testing.T) {
 	require.Equal(t, 1, countMap[0])
 	require.Equal(t, 1, countMap[1])
 	require.Equal(t, 1, countMap[2])
+	require.Equal(t, 1, countMap[3])
+}
+
+func TestDataIndexer_SaveBlockWithoutMiniblocksShouldSaveCheckpoint(t *testing.T) {
+	called := false
+
+	arguments := NewDataIndexerArguments()
+	arguments.BlockContainer = &mock.BlockContainerStub{
+		GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+			return dataBlock.NewEmptyHeaderV2Creator(), nil
+		},
+	}
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		SaveCheckpointCalled: func(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error {
+			called = true
+			require.Equal(t, []byte("hash"), headerHash)
+			require.Equal(t, uint64(5000), timestampMs)
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	args := &outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderType:  string(core.ShardHeaderV2),
+			HeaderHash:  []byte("hash"),
+			Body:        &dataBlock.Body{},
+			HeaderBytes: []byte("{}"),
+			TimestampMs: 5000,
+		},
+	}
+	err := ei.SaveBlock(args)
+	require.Nil(t, err)
+	require.True(t, called)
+}
+
+func TestDataIndexer_SaveBlockShouldSaveCommitAfterTheBlockData(t *testing.T) {
+	calls := make([]string, 0)
+
+	arguments := NewDataIndexerArguments()
+	arguments.BlockContainer = &mock.BlockContainerStub{
+		GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+			return dataBlock.NewEmptyHeaderV2Creator(), nil
+		},
+	}
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		SavePendingCommitCalled: func(header coreData.HeaderHandler, headerHash []byte) error {
+			calls = append(calls, "pending commit")
+			require.Equal(t, []byte("hash"), headerHash)
+			return nil
+		},
+		SaveHeaderCalled: func(outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+			calls = append(calls, "header")
+			return nil
+		},
+		SaveMiniblocksCalled: func(header coreData.HeaderHandler, miniBlocks []*dataBlock.MiniBlock, timestampMs uint64) error {
+			calls = append(calls, "miniblocks")
+			return nil
+		},
+		SaveTransactionsCalled: func(outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+			calls = append(calls, "transactions")
+			return nil
+		},
+		SaveCommitCalled: func(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error {
+			calls = append(calls, "commit")
+			require.Equal(t, []byte("hash"), headerHash)
+			require.Equal(t, uint64(5000), timestampMs)
+			return nil
+		},
+		SaveCheckpointCalled: func(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error {
+			calls = append(calls, "checkpoint")
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	args := &outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderType:  string(core.ShardHeaderV2),
+			HeaderHash:  []byte("hash"),
+			Body:        &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+			HeaderBytes: []byte("{}"),
+			TimestampMs: 5000,
+		},
+	}
+	err := ei.SaveBlock(args)
+	require.Nil(t, err)
+	require.Equal(t, []string{"pending commit", "header", "miniblocks", "transactions", "commit", "checkpoint"}, calls)
+}
+
+func TestDataIndexer_SaveBlockShouldNotSaveCommitIfTheBlockDataFails(t *testing.T) {
+	expectedErr := errors.New("expected error")
+
+	arguments := NewDataIndexerArguments()
+	arguments.BlockContainer = &mock.BlockContainerStub{
+		GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+			return dataBlock.NewEmptyHeaderV2Creator(), nil
+		},
+	}
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		SaveTransactionsCalled: func(outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+			return expectedErr
+		},
+		SaveCommitCalled: func(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	args := &outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderType:  string(core.ShardHeaderV2),
+			Body:        &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+			HeaderBytes: []byte("{}"),
+		},
+	}
+	err := ei.SaveBlock(args)
+	require.True(t, errors.Is(err, expectedErr))
+}
+
+func TestDataIndexer_SaveBlockShouldSaveNonceGaps(t *testing.T) {
+	savedGaps := make([]*indexerData.NonceGap, 0)
+
+	arguments := NewDataIndexerArguments()
+	arguments.BlockContainer = &mock.BlockContainerStub{
+		GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+			return dataBlock.NewEmptyHeaderV2Creator(), nil
+		},
+	}
+	arguments.HeaderMarshaller = &marshal.JsonMarshalizer{}
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		GetCheckpointsCalled: func() (map[uint32]*indexerData.ShardBlockInfo, error) {
+			return map[uint32]*indexerData.ShardBlockInfo{0: {Nonce: 5}}, nil
+		},
+		SaveNonceGapsCalled: func(shardID uint32, removedGaps []*indexerData.NonceGap, addedGaps []*indexerData.NonceGap) error {
+			savedGaps = append(savedGaps, addedGaps...)
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.SaveBlock(&outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderType:  string(core.ShardHeaderV2),
+			Body:        &dataBlock.Body{},
+			HeaderBytes: []byte(`{"Header":{"Nonce":8}}`),
+		},
+	})
+	require.Nil(t, err)
+	require.Len(t, savedGaps, 1)
+	require.Equal(t, uint64(6), savedGaps[0].FromNonce)
+	require.Equal(t, uint64(7), savedGaps[0].ToNonce)
+	require.Equal(t, savedGaps, ei.GetNonceGaps())
+}
+
+func TestDataIndexer_SaveBlockSaveNonceGapsFailedShouldErr(t *testing.T) {
+	expectedErr := errors.New("local error")
+	saveNonceGapsErr := expectedErr
+
+	arguments := NewDataIndexerArguments()
+	arguments.BlockContainer = &mock.BlockContainerStub{
+		GetCalled: func(headerType core.HeaderType) (dataBlock.EmptyBlockCreator, error) {
+			return dataBlock.NewEmptyHeaderV2Creator(), nil
+		},
+	}
+	arguments.HeaderMarshaller = &marshal.JsonMarshalizer{}
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		GetCheckpointsCalled: func() (map[uint32]*indexerData.ShardBlockInfo, error) {
+			return map[uint32]*indexerData.ShardBlockInfo{0: {Nonce: 5}}, nil
+		},
+		SaveNonceGapsCalled: func(shardID uint32, removedGaps []*indexerData.NonceGap, addedGaps []*indexerData.NonceGap) error {
+			return saveNonceGapsErr
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	outportBlock := &outport.OutportBlock{
+		BlockData: &outport.BlockData{
+			HeaderType:  string(core.ShardHeaderV2),
+			Body:        &dataBlock.Body{},
+			HeaderBytes: []byte(`{"Header":{"Nonce":8}}`),
+		},
+	}
+	err := ei.SaveBlock(outportBlock)
+	require.True(t, errors.Is(err, expectedErr))
+	require.Empty(t, ei.GetNonceGaps())
+
+	// the retried block opens the gap
+	saveNonceGapsErr = nil
+	err = ei.SaveBlock(outportBlock)
+	require.Nil(t, err)
+	require.Len(t, ei.GetNonceGaps(), 1)
 }
 
 func TestDataIndexer_SaveRoundInfo(t *testing.T) {
@@ -146,6 +348,36 @@ func TestDataIndexer_SaveValidatorsRating(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestDataIndexer_CancelledContextShouldNotIndexOrRevert(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	arguments := NewDataIndexerArguments()
+	arguments.Context = ctx
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		SaveHeaderCalled: func(_ *outport.OutportBlockWithHeader) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+		RemoveHeaderCalled: func(_ coreData.HeaderHandler) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	blockData := &outport.BlockData{
+		HeaderType:  string(core.ShardHeaderV2),
+		Body:        &dataBlock.Body{},
+		HeaderBytes: []byte("{}"),
+	}
+	err := ei.SaveBlock(&outport.OutportBlock{BlockData: blockData})
+	require.True(t, errors.Is(err, context.Canceled))
+
+	err = ei.RevertIndexedBlock(blockData)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
 func TestDataIndexer_RevertIndexedBlock(t *testing.T) {
 	countMap := map[int]int{}
 
@@ -171,11 +403,18 @@ func TestDataIndexer_RevertIndexedBlock(t *testing.T) {
 			countMap[3]++
 			return nil
 		},
+		RevertCheckpointCalled: func(header coreData.HeaderHandler, headerHash []byte) error {
+			require.Equal(t, []byte("hash"), headerHash)
+			require.Zero(t, countMap[0])
+			countMap[4]++
+			return nil
+		},
 	}
 	ei, _ := NewDataIndexer(arguments)
 
 	err := ei.RevertIndexedBlock(&outport.BlockData{
 		HeaderType:  string(core.ShardHeaderV2),
+		HeaderHash:  []byte("hash"),
 		Body:        &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
 		HeaderBytes: []byte("{}"),
 	})
@@ -184,4 +423,37 @@ func TestDataIndexer_RevertIndexedBlock(t *testing.T) {
 	require.Equal(t, 1, countMap[1])
 	require.Equal(t, 1, countMap[2])
 	require.Equal(t, 1, countMap[3])
+	require.Equal(t, 1, countMap[4])
+}
+
+func TestDataIndexer_FinalizedBlock(t *testing.T) {
+	called := false
+
+	arguments := NewDataIndexerArguments()
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		SaveFinalizedBlockCalled: func(finalizedBlock *outport.FinalizedBlock) error {
+			called = true
+			require.Equal(t, uint32(2), finalizedBlock.ShardID)
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.FinalizedBlock(&outport.FinalizedBlock{ShardID: 2, HeaderHash: []byte("hash")})
+	require.True(t, called)
+	require.Nil(t, err)
+}
+
+func TestDataIndexer_CloseShouldCloseTheElasticProcessor(t *testing.T) {
+	expectedErr := errors.New("expected error")
+	arguments := NewDataIndexerArguments()
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		CloseCalled: func() error {
+			return expectedErr
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.Close()
+	require.Equal(t, expectedErr, err)
 }
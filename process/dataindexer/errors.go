@@ -91,3 +91,40 @@ var ErrNilBlockContainerHandler = errors.New("nil bock container handler")
 
 // ErrNilMappingsHandler signals that a nil mappings handler has been provided
 var ErrNilMappingsHandler = errors.New("nil mappings handler")
+
+// ErrNilNonceGapDetector signals that a nil nonce gap detector has been provided
+var ErrNilNonceGapDetector = errors.New("nil nonce gap detector")
+
+// ErrNotOpenSearchCluster signals that the cluster selected as OpenSearch is not an OpenSearch cluster
+var ErrNotOpenSearchCluster = errors.New("the cluster is not an OpenSearch cluster")
+
+// ErrUnsupportedOpenSearchVersion signals that the version of the OpenSearch cluster is not supported
+var ErrUnsupportedOpenSearchVersion = errors.New("unsupported OpenSearch version")
+
+// ErrInvalidDatabaseBackend signals that an invalid database backend has been provided
+var ErrInvalidDatabaseBackend = errors.New("invalid database backend")
+
+// ErrBulkItemsRejected signals that some actions of a bulk request failed with a permanent error
+var ErrBulkItemsRejected = errors.New("bulk actions rejected")
+
+// ErrBulkItemsRetriesExceeded signals that some actions of a bulk request still failed after the last retry
+var ErrBulkItemsRetriesExceeded = errors.New("bulk actions failed after all the retries")
+
+// ErrInvalidBulkBody signals that a bulk body could not be parsed
+var ErrInvalidBulkBody = errors.New("invalid bulk body")
+
+// ErrCircuitBreakerOpen signals that a request was not sent because the cluster kept rejecting the previous requests
+var ErrCircuitBreakerOpen = errors.New("circuit breaker open, the cluster keeps rejecting the requests")
+
+// ErrInvalidRetryPolicy signals that an invalid retry policy configuration has been provided
+var ErrInvalidRetryPolicy = errors.New("invalid retry policy")
+
+// ErrInvalidCACertificate signals that no certificate could be read from the provided CA bundle
+var ErrInvalidCACertificate = errors.New("no certificate found in the CA bundle")
+
+// ErrNotSupportedBySQLBackend signals that a feature that only works with the search engine backends is enabled with a
+// SQL backend
+var ErrNotSupportedBySQLBackend = errors.New("not supported by the SQL database backends")
+
+// ErrIncompleteClientCertificate signals that only one of the client certificate and the client key has been provided
+var ErrIncompleteClientCertificate = errors.New("both the client certificate and the client key should be provided")
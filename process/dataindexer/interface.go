@@ -8,6 +8,7 @@ import (
 	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 	"github.com/multiversx/mx-chain-core-go/marshal"
+	indexerData "github.com/multiversx/mx-chain-es-indexer-go/data"
 )
 
 // ElasticProcessor defines the interface for the elastic search indexer
@@ -23,7 +24,16 @@ type ElasticProcessor interface {
 	SaveRoundsInfo(rounds *outport.RoundsInfo) error
 	SaveShardValidatorsPubKeys(validatorsPubKeys *outport.ValidatorsPubKeys) error
 	SaveAccounts(accounts *outport.Accounts) error
+	SaveFinalizedBlock(finalizedBlock *outport.FinalizedBlock) error
+	SavePendingCommit(header coreData.HeaderHandler, headerHash []byte) error
+	SaveCommit(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error
+	SaveCheckpoint(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error
+	RevertCheckpoint(header coreData.HeaderHandler, headerHash []byte) error
+	GetCheckpoints() (map[uint32]*indexerData.ShardBlockInfo, error)
+	SaveNonceGaps(shardID uint32, removedGaps []*indexerData.NonceGap, addedGaps []*indexerData.NonceGap) error
+	GetNonceGaps() ([]*indexerData.NonceGap, error)
 	SetOutportConfig(cfg outport.OutportConfig) error
+	Close() error
 	IsInterfaceNil() bool
 }
 
@@ -55,6 +65,8 @@ type Indexer interface {
 	SaveValidatorsRating(ratingData *outport.ValidatorsRating) error
 	SaveAccounts(accountsData *outport.Accounts) error
 	FinalizedBlock(finalizedBlock *outport.FinalizedBlock) error
+	GetCheckpoints() (map[uint32]*indexerData.ShardBlockInfo, error)
+	GetNonceGaps() []*indexerData.NonceGap
 	GetMarshaller() marshal.Marshalizer
 	RegisterHandler(handler func() error, topic string) error
 	SetCurrentSettings(cfg outport.OutportConfig) error
@@ -62,6 +74,17 @@ type Indexer interface {
 	IsInterfaceNil() bool
 }
 
+// NonceGapsSaveHandler saves the gaps that were closed and the gaps that were opened by an indexed nonce
+type NonceGapsSaveHandler func(removedGaps []*indexerData.NonceGap, addedGaps []*indexerData.NonceGap) error
+
+// NonceGapDetector defines what a component that tracks the indexed header nonces of every shard should be able to do
+type NonceGapDetector interface {
+	Initialize(lastNonces map[uint32]uint64, openGaps []*indexerData.NonceGap)
+	ProcessNonce(shardID uint32, nonce uint64, saveHandler NonceGapsSaveHandler) error
+	GetOpenGaps() []*indexerData.NonceGap
+	IsInterfaceNil() bool
+}
+
 // BalanceConverter defines what a balance converter should be able to do
 type BalanceConverter interface {
 	ComputeBalanceAsFloat(balance *big.Int) (float64, error)
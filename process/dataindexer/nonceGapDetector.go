@@ -0,0 +1,173 @@
+package dataindexer
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	indexerCore "github.com/multiversx/mx-chain-es-indexer-go/core"
+	indexerData "github.com/multiversx/mx-chain-es-indexer-go/data"
+)
+
+type nonceGapDetector struct {
+	mut            sync.RWMutex
+	lastNonces     map[uint32]uint64
+	gaps           map[uint32][]*indexerData.NonceGap
+	statusMetrics  indexerCore.StatusMetricsHandler
+	getTimeHandler func() time.Time
+}
+
+// NewNonceGapDetector will create a new instance of nonceGapDetector. The status metrics handler is optional, when
+// provided every detected gap is counted in the nonce gaps metrics of its shard
+func NewNonceGapDetector(statusMetrics indexerCore.StatusMetricsHandler) *nonceGapDetector {
+	return &nonceGapDetector{
+		lastNonces:     make(map[uint32]uint64),
+		gaps:           make(map[uint32][]*indexerData.NonceGap),
+		statusMetrics:  statusMetrics,
+		getTimeHandler: time.Now,
+	}
+}
+
+// Initialize will set the last indexed nonce of every shard and the gaps that are still open. A gap that is already
+// known is not added again
+func (ngd *nonceGapDetector) Initialize(lastNonces map[uint32]uint64, openGaps []*indexerData.NonceGap) {
+	ngd.mut.Lock()
+	defer ngd.mut.Unlock()
+
+	for shardID, nonce := range lastNonces {
+		ngd.lastNonces[shardID] = nonce
+	}
+	for _, gap := range openGaps {
+		if ngd.hasGapUnprotected(gap) {
+			continue
+		}
+		ngd.gaps[gap.ShardID] = append(ngd.gaps[gap.ShardID], gap)
+	}
+}
+
+func (ngd *nonceGapDetector) hasGapUnprotected(gap *indexerData.NonceGap) bool {
+	for _, shardGap := range ngd.gaps[gap.ShardID] {
+		if shardGap.FromNonce == gap.FromNonce && shardGap.ToNonce == gap.ToNonce {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProcessNonce will register the provided nonce as indexed. The gaps that were closed and the gaps that were opened by
+// it are passed to the save handler and the detector keeps the changes only if they were saved, so it never holds gaps
+// that are not in the database. A nonce higher than the next expected one opens a gap, while a nonce that falls inside
+// an open gap closes it, or splits it in two if it is not on the gap's edge
+func (ngd *nonceGapDetector) ProcessNonce(shardID uint32, nonce uint64, saveHandler NonceGapsSaveHandler) error {
+	ngd.mut.Lock()
+	defer ngd.mut.Unlock()
+
+	lastNonce, found := ngd.lastNonces[shardID]
+	if !found || nonce == lastNonce+1 {
+		ngd.lastNonces[shardID] = nonce
+		return nil
+	}
+
+	if nonce > lastNonce+1 {
+		return ngd.openGapUnprotected(shardID, lastNonce, nonce, saveHandler)
+	}
+
+	return ngd.closeNonceUnprotected(shardID, nonce, saveHandler)
+}
+
+func (ngd *nonceGapDetector) openGapUnprotected(shardID uint32, lastNonce uint64, nonce uint64, saveHandler NonceGapsSaveHandler) error {
+	gap := &indexerData.NonceGap{
+		ShardID:      shardID,
+		FromNonce:    lastNonce + 1,
+		ToNonce:      nonce - 1,
+		DetectedAtMs: uint64(ngd.getTimeHandler().UnixMilli()),
+	}
+	err := saveHandler(nil, []*indexerData.NonceGap{gap})
+	if err != nil {
+		return err
+	}
+
+	ngd.lastNonces[shardID] = nonce
+	ngd.gaps[shardID] = append(ngd.gaps[shardID], gap)
+	ngd.raiseMetric(gap)
+
+	log.Warn("nonceGapDetector: detected nonce gap", "shardID", shardID, "from", gap.FromNonce, "to", gap.ToNonce)
+
+	return nil
+}
+
+func (ngd *nonceGapDetector) closeNonceUnprotected(shardID uint32, nonce uint64, saveHandler NonceGapsSaveHandler) error {
+	shardGaps := ngd.gaps[shardID]
+	for idx, gap := range shardGaps {
+		if nonce < gap.FromNonce || nonce > gap.ToNonce {
+			continue
+		}
+
+		remainingGaps := make([]*indexerData.NonceGap, 0, 2)
+		if nonce > gap.FromNonce {
+			remainingGaps = append(remainingGaps, &indexerData.NonceGap{
+				ShardID:      shardID,
+				FromNonce:    gap.FromNonce,
+				ToNonce:      nonce - 1,
+				DetectedAtMs: gap.DetectedAtMs,
+			})
+		}
+		if nonce < gap.ToNonce {
+			remainingGaps = append(remainingGaps, &indexerData.NonceGap{
+				ShardID:      shardID,
+				FromNonce:    nonce + 1,
+				ToNonce:      gap.ToNonce,
+				DetectedAtMs: gap.DetectedAtMs,
+			})
+		}
+		err := saveHandler([]*indexerData.NonceGap{gap}, remainingGaps)
+		if err != nil {
+			return err
+		}
+
+		newShardGaps := append(shardGaps[:idx:idx], remainingGaps...)
+		ngd.gaps[shardID] = append(newShardGaps, shardGaps[idx+1:]...)
+
+		log.Debug("nonceGapDetector: closed nonce in gap", "shardID", shardID, "nonce", nonce,
+			"from", gap.FromNonce, "to", gap.ToNonce)
+
+		return nil
+	}
+
+	return nil
+}
+
+func (ngd *nonceGapDetector) raiseMetric(gap *indexerData.NonceGap) {
+	if check.IfNil(ngd.statusMetrics) {
+		return
+	}
+
+	ngd.statusMetrics.AddNonceGap(gap.ShardID, gap.ToNonce-gap.FromNonce+1)
+}
+
+// GetOpenGaps returns all the gaps that are still open, sorted by shard and nonce
+func (ngd *nonceGapDetector) GetOpenGaps() []*indexerData.NonceGap {
+	ngd.mut.RLock()
+	defer ngd.mut.RUnlock()
+
+	openGaps := make([]*indexerData.NonceGap, 0)
+	for _, shardGaps := range ngd.gaps {
+		openGaps = append(openGaps, shardGaps...)
+	}
+
+	sort.Slice(openGaps, func(i, j int) bool {
+		if openGaps[i].ShardID != openGaps[j].ShardID {
+			return openGaps[i].ShardID < openGaps[j].ShardID
+		}
+		return openGaps[i].FromNonce < openGaps[j].FromNonce
+	})
+
+	return openGaps
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ngd *nonceGapDetector) IsInterfaceNil() bool {
+	return ngd == nil
+}
@@ -0,0 +1,175 @@
+package dataindexer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	indexerData "github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func processNonce(t *testing.T, ngd *nonceGapDetector, shardID uint32, nonce uint64) ([]*indexerData.NonceGap, []*indexerData.NonceGap) {
+	var removed, added []*indexerData.NonceGap
+	err := ngd.ProcessNonce(shardID, nonce, func(removedGaps []*indexerData.NonceGap, addedGaps []*indexerData.NonceGap) error {
+		removed = removedGaps
+		added = addedGaps
+		return nil
+	})
+	require.Nil(t, err)
+
+	return removed, added
+}
+
+func TestNewNonceGapDetector(t *testing.T) {
+	t.Parallel()
+
+	ngd := NewNonceGapDetector(nil)
+	require.False(t, check.IfNil(ngd))
+	require.Empty(t, ngd.GetOpenGaps())
+}
+
+func TestNonceGapDetector_ProcessNonceConsecutiveNoncesShouldNotOpenGaps(t *testing.T) {
+	t.Parallel()
+
+	ngd := NewNonceGapDetector(nil)
+	for nonce := uint64(10); nonce < 20; nonce++ {
+		removed, added := processNonce(t, ngd, 1, nonce)
+		require.Nil(t, removed)
+		require.Nil(t, added)
+	}
+
+	// a re-indexed block, after a revert, should not open a gap
+	removed, added := processNonce(t, ngd, 1, 19)
+	require.Nil(t, removed)
+	require.Nil(t, added)
+	require.Empty(t, ngd.GetOpenGaps())
+}
+
+func TestNonceGapDetector_ProcessNonceShouldOpenAndCloseGaps(t *testing.T) {
+	t.Parallel()
+
+	numMissingNonces := uint64(0)
+	statusMetrics := metrics.NewStatusMetrics()
+	ngd := NewNonceGapDetector(statusMetrics)
+
+	_, _ = processNonce(t, ngd, 0, 10)
+	removed, added := processNonce(t, ngd, 0, 15)
+	require.Nil(t, removed)
+	require.Len(t, added, 1)
+	require.Equal(t, uint64(11), added[0].FromNonce)
+	require.Equal(t, uint64(14), added[0].ToNonce)
+	numMissingNonces += 4
+
+	prometheusMetrics := statusMetrics.GetMetricsForPrometheus()
+	require.Contains(t, prometheusMetrics, `nonce_gaps{operation="gaps_count",shardID="0"} 1`)
+	require.Contains(t, prometheusMetrics, fmt.Sprintf(`nonce_gaps{operation="missing_nonces",shardID="0"} %d`, numMissingNonces))
+
+	// backfill a nonce from the middle of the gap
+	removed, added = processNonce(t, ngd, 0, 12)
+	require.Equal(t, []*indexerData.NonceGap{{ShardID: 0, FromNonce: 11, ToNonce: 14, DetectedAtMs: removed[0].DetectedAtMs}}, removed)
+	require.Equal(t, []*indexerData.NonceGap{
+		{ShardID: 0, FromNonce: 11, ToNonce: 11, DetectedAtMs: removed[0].DetectedAtMs},
+		{ShardID: 0, FromNonce: 13, ToNonce: 14, DetectedAtMs: removed[0].DetectedAtMs},
+	}, added)
+
+	// backfill the edges
+	removed, added = processNonce(t, ngd, 0, 11)
+	require.Len(t, removed, 1)
+	require.Empty(t, added)
+	removed, added = processNonce(t, ngd, 0, 14)
+	require.Len(t, removed, 1)
+	require.Len(t, added, 1)
+	require.Equal(t, []*indexerData.NonceGap{{ShardID: 0, FromNonce: 13, ToNonce: 13, DetectedAtMs: added[0].DetectedAtMs}}, ngd.GetOpenGaps())
+
+	removed, added = processNonce(t, ngd, 0, 13)
+	require.Len(t, removed, 1)
+	require.Empty(t, added)
+	require.Empty(t, ngd.GetOpenGaps())
+}
+
+func TestNonceGapDetector_ProcessNonceSaveFailedShouldNotKeepTheChanges(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("local error")
+	saveHandlerFailed := func(removedGaps []*indexerData.NonceGap, addedGaps []*indexerData.NonceGap) error {
+		return expectedErr
+	}
+
+	statusMetrics := metrics.NewStatusMetrics()
+	ngd := NewNonceGapDetector(statusMetrics)
+	_, _ = processNonce(t, ngd, 0, 10)
+
+	err := ngd.ProcessNonce(0, 15, saveHandlerFailed)
+	require.Equal(t, expectedErr, err)
+	require.Empty(t, ngd.GetOpenGaps())
+	require.NotContains(t, statusMetrics.GetMetricsForPrometheus(), `nonce_gaps{operation="gaps_count",shardID="0"}`)
+
+	// the retried nonce opens the same gap
+	_, added := processNonce(t, ngd, 0, 15)
+	require.Equal(t, []*indexerData.NonceGap{{ShardID: 0, FromNonce: 11, ToNonce: 14, DetectedAtMs: added[0].DetectedAtMs}}, added)
+
+	err = ngd.ProcessNonce(0, 12, saveHandlerFailed)
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, added, ngd.GetOpenGaps())
+
+	removed, added := processNonce(t, ngd, 0, 12)
+	require.Len(t, removed, 1)
+	require.Len(t, added, 2)
+	require.Equal(t, added, ngd.GetOpenGaps())
+}
+
+func TestNonceGapDetector_InitializeShouldResumeFromLastNonces(t *testing.T) {
+	t.Parallel()
+
+	ngd := NewNonceGapDetector(nil)
+	ngd.Initialize(map[uint32]uint64{0: 100, 2: 50}, []*indexerData.NonceGap{
+		{ShardID: 2, FromNonce: 20, ToNonce: 30},
+		{ShardID: 0, FromNonce: 5, ToNonce: 6},
+		{ShardID: 2, FromNonce: 2, ToNonce: 3},
+	})
+
+	_, added := processNonce(t, ngd, 0, 103)
+	require.Equal(t, []*indexerData.NonceGap{{ShardID: 0, FromNonce: 101, ToNonce: 102, DetectedAtMs: added[0].DetectedAtMs}}, added)
+
+	_, added = processNonce(t, ngd, 2, 51)
+	require.Nil(t, added)
+
+	openGaps := ngd.GetOpenGaps()
+	require.Len(t, openGaps, 4)
+	require.Equal(t, uint64(5), openGaps[0].FromNonce)
+	require.Equal(t, uint64(101), openGaps[1].FromNonce)
+	require.Equal(t, uint64(2), openGaps[2].FromNonce)
+	require.Equal(t, uint64(20), openGaps[3].FromNonce)
+}
+
+func TestNonceGapDetector_InitializeShouldNotDuplicateGaps(t *testing.T) {
+	t.Parallel()
+
+	gaps := []*indexerData.NonceGap{
+		{ShardID: 0, FromNonce: 5, ToNonce: 6},
+		{ShardID: 0, FromNonce: 5, ToNonce: 6},
+	}
+	ngd := NewNonceGapDetector(nil)
+	ngd.Initialize(map[uint32]uint64{0: 100}, gaps)
+	ngd.Initialize(map[uint32]uint64{0: 100}, gaps)
+
+	require.Len(t, ngd.GetOpenGaps(), 1)
+}
+
+func TestNonceGapDetector_ShouldCountTheGapsInTheMetrics(t *testing.T) {
+	t.Parallel()
+
+	statusMetrics := metrics.NewStatusMetrics()
+	ngd := NewNonceGapDetector(statusMetrics)
+	processNonce(t, ngd, 4294967295, 10)
+	processNonce(t, ngd, 4294967295, 13)
+	processNonce(t, ngd, 4294967295, 15)
+
+	require.Empty(t, statusMetrics.GetMetrics())
+	prometheusMetrics := statusMetrics.GetMetricsForPrometheus()
+	require.Contains(t, prometheusMetrics, `nonce_gaps{operation="gaps_count",shardID="4294967295"} 2`)
+	require.Contains(t, prometheusMetrics, `nonce_gaps{operation="missing_nonces",shardID="4294967295"} 3`)
+}
@@ -0,0 +1,128 @@
+package deadletter
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("process/deadletter")
+
+// ArgsDeadLetterStore holds all the components needed to create a new instance of deadLetterStore
+type ArgsDeadLetterStore struct {
+	Path           string
+	Marshaller     marshal.Marshalizer
+	PayloadHandler core.PayloadHandler
+}
+
+type deadLetterStore struct {
+	path            string
+	marshaller      marshal.Marshalizer
+	handler         core.PayloadHandler
+	mut             sync.Mutex
+	lastTimestampNs int64
+	getTimeHandler  func() time.Time
+}
+
+// NewDeadLetterStore will create a new instance of deadLetterStore. Every payload that cannot be processed by the
+// wrapped payload handler is saved, together with the error, in the dead-letter directory
+func NewDeadLetterStore(args ArgsDeadLetterStore) (*deadLetterStore, error) {
+	if args.Path == "" {
+		return nil, ErrEmptyDeadLetterPath
+	}
+	if check.IfNil(args.Marshaller) {
+		return nil, dataindexer.ErrNilMarshalizer
+	}
+	if check.IfNil(args.PayloadHandler) {
+		return nil, ErrNilPayloadHandler
+	}
+
+	err := os.MkdirAll(args.Path, dirPermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadLetterStore{
+		path:           args.Path,
+		marshaller:     args.Marshaller,
+		handler:        args.PayloadHandler,
+		getTimeHandler: time.Now,
+	}, nil
+}
+
+// ProcessPayload will pass the payload to the wrapped payload handler and will save it in the dead-letter directory
+// if it cannot be processed. The processing error is returned as it is
+func (dls *deadLetterStore) ProcessPayload(payload []byte, topic string, version uint32) error {
+	err := dls.handler.ProcessPayload(payload, topic, version)
+	if err == nil {
+		return nil
+	}
+
+	errSave := dls.save(payload, topic, version, err)
+	if errSave != nil {
+		log.Error("deadLetterStore: cannot save failed payload", "topic", topic, "error", errSave)
+	}
+
+	return err
+}
+
+// SavePayload will save the provided payload, together with the error, in the dead-letter directory, without passing it
+// to the wrapped payload handler. It is used by the components that retry the payloads themselves, like the spool
+func (dls *deadLetterStore) SavePayload(payload []byte, topic string, version uint32, processingErr error) error {
+	return dls.save(payload, topic, version, processingErr)
+}
+
+func (dls *deadLetterStore) save(payload []byte, topic string, version uint32, processingErr error) error {
+	shard := &outport.Shard{}
+	errUnmarshal := dls.marshaller.Unmarshal(shard, payload)
+	if errUnmarshal != nil {
+		log.Debug("deadLetterStore: cannot get shardID from payload", "error", errUnmarshal)
+	}
+
+	dls.mut.Lock()
+	defer dls.mut.Unlock()
+
+	now := dls.getTimeHandler()
+	timestampNs := now.UnixNano()
+	if timestampNs <= dls.lastTimestampNs {
+		// two entries should never get the same id
+		timestampNs = dls.lastTimestampNs + 1
+	}
+	dls.lastTimestampNs = timestampNs
+
+	entry := &Entry{
+		Topic:       topic,
+		Version:     version,
+		ShardID:     shard.ShardID,
+		TimestampMs: uint64(now.UnixMilli()),
+		Error:       processingErr.Error(),
+		Payload:     payload,
+	}
+	entry.ID = entryID(entry, timestampNs)
+
+	err := writeEntry(dls.path, entry)
+	if err != nil {
+		return err
+	}
+
+	log.Warn("deadLetterStore: saved failed payload", "id", entry.ID, "error", processingErr)
+
+	return nil
+}
+
+// Close will close the wrapped payload handler
+func (dls *deadLetterStore) Close() error {
+	return dls.handler.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dls *deadLetterStore) IsInterfaceNil() bool {
+	return dls == nil
+}
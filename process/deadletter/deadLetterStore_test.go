@@ -0,0 +1,197 @@
+package deadletter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createShardPayload(t *testing.T, shardID uint32) []byte {
+	payload, err := (&marshal.JsonMarshalizer{}).Marshal(&outport.Shard{ShardID: shardID})
+	require.Nil(t, err)
+
+	return payload
+}
+
+func TestNewDeadLetterStore(t *testing.T) {
+	t.Parallel()
+
+	dls, err := NewDeadLetterStore(ArgsDeadLetterStore{Marshaller: &marshal.JsonMarshalizer{}, PayloadHandler: &mock.PayloadHandlerStub{}})
+	require.Nil(t, dls)
+	require.Equal(t, ErrEmptyDeadLetterPath, err)
+
+	dls, err = NewDeadLetterStore(ArgsDeadLetterStore{Path: t.TempDir(), PayloadHandler: &mock.PayloadHandlerStub{}})
+	require.Nil(t, dls)
+	require.Equal(t, dataindexer.ErrNilMarshalizer, err)
+
+	dls, err = NewDeadLetterStore(ArgsDeadLetterStore{Path: t.TempDir(), Marshaller: &marshal.JsonMarshalizer{}})
+	require.Nil(t, dls)
+	require.Equal(t, ErrNilPayloadHandler, err)
+
+	dir := filepath.Join(t.TempDir(), "dead-letters")
+	dls, err = NewDeadLetterStore(ArgsDeadLetterStore{Path: dir, Marshaller: &marshal.JsonMarshalizer{}, PayloadHandler: &mock.PayloadHandlerStub{}})
+	require.Nil(t, err)
+	require.False(t, dls.IsInterfaceNil())
+
+	_, err = os.Stat(dir)
+	require.Nil(t, err)
+}
+
+func TestDeadLetterStore_ProcessPayloadShouldSaveFailedPayloads(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	errProcess := errors.New("local error")
+	dls, _ := NewDeadLetterStore(ArgsDeadLetterStore{
+		Path:       dir,
+		Marshaller: &marshal.JsonMarshalizer{},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				if topic == outport.TopicFinalizedBlock {
+					return nil
+				}
+				return errProcess
+			},
+		},
+	})
+	// the same timestamp for every entry should still produce unique ids
+	dls.getTimeHandler = func() time.Time {
+		return time.UnixMilli(5000)
+	}
+
+	err := dls.ProcessPayload(createShardPayload(t, 1), outport.TopicFinalizedBlock, 1)
+	require.Nil(t, err)
+
+	err = dls.ProcessPayload(createShardPayload(t, 2), outport.TopicSaveBlock, 1)
+	require.Equal(t, errProcess, err)
+
+	err = dls.ProcessPayload(createShardPayload(t, 0), outport.TopicSaveAccounts, 1)
+	require.Equal(t, errProcess, err)
+
+	entries, err := ReadEntries(dir)
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+
+	require.Equal(t, outport.TopicSaveBlock, entries[0].Topic)
+	require.Equal(t, uint32(2), entries[0].ShardID)
+	require.Equal(t, uint32(1), entries[0].Version)
+	require.Equal(t, uint64(5000), entries[0].TimestampMs)
+	require.Equal(t, errProcess.Error(), entries[0].Error)
+	require.Equal(t, createShardPayload(t, 2), entries[0].Payload)
+
+	require.Equal(t, outport.TopicSaveAccounts, entries[1].Topic)
+	require.Equal(t, uint32(0), entries[1].ShardID)
+	require.NotEqual(t, entries[0].ID, entries[1].ID)
+}
+
+func TestDeadLetterStore_SavePayloadShouldNotProcessThePayload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	errProcess := errors.New("local error")
+	dls, _ := NewDeadLetterStore(ArgsDeadLetterStore{
+		Path:       dir,
+		Marshaller: &marshal.JsonMarshalizer{},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				require.Fail(t, "the payload should not be processed")
+				return nil
+			},
+		},
+	})
+
+	err := dls.SavePayload(createShardPayload(t, 1), outport.TopicSaveBlock, 1, errProcess)
+	require.Nil(t, err)
+
+	entries, err := ReadEntries(dir)
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, outport.TopicSaveBlock, entries[0].Topic)
+	require.Equal(t, uint32(1), entries[0].ShardID)
+	require.Equal(t, errProcess.Error(), entries[0].Error)
+	require.Equal(t, createShardPayload(t, 1), entries[0].Payload)
+}
+
+func TestReadEntries(t *testing.T) {
+	t.Parallel()
+
+	_, err := ReadEntries("")
+	require.Equal(t, ErrEmptyDeadLetterPath, err)
+
+	entries, err := ReadEntries(filepath.Join(t.TempDir(), "missing"))
+	require.Nil(t, err)
+	require.Empty(t, entries)
+}
+
+func TestRemoveEntry(t *testing.T) {
+	t.Parallel()
+
+	err := RemoveEntry(t.TempDir(), "")
+	require.True(t, errors.Is(err, ErrInvalidEntryID))
+
+	err = RemoveEntry(t.TempDir(), "../entry")
+	require.True(t, errors.Is(err, ErrInvalidEntryID))
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	dls, _ := NewDeadLetterStore(ArgsDeadLetterStore{
+		Path:       dir,
+		Marshaller: &marshal.JsonMarshalizer{},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				return errors.New("first error")
+			},
+		},
+	})
+	_ = dls.ProcessPayload(createShardPayload(t, 0), outport.TopicSaveBlock, 1)
+	_ = dls.ProcessPayload(createShardPayload(t, 1), outport.TopicSaveBlock, 1)
+	_ = dls.ProcessPayload(createShardPayload(t, 2), outport.TopicSaveBlock, 1)
+
+	entries, _ := ReadEntries(dir)
+	require.Len(t, entries, 3)
+
+	t.Run("nil handler should error", func(t *testing.T) {
+		_, err := Retry(dir, nil, nil)
+		require.Equal(t, ErrNilPayloadHandler, err)
+	})
+
+	t.Run("unknown id should error", func(t *testing.T) {
+		_, err := Retry(dir, []string{"unknown"}, &mock.PayloadHandlerStub{})
+		require.True(t, errors.Is(err, ErrInvalidEntryID))
+	})
+
+	t.Run("failed entries should be kept with the new error", func(t *testing.T) {
+		processed := make([]string, 0)
+		stats, err := Retry(dir, []string{entries[2].ID, entries[0].ID}, &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				processed = append(processed, string(payload))
+				if string(payload) == string(entries[2].Payload) {
+					return errors.New("second error")
+				}
+				return nil
+			},
+		})
+		require.Nil(t, err)
+		require.Equal(t, &RetryStats{NumRetried: 1, NumFailed: 1}, stats)
+		// the entries are retried in the order they were saved
+		require.Equal(t, []string{string(entries[0].Payload), string(entries[2].Payload)}, processed)
+
+		remaining, _ := ReadEntries(dir)
+		require.Len(t, remaining, 2)
+		require.Equal(t, entries[1].ID, remaining[0].ID)
+		require.Equal(t, "first error", remaining[0].Error)
+		require.Equal(t, entries[2].ID, remaining[1].ID)
+		require.Equal(t, "second error", remaining[1].Error)
+	})
+}
@@ -0,0 +1,109 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	entryFileExtension = ".json"
+	tmpFileExtension   = ".tmp"
+	filePermissions    = 0644
+	dirPermissions     = 0755
+)
+
+// Entry holds a payload that could not be indexed, together with the reason
+type Entry struct {
+	ID          string `json:"-"`
+	Topic       string `json:"topic"`
+	Version     uint32 `json:"version"`
+	ShardID     uint32 `json:"shardId"`
+	TimestampMs uint64 `json:"timestampMs"`
+	Error       string `json:"error"`
+	Payload     []byte `json:"payload"`
+}
+
+func entryID(entry *Entry, timestampNs int64) string {
+	return fmt.Sprintf("%020d_%d_%s", timestampNs, entry.ShardID, entry.Topic)
+}
+
+func writeEntry(path string, entry *Entry) error {
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	finalPath := filepath.Join(path, entry.ID+entryFileExtension)
+	tmpPath := finalPath + tmpFileExtension
+	err = os.WriteFile(tmpPath, entryBytes, filePermissions)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, finalPath)
+}
+
+// ReadEntries returns all the entries from the provided dead-letter directory, oldest first
+func ReadEntries(path string) ([]*Entry, error) {
+	if path == "" {
+		return nil, ErrEmptyDeadLetterPath
+	}
+
+	dirEntries, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return make([]*Entry, 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !strings.HasSuffix(name, entryFileExtension) {
+			continue
+		}
+
+		entry, errRead := readEntry(filepath.Join(path, name))
+		if errRead != nil {
+			return nil, fmt.Errorf("%w while reading dead-letter entry %s", errRead, name)
+		}
+		entry.ID = strings.TrimSuffix(name, entryFileExtension)
+		entries = append(entries, entry)
+	}
+
+	// the ids start with the zero padded timestamp
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+
+	return entries, nil
+}
+
+func readEntry(filePath string) (*Entry, error) {
+	entryBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{}
+	err = json.Unmarshal(entryBytes, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// RemoveEntry will remove the entry with the provided id from the dead-letter directory
+func RemoveEntry(path string, id string) error {
+	if id == "" || filepath.Base(id) != id {
+		return fmt.Errorf("%w: %s", ErrInvalidEntryID, id)
+	}
+
+	return os.Remove(filepath.Join(path, id+entryFileExtension))
+}
@@ -0,0 +1,12 @@
+package deadletter
+
+import "errors"
+
+// ErrNilPayloadHandler signals that a nil payload handler has been provided
+var ErrNilPayloadHandler = errors.New("nil payload handler")
+
+// ErrEmptyDeadLetterPath signals that an empty dead-letter path has been provided
+var ErrEmptyDeadLetterPath = errors.New("empty dead-letter path")
+
+// ErrInvalidEntryID signals that an invalid dead-letter entry id has been provided
+var ErrInvalidEntryID = errors.New("invalid dead-letter entry id")
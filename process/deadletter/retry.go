@@ -0,0 +1,84 @@
+package deadletter
+
+import (
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+)
+
+// RetryStats holds the results of a retry
+type RetryStats struct {
+	NumRetried int
+	NumFailed  int
+}
+
+// Retry will pass the entries with the provided ids, or all the entries if no id is provided, to the payload handler.
+// The entries that are processed are removed, while the ones that fail again are kept with the new error
+func Retry(path string, ids []string, handler core.PayloadHandler) (*RetryStats, error) {
+	if check.IfNil(handler) {
+		return nil, ErrNilPayloadHandler
+	}
+
+	entries, err := ReadEntries(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err = filterEntries(entries, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &RetryStats{}
+	for _, entry := range entries {
+		errProcess := handler.ProcessPayload(entry.Payload, entry.Topic, entry.Version)
+		if errProcess != nil {
+			log.Warn("deadletter.Retry: cannot process entry", "id", entry.ID, "error", errProcess)
+			stats.NumFailed++
+
+			entry.Error = errProcess.Error()
+			err = writeEntry(path, entry)
+			if err != nil {
+				return stats, err
+			}
+			continue
+		}
+
+		stats.NumRetried++
+		err = RemoveEntry(path, entry.ID)
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+func filterEntries(entries []*Entry, ids []string) ([]*Entry, error) {
+	if len(ids) == 0 {
+		return entries, nil
+	}
+
+	idsMap := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		idsMap[id] = struct{}{}
+	}
+
+	// the entries are kept in the order they were saved
+	filtered := make([]*Entry, 0, len(ids))
+	for _, entry := range entries {
+		_, found := idsMap[entry.ID]
+		if !found {
+			continue
+		}
+		filtered = append(filtered, entry)
+		delete(idsMap, entry.ID)
+	}
+
+	for id := range idsMap {
+		return nil, fmt.Errorf("%w: %s not found", ErrInvalidEntryID, id)
+	}
+
+	return filtered, nil
+}
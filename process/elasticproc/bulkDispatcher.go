@@ -0,0 +1,150 @@
+package elasticproc
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+)
+
+// newBufferSlice creates the buffers of the bulk bodies of a block. When the bulk bodies are compressed, they are
+// compressed while they are built, so a whole uncompressed body is never held in memory
+func (ei *elasticProcessor) newBufferSlice() *data.BufferSlice {
+	if ei.compressBulkBodies {
+		return data.NewCompressedBufferSlice(ei.bulkRequestMaxSize)
+	}
+
+	return data.NewBufferSlice(ei.bulkRequestMaxSize)
+}
+
+// computeBulkDependencies returns, for every buffer, the previous buffers that hold actions on the same documents. A
+// buffer with an action that cannot be decoded, or without an id, depends on all the previous buffers and all the next
+// buffers depend on it
+func computeBulkDependencies(buffSlice []*bytes.Buffer, defaultIndex string) [][]int {
+	dependencies := make([][]int, len(buffSlice))
+	lastBufferOfDocument := make(map[string]int)
+	lastBarrier := -1
+
+	for idx, buff := range buffSlice {
+		documents, isBarrier := extractBulkDocuments(buff.Bytes(), defaultIndex)
+
+		depsMap := make(map[int]struct{})
+		if lastBarrier >= 0 {
+			depsMap[lastBarrier] = struct{}{}
+		}
+		if isBarrier {
+			for prevIdx := lastBarrier + 1; prevIdx < idx; prevIdx++ {
+				depsMap[prevIdx] = struct{}{}
+			}
+			lastBarrier = idx
+		}
+		for _, document := range documents {
+			prevIdx, found := lastBufferOfDocument[document]
+			if found && prevIdx > lastBarrier {
+				depsMap[prevIdx] = struct{}{}
+			}
+			lastBufferOfDocument[document] = idx
+		}
+
+		for prevIdx := range depsMap {
+			if prevIdx != idx {
+				dependencies[idx] = append(dependencies[idx], prevIdx)
+			}
+		}
+	}
+
+	return dependencies
+}
+
+// extractBulkDocuments returns the index and id pairs of the actions of a bulk body
+func extractBulkDocuments(body []byte, defaultIndex string) ([]string, bool) {
+	actions, err := data.ParseBulkBody(body, defaultIndex)
+	if err != nil {
+		return nil, true
+	}
+
+	documents := make([]string, 0, len(actions))
+	for _, action := range actions {
+		if action.ID == "" {
+			return nil, true
+		}
+		documents = append(documents, action.Index+"/"+action.ID)
+	}
+
+	return documents, false
+}
+
+// dispatchBulkRequests sends the buffers with at most numWorkers requests in flight. A buffer is sent only after the
+// previous buffers that hold actions on the same documents were sent, so the scripted updates of a document are applied
+// in order. No buffer is sent after a request failed, and the first error is returned
+func dispatchBulkRequests(
+	ctx context.Context,
+	buffSlice []*bytes.Buffer,
+	index string,
+	numWorkers int,
+	sendHandler func(ctx context.Context, buff *bytes.Buffer, index string) error,
+) error {
+	if numWorkers <= 1 || len(buffSlice) <= 1 {
+		for _, buff := range buffSlice {
+			err := sendHandler(ctx, buff, index)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	dependencies := computeBulkDependencies(buffSlice, index)
+	done := make([]chan struct{}, len(buffSlice))
+	for idx := range done {
+		done[idx] = make(chan struct{})
+	}
+
+	workers := make(chan struct{}, numWorkers)
+	mutErr := sync.Mutex{}
+	var firstErr error
+	hasFailed := func() bool {
+		mutErr.Lock()
+		defer mutErr.Unlock()
+
+		return firstErr != nil
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(buffSlice))
+	for idx := range buffSlice {
+		go func(idx int) {
+			defer wg.Done()
+			defer close(done[idx])
+
+			for _, depIdx := range dependencies[idx] {
+				<-done[depIdx]
+			}
+			if hasFailed() {
+				return
+			}
+
+			workers <- struct{}{}
+			defer func() {
+				<-workers
+			}()
+			if hasFailed() {
+				return
+			}
+
+			err := sendHandler(ctx, buffSlice[idx], index)
+			if err != nil {
+				mutErr.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mutErr.Unlock()
+			}
+		}(idx)
+	}
+	wg.Wait()
+
+	return firstErr
+}
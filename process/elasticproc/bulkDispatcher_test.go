@@ -0,0 +1,152 @@
+package elasticproc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createBulkBuffer(actions ...string) *bytes.Buffer {
+	buff := &bytes.Buffer{}
+	for _, action := range actions {
+		buff.WriteString(action + "\n")
+		if action[:10] != `{"delete":` {
+			buff.WriteString(`{"doc":{}}` + "\n")
+		}
+	}
+
+	return buff
+}
+
+func sortedDependencies(dependencies [][]int) [][]int {
+	for _, deps := range dependencies {
+		sort.Ints(deps)
+	}
+
+	return dependencies
+}
+
+func TestComputeBulkDependencies(t *testing.T) {
+	t.Parallel()
+
+	buffers := []*bytes.Buffer{
+		createBulkBuffer(`{"index":{"_index":"transactions","_id":"t1"}}`, `{"update":{"_index":"tokens","_id":"TKN"}}`),
+		createBulkBuffer(`{"index":{"_index":"transactions","_id":"t2"}}`),
+		createBulkBuffer(`{"update":{"_index":"tokens","_id":"TKN"}}`, `{"delete":{"_index":"transactions","_id":"t2"}}`),
+		// same id, in another index
+		createBulkBuffer(`{"update":{"_index":"accountsesdt","_id":"TKN"}}`),
+		// the default index is used when the action has no index
+		createBulkBuffer(`{"update":{"_id":"TKN"}}`),
+	}
+
+	dependencies := computeBulkDependencies(buffers, "accountsesdt")
+	require.Equal(t, [][]int{nil, nil, {0, 1}, nil, {3}}, sortedDependencies(dependencies))
+}
+
+func TestComputeBulkDependencies_Barrier(t *testing.T) {
+	t.Parallel()
+
+	buffers := []*bytes.Buffer{
+		createBulkBuffer(`{"index":{"_index":"rounds","_id":"r1"}}`),
+		createBulkBuffer(`{"index":{"_index":"rounds","_id":"r2"}}`),
+		createBulkBuffer(`{"index":{"_index":"rounds"}}`),
+		createBulkBuffer(`{"index":{"_index":"rounds","_id":"r3"}}`),
+		createBulkBuffer(`{"index":{"_index":"rounds","_id":"r1"}}`),
+	}
+
+	dependencies := computeBulkDependencies(buffers, "")
+	require.Equal(t, [][]int{nil, nil, {0, 1}, {2}, {2}}, sortedDependencies(dependencies))
+}
+
+func TestDispatchBulkRequests_Sequential(t *testing.T) {
+	t.Parallel()
+
+	buffers := []*bytes.Buffer{
+		createBulkBuffer(`{"index":{"_index":"rounds","_id":"r1"}}`),
+		createBulkBuffer(`{"index":{"_index":"rounds","_id":"r2"}}`),
+		createBulkBuffer(`{"index":{"_index":"rounds","_id":"r3"}}`),
+	}
+
+	expectedErr := errors.New("expected error")
+	sent := make([]*bytes.Buffer, 0)
+	err := dispatchBulkRequests(context.Background(), buffers, "", 1, func(_ context.Context, buff *bytes.Buffer, _ string) error {
+		sent = append(sent, buff)
+		if len(sent) == 2 {
+			return expectedErr
+		}
+		return nil
+	})
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, buffers[:2], sent)
+}
+
+func TestDispatchBulkRequests_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	numBuffers := 20
+	numWorkers := 4
+	buffers := make([]*bytes.Buffer, 0, numBuffers)
+	for idx := 0; idx < numBuffers; idx++ {
+		// every fifth buffer updates the same document
+		id := fmt.Sprintf("doc%d", idx)
+		if idx%5 == 0 {
+			id = "shared"
+		}
+		buffers = append(buffers, createBulkBuffer(fmt.Sprintf(`{"update":{"_index":"tokens","_id":"%s"}}`, id)))
+	}
+	positions := make(map[*bytes.Buffer]int)
+	for idx, buff := range buffers {
+		positions[buff] = idx
+	}
+
+	inFlight, maxInFlight := int32(0), int32(0)
+	mut := sync.Mutex{}
+	sharedOrder := make([]int, 0)
+	err := dispatchBulkRequests(context.Background(), buffers, "", numWorkers, func(_ context.Context, buff *bytes.Buffer, _ string) error {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		mut.Lock()
+		if current > maxInFlight {
+			maxInFlight = current
+		}
+		if positions[buff]%5 == 0 {
+			sharedOrder = append(sharedOrder, positions[buff])
+		}
+		mut.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+	require.Nil(t, err)
+	require.LessOrEqual(t, maxInFlight, int32(numWorkers))
+	require.Greater(t, maxInFlight, int32(1))
+	require.Equal(t, []int{0, 5, 10, 15}, sharedOrder)
+}
+
+func TestDispatchBulkRequests_ErrorStopsTheDependentRequests(t *testing.T) {
+	t.Parallel()
+
+	buffers := []*bytes.Buffer{
+		createBulkBuffer(`{"update":{"_index":"tokens","_id":"shared"}}`),
+		createBulkBuffer(`{"update":{"_index":"tokens","_id":"shared"}}`),
+		createBulkBuffer(`{"update":{"_index":"tokens","_id":"shared"}}`),
+	}
+
+	expectedErr := errors.New("expected error")
+	numSent := int32(0)
+	err := dispatchBulkRequests(context.Background(), buffers, "", 3, func(_ context.Context, _ *bytes.Buffer, _ string) error {
+		atomic.AddInt32(&numSent, 1)
+		return expectedErr
+	})
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&numSent))
+}
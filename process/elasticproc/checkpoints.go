@@ -0,0 +1,135 @@
+package elasticproc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	coreData "github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	elasticIndexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+const checkpointKeyPrefix = "checkpoint"
+
+// CheckpointKey returns the id of the document from the values index that holds the last indexed block of a shard
+func CheckpointKey(shardID uint32) string {
+	return fmt.Sprintf("%s-%d", checkpointKeyPrefix, shardID)
+}
+
+// SaveCheckpoint will save in the values index the provided block as the last block that was successfully indexed for
+// its shard. The checkpoint only moves forward, so an older or a replayed block does not overwrite it
+func (ei *elasticProcessor) SaveCheckpoint(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error {
+	if !ei.isIndexEnabled(elasticIndexer.ValuesIndex) {
+		return nil
+	}
+
+	shardID := header.GetShardID()
+	checkpoint := &data.ShardBlockInfo{
+		Key:         CheckpointKey(shardID),
+		ShardID:     shardID,
+		HeaderHash:  hex.EncodeToString(headerHash),
+		Nonce:       header.GetNonce(),
+		Round:       header.GetRound(),
+		TimestampMs: timestampMs,
+	}
+
+	buffSlice := ei.newBufferSlice()
+	err := serializeShardBlockInfo(checkpoint, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBlockBulkRequests("", buffSlice.Buffers(), shardID, checkpoint.Nonce)
+}
+
+// RevertCheckpoint will move the checkpoint of the shard of the provided block to the previous block, if the checkpoint
+// points to the reverted block
+func (ei *elasticProcessor) RevertCheckpoint(header coreData.HeaderHandler, headerHash []byte) error {
+	if !ei.isIndexEnabled(elasticIndexer.ValuesIndex) || header.GetNonce() == 0 {
+		return nil
+	}
+
+	shardID := header.GetShardID()
+	previous := &data.ShardBlockInfo{
+		Key:        CheckpointKey(shardID),
+		ShardID:    shardID,
+		HeaderHash: hex.EncodeToString(header.GetPrevHash()),
+		Nonce:      header.GetNonce() - 1,
+	}
+	if ei.isIndexEnabled(elasticIndexer.BlockIndex) {
+		previousBlock, found, err := ei.getIndexedBlock(previous.HeaderHash, shardID)
+		if err != nil {
+			return err
+		}
+		if found {
+			previous.Round = previousBlock.Round
+			previous.TimestampMs = previousBlock.TimestampMs
+		}
+	}
+
+	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, elasticIndexer.ValuesIndex, converters.JsonEscape(previous.Key), "\n"))
+	infoBytes, err := json.Marshal(previous)
+	if err != nil {
+		return err
+	}
+
+	codeToExecute := `
+		if (('create' != ctx.op) && (ctx._source.headerHash == params.revertedHash)) {
+			ctx._source = params.info;
+		} else {
+			ctx.op = 'noop';
+		}
+`
+	serializedData := []byte(fmt.Sprintf(`{"scripted_upsert": true, "script": {"source": "%s","lang": "painless","params": {"info": %s, "revertedHash": "%s"}},"upsert": {}}`,
+		converters.FormatPainlessSource(codeToExecute), string(infoBytes), hex.EncodeToString(headerHash)),
+	)
+
+	buffSlice := ei.newBufferSlice()
+	err = buffSlice.PutData(meta, serializedData)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBlockBulkRequests("", buffSlice.Buffers(), shardID, header.GetNonce())
+}
+
+// GetCheckpoints returns the last indexed block of every shard, as it is stored in the values index
+func (ei *elasticProcessor) GetCheckpoints() (map[uint32]*data.ShardBlockInfo, error) {
+	checkpoints := make(map[uint32]*data.ShardBlockInfo)
+	if !ei.isIndexEnabled(elasticIndexer.ValuesIndex) {
+		return checkpoints, nil
+	}
+
+	handlerFunc := func(responseBytes []byte) error {
+		responseScroll := &data.ResponseScroll{}
+		err := json.Unmarshal(responseBytes, responseScroll)
+		if err != nil {
+			return err
+		}
+
+		for _, hit := range responseScroll.Hits.Hits {
+			checkpoint := &data.ShardBlockInfo{}
+			err = json.Unmarshal(hit.Source, checkpoint)
+			if err != nil {
+				return err
+			}
+
+			checkpoints[checkpoint.ShardID] = checkpoint
+		}
+
+		return nil
+	}
+
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ScrollTopic)
+	query := fmt.Sprintf(`{"query": {"prefix": {"key": "%s-"}}}`, checkpointKeyPrefix)
+	err := ei.elasticClient.DoScrollRequest(ctxWithValue, elasticIndexer.ValuesIndex, []byte(query), true, handlerFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkpoints, nil
+}
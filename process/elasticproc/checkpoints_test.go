@@ -0,0 +1,133 @@
+package elasticproc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/memory"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticProcessor_SaveCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody = buff.String()
+			return nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	header := &dataBlock.Header{ShardID: 2, Nonce: 10, Round: 11}
+	err = elasticProc.SaveCheckpoint(header, []byte("hash1"), 12000)
+	require.Nil(t, err)
+
+	expectedBody := `{ "update" : { "_index":"values", "_id" : "checkpoint-2" } }
+{"scripted_upsert": true, "script": {"source": "if (('create' == ctx.op) || (ctx._source.nonce == null) || (ctx._source.nonce < params.info.nonce)) {ctx._source = params.info;} else {ctx.op = 'noop';}","lang": "painless","params": {"info": {"key":"checkpoint-2","shardId":2,"headerHash":"6861736831","nonce":10,"round":11,"timestampMs":12000}}},"upsert": {}}
+`
+	require.Equal(t, expectedBody, bulkBody)
+}
+
+func TestElasticProcessor_SaveCheckpointShouldNotMoveBackwards(t *testing.T) {
+	t.Parallel()
+
+	dbClient := memory.NewMemoryClient()
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+	args.DBClient = dbClient
+	elasticProc, _ := NewElasticProcessor(args)
+
+	err := elasticProc.SaveCheckpoint(&dataBlock.Header{ShardID: 1, Nonce: 10}, []byte("hash10"), 0)
+	require.Nil(t, err)
+	err = elasticProc.SaveCheckpoint(&dataBlock.Header{ShardID: 1, Nonce: 9}, []byte("hash9"), 0)
+	require.Nil(t, err)
+
+	checkpoints, err := elasticProc.GetCheckpoints()
+	require.Nil(t, err)
+	require.Equal(t, uint64(10), checkpoints[1].Nonce)
+	require.Equal(t, hex.EncodeToString([]byte("hash10")), checkpoints[1].HeaderHash)
+}
+
+func TestElasticProcessor_RevertCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	dbClient := memory.NewMemoryClient()
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+	args.DBClient = dbClient
+	elasticProc, _ := NewElasticProcessor(args)
+
+	err := elasticProc.SaveCheckpoint(&dataBlock.Header{ShardID: 1, Nonce: 10}, []byte("hash10"), 0)
+	require.Nil(t, err)
+
+	// a block that is not the checkpoint does not move it
+	err = elasticProc.RevertCheckpoint(&dataBlock.Header{ShardID: 1, Nonce: 8, PrevHash: []byte("hash7")}, []byte("hash8"))
+	require.Nil(t, err)
+	checkpoints, _ := elasticProc.GetCheckpoints()
+	require.Equal(t, uint64(10), checkpoints[1].Nonce)
+
+	err = elasticProc.RevertCheckpoint(&dataBlock.Header{ShardID: 1, Nonce: 10, PrevHash: []byte("hash9")}, []byte("hash10"))
+	require.Nil(t, err)
+	checkpoints, _ = elasticProc.GetCheckpoints()
+	require.Equal(t, uint64(9), checkpoints[1].Nonce)
+	require.Equal(t, hex.EncodeToString([]byte("hash9")), checkpoints[1].HeaderHash)
+
+	// the next block of the shard moves it forward again
+	err = elasticProc.SaveCheckpoint(&dataBlock.Header{ShardID: 1, Nonce: 10}, []byte("hash10b"), 0)
+	require.Nil(t, err)
+	checkpoints, _ = elasticProc.GetCheckpoints()
+	require.Equal(t, hex.EncodeToString([]byte("hash10b")), checkpoints[1].HeaderHash)
+}
+
+func TestElasticProcessor_SaveCheckpointValuesIndexDisabledShouldNotDoRequest(t *testing.T) {
+	t.Parallel()
+
+	args := createMockElasticProcessorArgs()
+	delete(args.EnabledIndexes, dataindexer.ValuesIndex)
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	err := elasticProc.SaveCheckpoint(&dataBlock.Header{}, []byte("hash1"), 0)
+	require.Nil(t, err)
+}
+
+func TestElasticProcessor_GetCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			require.Equal(t, dataindexer.ValuesIndex, index)
+			require.Equal(t, `{"query": {"prefix": {"key": "checkpoint-"}}}`, string(body))
+
+			return handlerFunc([]byte(`{"hits":{"hits":[
+				{"_id":"checkpoint-0","_source":{"key":"checkpoint-0","shardId":0,"headerHash":"aa","nonce":5,"round":6}},
+				{"_id":"checkpoint-4294967295","_source":{"key":"checkpoint-4294967295","shardId":4294967295,"headerHash":"bb","nonce":7,"round":8,"timestampMs":9000}}
+			]}}`))
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	checkpoints, err := elasticProc.GetCheckpoints()
+	require.Nil(t, err)
+	require.Equal(t, map[uint32]*data.ShardBlockInfo{
+		0:          {Key: "checkpoint-0", ShardID: 0, HeaderHash: "aa", Nonce: 5, Round: 6},
+		4294967295: {Key: "checkpoint-4294967295", ShardID: 4294967295, HeaderHash: "bb", Nonce: 7, Round: 8, TimestampMs: 9000},
+	}, checkpoints)
+}
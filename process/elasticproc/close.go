@@ -0,0 +1,22 @@
+package elasticproc
+
+import (
+	"io"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+)
+
+// CloseDatabaseClient closes the database client when it holds resources that have to be released, like the goroutines
+// of the mirror clusters or the files of the bulk sink. The database clients that wrap another client close it as well
+func CloseDatabaseClient(databaseClient DatabaseClientHandler) error {
+	if check.IfNil(databaseClient) {
+		return nil
+	}
+
+	closer, ok := databaseClient.(io.Closer)
+	if !ok {
+		return nil
+	}
+
+	return closer.Close()
+}
@@ -0,0 +1,164 @@
+package elasticproc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	coreData "github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	elasticIndexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+const (
+	pendingCommitsIDPrefix = "pending-"
+	// PendingCommitIDsField is the field of the pending commits document of a shard that holds the commit ids of the
+	// blocks that are being written
+	PendingCommitIDsField = "pendingCommitIds"
+)
+
+// CommitID returns the commit id of a block, which is also the id of its document from the commits index
+func CommitID(headerHash []byte) string {
+	return hex.EncodeToString(headerHash)
+}
+
+// PendingCommitsID returns the id of the document from the commits index that holds the commit ids of the blocks of
+// the shard that are not committed yet. The readers exclude these commit ids with a terms lookup on the document
+func PendingCommitsID(shardID uint32) string {
+	return pendingCommitsIDPrefix + strconv.FormatUint(uint64(shardID), 10)
+}
+
+// SavePendingCommit will add the commit id of the provided block to the pending commits of its shard. It has to be
+// called before the documents of the block are saved, so the readers that filter their queries on the pending
+// commits never see a block that is written halfway
+func (ei *elasticProcessor) SavePendingCommit(header coreData.HeaderHandler, headerHash []byte) error {
+	if !ei.isIndexEnabled(elasticIndexer.CommitsIndex) {
+		return nil
+	}
+
+	buffSlice := ei.newBufferSlice()
+	err := serializePendingCommit(buffSlice, header.GetShardID(), CommitID(headerHash), true)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBlockBulkRequests("", buffSlice.Buffers(), header.GetShardID(), header.GetNonce())
+}
+
+// SaveCommit will save in the commits index the commit record of the provided block and will remove its commit id from
+// the pending commits of its shard. It has to be called after all the documents of the block were saved, because the
+// readers show the documents stamped with the commit id of the block only after its commit record exists
+func (ei *elasticProcessor) SaveCommit(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error {
+	if !ei.isIndexEnabled(elasticIndexer.CommitsIndex) {
+		return nil
+	}
+
+	commit := &data.BlockCommit{
+		CommitID:    CommitID(headerHash),
+		ShardID:     header.GetShardID(),
+		Nonce:       header.GetNonce(),
+		Round:       header.GetRound(),
+		TimestampMs: timestampMs,
+	}
+
+	meta := []byte(fmt.Sprintf(`{ "index" : { "_index":"%s", "_id" : "%s" } }%s`, elasticIndexer.CommitsIndex, converters.JsonEscape(commit.CommitID), "\n"))
+	serializedData, err := json.Marshal(commit)
+	if err != nil {
+		return err
+	}
+
+	buffSlice := ei.newBufferSlice()
+	err = buffSlice.PutData(meta, serializedData)
+	if err != nil {
+		return err
+	}
+	err = serializePendingCommit(buffSlice, commit.ShardID, commit.CommitID, false)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBlockBulkRequests("", buffSlice.Buffers(), commit.ShardID, commit.Nonce)
+}
+
+// serializePendingCommit adds or removes the commit id from the pending commits document of the shard. The document
+// of a shard is only updated by the blocks of the shard, which are indexed one at a time, so the updates never conflict
+func serializePendingCommit(buffSlice *data.BufferSlice, shardID uint32, commitID string, isPending bool) error {
+	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, elasticIndexer.CommitsIndex, PendingCommitsID(shardID), "\n"))
+
+	codeToExecute := `
+		if (ctx._source.pendingCommitIds == null) {
+			ctx._source.pendingCommitIds = new ArrayList();
+		}
+		ctx._source.pendingCommitIds.removeIf(id -> id == params.commitId);
+		if (params.isPending) {
+			ctx._source.pendingCommitIds.add(params.commitId);
+		}
+`
+	serializedData := []byte(fmt.Sprintf(`{"scripted_upsert": true, "script": {"source": "%s","lang": "painless","params": {"commitId": "%s", "isPending": %t}}, "upsert": {}}`,
+		converters.FormatPainlessSource(codeToExecute), converters.JsonEscape(commitID), isPending))
+
+	return buffSlice.PutData(meta, serializedData)
+}
+
+// removeCommit adds the commit id of a block to the pending commits of its shard and removes its commit record, so its
+// documents are hidden from the readers before they are removed. The commit id stays pending, as nothing is left of
+// the reverted block, until the block is indexed again
+func (ei *elasticProcessor) removeCommit(header coreData.HeaderHandler, headerHash []byte) error {
+	if !ei.isIndexEnabled(elasticIndexer.CommitsIndex) {
+		return nil
+	}
+
+	shardID := header.GetShardID()
+	buffSlice := ei.newBufferSlice()
+	err := serializePendingCommit(buffSlice, shardID, CommitID(headerHash), true)
+	if err != nil {
+		return err
+	}
+	err = ei.doBlockBulkRequests("", buffSlice.Buffers(), shardID, header.GetNonce())
+	if err != nil {
+		return err
+	}
+
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+	return ei.elasticClient.DoQueryRemove(
+		ctxWithValue,
+		elasticIndexer.CommitsIndex,
+		converters.PrepareHashesForQueryRemove([]string{CommitID(headerHash)}),
+	)
+}
+
+// commitIDForBlock returns the commit id the documents of the block are stamped with, or an empty string if the commit
+// records are not saved
+func (ei *elasticProcessor) commitIDForBlock(headerHash []byte) string {
+	if !ei.isIndexEnabled(elasticIndexer.CommitsIndex) {
+		return ""
+	}
+
+	return CommitID(headerHash)
+}
+
+func stampTransactionsData(commitID string, preparedResults *data.PreparedResults, logsData *data.PreparedLogsResults) {
+	if commitID == "" {
+		return
+	}
+
+	for _, tx := range preparedResults.Transactions {
+		tx.CommitID = commitID
+	}
+	for _, scr := range preparedResults.ScResults {
+		scr.CommitID = commitID
+	}
+	for _, receipt := range preparedResults.Receipts {
+		receipt.CommitID = commitID
+	}
+	for _, dbLog := range logsData.DBLogs {
+		dbLog.CommitID = commitID
+	}
+	for _, event := range logsData.DBEvents {
+		event.CommitID = commitID
+	}
+}
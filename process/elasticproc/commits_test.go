@@ -0,0 +1,164 @@
+package elasticproc
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/memory"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticProcessor_SaveCommit(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.CommitsIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody = buff.String()
+			return nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	header := &dataBlock.Header{ShardID: 2, Nonce: 10, Round: 11}
+	err = elasticProc.SaveCommit(header, []byte("hash1"), 12000)
+	require.Nil(t, err)
+
+	expectedRecord := `{ "index" : { "_index":"commits", "_id" : "6861736831" } }
+{"commitId":"6861736831","shardId":2,"nonce":10,"round":11,"timestampMs":12000}
+{ "update" : { "_index":"commits", "_id" : "pending-2" } }
+`
+	require.True(t, strings.HasPrefix(bulkBody, expectedRecord))
+}
+
+func TestElasticProcessor_SavePendingCommitAndSaveCommit(t *testing.T) {
+	t.Parallel()
+
+	dbClient := memory.NewMemoryClient()
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.CommitsIndex] = struct{}{}
+	args.DBClient = dbClient
+	elasticProc, _ := NewElasticProcessor(args)
+
+	getPendingCommitIDs := func() []string {
+		response := &struct {
+			Docs []struct {
+				Source map[string][]string `json:"_source"`
+			} `json:"docs"`
+		}{}
+		err := dbClient.DoMultiGet(context.Background(), []string{PendingCommitsID(2)}, dataindexer.CommitsIndex, true, response)
+		require.Nil(t, err)
+
+		return response.Docs[0].Source[PendingCommitIDsField]
+	}
+
+	header1 := &dataBlock.Header{ShardID: 2, Nonce: 10}
+	header2 := &dataBlock.Header{ShardID: 2, Nonce: 11}
+	require.Nil(t, elasticProc.SavePendingCommit(header1, []byte("hash1")))
+	require.Nil(t, elasticProc.SavePendingCommit(header2, []byte("hash2")))
+	// a block that is indexed again is pending only once
+	require.Nil(t, elasticProc.SavePendingCommit(header1, []byte("hash1")))
+	require.Equal(t, []string{"6861736832", "6861736831"}, getPendingCommitIDs())
+
+	require.Nil(t, elasticProc.SaveCommit(header1, []byte("hash1"), 0))
+	require.Equal(t, []string{"6861736832"}, getPendingCommitIDs())
+}
+
+func TestElasticProcessor_SaveCommitCommitsIndexDisabledShouldNotDoRequest(t *testing.T) {
+	t.Parallel()
+
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	err := elasticProc.SaveCommit(&dataBlock.Header{}, []byte("hash1"), 0)
+	require.Nil(t, err)
+}
+
+func TestElasticProcessor_RemoveHeaderShouldRemoveTheCommitFirst(t *testing.T) {
+	t.Parallel()
+
+	removedFrom := make([]string, 0)
+	bulkBody := ""
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.CommitsIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.Empty(t, removedFrom)
+			bulkBody = buff.String()
+			return nil
+		},
+		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
+			removedFrom = append(removedFrom, index)
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	err := elasticProc.RemoveHeader(&dataBlock.Header{})
+	require.Nil(t, err)
+	require.Equal(t, []string{dataindexer.CommitsIndex, dataindexer.BlockIndex}, removedFrom)
+	// the commit id is pending before the commit record is removed
+	require.Contains(t, bulkBody, `"_id" : "pending-0"`)
+	require.Contains(t, bulkBody, `"isPending": true`)
+}
+
+func TestElasticProcessor_SaveMiniblocksShouldStampTheCommitID(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.CommitsIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody = buff.String()
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	header := &dataBlock.Header{}
+	headerHash, _ := args.BlockProc.ComputeHeaderHash(header)
+	miniBlocks := []*dataBlock.MiniBlock{{SenderShardID: 0, ReceiverShardID: 1}}
+	err := elasticProc.SaveMiniblocks(header, miniBlocks, 0)
+	require.Nil(t, err)
+	require.True(t, strings.Contains(bulkBody, `"commitId":"`+CommitID(headerHash)+`"`))
+}
+
+func TestStampTransactionsData(t *testing.T) {
+	t.Parallel()
+
+	preparedResults := &data.PreparedResults{
+		Transactions: []*data.Transaction{{}},
+		ScResults:    []*data.ScResult{{}},
+		Receipts:     []*data.Receipt{{}},
+	}
+	logsData := &data.PreparedLogsResults{
+		DBLogs:   []*data.Logs{{}},
+		DBEvents: []*data.LogEvent{{}},
+	}
+
+	stampTransactionsData("", preparedResults, logsData)
+	require.Empty(t, preparedResults.Transactions[0].CommitID)
+
+	stampTransactionsData("abcd", preparedResults, logsData)
+	require.Equal(t, "abcd", preparedResults.Transactions[0].CommitID)
+	require.Equal(t, "abcd", preparedResults.ScResults[0].CommitID)
+	require.Equal(t, "abcd", preparedResults.Receipts[0].CommitID)
+	require.Equal(t, "abcd", logsData.DBLogs[0].CommitID)
+	require.Equal(t, "abcd", logsData.DBEvents[0].CommitID)
+}
@@ -30,7 +30,7 @@ var (
 		elasticIndexer.TransactionsIndex, elasticIndexer.BlockIndex, elasticIndexer.MiniblocksIndex, elasticIndexer.RatingIndex, elasticIndexer.RoundsIndex, elasticIndexer.ValidatorsIndex,
 		elasticIndexer.AccountsIndex, elasticIndexer.AccountsHistoryIndex, elasticIndexer.ReceiptsIndex, elasticIndexer.ScResultsIndex, elasticIndexer.AccountsESDTHistoryIndex, elasticIndexer.AccountsESDTIndex,
 		elasticIndexer.EpochInfoIndex, elasticIndexer.SCDeploysIndex, elasticIndexer.TokensIndex, elasticIndexer.TagsIndex, elasticIndexer.LogsIndex, elasticIndexer.DelegatorsIndex, elasticIndexer.OperationsIndex,
-		elasticIndexer.ESDTsIndex, elasticIndexer.ValuesIndex, elasticIndexer.EventsIndex,
+		elasticIndexer.ESDTsIndex, elasticIndexer.ValuesIndex, elasticIndexer.EventsIndex, elasticIndexer.NonceGapsIndex, elasticIndexer.CommitsIndex,
 	}
 )
 
@@ -39,7 +39,10 @@ const versionStr = "indexer-version"
 // ArgElasticProcessor holds all dependencies required by the elasticProcessor in order to create
 // new instances
 type ArgElasticProcessor struct {
+	Context            context.Context
 	BulkRequestMaxSize int
+	BulkRequestWorkers int
+	CompressBulkBodies bool
 	UseKibana          bool
 	ImportDB           bool
 	EnabledIndexes     map[string]struct{}
@@ -57,7 +60,10 @@ type ArgElasticProcessor struct {
 }
 
 type elasticProcessor struct {
+	ctx                context.Context
 	bulkRequestMaxSize int
+	bulkRequestWorkers int
+	compressBulkBodies bool
 	importDB           bool
 	enabledIndexes     map[string]struct{}
 	mutex              sync.RWMutex
@@ -71,16 +77,25 @@ type elasticProcessor struct {
 	logsAndEventsProc  DBLogsAndEventsHandler
 	operationsProc     OperationsHandler
 	mappingsHandler    TemplatesAndPoliciesHandler
+	tokensLocker       *tokensLocker
 }
 
-// NewElasticProcessor handles Elasticsearch operations such as initialization, adding, modifying or removing data
+// NewElasticProcessor handles Elasticsearch operations such as initialization, adding, modifying or removing data.
+// All the requests are sent with the provided context, so they are interrupted when it is cancelled. A nil context is
+// never cancelled
 func NewElasticProcessor(arguments *ArgElasticProcessor) (*elasticProcessor, error) {
 	err := checkArguments(arguments)
 	if err != nil {
 		return nil, err
 	}
 
+	ctx := arguments.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	ei := &elasticProcessor{
+		ctx:                ctx,
 		elasticClient:      arguments.DBClient,
 		enabledIndexes:     arguments.EnabledIndexes,
 		accountsProc:       arguments.AccountsProc,
@@ -92,7 +107,10 @@ func NewElasticProcessor(arguments *ArgElasticProcessor) (*elasticProcessor, err
 		logsAndEventsProc:  arguments.LogsAndEventsProc,
 		operationsProc:     arguments.OperationsProc,
 		bulkRequestMaxSize: arguments.BulkRequestMaxSize,
+		bulkRequestWorkers: arguments.BulkRequestWorkers,
+		compressBulkBodies: arguments.CompressBulkBodies,
 		mappingsHandler:    arguments.MappingsHandler,
+		tokensLocker:       newTokensLocker(),
 	}
 
 	err = ei.init()
@@ -107,7 +125,13 @@ func NewElasticProcessor(arguments *ArgElasticProcessor) (*elasticProcessor, err
 
 // TODO move all the index create part in a new component
 func (ei *elasticProcessor) init() error {
-	indexTemplates, _, err := ei.mappingsHandler.GetElasticTemplatesAndPolicies()
+	indexTemplates, indexPolicies, err := ei.mappingsHandler.GetElasticTemplatesAndPolicies()
+	if err != nil {
+		return err
+	}
+
+	// the policies are attached to the indices when they are created
+	err = ei.createIndexPolicies(indexPolicies)
 	if err != nil {
 		return err
 	}
@@ -174,20 +198,14 @@ func (ei *elasticProcessor) indexVersion(version string) error {
 		return err
 	}
 
-	return ei.elasticClient.DoBulkRequest(context.Background(), buffSlice.Buffers()[0], "")
+	return ei.elasticClient.DoBulkRequest(ei.ctx, buffSlice.Buffers()[0], "")
 }
 
-// nolint
 func (ei *elasticProcessor) createIndexPolicies(indexPolicies map[string]*bytes.Buffer) error {
-	indexesPolicies := []string{elasticIndexer.TransactionsPolicy, elasticIndexer.BlockPolicy, elasticIndexer.MiniblocksPolicy, elasticIndexer.RatingPolicy, elasticIndexer.RoundsPolicy, elasticIndexer.ValidatorsPolicy,
-		elasticIndexer.AccountsPolicy, elasticIndexer.AccountsESDTPolicy, elasticIndexer.AccountsHistoryPolicy, elasticIndexer.AccountsESDTHistoryPolicy, elasticIndexer.AccountsESDTIndex, elasticIndexer.ReceiptsPolicy, elasticIndexer.ScResultsPolicy}
-	for _, indexPolicyName := range indexesPolicies {
-		indexPolicy := getTemplateByName(indexPolicyName, indexPolicies)
-		if indexPolicy != nil {
-			err := ei.elasticClient.CheckAndCreatePolicy(indexPolicyName, indexPolicy)
-			if err != nil {
-				return err
-			}
+	for indexPolicyName, indexPolicy := range indexPolicies {
+		err := ei.elasticClient.CheckAndCreatePolicy(indexPolicyName, indexPolicy)
+		if err != nil {
+			return fmt.Errorf("policy: %s, error: %w", indexPolicyName, err)
 		}
 	}
 
@@ -262,8 +280,9 @@ func (ei *elasticProcessor) SaveHeader(outportBlockWithHeader *outport.OutportBl
 	if err != nil {
 		return err
 	}
+	elasticBlock.CommitID = ei.commitIDForBlock(outportBlockWithHeader.BlockData.HeaderHash)
 
-	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	buffSlice := ei.newBufferSlice()
 	err = ei.blockProc.SerializeBlock(elasticBlock, buffSlice, elasticIndexer.BlockIndex)
 	if err != nil {
 		return err
@@ -274,7 +293,7 @@ func (ei *elasticProcessor) SaveHeader(outportBlockWithHeader *outport.OutportBl
 		return err
 	}
 
-	return ei.doBulkRequests("", buffSlice.Buffers(), outportBlockWithHeader.ShardID)
+	return ei.doBlockBulkRequests("", buffSlice.Buffers(), outportBlockWithHeader.ShardID, outportBlockWithHeader.Header.GetNonce())
 }
 
 func (ei *elasticProcessor) indexEpochInfoData(header coreData.HeaderHandler, buffSlice *data.BufferSlice) error {
@@ -286,14 +305,19 @@ func (ei *elasticProcessor) indexEpochInfoData(header coreData.HeaderHandler, bu
 	return ei.blockProc.SerializeEpochInfoData(header, buffSlice, elasticIndexer.EpochInfoIndex)
 }
 
-// RemoveHeader will remove a block from elasticsearch server
+// RemoveHeader will remove a block from elasticsearch server. The commit record of the block is removed first
 func (ei *elasticProcessor) RemoveHeader(header coreData.HeaderHandler) error {
 	headerHash, err := ei.blockProc.ComputeHeaderHash(header)
 	if err != nil {
 		return err
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, header.GetShardID()))
+	err = ei.removeCommit(header, headerHash)
+	if err != nil {
+		return err
+	}
+
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, header.GetShardID()))
 	return ei.elasticClient.DoQueryRemove(
 		ctxWithValue,
 		elasticIndexer.BlockIndex,
@@ -308,7 +332,7 @@ func (ei *elasticProcessor) RemoveMiniblocks(header coreData.HeaderHandler, body
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, header.GetShardID()))
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, header.GetShardID()))
 	return ei.elasticClient.DoQueryRemove(
 		ctxWithValue,
 		elasticIndexer.MiniblocksIndex,
@@ -358,7 +382,7 @@ func (ei *elasticProcessor) updateDelegatorsInCaseOfRevert(header coreData.Heade
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.UpdateTopic, header.GetShardID()))
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.UpdateTopic, header.GetShardID()))
 
 	delegatorsQuery := ei.logsAndEventsProc.PrepareDelegatorsQueryInCaseOfRevert(timestampMs)
 	return ei.elasticClient.UpdateByQuery(ctxWithValue, elasticIndexer.DelegatorsIndex, delegatorsQuery)
@@ -369,7 +393,7 @@ func (ei *elasticProcessor) removeIfHashesNotEmpty(index string, hashes []string
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
 	return ei.elasticClient.DoQueryRemove(
 		ctxWithValue,
 		index,
@@ -388,7 +412,7 @@ func (ei *elasticProcessor) RemoveAccountsESDT(shardID uint32, timestampMs uint6
 }
 
 func (ei *elasticProcessor) removeFromIndexByTimestampAndShardID(shardID uint32, index string, timestampMs uint64) error {
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.RemoveTopic, shardID))
 	query := fmt.Sprintf(`{"query": {"bool": {"must": [{"match": {"shardID": {"query": %d,"operator": "AND"}}},{"match": {"timestampMs": {"query": "%d","operator": "AND"}}}]}}}`, shardID, timestampMs)
 
 	return ei.elasticClient.DoQueryRemove(
@@ -409,10 +433,22 @@ func (ei *elasticProcessor) SaveMiniblocks(header coreData.HeaderHandler, miniBl
 		return nil
 	}
 
-	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	if ei.isIndexEnabled(elasticIndexer.CommitsIndex) {
+		headerHash, err := ei.blockProc.ComputeHeaderHash(header)
+		if err != nil {
+			return err
+		}
+
+		commitID := CommitID(headerHash)
+		for _, mb := range mbs {
+			mb.CommitID = commitID
+		}
+	}
+
+	buffSlice := ei.newBufferSlice()
 	ei.miniblocksProc.SerializeBulkMiniBlocks(mbs, buffSlice, elasticIndexer.MiniblocksIndex, header.GetShardID())
 
-	return ei.doBulkRequests("", buffSlice.Buffers(), header.GetShardID())
+	return ei.doBlockBulkRequests("", buffSlice.Buffers(), header.GetShardID(), header.GetNonce())
 }
 
 // SaveTransactions will prepare and save information about a transactions in elasticsearch server
@@ -422,8 +458,15 @@ func (ei *elasticProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader
 	miniBlocks := append(obh.BlockData.Body.MiniBlocks, obh.BlockData.IntraShardMiniBlocks...)
 	preparedResults := ei.transactionsProc.PrepareTransactionsForDatabase(miniBlocks, obh.Header, obh.TransactionPool, ei.isImportDB(), obh.NumberOfShards, obh.BlockData.TimestampMs)
 	logsData := ei.logsAndEventsProc.ExtractDataFromLogs(obh.TransactionPool.Logs, preparedResults, headerTimestamp, obh.Header.GetShardID(), obh.NumberOfShards, obh.BlockData.TimestampMs)
+	stampTransactionsData(ei.commitIDForBlock(obh.BlockData.HeaderHash), preparedResults, logsData)
 
-	buffers := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	if obh.ShardID == core.MetachainShardId {
+		// the shard blocks processed at the same time do not read the tokens while this block writes them
+		unlockTokens := ei.tokensLocker.lock(getIssuedTokens(logsData.TokensInfo))
+		defer unlockTokens()
+	}
+
+	buffers := ei.newBufferSlice()
 	err := ei.indexTransactions(preparedResults.Transactions, logsData.TxHashStatusInfo, obh.Header, buffers)
 	if err != nil {
 		return err
@@ -504,7 +547,7 @@ func (ei *elasticProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader
 		return err
 	}
 
-	return ei.doBulkRequests("", buffers.Buffers(), obh.ShardID)
+	return ei.doBlockBulkRequests("", buffers.Buffers(), obh.ShardID, obh.Header.GetNonce())
 }
 
 func (ei *elasticProcessor) prepareAndIndexRolesData(tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties, buffSlice *data.BufferSlice, index string) error {
@@ -631,7 +674,7 @@ func (ei *elasticProcessor) SaveRoundsInfo(rounds *outport.RoundsInfo) error {
 
 	buff := ei.statisticsProc.SerializeRoundsInfo(rounds)
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.BulkTopic, rounds.ShardID))
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.BulkTopic, rounds.ShardID))
 	return ei.elasticClient.DoBulkRequest(ctxWithValue, buff, elasticIndexer.RoundsIndex)
 }
 
@@ -680,9 +723,13 @@ func (ei *elasticProcessor) addTokenTypeAndCurrentOwnerInAccountsESDT(tokensData
 		return nil
 	}
 
+	tokens := tokensData.GetAllTokens()
+	unlockTokens := ei.rLockTokens(tokens, shardID)
+	defer unlockTokens()
+
 	responseTokens := &data.ResponseTokens{}
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
-	err := ei.elasticClient.DoMultiGet(ctxWithValue, tokensData.GetAllTokens(), elasticIndexer.TokensIndex, true, responseTokens)
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, tokens, elasticIndexer.TokensIndex, true, responseTokens)
 	if err != nil {
 		return err
 	}
@@ -720,9 +767,13 @@ func (ei *elasticProcessor) indexNFTCreateInfo(tokensData data.TokensHandler, co
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	tokensToGet := tokensData.GetAllTokens()
+	unlockTokens := ei.rLockTokens(tokensToGet, shardID)
+	defer unlockTokens()
+
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
 	responseTokens := &data.ResponseTokens{}
-	err := ei.elasticClient.DoMultiGet(ctxWithValue, tokensData.GetAllTokens(), elasticIndexer.TokensIndex, true, responseTokens)
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, tokensToGet, elasticIndexer.TokensIndex, true, responseTokens)
 	if err != nil {
 		return err
 	}
@@ -741,9 +792,13 @@ func (ei *elasticProcessor) indexNFTBurnInfo(tokensData data.TokensHandler, buff
 		return nil
 	}
 
-	ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	tokensToGet := tokensData.GetAllTokens()
+	unlockTokens := ei.rLockTokens(tokensToGet, shardID)
+	defer unlockTokens()
+
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
 	responseTokens := &data.ResponseTokens{}
-	err := ei.elasticClient.DoMultiGet(ctxWithValue, tokensData.GetAllTokens(), elasticIndexer.TokensIndex, true, responseTokens)
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, tokensToGet, elasticIndexer.TokensIndex, true, responseTokens)
 	if err != nil {
 		return err
 	}
@@ -755,7 +810,7 @@ func (ei *elasticProcessor) indexNFTBurnInfo(tokensData data.TokensHandler, buff
 
 // SaveAccounts will prepare and save information about provided accounts in elasticsearch server
 func (ei *elasticProcessor) SaveAccounts(accountsData *outport.Accounts) error {
-	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+	buffSlice := ei.newBufferSlice()
 
 	accounts := make([]*data.Account, 0, len(accountsData.AlteredAccounts))
 	for _, account := range accountsData.AlteredAccounts {
@@ -836,16 +891,18 @@ func (ei *elasticProcessor) isIndexEnabled(index string) bool {
 }
 
 func (ei *elasticProcessor) doBulkRequests(index string, buffSlice []*bytes.Buffer, shardID uint32) error {
-	var err error
-	for idx := range buffSlice {
-		ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.BulkTopic, shardID))
-		err = ei.elasticClient.DoBulkRequest(ctxWithValue, buffSlice[idx], index)
-		if err != nil {
-			return err
-		}
-	}
+	return ei.doBulkRequestsWithContext(ei.ctx, index, buffSlice, shardID)
+}
 
-	return nil
+// doBlockBulkRequests sends the bulk requests that hold the data of a block, with the nonce of the block in the context
+func (ei *elasticProcessor) doBlockBulkRequests(index string, buffSlice []*bytes.Buffer, shardID uint32, nonce uint64) error {
+	ctx := context.WithValue(ei.ctx, request.NonceContextKey, nonce)
+	return ei.doBulkRequestsWithContext(ctx, index, buffSlice, shardID)
+}
+
+func (ei *elasticProcessor) doBulkRequestsWithContext(ctx context.Context, index string, buffSlice []*bytes.Buffer, shardID uint32) error {
+	ctxWithValue := context.WithValue(ctx, request.ContextKey, request.ExtendTopicWithShardID(request.BulkTopic, shardID))
+	return dispatchBulkRequests(ctxWithValue, buffSlice, index, ei.bulkRequestWorkers, ei.elasticClient.DoBulkRequest)
 }
 
 // SetOutportConfig will set the outport config
@@ -865,6 +922,11 @@ func (ei *elasticProcessor) isImportDB() bool {
 	return ei.importDB
 }
 
+// Close will close the database client
+func (ei *elasticProcessor) Close() error {
+	return CloseDatabaseClient(ei.elasticClient)
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (ei *elasticProcessor) IsInterfaceNil() bool {
 	return ei == nil
@@ -2,6 +2,7 @@ package elasticproc
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
 	"strings"
@@ -12,6 +13,8 @@ import (
 	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
 	"github.com/multiversx/mx-chain-es-indexer-go/data"
 	"github.com/multiversx/mx-chain-es-indexer-go/mock"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
@@ -31,6 +34,7 @@ import (
 
 func newElasticsearchProcessor(elasticsearchWriter DatabaseClientHandler, arguments *ArgElasticProcessor) *elasticProcessor {
 	return &elasticProcessor{
+		ctx:               context.Background(),
 		elasticClient:     elasticsearchWriter,
 		enabledIndexes:    arguments.EnabledIndexes,
 		blockProc:         arguments.BlockProc,
@@ -40,6 +44,7 @@ func newElasticsearchProcessor(elasticsearchWriter DatabaseClientHandler, argume
 		validatorsProc:    arguments.ValidatorsProc,
 		statisticsProc:    arguments.StatisticsProc,
 		logsAndEventsProc: arguments.LogsAndEventsProc,
+		tokensLocker:      newTokensLocker(),
 	}
 }
 
@@ -88,7 +93,7 @@ func createMockElasticProcessorArgs() *ArgElasticProcessor {
 		BlockProc:         bp,
 		LogsAndEventsProc: lp,
 		OperationsProc:    op,
-		MappingsHandler:   templatesAndPolicies.NewTemplatesAndPolicyReader(),
+		MappingsHandler:   templatesAndPolicies.NewTemplatesAndPolicyReader(config.ISMConfig{}),
 	}
 }
 
@@ -465,6 +470,29 @@ func TestElasticsearch_saveRoundInfoRequestError(t *testing.T) {
 
 }
 
+type contextRecorderStub struct {
+	mock.DatabaseWriterStub
+	ctx context.Context
+}
+
+func (crs *contextRecorderStub) DoBulkRequest(ctx context.Context, _ *bytes.Buffer, _ string) error {
+	crs.ctx = ctx
+	return ctx.Err()
+}
+
+func TestElasticProcessor_RequestsAreSentWithTheRootContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dbWriter := &contextRecorderStub{}
+	elasticDatabase := newElasticsearchProcessor(dbWriter, createMockElasticProcessorArgs())
+	elasticDatabase.ctx = ctx
+
+	err := elasticDatabase.SaveRoundsInfo(&outport.RoundsInfo{RoundsInfo: []*outport.RoundInfo{{}}})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, request.ExtendTopicWithShardID(request.BulkTopic, 0), dbWriter.ctx.Value(request.ContextKey))
+}
+
 func TestElasticProcessor_RemoveTransactions(t *testing.T) {
 	arguments := createMockElasticProcessorArgs()
 
@@ -1,6 +1,8 @@
 package factory
 
 import (
+	"context"
+
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/hashing"
 	"github.com/multiversx/mx-chain-core-go/marshal"
@@ -21,6 +23,7 @@ import (
 
 // ArgElasticProcessorFactory is struct that is used to store all components that are needed to create an elastic processor factory
 type ArgElasticProcessorFactory struct {
+	Context                  context.Context
 	Marshalizer              marshal.Marshalizer
 	Hasher                   hashing.Hasher
 	AddressPubkeyConverter   core.PubkeyConverter
@@ -30,14 +33,17 @@ type ArgElasticProcessorFactory struct {
 	Version                  string
 	Denomination             int
 	BulkRequestMaxSize       int
+	BulkRequestWorkers       int
+	CompressBulkBodies       bool
 	UseKibana                bool
 	ImportDB                 bool
 	EnableEpochsConfig       config.EnableEpochsConfig
+	ISMConfig                config.ISMConfig
 }
 
 // CreateElasticProcessor will create a new instance of ElasticProcessor
 func CreateElasticProcessor(arguments ArgElasticProcessorFactory) (dataindexer.ElasticProcessor, error) {
-	templatesAndPoliciesReader := templatesAndPolicies.NewTemplatesAndPolicyReader()
+	templatesAndPoliciesReader := templatesAndPolicies.NewTemplatesAndPolicyReader(arguments.ISMConfig)
 
 	enabledIndexesMap := make(map[string]struct{})
 	for _, index := range arguments.EnabledIndexes {
@@ -105,7 +111,10 @@ func CreateElasticProcessor(arguments ArgElasticProcessorFactory) (dataindexer.E
 	}
 
 	args := &elasticproc.ArgElasticProcessor{
+		Context:            arguments.Context,
 		BulkRequestMaxSize: arguments.BulkRequestMaxSize,
+		BulkRequestWorkers: arguments.BulkRequestWorkers,
+		CompressBulkBodies: arguments.CompressBulkBodies,
 		TransactionsProc:   txsProc,
 		AccountsProc:       accountsProc,
 		BlockProc:          blockProcHandler,
@@ -0,0 +1,160 @@
+package elasticproc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	elasticIndexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+)
+
+const finalizedBlockKeyPrefix = "finalized-block"
+
+// FinalizedBlockKey returns the id of the document from the values index that holds the last finalized block of a shard
+func FinalizedBlockKey(shardID uint32) string {
+	return fmt.Sprintf("%s-%d", finalizedBlockKeyPrefix, shardID)
+}
+
+// SaveFinalizedBlock will mark the provided block as final and will move forward the finalized block watermark
+// of the block's shard. All the blocks of a shard with a nonce lower or equal to the watermark nonce are final. The
+// block is marked as final when the blocks index is enabled and the watermark is moved when the values index is enabled
+func (ei *elasticProcessor) SaveFinalizedBlock(finalizedBlock *outport.FinalizedBlock) error {
+	isBlocksIndexEnabled := ei.isIndexEnabled(elasticIndexer.BlockIndex)
+	isValuesIndexEnabled := ei.isIndexEnabled(elasticIndexer.ValuesIndex)
+	if !isBlocksIndexEnabled && !isValuesIndexEnabled {
+		return nil
+	}
+
+	headerHash := hex.EncodeToString(finalizedBlock.HeaderHash)
+	finalizedBlockInfo, found, err := ei.getFinalizedBlockInfo(headerHash, finalizedBlock.ShardID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Debug("elasticProcessor.SaveFinalizedBlock: block not indexed", "shardID", finalizedBlock.ShardID, "hash", headerHash)
+		return nil
+	}
+
+	buffSlice := ei.newBufferSlice()
+	if isBlocksIndexEnabled {
+		err = serializeBlockIsFinal(headerHash, buffSlice)
+		if err != nil {
+			return err
+		}
+	}
+
+	if isValuesIndexEnabled {
+		err = serializeShardBlockInfo(finalizedBlockInfo, buffSlice)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ei.doBlockBulkRequests("", buffSlice.Buffers(), finalizedBlock.ShardID, finalizedBlockInfo.Nonce)
+}
+
+// getFinalizedBlockInfo reads the nonce, the round and the timestamp of the finalized block from the blocks index or,
+// when the blocks index is disabled, from the commit record of the block
+func (ei *elasticProcessor) getFinalizedBlockInfo(headerHash string, shardID uint32) (*data.ShardBlockInfo, bool, error) {
+	info := &data.ShardBlockInfo{
+		Key:        FinalizedBlockKey(shardID),
+		ShardID:    shardID,
+		HeaderHash: headerHash,
+	}
+
+	if ei.isIndexEnabled(elasticIndexer.BlockIndex) {
+		indexedBlock, found, err := ei.getIndexedBlock(headerHash, shardID)
+		if err != nil || !found {
+			return nil, false, err
+		}
+
+		info.Nonce = indexedBlock.Nonce
+		info.Round = indexedBlock.Round
+		info.TimestampMs = indexedBlock.TimestampMs
+		return info, true, nil
+	}
+
+	if !ei.isIndexEnabled(elasticIndexer.CommitsIndex) {
+		log.Debug("elasticProcessor.SaveFinalizedBlock: the finalized block watermark needs the blocks or the commits index",
+			"shardID", shardID, "hash", headerHash)
+		return nil, false, nil
+	}
+
+	commit, found, err := ei.getCommit(headerHash, shardID)
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	info.Nonce = commit.Nonce
+	info.Round = commit.Round
+	info.TimestampMs = commit.TimestampMs
+	return info, true, nil
+}
+
+func (ei *elasticProcessor) getIndexedBlock(headerHash string, shardID uint32) (*data.Block, bool, error) {
+	responseBlocks := &data.ResponseBlocks{}
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, []string{headerHash}, elasticIndexer.BlockIndex, true, responseBlocks)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, doc := range responseBlocks.Docs {
+		if doc.Found && doc.ID == headerHash {
+			return &doc.Source, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+func (ei *elasticProcessor) getCommit(commitID string, shardID uint32) (*data.BlockCommit, bool, error) {
+	responseCommits := &data.ResponseCommits{}
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+	err := ei.elasticClient.DoMultiGet(ctxWithValue, []string{commitID}, elasticIndexer.CommitsIndex, true, responseCommits)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, doc := range responseCommits.Docs {
+		if doc.Found && doc.ID == commitID {
+			return &doc.Source, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+func serializeBlockIsFinal(headerHash string, buffSlice *data.BufferSlice) error {
+	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, elasticIndexer.BlockIndex, converters.JsonEscape(headerHash), "\n"))
+	serializedData := []byte(`{"doc": {"isFinal": true}}`)
+
+	return buffSlice.PutData(meta, serializedData)
+}
+
+// serializeShardBlockInfo will serialize a scripted upsert that replaces the stored block info only if the provided one has a higher nonce
+func serializeShardBlockInfo(info *data.ShardBlockInfo, buffSlice *data.BufferSlice) error {
+	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, elasticIndexer.ValuesIndex, converters.JsonEscape(info.Key), "\n"))
+	infoBytes, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	codeToExecute := `
+		if (('create' == ctx.op) || (ctx._source.nonce == null) || (ctx._source.nonce < params.info.nonce)) {
+			ctx._source = params.info;
+		} else {
+			ctx.op = 'noop';
+		}
+`
+	serializedData := []byte(fmt.Sprintf(`{"scripted_upsert": true, "script": {"source": "%s","lang": "painless","params": {"info": %s}},"upsert": {}}`,
+		converters.FormatPainlessSource(codeToExecute), string(infoBytes)),
+	)
+
+	return buffSlice.PutData(meta, serializedData)
+}
@@ -0,0 +1,146 @@
+package elasticproc
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticProcessor_SaveFinalizedBlock(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.ValuesIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, []string{"6861736831"}, ids)
+			require.Equal(t, dataindexer.BlockIndex, index)
+
+			return json.Unmarshal([]byte(`{"docs":[{"found":true,"_id":"6861736831","_source":{"nonce":10,"round":11,"timestampMs":12000,"shardId":1}}]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody = buff.String()
+			return nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	err = elasticProc.SaveFinalizedBlock(&outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("hash1")})
+	require.Nil(t, err)
+
+	expectedBody := `{ "update" : { "_index":"blocks", "_id" : "6861736831" } }
+{"doc": {"isFinal": true}}
+{ "update" : { "_index":"values", "_id" : "finalized-block-1" } }
+{"scripted_upsert": true, "script": {"source": "if (('create' == ctx.op) || (ctx._source.nonce == null) || (ctx._source.nonce < params.info.nonce)) {ctx._source = params.info;} else {ctx.op = 'noop';}","lang": "painless","params": {"info": {"key":"finalized-block-1","shardId":1,"headerHash":"6861736831","nonce":10,"round":11,"timestampMs":12000}}},"upsert": {}}
+`
+	require.Equal(t, expectedBody, bulkBody)
+}
+
+func TestElasticProcessor_SaveFinalizedBlockWithoutValuesIndexShouldOnlyMarkTheBlock(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			return json.Unmarshal([]byte(`{"docs":[{"found":true,"_id":"6861736831","_source":{"nonce":10,"round":11,"timestampMs":12000,"shardId":1}}]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody = buff.String()
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	err := elasticProc.SaveFinalizedBlock(&outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("hash1")})
+	require.Nil(t, err)
+
+	expectedBody := `{ "update" : { "_index":"blocks", "_id" : "6861736831" } }
+{"doc": {"isFinal": true}}
+`
+	require.Equal(t, expectedBody, bulkBody)
+}
+
+func TestElasticProcessor_SaveFinalizedBlockWithoutBlocksIndexShouldMoveTheWatermark(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes = map[string]struct{}{dataindexer.ValuesIndex: {}, dataindexer.CommitsIndex: {}}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, []string{"6861736831"}, ids)
+			require.Equal(t, dataindexer.CommitsIndex, index)
+
+			return json.Unmarshal([]byte(`{"docs":[{"found":true,"_id":"6861736831","_source":{"commitId":"6861736831","nonce":10,"round":11,"timestampMs":12000,"shardId":1}}]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			bulkBody = buff.String()
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	err := elasticProc.SaveFinalizedBlock(&outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("hash1")})
+	require.Nil(t, err)
+
+	require.NotContains(t, bulkBody, `"_index":"blocks"`)
+	require.Contains(t, bulkBody, `{"info": {"key":"finalized-block-1","shardId":1,"headerHash":"6861736831","nonce":10,"round":11,"timestampMs":12000}}`)
+}
+
+func TestElasticProcessor_SaveFinalizedBlockWithoutBlocksAndCommitsIndexShouldNotDoRequest(t *testing.T) {
+	t.Parallel()
+
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes = map[string]struct{}{dataindexer.ValuesIndex: {}}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	err := elasticProc.SaveFinalizedBlock(&outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("hash1")})
+	require.Nil(t, err)
+}
+
+func TestElasticProcessor_SaveFinalizedBlockNotIndexedShouldNotDoRequest(t *testing.T) {
+	t.Parallel()
+
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			return json.Unmarshal([]byte(`{"docs":[{"found":false,"_id":"6861736831"}]}`), response)
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	err := elasticProc.SaveFinalizedBlock(&outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("hash1")})
+	require.Nil(t, err)
+}
+
+func TestSerializeShardBlockInfo(t *testing.T) {
+	t.Parallel()
+
+	buffSlice := data.NewBufferSlice(0)
+	err := serializeShardBlockInfo(&data.ShardBlockInfo{Key: FinalizedBlockKey(4294967295), Nonce: 1}, buffSlice)
+	require.Nil(t, err)
+	require.Contains(t, buffSlice.Buffers()[0].String(), `"_id" : "finalized-block-4294967295"`)
+}
@@ -0,0 +1,87 @@
+package elasticproc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/core/request"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	elasticIndexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+// NonceGapID returns the id of the document from the noncegaps index that holds the provided gap
+func NonceGapID(gap *data.NonceGap) string {
+	return fmt.Sprintf("%d_%d_%d", gap.ShardID, gap.FromNonce, gap.ToNonce)
+}
+
+// SaveNonceGaps will remove the closed nonce gaps of a shard and will save the new ones in the noncegaps index
+func (ei *elasticProcessor) SaveNonceGaps(shardID uint32, removedGaps []*data.NonceGap, addedGaps []*data.NonceGap) error {
+	if !ei.isIndexEnabled(elasticIndexer.NonceGapsIndex) {
+		return nil
+	}
+	if len(removedGaps) == 0 && len(addedGaps) == 0 {
+		return nil
+	}
+
+	buffSlice := ei.newBufferSlice()
+	for _, gap := range removedGaps {
+		meta := []byte(fmt.Sprintf(`{ "delete" : { "_index": "%s", "_id" : "%s" } }%s`, elasticIndexer.NonceGapsIndex, NonceGapID(gap), "\n"))
+		err := buffSlice.PutData(meta, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, gap := range addedGaps {
+		meta := []byte(fmt.Sprintf(`{ "index" : { "_index":"%s", "_id" : "%s" } }%s`, elasticIndexer.NonceGapsIndex, NonceGapID(gap), "\n"))
+		serializedData, err := json.Marshal(gap)
+		if err != nil {
+			return err
+		}
+
+		err = buffSlice.PutData(meta, serializedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ei.doBulkRequests(elasticIndexer.NonceGapsIndex, buffSlice.Buffers(), shardID)
+}
+
+// GetNonceGaps returns all the nonce gaps that are stored in the noncegaps index
+func (ei *elasticProcessor) GetNonceGaps() ([]*data.NonceGap, error) {
+	gaps := make([]*data.NonceGap, 0)
+	if !ei.isIndexEnabled(elasticIndexer.NonceGapsIndex) {
+		return gaps, nil
+	}
+
+	handlerFunc := func(responseBytes []byte) error {
+		responseScroll := &data.ResponseScroll{}
+		err := json.Unmarshal(responseBytes, responseScroll)
+		if err != nil {
+			return err
+		}
+
+		for _, hit := range responseScroll.Hits.Hits {
+			gap := &data.NonceGap{}
+			err = json.Unmarshal(hit.Source, gap)
+			if err != nil {
+				return err
+			}
+
+			gaps = append(gaps, gap)
+		}
+
+		return nil
+	}
+
+	ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ScrollTopic)
+	query := []byte(`{"query": {"match_all": {}}}`)
+	err := ei.elasticClient.DoScrollRequest(ctxWithValue, elasticIndexer.NonceGapsIndex, query, true, handlerFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return gaps, nil
+}
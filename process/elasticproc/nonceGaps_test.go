@@ -0,0 +1,80 @@
+package elasticproc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticProcessor_SaveNonceGaps(t *testing.T) {
+	t.Parallel()
+
+	bulkBody := ""
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.NonceGapsIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.Equal(t, dataindexer.NonceGapsIndex, index)
+			bulkBody = buff.String()
+			return nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	removed := []*data.NonceGap{{ShardID: 1, FromNonce: 10, ToNonce: 20, DetectedAtMs: 1000}}
+	added := []*data.NonceGap{
+		{ShardID: 1, FromNonce: 10, ToNonce: 14, DetectedAtMs: 1000},
+		{ShardID: 1, FromNonce: 16, ToNonce: 20, DetectedAtMs: 1000},
+	}
+	err = elasticProc.SaveNonceGaps(1, removed, added)
+	require.Nil(t, err)
+
+	expectedBody := `{ "delete" : { "_index": "noncegaps", "_id" : "1_10_20" } }
+{ "index" : { "_index":"noncegaps", "_id" : "1_10_14" } }
+{"shardId":1,"fromNonce":10,"toNonce":14,"detectedAtMs":1000}
+{ "index" : { "_index":"noncegaps", "_id" : "1_16_20" } }
+{"shardId":1,"fromNonce":16,"toNonce":20,"detectedAtMs":1000}
+`
+	require.Equal(t, expectedBody, bulkBody)
+}
+
+func TestElasticProcessor_SaveNonceGapsNothingToSaveShouldNotDoRequest(t *testing.T) {
+	t.Parallel()
+
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.NonceGapsIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	err := elasticProc.SaveNonceGaps(0, nil, nil)
+	require.Nil(t, err)
+}
+
+func TestElasticProcessor_GetNonceGaps(t *testing.T) {
+	t.Parallel()
+
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[dataindexer.NonceGapsIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			require.Equal(t, dataindexer.NonceGapsIndex, index)
+
+			return handlerFunc([]byte(`{"hits":{"hits":[{"_id":"2_5_7","_source":{"shardId":2,"fromNonce":5,"toNonce":7,"detectedAtMs":3000}}]}}`))
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(args)
+
+	gaps, err := elasticProc.GetNonceGaps()
+	require.Nil(t, err)
+	require.Equal(t, []*data.NonceGap{{ShardID: 2, FromNonce: 5, ToNonce: 7, DetectedAtMs: 3000}}, gaps)
+}
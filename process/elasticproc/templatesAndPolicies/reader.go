@@ -2,17 +2,40 @@ package templatesAndPolicies
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
 	indexer "github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	"github.com/multiversx/mx-chain-es-indexer-go/templates"
 	"github.com/multiversx/mx-chain-es-indexer-go/templates/indices"
 )
 
-type templatesAndPolicyReader struct{}
+const (
+	policySuffix       = "_policy"
+	rolloverAliasField = "plugins.index_state_management.rollover_alias"
+)
+
+var (
+	errIndexCannotRollOver = errors.New("index cannot be rolled over")
+	errNoRolloverCondition = errors.New("no rollover condition is set")
+)
 
-// NewTemplatesAndPolicyReader will create a new instance of templatesAndPolicyReader
-func NewTemplatesAndPolicyReader() *templatesAndPolicyReader {
-	return new(templatesAndPolicyReader)
+type templatesAndPolicyReader struct {
+	ismConfig config.ISMConfig
+}
+
+// NewTemplatesAndPolicyReader will create a new instance of templatesAndPolicyReader. When the ISM config is enabled,
+// an OpenSearch rollover policy is returned for each of its indices
+func NewTemplatesAndPolicyReader(ismConfig config.ISMConfig) *templatesAndPolicyReader {
+	return &templatesAndPolicyReader{
+		ismConfig: ismConfig,
+	}
+}
+
+// PolicyName returns the name of the index policy of the provided index
+func PolicyName(index string) string {
+	return index + policySuffix
 }
 
 // GetElasticTemplatesAndPolicies will return templates and policies
@@ -43,10 +66,78 @@ func (tr *templatesAndPolicyReader) GetElasticTemplatesAndPolicies() (map[string
 	indexTemplates[indexer.ESDTsIndex] = indices.ESDTs.ToBuffer()
 	indexTemplates[indexer.ValuesIndex] = indices.Values.ToBuffer()
 	indexTemplates[indexer.EventsIndex] = indices.Events.ToBuffer()
+	indexTemplates[indexer.NonceGapsIndex] = indices.NonceGaps.ToBuffer()
+	indexTemplates[indexer.CommitsIndex] = indices.Commits.ToBuffer()
+
+	if !tr.ismConfig.Enabled {
+		return indexTemplates, indexPolicies, nil
+	}
+	// without any condition the rollover action would roll over the indices as soon as the policy is applied
+	if len(tr.ismConfig.Indices) > 0 && tr.ismConfig.RolloverMinSize == "" && tr.ismConfig.RolloverMinIndexAge == "" {
+		return nil, nil, fmt.Errorf("%w: rollover-min-size or rollover-min-index-age should be set", errNoRolloverCondition)
+	}
+
+	for _, index := range tr.ismConfig.Indices {
+		template, found := getRolloverTemplate(index)
+		if !found {
+			return nil, nil, fmt.Errorf("%w: %s", errIndexCannotRollOver, index)
+		}
+
+		rolloverTemplate := withRolloverAlias(template, index)
+		indexTemplates[index] = rolloverTemplate.ToBuffer()
+		policy := indices.ISMRolloverPolicy(index, tr.ismConfig.RolloverMinSize, tr.ismConfig.RolloverMinIndexAge)
+		indexPolicies[PolicyName(index)] = policy.ToBuffer()
+	}
 
 	return indexTemplates, indexPolicies, nil
 }
 
+// getRolloverTemplate returns the template of the provided index if its documents are never updated after they are
+// written, so they can be moved to an older index of the alias
+func getRolloverTemplate(index string) (templates.Object, bool) {
+	switch index {
+	case indexer.RoundsIndex:
+		return indices.Rounds, true
+	case indexer.AccountsHistoryIndex:
+		return indices.AccountsHistory, true
+	case indexer.AccountsESDTHistoryIndex:
+		return indices.AccountsESDTHistory, true
+	case indexer.ReceiptsIndex:
+		return indices.Receipts, true
+	case indexer.EventsIndex:
+		return indices.Events, true
+	default:
+		return nil, false
+	}
+}
+
+// withRolloverAlias returns a copy of the provided template, with the setting needed by the rollover action. The
+// shared template objects are not modified
+func withRolloverAlias(template templates.Object, alias string) templates.Object {
+	newTemplate := templates.Object{}
+	for key, value := range template {
+		newTemplate[key] = value
+	}
+
+	indexTemplate := templates.Object{}
+	existingIndexTemplate, _ := template["template"].(templates.Object)
+	for key, value := range existingIndexTemplate {
+		indexTemplate[key] = value
+	}
+
+	settings := templates.Object{}
+	existingSettings, _ := existingIndexTemplate["settings"].(templates.Object)
+	for key, value := range existingSettings {
+		settings[key] = value
+	}
+	settings[rolloverAliasField] = alias
+
+	indexTemplate["settings"] = settings
+	newTemplate["template"] = indexTemplate
+
+	return newTemplate
+}
+
 // GetTimestampMsMappings will return the timestampMs field mappings for all indices
 func (tr *templatesAndPolicyReader) GetTimestampMsMappings() ([]templates.ExtraMapping, error) {
 	return []templates.ExtraMapping{
@@ -103,6 +194,10 @@ func (tr *templatesAndPolicyReader) GetTimestampMsMappings() ([]templates.ExtraM
 			Index:    indexer.EventsIndex,
 			Mappings: indices.TimestampMs.ToBuffer(),
 		},
+		{
+			Index:    indexer.ValuesIndex,
+			Mappings: indices.TimestampMs.ToBuffer(),
+		},
 		{
 			Index:    indexer.TokensIndex,
 			Mappings: indices.TokensTimestampMs.ToBuffer(),
@@ -1,18 +1,69 @@
 package templatesAndPolicies
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
 	"github.com/stretchr/testify/require"
 )
 
 func TestTemplatesAndPolicyReaderNoKibana_GetElasticTemplatesAndPolicies(t *testing.T) {
 	t.Parallel()
 
-	reader := NewTemplatesAndPolicyReader()
+	reader := NewTemplatesAndPolicyReader(config.ISMConfig{})
 
 	templates, policies, err := reader.GetElasticTemplatesAndPolicies()
 	require.Nil(t, err)
 	require.Len(t, policies, 0)
-	require.Len(t, templates, 23)
+	require.Len(t, templates, 25)
+}
+
+func TestTemplatesAndPolicyReader_GetElasticTemplatesAndPoliciesWithISM(t *testing.T) {
+	t.Parallel()
+
+	reader := NewTemplatesAndPolicyReader(config.ISMConfig{
+		Enabled:             true,
+		Indices:             []string{"accountshistory"},
+		RolloverMinSize:     "50gb",
+		RolloverMinIndexAge: "",
+	})
+
+	templates, policies, err := reader.GetElasticTemplatesAndPolicies()
+	require.Nil(t, err)
+	require.Len(t, templates, 25)
+	require.Len(t, policies, 1)
+
+	require.Equal(t, `{"policy":{"default_state":"hot","description":"rollover policy for the accountshistory index",`+
+		`"ism_template":[{"index_patterns":["accountshistory-*"],"priority":100}],`+
+		`"states":[{"actions":[{"rollover":{"min_size":"50gb"}}],"name":"hot","transitions":[]}]}}`, policies[PolicyName("accountshistory")].String())
+
+	template := struct {
+		Template struct {
+			Settings map[string]interface{} `json:"settings"`
+		} `json:"template"`
+	}{}
+	err = json.Unmarshal(templates["accountshistory"].Bytes(), &template)
+	require.Nil(t, err)
+	require.Equal(t, "accountshistory", template.Template.Settings[rolloverAliasField])
+
+	// the shared templates should not be modified
+	reader = NewTemplatesAndPolicyReader(config.ISMConfig{})
+	templates, _, _ = reader.GetElasticTemplatesAndPolicies()
+	require.NotContains(t, templates["accountshistory"].String(), rolloverAliasField)
+
+	reader = NewTemplatesAndPolicyReader(config.ISMConfig{
+		Enabled:             true,
+		Indices:             []string{"accounts"},
+		RolloverMinIndexAge: "30d",
+	})
+	_, _, err = reader.GetElasticTemplatesAndPolicies()
+	require.ErrorIs(t, err, errIndexCannotRollOver)
+
+	reader = NewTemplatesAndPolicyReader(config.ISMConfig{
+		Enabled: true,
+		Indices: []string{"accountshistory"},
+	})
+	_, _, err = reader.GetElasticTemplatesAndPolicies()
+	require.ErrorIs(t, err, errNoRolloverCondition)
 }
@@ -0,0 +1,91 @@
+package elasticproc
+
+import (
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+)
+
+const numTokensLockStripes = 256
+
+// tokensLocker guards the token documents the metachain blocks write and the shard blocks read. The metachain blocks
+// index the type and the owner of the tokens they issue, while the shard blocks read them from the tokens index, so a
+// shard block does not read a token while a metachain block writes it. The locks do not order the blocks: a shard block
+// processed before the metachain block that issues or transfers a token still reads the previous data, so the token
+// type and owner added to the shard documents are best-effort. The tokens are spread over a fixed number of locks,
+// which are always taken in ascending order so two blocks never wait for each other
+type tokensLocker struct {
+	stripes [numTokensLockStripes]sync.RWMutex
+}
+
+func newTokensLocker() *tokensLocker {
+	return &tokensLocker{}
+}
+
+// lock will lock the provided tokens for writing and returns the function that unlocks them
+func (tl *tokensLocker) lock(tokens []string) func() {
+	indexes := stripesIndexes(tokens)
+	for _, idx := range indexes {
+		tl.stripes[idx].Lock()
+	}
+
+	return func() {
+		for _, idx := range indexes {
+			tl.stripes[idx].Unlock()
+		}
+	}
+}
+
+// rLock will lock the provided tokens for reading and returns the function that unlocks them
+func (tl *tokensLocker) rLock(tokens []string) func() {
+	indexes := stripesIndexes(tokens)
+	for _, idx := range indexes {
+		tl.stripes[idx].RLock()
+	}
+
+	return func() {
+		for _, idx := range indexes {
+			tl.stripes[idx].RUnlock()
+		}
+	}
+}
+
+func stripesIndexes(tokens []string) []int {
+	indexesMap := make(map[int]struct{}, len(tokens))
+	for _, token := range tokens {
+		hasher := fnv.New32a()
+		_, _ = hasher.Write([]byte(token))
+		indexesMap[int(hasher.Sum32()%numTokensLockStripes)] = struct{}{}
+	}
+
+	indexes := make([]int, 0, len(indexesMap))
+	for idx := range indexesMap {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	return indexes
+}
+
+// rLockTokens will lock the provided tokens for reading while a shard block reads them from the tokens index, so it does
+// not read a token that is being written. The metachain blocks do not wait, they already hold the locks of the tokens
+// they write
+func (ei *elasticProcessor) rLockTokens(tokens []string, shardID uint32) func() {
+	if shardID == core.MetachainShardId {
+		return func() {}
+	}
+
+	return ei.tokensLocker.rLock(tokens)
+}
+
+func getIssuedTokens(tokensInfo []*data.TokenInfo) []string {
+	tokens := make([]string, 0, len(tokensInfo))
+	for _, tokenInfo := range tokensInfo {
+		tokens = append(tokens, tokenInfo.Token)
+	}
+
+	return tokens
+}
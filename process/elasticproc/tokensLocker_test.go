@@ -0,0 +1,58 @@
+package elasticproc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripesIndexes(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, stripesIndexes(nil))
+
+	indexes := stripesIndexes([]string{"TKN-abcd", "TKN-abcd", "NFT-1234", "SFT-5678"})
+	require.True(t, len(indexes) > 0 && len(indexes) <= 3)
+	for idx := 1; idx < len(indexes); idx++ {
+		require.Less(t, indexes[idx-1], indexes[idx])
+	}
+}
+
+func TestElasticProcessor_RLockTokensShouldWaitForTheTokensBeingWritten(t *testing.T) {
+	t.Parallel()
+
+	ei := &elasticProcessor{tokensLocker: newTokensLocker()}
+	unlockWritten := ei.tokensLocker.lock(getIssuedTokens([]*data.TokenInfo{{Token: "TKN-abcd"}}))
+
+	// another token is not blocked by the written one
+	otherToken := "TKN-0000"
+	for stripesIndexes([]string{otherToken})[0] == stripesIndexes([]string{"TKN-abcd"})[0] {
+		otherToken += "0"
+	}
+	ei.rLockTokens([]string{otherToken}, 0)()
+
+	// the metachain blocks do not wait for the tokens they write
+	ei.rLockTokens([]string{"TKN-abcd"}, core.MetachainShardId)()
+
+	readDone := make(chan struct{})
+	go func() {
+		ei.rLockTokens([]string{"TKN-abcd"}, 1)()
+		close(readDone)
+	}()
+
+	select {
+	case <-readDone:
+		require.Fail(t, "the shard block should wait for the written token")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlockWritten()
+	select {
+	case <-readDone:
+	case <-time.After(time.Second):
+		require.Fail(t, "the shard block should read the token after it was written")
+	}
+}
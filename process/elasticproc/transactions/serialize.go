@@ -241,10 +241,14 @@ func prepareSerializedDataForESDTTransferOnDestination(marshaledTx []byte) []byt
 			def gasUsed = ctx._source.gasUsed;
 			def fee = ctx._source.fee;
 			def feeNum = ctx._source.feeNum;
+			def commitId = ctx._source.commitId;
 			ctx._source = params.tx;
 			ctx._source.gasUsed = gasUsed;
 			ctx._source.fee = fee;
 			ctx._source.feeNum = feeNum;
+			if (commitId != null) {
+				ctx._source.commitId = commitId;
+			}
 		}
 `
 	return []byte(fmt.Sprintf(`{"scripted_upsert": true, "script":{"source":"%s","lang": "painless","params":{"tx": %s}},"upsert":{}}`,
@@ -259,6 +263,7 @@ func prepareNFTESDTTransferOrMultiESDTTransfer(marshaledTx []byte) ([]byte, erro
 			def status = ctx._source.status;
 			def errorEvent = ctx._source.errorEvent;
 			def completedEvent = ctx._source.completedEvent;
+			def commitId = ctx._source.commitId;
 
 			ctx._source = params.tx;
 			if (!status.isEmpty()) {
@@ -270,6 +275,9 @@ func prepareNFTESDTTransferOrMultiESDTTransfer(marshaledTx []byte) ([]byte, erro
 			if (completedEvent != null) {
 				ctx._source.completedEvent = completedEvent;
 			}
+			if (commitId != null) {
+				ctx._source.commitId = commitId;
+			}
 		}
 `
 	serializedData := []byte(fmt.Sprintf(`{"scripted_upsert": true, "script":{"source":"%s","lang": "painless","params":{"tx": %s}},"upsert":{}}`,
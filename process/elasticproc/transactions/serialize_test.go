@@ -1,9 +1,13 @@
 package transactions
 
 import (
+	"bytes"
+	"context"
 	"testing"
 
+	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/memory"
 	"github.com/multiversx/mx-chain-es-indexer-go/data"
 	"github.com/stretchr/testify/require"
 )
@@ -129,6 +133,66 @@ func TestSerializeTransactionsCrossShardTxDestination(t *testing.T) {
 	require.Equal(t, expectedBuff, buffSlice.Buffers()[0].String())
 }
 
+func TestSerializeTransactions_UpdatedTransactionShouldKeepTheCommitIDOfTheFirstBlock(t *testing.T) {
+	t.Parallel()
+
+	serializeTransaction := func(tx data.Transaction, selfShardID uint32) *bytes.Buffer {
+		buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+		err := (&txsDatabaseProcessor{}).SerializeTransactions([]*data.Transaction{&tx}, map[string]*outport.StatusInfo{}, selfShardID, buffSlice, "transactions")
+		require.Nil(t, err)
+
+		return buffSlice.Buffers()[0]
+	}
+
+	t.Run("cross-shard ESDT transfer", func(t *testing.T) {
+		t.Parallel()
+
+		dbClient := memory.NewMemoryClient()
+		tx := data.Transaction{
+			Hash:          "txHash",
+			SenderShard:   0,
+			ReceiverShard: 1,
+			Operation:     core.BuiltInFunctionESDTTransfer,
+			Fee:           "100",
+			CommitID:      "source",
+		}
+		require.Nil(t, dbClient.DoBulkRequest(context.Background(), serializeTransaction(tx, 0), ""))
+
+		tx.Fee = ""
+		tx.Status = "success"
+		tx.CommitID = "destination"
+		require.Nil(t, dbClient.DoBulkRequest(context.Background(), serializeTransaction(tx, 1), ""))
+
+		doc, found := dbClient.Document("transactions", "txHash")
+		require.True(t, found)
+		require.Equal(t, "source", doc["commitId"])
+		require.Equal(t, "100", doc["fee"])
+		require.Equal(t, "success", doc["status"])
+	})
+
+	t.Run("NFT transfer", func(t *testing.T) {
+		t.Parallel()
+
+		dbClient := memory.NewMemoryClient()
+		tx := data.Transaction{
+			Hash:     "txHash",
+			Data:     []byte("ESDTNFTTransfer@4e4654@01@01@726563"),
+			Status:   "pending",
+			CommitID: "first",
+		}
+		require.Nil(t, dbClient.DoBulkRequest(context.Background(), serializeTransaction(tx, 0), ""))
+
+		tx.Status = "success"
+		tx.CommitID = "second"
+		require.Nil(t, dbClient.DoBulkRequest(context.Background(), serializeTransaction(tx, 0), ""))
+
+		doc, found := dbClient.Document("transactions", "txHash")
+		require.True(t, found)
+		require.Equal(t, "first", doc["commitId"])
+		require.Equal(t, "pending", doc["status"])
+	})
+}
+
 func TestTxsDatabaseProcessor_SerializeTransactionWithRefund(t *testing.T) {
 	t.Parallel()
 
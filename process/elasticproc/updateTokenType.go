@@ -64,7 +64,7 @@ func (ei *elasticProcessor) addTokenType(tokensData []*data.TokenInfo, index str
 				ids = append(ids, res.ID)
 			}
 
-			buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
+			buffSlice := ei.newBufferSlice()
 			err = ei.accountsProc.SerializeTypeForProvidedIDs(ids, td.Type, buffSlice, index)
 			if err != nil {
 				return err
@@ -73,14 +73,14 @@ func (ei *elasticProcessor) addTokenType(tokensData []*data.TokenInfo, index str
 			return ei.doBulkRequests(index, buffSlice.Buffers(), shardID)
 		}
 
-		ctxWithValue := context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
+		ctxWithValue := context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.GetTopic, shardID))
 		query := fmt.Sprintf(`{"query": {"bool": {"must": [{"match": {"token": {"query": "%s","operator": "AND"}}}],"must_not":[{"exists": {"field": "type"}}]}}}`, td.Token)
 		resultsCount, err := ei.elasticClient.DoCountRequest(ctxWithValue, index, []byte(query))
 		if err != nil || resultsCount == 0 {
 			return err
 		}
 
-		ctxWithValue = context.WithValue(context.Background(), request.ContextKey, request.ExtendTopicWithShardID(request.ScrollTopic, shardID))
+		ctxWithValue = context.WithValue(ei.ctx, request.ContextKey, request.ExtendTopicWithShardID(request.ScrollTopic, shardID))
 		err = ei.elasticClient.DoScrollRequest(ctxWithValue, index, []byte(query), false, handlerFunc)
 		if err != nil {
 			return err
@@ -1,41 +1,85 @@
 package factory
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"math"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
+	elasticsearch8 "github.com/elastic/go-elasticsearch/v8"
 	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-core-go/data/block"
 	"github.com/multiversx/mx-chain-core-go/hashing"
 	"github.com/multiversx/mx-chain-core-go/marshal"
 	"github.com/multiversx/mx-chain-es-indexer-go/client"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/bulksink"
 	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/memory"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/mirror"
+	"github.com/multiversx/mx-chain-es-indexer-go/client/timeout"
 	"github.com/multiversx/mx-chain-es-indexer-go/client/transport"
 	"github.com/multiversx/mx-chain-es-indexer-go/config"
 	indexerCore "github.com/multiversx/mx-chain-es-indexer-go/core"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/factory"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/sqlproc"
+	sqlFactory "github.com/multiversx/mx-chain-es-indexer-go/process/sqlproc/factory"
 	logger "github.com/multiversx/mx-chain-logger-go"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
 )
 
 var log = logger.GetOrCreate("indexer/factory")
 
+const (
+	bytesInMB                    = 1024 * 1024
+	defaultMirrorCatchUpInterval = 10 * time.Second
+	defaultMaxDocumentsInMemory  = 1_000_000
+)
+
+const (
+	// ElasticsearchBackend is the name of the Elasticsearch database backend
+	ElasticsearchBackend = "elasticsearch"
+	// Elasticsearch8Backend is the name of the Elasticsearch 8.x database backend
+	Elasticsearch8Backend = "elasticsearch8"
+	// OpenSearchBackend is the name of the OpenSearch database backend
+	OpenSearchBackend = "opensearch"
+	// PostgresBackend is the name of the PostgreSQL database backend
+	PostgresBackend = "postgres"
+	// SQLiteBackend is the name of the SQLite database backend
+	SQLiteBackend = "sqlite"
+)
+
+const (
+	postgresDriverName = "pgx"
+	sqliteDriverName   = "sqlite"
+)
+
 // ArgsIndexerFactory holds all dependencies required by the data indexer factory in order to create
 // new instances
 type ArgsIndexerFactory struct {
+	Context                  context.Context
 	Enabled                  bool
 	UseKibana                bool
 	ImportDB                 bool
 	Denomination             int
 	BulkRequestMaxSize       int
+	BulkRequestWorkers       int
 	Url                      string
+	Urls                     []string
+	SniffingConfig           config.SniffingConfig
 	UserName                 string
 	Password                 string
+	APIKey                   string
+	BearerToken              string
+	TLSConfig                config.TLSConfig
 	TemplatesPath            string
 	Version                  string
 	EnabledIndexes           []string
@@ -45,7 +89,16 @@ type ArgsIndexerFactory struct {
 	AddressPubkeyConverter   core.PubkeyConverter
 	ValidatorPubkeyConverter core.PubkeyConverter
 	StatusMetrics            indexerCore.StatusMetricsHandler
+	NonceGapDetector         dataindexer.NonceGapDetector
 	EnableEpochsConfig       config.EnableEpochsConfig
+	Backend                  string
+	ISMConfig                config.ISMConfig
+	DatabaseClient           elasticproc.DatabaseClientHandler
+	BulkSinkConfig           config.BulkSinkConfig
+	MirrorConfig             config.MirrorConfig
+	RetryPolicyConfig        config.RetryPolicyConfig
+	CompressBulkRequests     bool
+	TimeoutsConfig           config.TimeoutsConfig
 }
 
 // NewIndexer will create a new instance of Indexer
@@ -55,23 +108,35 @@ func NewIndexer(args ArgsIndexerFactory) (dataindexer.Indexer, error) {
 		return nil, err
 	}
 
-	elasticProcessor, err := createElasticProcessor(args)
+	elasticProcessor, err := createDatabaseProcessor(args)
 	if err != nil {
 		return nil, err
 	}
 
-	blockContainer, err := createBlockCreatorsContainer()
+	blockContainer, err := CreateBlockCreatorsContainer()
 	if err != nil {
 		return nil, err
 	}
 
+	nonceGapDetector := args.NonceGapDetector
+	if check.IfNil(nonceGapDetector) {
+		nonceGapDetector = dataindexer.NewNonceGapDetector(args.StatusMetrics)
+	}
+
 	arguments := dataindexer.ArgDataIndexer{
+		Context:          args.Context,
 		HeaderMarshaller: args.HeaderMarshaller,
 		ElasticProcessor: elasticProcessor,
 		BlockContainer:   blockContainer,
+		NonceGapDetector: nonceGapDetector,
 	}
 
-	return dataindexer.NewDataIndexer(arguments)
+	dataIndexer, err := dataindexer.NewDataIndexer(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return dataIndexer, nil
 }
 
 func retryBackOff(attempt int) time.Duration {
@@ -81,13 +146,80 @@ func retryBackOff(attempt int) time.Duration {
 	return d
 }
 
+func createDatabaseProcessor(args ArgsIndexerFactory) (dataindexer.ElasticProcessor, error) {
+	isSQLBackend := args.Backend == PostgresBackend || args.Backend == SQLiteBackend
+	if !isSQLBackend || !check.IfNil(args.DatabaseClient) {
+		return createElasticProcessor(args)
+	}
+
+	return createSQLProcessor(args)
+}
+
+// createSQLProcessor opens the database whose data source name is the configured url and creates the SQL processor
+func createSQLProcessor(args ArgsIndexerFactory) (dataindexer.ElasticProcessor, error) {
+	if args.BulkSinkConfig.Enabled {
+		return nil, fmt.Errorf("%w: the bulk sink is enabled with the %s backend", dataindexer.ErrNotSupportedBySQLBackend, args.Backend)
+	}
+	if args.MirrorConfig.Enabled {
+		return nil, fmt.Errorf("%w: the mirror is enabled with the %s backend", dataindexer.ErrNotSupportedBySQLBackend, args.Backend)
+	}
+
+	driverName := postgresDriverName
+	dialect := sqlproc.PostgresDialect
+	if args.Backend == SQLiteBackend {
+		driverName = sqliteDriverName
+		dialect = sqlproc.SQLiteDialect
+	}
+
+	db, err := sql.Open(driverName, args.Url)
+	if err != nil {
+		return nil, err
+	}
+	if args.Backend == SQLiteBackend {
+		// SQLite allows a single writer, and every connection to an in-memory database opens a different database
+		db.SetMaxOpenConns(1)
+	}
+
+	err = db.Ping()
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("%w while connecting to the %s database", err, args.Backend)
+	}
+
+	processor, err := sqlFactory.CreateSQLProcessor(sqlFactory.ArgSQLProcessorFactory{
+		Context:                  args.Context,
+		DB:                       db,
+		Dialect:                  dialect,
+		Marshalizer:              args.Marshalizer,
+		Hasher:                   args.Hasher,
+		AddressPubkeyConverter:   args.AddressPubkeyConverter,
+		ValidatorPubkeyConverter: args.ValidatorPubkeyConverter,
+		EnabledIndexes:           args.EnabledIndexes,
+		Version:                  args.Version,
+		Denomination:             args.Denomination,
+		ImportDB:                 args.ImportDB,
+		EnableEpochsConfig:       args.EnableEpochsConfig,
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return processor, nil
+}
+
 func createElasticProcessor(args ArgsIndexerFactory) (dataindexer.ElasticProcessor, error) {
-	databaseClient, err := createElasticClient(args)
+	databaseClient, err := createDatabaseClient(args)
+	if err != nil {
+		return nil, err
+	}
+	databaseClient, err = createTimeoutClient(args, databaseClient)
 	if err != nil {
 		return nil, err
 	}
 
 	argsElasticProcFac := factory.ArgElasticProcessorFactory{
+		Context:                  args.Context,
 		Marshalizer:              args.Marshalizer,
 		Hasher:                   args.Hasher,
 		AddressPubkeyConverter:   args.AddressPubkeyConverter,
@@ -97,35 +229,341 @@ func createElasticProcessor(args ArgsIndexerFactory) (dataindexer.ElasticProcess
 		Denomination:             args.Denomination,
 		EnabledIndexes:           args.EnabledIndexes,
 		BulkRequestMaxSize:       args.BulkRequestMaxSize,
+		BulkRequestWorkers:       args.BulkRequestWorkers,
+		CompressBulkBodies:       shouldCompressBulkBodies(args),
 		ImportDB:                 args.ImportDB,
 		Version:                  args.Version,
 		EnableEpochsConfig:       args.EnableEpochsConfig,
+		ISMConfig:                getISMConfig(args),
 	}
 
 	return factory.CreateElasticProcessor(argsElasticProcFac)
 }
 
+// shouldCompressBulkBodies returns true when the bulk bodies are sent compressed by a client that accepts them already
+// compressed, so they are compressed while they are built. The Elasticsearch 8 client compresses the requests itself
+// and the in-memory databases read the bodies uncompressed
+func shouldCompressBulkBodies(args ArgsIndexerFactory) bool {
+	if !args.CompressBulkRequests || !check.IfNil(args.DatabaseClient) {
+		return false
+	}
+	if args.BulkSinkConfig.Enabled && args.BulkSinkConfig.ReplaceDatabase {
+		return false
+	}
+
+	switch args.Backend {
+	case "", ElasticsearchBackend, OpenSearchBackend:
+		return true
+	default:
+		return false
+	}
+}
+
+// createTimeoutClient bounds the duration of the requests sent with the database client. The bulk timeout covers the
+// retries of the rejected requests, so it should not be lower than the max elapsed time of the retry policy
+func createTimeoutClient(args ArgsIndexerFactory, databaseClient elasticproc.DatabaseClientHandler) (elasticproc.DatabaseClientHandler, error) {
+	timeoutsCfg := args.TimeoutsConfig
+	bulkTimeout := time.Duration(timeoutsCfg.BulkInSeconds) * time.Second
+	maxElapsedTime := time.Duration(args.RetryPolicyConfig.MaxElapsedTimeInSeconds) * time.Second
+	if args.RetryPolicyConfig.Enabled && bulkTimeout > 0 && bulkTimeout < maxElapsedTime {
+		log.Warn("the bulk requests time out before the retry policy gives up",
+			"bulk timeout", bulkTimeout, "max elapsed time", maxElapsedTime)
+	}
+
+	return timeout.NewTimeoutClient(timeout.ArgsTimeoutClient{
+		DatabaseClient:       databaseClient,
+		BulkTimeout:          bulkTimeout,
+		MultiGetTimeout:      time.Duration(timeoutsCfg.MultiGetInSeconds) * time.Second,
+		ScrollTimeout:        time.Duration(timeoutsCfg.ScrollInSeconds) * time.Second,
+		DeleteByQueryTimeout: time.Duration(timeoutsCfg.DeleteByQueryInSeconds) * time.Second,
+		UpdateByQueryTimeout: time.Duration(timeoutsCfg.UpdateByQueryInSeconds) * time.Second,
+	})
+}
+
+// CreateMemoryClient creates the in-memory database client, which keeps at most the maximum number of documents from
+// the bulk sink config
+func CreateMemoryClient(sinkCfg config.BulkSinkConfig) elasticproc.DatabaseClientHandler {
+	return memory.NewMemoryClientWithMaxDocuments(getMaxDocumentsInMemory(sinkCfg))
+}
+
+func getMaxDocumentsInMemory(sinkCfg config.BulkSinkConfig) uint64 {
+	if sinkCfg.MaxDocumentsInMemory == 0 {
+		return defaultMaxDocumentsInMemory
+	}
+
+	return sinkCfg.MaxDocumentsInMemory
+}
+
+func createDatabaseClient(args ArgsIndexerFactory) (elasticproc.DatabaseClientHandler, error) {
+	sinkCfg := args.BulkSinkConfig
+	if !sinkCfg.Enabled {
+		return createElasticClient(args)
+	}
+
+	var databaseClient elasticproc.DatabaseClientHandler
+	var err error
+	if sinkCfg.ReplaceDatabase && check.IfNil(args.DatabaseClient) {
+		log.Info("the bulk bodies are only written in files, the documents are kept in memory",
+			"path", sinkCfg.Path, "max documents", getMaxDocumentsInMemory(sinkCfg))
+		databaseClient = CreateMemoryClient(sinkCfg)
+	} else {
+		databaseClient, err = createElasticClient(args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bulksink.NewBulkFileSink(bulksink.ArgsBulkFileSink{
+		Path:           sinkCfg.Path,
+		MaxFileSize:    sinkCfg.MaxFileSizeInMB * bytesInMB,
+		DatabaseClient: databaseClient,
+	})
+}
+
 func createElasticClient(args ArgsIndexerFactory) (elasticproc.DatabaseClientHandler, error) {
-	argsEsClient := elasticsearch.Config{
-		Addresses:     []string{args.Url},
-		Username:      args.UserName,
-		Password:      args.Password,
-		Logger:        &logging.CustomLogger{},
-		RetryOnStatus: []int{http.StatusConflict},
-		RetryBackoff:  retryBackOff,
+	if !check.IfNil(args.DatabaseClient) {
+		log.Info("using the provided database client instead of the configured backend", "backend", args.Backend)
+		return args.DatabaseClient, nil
+	}
+
+	primaryCluster := config.MirrorClusterConfig{
+		URL:         args.Url,
+		URLs:        args.Urls,
+		UserName:    args.UserName,
+		Password:    args.Password,
+		APIKey:      args.APIKey,
+		BearerToken: args.BearerToken,
+		TLS:         args.TLSConfig,
+	}
+	databaseClient, err := createClusterClient(args, primaryCluster, args.StatusMetrics)
+	if err != nil {
+		return nil, err
+	}
+	if !args.MirrorConfig.Enabled {
+		return databaseClient, nil
+	}
+
+	return createMirrorClient(args, databaseClient)
+}
+
+// createMirrorClient creates the clients of the mirror clusters, with the same backend, retry policy and timeouts as the
+// primary cluster. The requests sent to the mirror clusters are not counted in the status metrics, only the health of
+// the mirror clusters is reported
+func createMirrorClient(args ArgsIndexerFactory, primary elasticproc.DatabaseClientHandler) (elasticproc.DatabaseClientHandler, error) {
+	mirrorCfg := args.MirrorConfig
+	mirrors := make(map[string]elasticproc.DatabaseClientHandler, len(mirrorCfg.Clusters))
+	for _, cluster := range mirrorCfg.Clusters {
+		addresses, err := getClusterAddresses(cluster)
+		if err != nil {
+			return nil, fmt.Errorf("%w for a mirror cluster", err)
+		}
+
+		mirrorClient, err := createClusterClient(args, cluster, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w while creating the client of the mirror cluster %s", err, addresses[0])
+		}
+		mirrorClient, err = createTimeoutClient(args, mirrorClient)
+		if err != nil {
+			return nil, err
+		}
+		mirrors[strings.Join(addresses, ",")] = mirrorClient
 	}
 
-	if check.IfNil(args.StatusMetrics) {
-		return client.NewElasticClient(argsEsClient)
+	catchUpInterval := time.Duration(mirrorCfg.CatchUpIntervalInSeconds) * time.Second
+	if catchUpInterval == 0 {
+		catchUpInterval = defaultMirrorCatchUpInterval
 	}
 
-	transportMetrics, err := transport.NewMetricsTransport(args.StatusMetrics)
+	log.Info("the writes are mirrored", "clusters", len(mirrors))
+	return mirror.NewMirrorClient(mirror.ArgsMirrorClient{
+		Primary:         primary,
+		Mirrors:         mirrors,
+		MaxBufferSize:   mirrorCfg.MaxBufferSizeInMB * bytesInMB,
+		CatchUpInterval: catchUpInterval,
+		FlushTimeout:    time.Duration(mirrorCfg.FlushTimeoutInSeconds) * time.Second,
+		StatusMetrics:   args.StatusMetrics,
+	})
+}
+
+func createClusterClient(
+	args ArgsIndexerFactory,
+	cluster config.MirrorClusterConfig,
+	statusMetrics indexerCore.StatusMetricsHandler,
+) (elasticproc.DatabaseClientHandler, error) {
+	addresses, err := getClusterAddresses(cluster)
+	if err != nil {
+		return nil, err
+	}
+	clusterTransport, err := createClusterTransport(cluster, addresses, statusMetrics)
 	if err != nil {
 		return nil, err
 	}
-	argsEsClient.Transport = transportMetrics
 
-	return client.NewElasticClient(argsEsClient)
+	discoverNodesInterval := time.Duration(0)
+	if args.SniffingConfig.Enabled {
+		discoverNodesInterval = time.Duration(args.SniffingConfig.IntervalInSeconds) * time.Second
+	}
+
+	backend := args.Backend
+	if backend == Elasticsearch8Backend {
+		esClientV8, err := client.NewElasticClientV8(elasticsearch8.Config{
+			Addresses:             addresses,
+			Username:              cluster.UserName,
+			Password:              cluster.Password,
+			APIKey:                cluster.APIKey,
+			Logger:                &logging.CustomLogger{},
+			RetryOnStatus:         getRetryOnStatus(addresses),
+			RetryBackoff:          retryBackOff,
+			Header:                client.NewBearerTokenHeader(cluster.BearerToken),
+			Transport:             clusterTransport,
+			CompressRequestBody:   args.CompressBulkRequests,
+			PoolCompressor:        args.CompressBulkRequests,
+			DiscoverNodesOnStart:  args.SniffingConfig.Enabled,
+			DiscoverNodesInterval: discoverNodesInterval,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return esClientV8, enableRetryPolicy(esClientV8, args, statusMetrics)
+	}
+
+	argsEsClient := elasticsearch.Config{
+		Addresses:             addresses,
+		Username:              cluster.UserName,
+		Password:              cluster.Password,
+		APIKey:                cluster.APIKey,
+		Header:                client.NewBearerTokenHeader(cluster.BearerToken),
+		Logger:                &logging.CustomLogger{},
+		RetryOnStatus:         getRetryOnStatus(addresses),
+		RetryBackoff:          retryBackOff,
+		Transport:             clusterTransport,
+		DiscoverNodesOnStart:  args.SniffingConfig.Enabled,
+		DiscoverNodesInterval: discoverNodesInterval,
+	}
+
+	switch backend {
+	case "", ElasticsearchBackend:
+		esClient, err := client.NewElasticClient(argsEsClient)
+		if err != nil {
+			return nil, err
+		}
+		return esClient, configureClusterClient(esClient, args, statusMetrics)
+	case OpenSearchBackend:
+		openSearchClient, err := client.NewOpenSearchClient(argsEsClient)
+		if err != nil {
+			return nil, err
+		}
+		return openSearchClient, configureClusterClient(openSearchClient, args, statusMetrics)
+	default:
+		return nil, fmt.Errorf("%w: %s", dataindexer.ErrInvalidDatabaseBackend, backend)
+	}
+}
+
+// getClusterAddresses returns the urls of the nodes of the cluster. The list of urls is used when it is provided,
+// otherwise the single url
+func getClusterAddresses(cluster config.MirrorClusterConfig) ([]string, error) {
+	addresses := cluster.URLs
+	if len(addresses) == 0 {
+		addresses = []string{cluster.URL}
+	}
+
+	for _, address := range addresses {
+		if address == "" {
+			return nil, dataindexer.ErrNilUrl
+		}
+	}
+
+	return addresses, nil
+}
+
+// getRetryOnStatus returns the response statuses for which the client sends the request again. When the cluster has
+// multiple nodes, the requests that a node fails to serve, for example while it restarts, are sent to the next node
+func getRetryOnStatus(addresses []string) []int {
+	if len(addresses) == 1 {
+		return []int{http.StatusConflict}
+	}
+
+	return []int{http.StatusConflict, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+}
+
+// createClusterTransport creates the HTTPS transport of the cluster. The requests are counted in the status metrics
+// when a status metrics handler is provided
+func createClusterTransport(
+	cluster config.MirrorClusterConfig,
+	addresses []string,
+	statusMetrics indexerCore.StatusMetricsHandler,
+) (http.RoundTripper, error) {
+	hasCredentials := cluster.UserName != "" || cluster.Password != "" || cluster.APIKey != "" || cluster.BearerToken != ""
+	for _, address := range addresses {
+		if hasCredentials && strings.HasPrefix(address, "http://") {
+			log.Warn("the credentials of the cluster are sent unencrypted, an https url should be used", "url", address)
+		}
+	}
+
+	httpTransport, err := client.NewHTTPTransport(cluster.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("%w for the cluster %s", err, addresses[0])
+	}
+	if check.IfNil(statusMetrics) {
+		return httpTransport, nil
+	}
+
+	return transport.NewMetricsTransport(statusMetrics, httpTransport)
+}
+
+type retryPolicyHandler interface {
+	EnableRetryPolicy(args client.ArgsRetryPolicy) error
+}
+
+type clusterClientOptionsHandler interface {
+	retryPolicyHandler
+	EnableBulkCompression()
+}
+
+// configureClusterClient enables the bulk compression and creates a retry policy for every cluster, so a cluster that
+// keeps rejecting the requests only opens its own circuit breaker
+func configureClusterClient(
+	databaseClient clusterClientOptionsHandler,
+	args ArgsIndexerFactory,
+	statusMetrics indexerCore.StatusMetricsHandler,
+) error {
+	if args.CompressBulkRequests {
+		databaseClient.EnableBulkCompression()
+	}
+
+	return enableRetryPolicy(databaseClient, args, statusMetrics)
+}
+
+// enableRetryPolicy creates the retry policy of the cluster client when it is enabled in the config
+func enableRetryPolicy(
+	databaseClient retryPolicyHandler,
+	args ArgsIndexerFactory,
+	statusMetrics indexerCore.StatusMetricsHandler,
+) error {
+	retryCfg := args.RetryPolicyConfig
+	if !retryCfg.Enabled {
+		return nil
+	}
+
+	return databaseClient.EnableRetryPolicy(client.ArgsRetryPolicy{
+		InitialInterval:     time.Duration(retryCfg.InitialIntervalInMs) * time.Millisecond,
+		MaxInterval:         time.Duration(retryCfg.MaxIntervalInMs) * time.Millisecond,
+		MaxElapsedTime:      time.Duration(retryCfg.MaxElapsedTimeInSeconds) * time.Second,
+		FailureThreshold:    retryCfg.CircuitBreakerFailureThreshold,
+		OpenCircuitDuration: time.Duration(retryCfg.CircuitBreakerOpenDurationInSeconds) * time.Second,
+		StatusMetrics:       statusMetrics,
+	})
+}
+
+func getISMConfig(args ArgsIndexerFactory) config.ISMConfig {
+	if args.Backend == OpenSearchBackend {
+		return args.ISMConfig
+	}
+	if args.ISMConfig.Enabled {
+		log.Warn("the Index State Management policies are only supported by the OpenSearch backend", "backend", args.Backend)
+	}
+
+	return config.ISMConfig{}
 }
 
 func checkDataIndexerParams(arguments ArgsIndexerFactory) error {
@@ -135,7 +573,7 @@ func checkDataIndexerParams(arguments ArgsIndexerFactory) error {
 	if check.IfNil(arguments.ValidatorPubkeyConverter) {
 		return fmt.Errorf("%w when setting ValidatorPubkeyConverter in indexer", dataindexer.ErrNilPubkeyConverter)
 	}
-	if arguments.Url == "" {
+	if arguments.Url == "" && len(arguments.Urls) == 0 {
 		return dataindexer.ErrNilUrl
 	}
 	if check.IfNil(arguments.Marshalizer) {
@@ -151,7 +589,8 @@ func checkDataIndexerParams(arguments ArgsIndexerFactory) error {
 	return nil
 }
 
-func createBlockCreatorsContainer() (dataindexer.BlockContainerHandler, error) {
+// CreateBlockCreatorsContainer will create the container of the empty headers used to decode the header bytes
+func CreateBlockCreatorsContainer() (dataindexer.BlockContainerHandler, error) {
 	container := block.NewEmptyBlockCreatorsContainer()
 	err := container.Add(core.ShardHeaderV1, block.NewEmptyHeaderCreator())
 	if err != nil {
@@ -4,8 +4,11 @@ import (
 	errorsGo "errors"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
+	"github.com/multiversx/mx-chain-es-indexer-go/client/memory"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
 	"github.com/multiversx/mx-chain-es-indexer-go/mock"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
 	"github.com/stretchr/testify/require"
@@ -117,3 +120,191 @@ func TestIndexerFactoryCreate_ElasticIndexer(t *testing.T) {
 	err = elasticIndexer.Close()
 	require.NoError(t, err)
 }
+
+func TestIndexerFactoryCreate_WithDatabaseClient(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.Url = "http://unreachable:9200"
+	args.DatabaseClient = memory.NewMemoryClient()
+
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_SQLiteBackend(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.Backend = SQLiteBackend
+	args.Url = filepath.Join(t.TempDir(), "indexer.db")
+
+	sqlIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+
+	err = sqlIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_SQLBackendWithBulkSinkOrMirrorShouldErr(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.Backend = SQLiteBackend
+	args.Url = filepath.Join(t.TempDir(), "indexer.db")
+	args.BulkSinkConfig.Enabled = true
+
+	sqlIndexer, err := NewIndexer(args)
+	require.Nil(t, sqlIndexer)
+	require.True(t, errorsGo.Is(err, dataindexer.ErrNotSupportedBySQLBackend))
+
+	args.BulkSinkConfig.Enabled = false
+	args.MirrorConfig.Enabled = true
+	sqlIndexer, err = NewIndexer(args)
+	require.Nil(t, sqlIndexer)
+	require.True(t, errorsGo.Is(err, dataindexer.ErrNotSupportedBySQLBackend))
+}
+
+func TestIndexerFactoryCreate_WithMirrorClusters(t *testing.T) {
+	mirrorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer mirrorServer.Close()
+
+	args := createMockIndexerFactoryArgs()
+	args.MirrorConfig = config.MirrorConfig{
+		Enabled:  true,
+		Clusters: []config.MirrorClusterConfig{{URL: ""}},
+	}
+	_, err := NewIndexer(args)
+	require.True(t, errorsGo.Is(err, dataindexer.ErrNilUrl))
+
+	args.MirrorConfig.Clusters = []config.MirrorClusterConfig{{URL: mirrorServer.URL}}
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_WithMultipleUrls(t *testing.T) {
+	stoppedNode := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	stoppedNode.Close()
+
+	args := createMockIndexerFactoryArgs()
+	args.Urls = []string{args.Url, ""}
+	_, err := NewIndexer(args)
+	require.True(t, errorsGo.Is(err, dataindexer.ErrNilUrl))
+
+	args.Urls = []string{stoppedNode.URL, args.Url}
+	args.Url = ""
+	args.SniffingConfig = config.SniffingConfig{Enabled: true, IntervalInSeconds: 60}
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_WithTimeouts(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.TimeoutsConfig = config.TimeoutsConfig{
+		BulkInSeconds:          180,
+		MultiGetInSeconds:      60,
+		ScrollInSeconds:        600,
+		DeleteByQueryInSeconds: 300,
+		UpdateByQueryInSeconds: 300,
+	}
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestGetRetryOnStatus(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []int{http.StatusConflict}, getRetryOnStatus([]string{"http://node1:9200"}))
+	require.Contains(t, getRetryOnStatus([]string{"http://node1:9200", "http://node2:9200"}), http.StatusServiceUnavailable)
+}
+
+func TestIndexerFactoryCreate_WithRetryPolicy(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.RetryPolicyConfig = config.RetryPolicyConfig{
+		Enabled:             true,
+		InitialIntervalInMs: 500,
+		MaxIntervalInMs:     100,
+	}
+	_, err := NewIndexer(args)
+	require.True(t, errorsGo.Is(err, dataindexer.ErrInvalidRetryPolicy))
+
+	args.RetryPolicyConfig.MaxIntervalInMs = 30000
+	args.RetryPolicyConfig.MaxElapsedTimeInSeconds = 120
+	args.RetryPolicyConfig.CircuitBreakerFailureThreshold = 10
+	args.RetryPolicyConfig.CircuitBreakerOpenDurationInSeconds = 30
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_WithTLSConfig(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.TLSConfig = config.TLSConfig{CertFile: "client.crt"}
+	_, err := NewIndexer(args)
+	require.True(t, errorsGo.Is(err, dataindexer.ErrIncompleteClientCertificate))
+
+	args.TLSConfig = config.TLSConfig{InsecureSkipVerify: true}
+	args.BearerToken = "token"
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestCreateClusterClient_BearerTokenShouldBeUsedInsteadOfTheAPIKey(t *testing.T) {
+	t.Parallel()
+
+	for _, backend := range []string{ElasticsearchBackend, Elasticsearch8Backend} {
+		authorizationHeaders := make(chan string, 10)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizationHeaders <- r.Header.Get("Authorization")
+			w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		}))
+
+		args := createMockIndexerFactoryArgs()
+		args.Backend = backend
+		databaseClient, err := createClusterClient(args, config.MirrorClusterConfig{
+			URL:         ts.URL,
+			APIKey:      "key",
+			BearerToken: "token",
+		}, nil)
+		require.NoError(t, err, backend)
+
+		_ = databaseClient.CheckAndCreateIndex("blocks")
+		require.Equal(t, "Bearer token", <-authorizationHeaders, backend)
+		ts.Close()
+	}
+}
+
+func TestShouldCompressBulkBodies(t *testing.T) {
+	t.Parallel()
+
+	args := createMockIndexerFactoryArgs()
+	require.False(t, shouldCompressBulkBodies(args))
+
+	args.CompressBulkRequests = true
+	for _, backend := range []string{"", ElasticsearchBackend, OpenSearchBackend} {
+		args.Backend = backend
+		require.True(t, shouldCompressBulkBodies(args), backend)
+	}
+
+	args.Backend = Elasticsearch8Backend
+	require.False(t, shouldCompressBulkBodies(args))
+
+	args.Backend = ElasticsearchBackend
+	args.BulkSinkConfig = config.BulkSinkConfig{Enabled: true, ReplaceDatabase: true}
+	require.False(t, shouldCompressBulkBodies(args))
+
+	args.BulkSinkConfig = config.BulkSinkConfig{}
+	args.DatabaseClient = &mock.DatabaseWriterStub{}
+	require.False(t, shouldCompressBulkBodies(args))
+}
@@ -0,0 +1,300 @@
+package offline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	indexerCore "github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("process/offline")
+
+const (
+	settingsFilePrefix = "settings"
+	revertFilePrefix   = "revert"
+	payloadVersion     = 1
+)
+
+// the position of a file between the files of the same shard and nonce
+const (
+	rankRevertedBlock = iota
+	rankRevert
+	rankBlock
+)
+
+// ArgsDirIngester holds all the components needed to create a new instance of dirIngester
+type ArgsDirIngester struct {
+	Path            string
+	Marshaller      marshal.Marshalizer
+	BlockContainer  dataindexer.BlockContainerHandler
+	PayloadHandler  indexerCore.PayloadHandler
+	ContinueOnError bool
+}
+
+type dirIngester struct {
+	path            string
+	marshaller      marshal.Marshalizer
+	blockContainer  dataindexer.BlockContainerHandler
+	handler         indexerCore.PayloadHandler
+	continueOnError bool
+}
+
+// IngestStats holds the results of an ingestion
+type IngestStats struct {
+	NumFiles   int
+	NumSkipped int
+	NumErrors  int
+}
+
+// ingestFile holds the sort keys of a file. The payload is read again right before it is indexed
+type ingestFile struct {
+	path       string
+	topic      string
+	shardID    uint32
+	nonce      uint64
+	round      uint64
+	headerHash []byte
+	rank       int
+}
+
+// NewDirIngester will create a new instance of dirIngester. It indexes a directory of serialized outport payloads,
+// without a node attached. The files whose names start with "settings" hold an outport.OutportConfig, the ones whose
+// names start with "revert" hold the outport.BlockData of a reverted block and all the other files hold an
+// outport.OutportBlock. The files are serialized with the provided marshaller. Only the sort keys of the files are
+// kept in memory, a file is read again right before it is indexed
+func NewDirIngester(args ArgsDirIngester) (*dirIngester, error) {
+	if args.Path == "" {
+		return nil, ErrEmptyPath
+	}
+	if check.IfNil(args.Marshaller) {
+		return nil, dataindexer.ErrNilMarshalizer
+	}
+	if check.IfNilReflect(args.BlockContainer) {
+		return nil, dataindexer.ErrNilBlockContainerHandler
+	}
+	if check.IfNil(args.PayloadHandler) {
+		return nil, ErrNilPayloadHandler
+	}
+
+	return &dirIngester{
+		path:            args.Path,
+		marshaller:      args.Marshaller,
+		blockContainer:  args.BlockContainer,
+		handler:         args.PayloadHandler,
+		continueOnError: args.ContinueOnError,
+	}, nil
+}
+
+// Ingest will feed the files from the directory to the payload handler. The settings files are processed first and
+// the blocks and the reverts after them, in round order, because the nonces of the shards are unrelated. The
+// metachain block of a round is processed before the shard blocks of the same round, and the blocks of a shard are
+// processed in nonce order. A revert is processed after the block it reverts and before the other blocks with the
+// same nonce from its shard
+func (di *dirIngester) Ingest(ctx context.Context) (*IngestStats, error) {
+	names, err := di.getFileNames()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &IngestStats{}
+	settingsFiles := make([]*ingestFile, 0)
+	blockFiles := make([]*ingestFile, 0, len(names))
+	for _, name := range names {
+		file, errRead := di.readFileInfo(name)
+		if errRead != nil {
+			if !di.continueOnError {
+				return stats, fmt.Errorf("%w while reading file %s", errRead, name)
+			}
+			log.Warn("dirIngester: cannot read file", "file", name, "error", errRead)
+			stats.NumSkipped++
+			continue
+		}
+
+		if file.topic == outport.TopicSettings {
+			settingsFiles = append(settingsFiles, file)
+			continue
+		}
+		blockFiles = append(blockFiles, file)
+	}
+
+	sortBlockFiles(blockFiles)
+
+	for _, file := range append(settingsFiles, blockFiles...) {
+		if ctx.Err() != nil {
+			return stats, ctx.Err()
+		}
+
+		err = di.ingestFile(file)
+		stats.NumFiles++
+		if err == nil {
+			continue
+		}
+
+		stats.NumErrors++
+		if !di.continueOnError {
+			return stats, fmt.Errorf("%w while indexing file %s", err, file.path)
+		}
+		log.Warn("dirIngester: cannot index file", "file", file.path, "error", err)
+	}
+
+	return stats, nil
+}
+
+func (di *dirIngester) getFileNames() ([]string, error) {
+	entries, err := os.ReadDir(di.path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoFiles, di.path)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (di *dirIngester) readFileInfo(name string) (*ingestFile, error) {
+	file := &ingestFile{
+		path:  filepath.Join(di.path, name),
+		topic: getTopic(name),
+		rank:  rankBlock,
+	}
+	if file.topic == outport.TopicSettings {
+		return file, nil
+	}
+
+	payload, err := os.ReadFile(file.path)
+	if err != nil {
+		return nil, err
+	}
+
+	blockData, err := di.getBlockData(file.topic, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	creator, err := di.blockContainer.Get(core.HeaderType(blockData.HeaderType))
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := block.GetHeaderFromBytes(di.marshaller, creator, blockData.HeaderBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	file.shardID = header.GetShardID()
+	file.nonce = header.GetNonce()
+	file.round = header.GetRound()
+	file.headerHash = blockData.HeaderHash
+	if file.topic == outport.TopicRevertIndexedBlock {
+		file.rank = rankRevert
+	}
+
+	return file, nil
+}
+
+func (di *dirIngester) getBlockData(topic string, payload []byte) (*outport.BlockData, error) {
+	if topic == outport.TopicRevertIndexedBlock {
+		blockData := &outport.BlockData{}
+		err := di.marshaller.Unmarshal(blockData, payload)
+		return blockData, err
+	}
+
+	outportBlock := &outport.OutportBlock{}
+	err := di.marshaller.Unmarshal(outportBlock, payload)
+	if err != nil {
+		return nil, err
+	}
+	if outportBlock.BlockData == nil {
+		return nil, ErrMissingBlockData
+	}
+
+	return outportBlock.BlockData, nil
+}
+
+func getTopic(name string) string {
+	lowerName := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lowerName, settingsFilePrefix):
+		return outport.TopicSettings
+	case strings.HasPrefix(lowerName, revertFilePrefix):
+		return outport.TopicRevertIndexedBlock
+	default:
+		return outport.TopicSaveBlock
+	}
+}
+
+func sortBlockFiles(files []*ingestFile) {
+	// the block that is reverted has to be indexed before its revert
+	for _, revert := range files {
+		if revert.topic != outport.TopicRevertIndexedBlock {
+			continue
+		}
+		for _, file := range files {
+			if file.topic == outport.TopicSaveBlock && file.shardID == revert.shardID && bytes.Equal(file.headerHash, revert.headerHash) {
+				file.rank = rankRevertedBlock
+			}
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].round != files[j].round {
+			return files[i].round < files[j].round
+		}
+		if files[i].shardID != files[j].shardID {
+			// the shard blocks can use the tokens issued by the metachain blocks
+			return getShardOrder(files[i].shardID) < getShardOrder(files[j].shardID)
+		}
+		if files[i].nonce != files[j].nonce {
+			return files[i].nonce < files[j].nonce
+		}
+		return files[i].rank < files[j].rank
+	})
+}
+
+func getShardOrder(shardID uint32) uint64 {
+	if shardID == core.MetachainShardId {
+		return 0
+	}
+
+	return uint64(shardID) + 1
+}
+
+func (di *dirIngester) ingestFile(file *ingestFile) error {
+	log.Debug("dirIngester: indexing file", "file", file.path, "topic", file.topic, "shardID", file.shardID, "nonce", file.nonce, "round", file.round)
+
+	payload, err := os.ReadFile(file.path)
+	if err != nil {
+		return err
+	}
+
+	return di.handler.ProcessPayload(payload, file.topic, payloadVersion)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (di *dirIngester) IsInterfaceNil() bool {
+	return di == nil
+}
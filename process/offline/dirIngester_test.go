@@ -0,0 +1,186 @@
+package offline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	indexerCore "github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+type processedPayload struct {
+	topic string
+	name  string
+}
+
+func createBlockContainer(t *testing.T) dataindexer.BlockContainerHandler {
+	container := block.NewEmptyBlockCreatorsContainer()
+	require.Nil(t, container.Add(core.ShardHeaderV1, block.NewEmptyHeaderCreator()))
+	require.Nil(t, container.Add(core.MetaHeader, block.NewEmptyMetaBlockCreator()))
+
+	return container
+}
+
+func createBlockData(t *testing.T, shardID uint32, nonce uint64, round uint64, hash string) *outport.BlockData {
+	marshaller := &marshal.JsonMarshalizer{}
+
+	var headerBytes []byte
+	var err error
+	headerType := core.ShardHeaderV1
+	if shardID == core.MetachainShardId {
+		headerType = core.MetaHeader
+		headerBytes, err = marshaller.Marshal(&block.MetaBlock{Nonce: nonce, Round: round})
+	} else {
+		headerBytes, err = marshaller.Marshal(&block.Header{ShardID: shardID, Nonce: nonce, Round: round})
+	}
+	require.Nil(t, err)
+
+	return &outport.BlockData{
+		ShardID:     shardID,
+		HeaderBytes: headerBytes,
+		HeaderType:  string(headerType),
+		HeaderHash:  []byte(hash),
+	}
+}
+
+func writeFile(t *testing.T, dir string, name string, obj interface{}) {
+	fileBytes, err := (&marshal.JsonMarshalizer{}).Marshal(obj)
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(filepath.Join(dir, name), fileBytes, 0644))
+}
+
+func createArgs(dir string, handler indexerCore.PayloadHandler, container dataindexer.BlockContainerHandler) ArgsDirIngester {
+	return ArgsDirIngester{
+		Path:           dir,
+		Marshaller:     &marshal.JsonMarshalizer{},
+		BlockContainer: container,
+		PayloadHandler: handler,
+	}
+}
+
+func TestNewDirIngester(t *testing.T) {
+	t.Parallel()
+
+	container := createBlockContainer(t)
+
+	args := createArgs("", &mock.PayloadHandlerStub{}, container)
+	di, err := NewDirIngester(args)
+	require.Nil(t, di)
+	require.Equal(t, ErrEmptyPath, err)
+
+	args = createArgs(t.TempDir(), &mock.PayloadHandlerStub{}, container)
+	args.Marshaller = nil
+	di, err = NewDirIngester(args)
+	require.Nil(t, di)
+	require.Equal(t, dataindexer.ErrNilMarshalizer, err)
+
+	args = createArgs(t.TempDir(), &mock.PayloadHandlerStub{}, nil)
+	di, err = NewDirIngester(args)
+	require.Nil(t, di)
+	require.Equal(t, dataindexer.ErrNilBlockContainerHandler, err)
+
+	args = createArgs(t.TempDir(), nil, container)
+	di, err = NewDirIngester(args)
+	require.Nil(t, di)
+	require.Equal(t, ErrNilPayloadHandler, err)
+
+	di, err = NewDirIngester(createArgs(t.TempDir(), &mock.PayloadHandlerStub{}, container))
+	require.Nil(t, err)
+	require.False(t, di.IsInterfaceNil())
+}
+
+func TestDirIngester_IngestEmptyDirectoryShouldErr(t *testing.T) {
+	t.Parallel()
+
+	di, _ := NewDirIngester(createArgs(t.TempDir(), &mock.PayloadHandlerStub{}, createBlockContainer(t)))
+	stats, err := di.Ingest(context.Background())
+	require.Nil(t, stats)
+	require.True(t, errors.Is(err, ErrNoFiles))
+}
+
+func TestDirIngester_IngestShouldProcessFilesInRoundOrder(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, dir, "a-block-shard0-nonce3.json", &outport.OutportBlock{BlockData: createBlockData(t, 0, 3, 5, "h3b")})
+	writeFile(t, dir, "b-block-shard0-nonce3.json", &outport.OutportBlock{BlockData: createBlockData(t, 0, 3, 4, "h3a")})
+	writeFile(t, dir, "c-block-shard0-nonce1.json", &outport.OutportBlock{BlockData: createBlockData(t, 0, 1, 1, "h1")})
+	writeFile(t, dir, "d-block-meta-nonce20.json", &outport.OutportBlock{BlockData: createBlockData(t, core.MetachainShardId, 20, 2, "m20")})
+	writeFile(t, dir, "e-block-shard0-nonce2.json", &outport.OutportBlock{BlockData: createBlockData(t, 0, 2, 2, "h2")})
+	writeFile(t, dir, "f-block-shard1-nonce9.json", &outport.OutportBlock{BlockData: createBlockData(t, 1, 9, 3, "s9")})
+	writeFile(t, dir, "revert-shard0-nonce3.json", createBlockData(t, 0, 3, 4, "h3a"))
+	writeFile(t, dir, "settings.json", &outport.OutportConfig{IsInImportDBMode: true})
+	require.Nil(t, os.Mkdir(filepath.Join(dir, "subdir"), 0755))
+
+	processed := make([]processedPayload, 0)
+	handler := &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, uint32(1), version)
+
+			for _, name := range []string{"settings.json", "c-block-shard0-nonce1.json", "d-block-meta-nonce20.json",
+				"e-block-shard0-nonce2.json", "f-block-shard1-nonce9.json", "b-block-shard0-nonce3.json",
+				"revert-shard0-nonce3.json", "a-block-shard0-nonce3.json"} {
+				fileBytes, _ := os.ReadFile(filepath.Join(dir, name))
+				if string(fileBytes) == string(payload) {
+					processed = append(processed, processedPayload{topic: topic, name: name})
+				}
+			}
+			return nil
+		},
+	}
+
+	di, _ := NewDirIngester(createArgs(dir, handler, createBlockContainer(t)))
+	stats, err := di.Ingest(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, &IngestStats{NumFiles: 8}, stats)
+	require.Equal(t, []processedPayload{
+		{topic: outport.TopicSettings, name: "settings.json"},
+		{topic: outport.TopicSaveBlock, name: "c-block-shard0-nonce1.json"},
+		{topic: outport.TopicSaveBlock, name: "d-block-meta-nonce20.json"},
+		{topic: outport.TopicSaveBlock, name: "e-block-shard0-nonce2.json"},
+		{topic: outport.TopicSaveBlock, name: "f-block-shard1-nonce9.json"},
+		{topic: outport.TopicSaveBlock, name: "b-block-shard0-nonce3.json"},
+		{topic: outport.TopicRevertIndexedBlock, name: "revert-shard0-nonce3.json"},
+		{topic: outport.TopicSaveBlock, name: "a-block-shard0-nonce3.json"},
+	}, processed)
+}
+
+func TestDirIngester_IngestContinueOnError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, dir, "block1.json", &outport.OutportBlock{BlockData: createBlockData(t, 0, 1, 1, "h1")})
+	writeFile(t, dir, "block2.json", &outport.OutportBlock{BlockData: createBlockData(t, 0, 2, 2, "h2")})
+	writeFile(t, dir, "invalid.json", &outport.OutportBlock{})
+
+	errProcess := errors.New("local error")
+	numCalls := 0
+	handler := &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			numCalls++
+			return errProcess
+		},
+	}
+
+	di, _ := NewDirIngester(createArgs(dir, handler, createBlockContainer(t)))
+	_, err := di.Ingest(context.Background())
+	require.True(t, errors.Is(err, ErrMissingBlockData))
+	require.Equal(t, 0, numCalls)
+
+	args := createArgs(dir, handler, createBlockContainer(t))
+	args.ContinueOnError = true
+	di, _ = NewDirIngester(args)
+	stats, err := di.Ingest(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, &IngestStats{NumFiles: 2, NumSkipped: 1, NumErrors: 2}, stats)
+	require.Equal(t, 2, numCalls)
+}
@@ -0,0 +1,15 @@
+package offline
+
+import "errors"
+
+// ErrNilPayloadHandler signals that a nil payload handler has been provided
+var ErrNilPayloadHandler = errors.New("nil payload handler")
+
+// ErrEmptyPath signals that an empty path has been provided
+var ErrEmptyPath = errors.New("empty path")
+
+// ErrNoFiles signals that no files were found in the provided directory
+var ErrNoFiles = errors.New("no files found")
+
+// ErrMissingBlockData signals that a block file does not contain the block data
+var ErrMissingBlockData = errors.New("missing block data")
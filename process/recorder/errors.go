@@ -0,0 +1,15 @@
+package recorder
+
+import "errors"
+
+// ErrNilPayloadHandler signals that a nil payload handler has been provided
+var ErrNilPayloadHandler = errors.New("nil payload handler")
+
+// ErrEmptyRecordsPath signals that an empty records path has been provided
+var ErrEmptyRecordsPath = errors.New("empty records path")
+
+// ErrInvalidFrame signals that a recorded frame could not be decoded
+var ErrInvalidFrame = errors.New("invalid recorded frame")
+
+// ErrNoRecordedFiles signals that no recorded files were found at the provided path
+var ErrNoRecordedFiles = errors.New("no recorded files found")
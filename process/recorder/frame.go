@@ -0,0 +1,62 @@
+package recorder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// recordedAtMs(8 bytes) | version(4 bytes) | topic length(2 bytes) | payload length(4 bytes)
+	frameHeaderLen = 8 + 4 + 2 + 4
+)
+
+// Frame holds a payload, as it was received by the indexer, together with the moment it was received
+type Frame struct {
+	RecordedAtMs uint64
+	Version      uint32
+	Topic        string
+	Payload      []byte
+}
+
+func writeFrame(writer io.Writer, frame *Frame) (int, error) {
+	header := make([]byte, frameHeaderLen)
+	binary.BigEndian.PutUint64(header[0:8], frame.RecordedAtMs)
+	binary.BigEndian.PutUint32(header[8:12], frame.Version)
+	binary.BigEndian.PutUint16(header[12:14], uint16(len(frame.Topic)))
+	binary.BigEndian.PutUint32(header[14:18], uint32(len(frame.Payload)))
+
+	buff := make([]byte, 0, frameHeaderLen+len(frame.Topic)+len(frame.Payload))
+	buff = append(buff, header...)
+	buff = append(buff, frame.Topic...)
+	buff = append(buff, frame.Payload...)
+
+	return writer.Write(buff)
+}
+
+// readFrame will read the next frame from the provided reader. It returns io.EOF if there are no more frames
+func readFrame(reader io.Reader) (*Frame, error) {
+	header := make([]byte, frameHeaderLen)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return nil, err
+	}
+
+	frame := &Frame{
+		RecordedAtMs: binary.BigEndian.Uint64(header[0:8]),
+		Version:      binary.BigEndian.Uint32(header[8:12]),
+	}
+	topicLen := int(binary.BigEndian.Uint16(header[12:14]))
+	payloadLen := int(binary.BigEndian.Uint32(header[14:18]))
+
+	body := make([]byte, topicLen+payloadLen)
+	_, err = io.ReadFull(reader, body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFrame, err.Error())
+	}
+
+	frame.Topic = string(body[:topicLen])
+	frame.Payload = body[topicLen:]
+
+	return frame, nil
+}
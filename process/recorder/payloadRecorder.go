@@ -0,0 +1,162 @@
+package recorder
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const (
+	filePrefix             = "payloads_"
+	fileExtension          = ".bin.gz"
+	dirPermissions         = 0755
+	defaultMaxFileSize     = 1024 * 1024 * 1024 // 1GB
+	recordedFileNameFormat = "%s%020d%s"
+)
+
+var log = logger.GetOrCreate("process/recorder")
+
+// ArgsPayloadRecorder holds all the components needed to create a new instance of payloadRecorder
+type ArgsPayloadRecorder struct {
+	Path           string
+	MaxFileSize    uint64
+	PayloadHandler core.PayloadHandler
+}
+
+type payloadRecorder struct {
+	path        string
+	maxFileSize uint64
+	handler     core.PayloadHandler
+
+	mut             sync.Mutex
+	file            *os.File
+	gzipWriter      *gzip.Writer
+	currentFileSize uint64
+	getTimeHandler  func() time.Time
+}
+
+// NewPayloadRecorder will create a new instance of payloadRecorder. Every payload received is written in a rotating
+// gzip compressed file and then forwarded to the wrapped payload handler
+func NewPayloadRecorder(args ArgsPayloadRecorder) (*payloadRecorder, error) {
+	if args.Path == "" {
+		return nil, ErrEmptyRecordsPath
+	}
+	if check.IfNil(args.PayloadHandler) {
+		return nil, ErrNilPayloadHandler
+	}
+
+	err := os.MkdirAll(args.Path, dirPermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	maxFileSize := args.MaxFileSize
+	if maxFileSize == 0 {
+		maxFileSize = defaultMaxFileSize
+	}
+
+	return &payloadRecorder{
+		path:           args.Path,
+		maxFileSize:    maxFileSize,
+		handler:        args.PayloadHandler,
+		getTimeHandler: time.Now,
+	}, nil
+}
+
+// ProcessPayload will record the provided payload and will pass it to the wrapped payload handler
+func (pr *payloadRecorder) ProcessPayload(payload []byte, topic string, version uint32) error {
+	err := pr.record(&Frame{
+		RecordedAtMs: uint64(pr.getTimeHandler().UnixMilli()),
+		Version:      version,
+		Topic:        topic,
+		Payload:      payload,
+	})
+	if err != nil {
+		log.Warn("payloadRecorder.ProcessPayload: cannot record payload", "topic", topic, "error", err)
+	}
+
+	return pr.handler.ProcessPayload(payload, topic, version)
+}
+
+func (pr *payloadRecorder) record(frame *Frame) error {
+	pr.mut.Lock()
+	defer pr.mut.Unlock()
+
+	if pr.gzipWriter == nil || pr.currentFileSize >= pr.maxFileSize {
+		err := pr.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	n, err := writeFrame(pr.gzipWriter, frame)
+	pr.currentFileSize += uint64(n)
+	if err != nil {
+		return err
+	}
+
+	// flush after each frame so a crash loses at most the frame that was being written
+	return pr.gzipWriter.Flush()
+}
+
+func (pr *payloadRecorder) rotate() error {
+	err := pr.closeCurrentFile()
+	if err != nil {
+		log.Warn("payloadRecorder: cannot close recorded file", "error", err)
+	}
+
+	fileName := fmt.Sprintf(recordedFileNameFormat, filePrefix, pr.getTimeHandler().UnixNano(), fileExtension)
+	file, err := os.Create(filepath.Join(pr.path, fileName))
+	if err != nil {
+		return err
+	}
+
+	log.Debug("payloadRecorder: recording payloads", "file", fileName)
+
+	pr.file = file
+	pr.gzipWriter = gzip.NewWriter(file)
+	pr.currentFileSize = 0
+
+	return nil
+}
+
+func (pr *payloadRecorder) closeCurrentFile() error {
+	if pr.gzipWriter == nil {
+		return nil
+	}
+
+	errGzip := pr.gzipWriter.Close()
+	errFile := pr.file.Close()
+	pr.gzipWriter = nil
+	pr.file = nil
+
+	if errGzip != nil {
+		return errGzip
+	}
+
+	return errFile
+}
+
+// Close will close the current recorded file and the wrapped payload handler
+func (pr *payloadRecorder) Close() error {
+	pr.mut.Lock()
+	err := pr.closeCurrentFile()
+	pr.mut.Unlock()
+	if err != nil {
+		log.Warn("payloadRecorder: cannot close recorded file", "error", err)
+	}
+
+	return pr.handler.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (pr *payloadRecorder) IsInterfaceNil() bool {
+	return pr == nil
+}
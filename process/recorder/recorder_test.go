@@ -0,0 +1,167 @@
+package recorder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPayloadRecorder(t *testing.T) {
+	t.Parallel()
+
+	pr, err := NewPayloadRecorder(ArgsPayloadRecorder{PayloadHandler: &mock.PayloadHandlerStub{}})
+	require.Nil(t, pr)
+	require.Equal(t, ErrEmptyRecordsPath, err)
+
+	pr, err = NewPayloadRecorder(ArgsPayloadRecorder{Path: t.TempDir()})
+	require.Nil(t, pr)
+	require.Equal(t, ErrNilPayloadHandler, err)
+
+	pr, err = NewPayloadRecorder(ArgsPayloadRecorder{Path: t.TempDir(), PayloadHandler: &mock.PayloadHandlerStub{}})
+	require.Nil(t, err)
+	require.False(t, pr.IsInterfaceNil())
+}
+
+func TestPayloadRecorder_RecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	forwarded := 0
+	pr, _ := NewPayloadRecorder(ArgsPayloadRecorder{
+		Path:        dir,
+		MaxFileSize: 50,
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				forwarded++
+				return nil
+			},
+		},
+	})
+
+	currentTime := int64(1000)
+	pr.getTimeHandler = func() time.Time {
+		currentTime++
+		return time.UnixMilli(currentTime)
+	}
+
+	numPayloads := 5
+	for i := 0; i < numPayloads; i++ {
+		err := pr.ProcessPayload([]byte(fmt.Sprintf("payload-%d", i)), outport.TopicSaveBlock, uint32(i))
+		require.Nil(t, err)
+	}
+	require.Nil(t, pr.Close())
+	require.Equal(t, numPayloads, forwarded)
+
+	files, err := getRecordedFiles(dir)
+	require.Nil(t, err)
+	require.True(t, len(files) > 1)
+
+	replayed := make([]string, 0)
+	handler := &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			replayed = append(replayed, fmt.Sprintf("%s|%s|%d", payload, topic, version))
+			return nil
+		},
+	}
+	rep, err := NewReplayer(ArgsReplayer{
+		Path:           dir,
+		PayloadHandler: handler,
+	})
+	require.Nil(t, err)
+
+	stats, err := rep.Replay(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, numPayloads, stats.NumFrames)
+	require.Equal(t, len(files), stats.NumFiles)
+
+	expected := make([]string, 0, numPayloads)
+	for i := 0; i < numPayloads; i++ {
+		expected = append(expected, fmt.Sprintf("payload-%d|%s|%d", i, outport.TopicSaveBlock, i))
+	}
+	require.Equal(t, expected, replayed)
+}
+
+func TestReplayer_ReplayShouldFilterByTimeRangeAndStopOnError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pr, _ := NewPayloadRecorder(ArgsPayloadRecorder{Path: dir, PayloadHandler: &mock.PayloadHandlerStub{}})
+	currentTime := int64(0)
+	pr.getTimeHandler = func() time.Time {
+		currentTime += 10
+		return time.UnixMilli(currentTime)
+	}
+	for i := 0; i < 5; i++ {
+		_ = pr.ProcessPayload([]byte("payload"), outport.TopicSaveAccounts, 1)
+	}
+	require.Nil(t, pr.Close())
+
+	numCalls := 0
+	rep, _ := NewReplayer(ArgsReplayer{
+		Path:            dir,
+		FromTimestampMs: 30,
+		ToTimestampMs:   50,
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				numCalls++
+				return nil
+			},
+		},
+	})
+	stats, err := rep.Replay(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, 3, numCalls)
+	require.Equal(t, 2, stats.NumSkipped)
+
+	expectedErr := errors.New("expected error")
+	rep, _ = NewReplayer(ArgsReplayer{
+		Path: dir,
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				return expectedErr
+			},
+		},
+	})
+	stats, err = rep.Replay(context.Background())
+	require.True(t, errors.Is(err, expectedErr))
+	require.Equal(t, 1, stats.NumErrors)
+}
+
+func TestReplayer_ReplayTruncatedFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pr, _ := NewPayloadRecorder(ArgsPayloadRecorder{Path: dir, PayloadHandler: &mock.PayloadHandlerStub{}})
+	_ = pr.ProcessPayload([]byte("payload-0"), outport.TopicSaveBlock, 1)
+	_ = pr.ProcessPayload([]byte("payload-1"), outport.TopicSaveBlock, 1)
+
+	// simulate a crash: the gzip stream is flushed, but never closed
+	files, _ := getRecordedFiles(dir)
+	content, err := os.ReadFile(files[0])
+	require.Nil(t, err)
+	truncatedFile := filepath.Join(t.TempDir(), filepath.Base(files[0]))
+	require.Nil(t, os.WriteFile(truncatedFile, content, 0644))
+
+	numCalls := 0
+	rep, _ := NewReplayer(ArgsReplayer{
+		Path: truncatedFile,
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				numCalls++
+				return nil
+			},
+		},
+	})
+	_, err = rep.Replay(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, 2, numCalls)
+	require.Nil(t, pr.Close())
+}
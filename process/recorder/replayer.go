@@ -0,0 +1,186 @@
+package recorder
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+)
+
+// ArgsReplayer holds all the components needed to create a new instance of replayer
+type ArgsReplayer struct {
+	Path            string
+	FromTimestampMs uint64
+	ToTimestampMs   uint64
+	ContinueOnError bool
+	PayloadHandler  core.PayloadHandler
+}
+
+type replayer struct {
+	path            string
+	fromTimestampMs uint64
+	toTimestampMs   uint64
+	continueOnError bool
+	handler         core.PayloadHandler
+}
+
+// ReplayStats holds the results of a replay
+type ReplayStats struct {
+	NumFiles   int
+	NumFrames  int
+	NumSkipped int
+	NumErrors  int
+}
+
+// NewReplayer will create a new instance of replayer. The path can be a recorded file or a directory with recorded files
+func NewReplayer(args ArgsReplayer) (*replayer, error) {
+	if args.Path == "" {
+		return nil, ErrEmptyRecordsPath
+	}
+	if check.IfNil(args.PayloadHandler) {
+		return nil, ErrNilPayloadHandler
+	}
+
+	return &replayer{
+		path:            args.Path,
+		fromTimestampMs: args.FromTimestampMs,
+		toTimestampMs:   args.ToTimestampMs,
+		continueOnError: args.ContinueOnError,
+		handler:         args.PayloadHandler,
+	}, nil
+}
+
+// Replay will feed all the recorded frames, in the order they were recorded, to the payload handler
+func (r *replayer) Replay(ctx context.Context) (*ReplayStats, error) {
+	files, err := getRecordedFiles(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ReplayStats{}
+	for _, file := range files {
+		log.Info("replayer: replaying file", "file", file)
+
+		err = r.replayFile(ctx, file, stats)
+		if err != nil {
+			return stats, fmt.Errorf("%w while replaying file %s", err, file)
+		}
+		stats.NumFiles++
+	}
+
+	return stats, nil
+}
+
+func (r *replayer) replayFile(ctx context.Context, filePath string, stats *ReplayStats) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		frame, errRead := readFrame(reader)
+		if errRead == io.EOF {
+			return nil
+		}
+		if isTruncatedFrameErr(errRead) {
+			// the file was not closed properly, most probably the indexer was stopped while recording
+			log.Warn("replayer: truncated recorded file", "file", filePath, "error", errRead)
+			return nil
+		}
+		if errRead != nil {
+			return errRead
+		}
+
+		if !r.isInTimeRange(frame.RecordedAtMs) {
+			stats.NumSkipped++
+			continue
+		}
+
+		err = r.handler.ProcessPayload(frame.Payload, frame.Topic, frame.Version)
+		stats.NumFrames++
+		if err == nil {
+			continue
+		}
+
+		stats.NumErrors++
+		if !r.continueOnError {
+			return fmt.Errorf("%w for topic %s recorded at %d", err, frame.Topic, frame.RecordedAtMs)
+		}
+		log.Warn("replayer: cannot process frame", "topic", frame.Topic, "recorded at", frame.RecordedAtMs, "error", err)
+	}
+}
+
+func (r *replayer) isInTimeRange(recordedAtMs uint64) bool {
+	if r.fromTimestampMs > 0 && recordedAtMs < r.fromTimestampMs {
+		return false
+	}
+	if r.toTimestampMs > 0 && recordedAtMs > r.toTimestampMs {
+		return false
+	}
+
+	return true
+}
+
+func getRecordedFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileExtension) {
+			continue
+		}
+		files = append(files, filepath.Join(path, name))
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoRecordedFiles, path)
+	}
+
+	// the recorded file names contain the zero padded creation timestamp
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (r *replayer) IsInterfaceNil() bool {
+	return r == nil
+}
+
+func isTruncatedFrameErr(err error) bool {
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, ErrInvalidFrame)
+}
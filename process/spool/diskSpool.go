@@ -0,0 +1,529 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const (
+	shardDirPrefix       = "shard_"
+	filePermissions      = 0644
+	dirPermissions       = 0755
+	defaultRetryDuration = 5 * time.Second
+)
+
+var log = logger.GetOrCreate("process/spool")
+
+// ArgsDiskSpool holds all the components needed to create a new instance of diskSpool
+type ArgsDiskSpool struct {
+	Path              string
+	MaxSizeInBytes    uint64
+	RetryDuration     time.Duration
+	MaxAttempts       uint32
+	Marshaller        marshal.Marshalizer
+	PayloadHandler    core.PayloadHandler
+	DeadLetterHandler DeadLetterHandler
+}
+
+type shardQueue struct {
+	mut     sync.Mutex
+	shardID uint32
+	dir     string
+	nextSeq uint64
+	notify  chan struct{}
+
+	// the attempts of the oldest payload of the queue, only used by the goroutine that drains the queue
+	failedFile     string
+	failedAttempts uint32
+}
+
+type diskSpool struct {
+	path              string
+	maxSizeInBytes    uint64
+	retryDuration     time.Duration
+	maxAttempts       uint32
+	marshaller        marshal.Marshalizer
+	handler           core.PayloadHandler
+	deadLetterHandler DeadLetterHandler
+
+	mut         sync.Mutex
+	sizeInBytes uint64
+	closed      bool
+	queues      map[uint32]*shardQueue
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDiskSpool will create a new instance of diskSpool. Every received payload is persisted on disk before being
+// acknowledged and is then drained, in order, towards the wrapped payload handler by one goroutine per shard. A payload
+// that fails max attempts times in a row is moved to the dead-letter handler, so it does not block its shard. Without
+// a dead-letter handler, or with 0 max attempts, a payload is retried until it is indexed
+func NewDiskSpool(args ArgsDiskSpool) (*diskSpool, error) {
+	err := checkArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	retryDuration := args.RetryDuration
+	if retryDuration == 0 {
+		retryDuration = defaultRetryDuration
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ds := &diskSpool{
+		path:              args.Path,
+		maxSizeInBytes:    args.MaxSizeInBytes,
+		retryDuration:     retryDuration,
+		maxAttempts:       args.MaxAttempts,
+		marshaller:        args.Marshaller,
+		handler:           args.PayloadHandler,
+		deadLetterHandler: args.DeadLetterHandler,
+		queues:            make(map[uint32]*shardQueue),
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+
+	err = ds.loadExistingQueues()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return ds, nil
+}
+
+func checkArgs(args ArgsDiskSpool) error {
+	if args.Path == "" {
+		return ErrEmptySpoolPath
+	}
+	if check.IfNil(args.Marshaller) {
+		return dataindexer.ErrNilMarshalizer
+	}
+	if check.IfNil(args.PayloadHandler) {
+		return ErrNilPayloadHandler
+	}
+
+	return nil
+}
+
+func (ds *diskSpool) loadExistingQueues() error {
+	err := os.MkdirAll(ds.path, dirPermissions)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(ds.path)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), shardDirPrefix) {
+			continue
+		}
+
+		shardID, errParse := strconv.ParseUint(strings.TrimPrefix(entry.Name(), shardDirPrefix), 10, 32)
+		if errParse != nil {
+			log.Warn("diskSpool: skipping unknown directory", "name", entry.Name())
+			continue
+		}
+
+		err = ds.loadQueue(uint32(shardID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ds *diskSpool) loadQueue(shardID uint32) error {
+	queue := ds.newShardQueue(shardID)
+
+	entries, err := os.ReadDir(queue.dir)
+	if err != nil {
+		return err
+	}
+
+	numRecords := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, tmpFileExt) {
+			// a write that was interrupted before being acknowledged
+			_ = os.Remove(filepath.Join(queue.dir, name))
+			continue
+		}
+
+		seq, ok := parseRecordFileName(name)
+		if !ok {
+			continue
+		}
+
+		info, errInfo := entry.Info()
+		if errInfo != nil {
+			return errInfo
+		}
+
+		ds.sizeInBytes += uint64(info.Size())
+		if seq >= queue.nextSeq {
+			queue.nextSeq = seq + 1
+		}
+		numRecords++
+	}
+
+	log.Info("diskSpool: loaded spooled payloads", "shardID", shardID, "num records", numRecords)
+
+	ds.startQueue(queue)
+
+	return nil
+}
+
+func (ds *diskSpool) newShardQueue(shardID uint32) *shardQueue {
+	return &shardQueue{
+		shardID: shardID,
+		dir:     filepath.Join(ds.path, fmt.Sprintf("%s%d", shardDirPrefix, shardID)),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+func (ds *diskSpool) startQueue(queue *shardQueue) {
+	ds.queues[queue.shardID] = queue
+	ds.wg.Add(1)
+	go ds.drain(queue)
+}
+
+// ProcessPayload will persist the provided payload on disk. The payload is considered acknowledged once this
+// function returns without error
+func (ds *diskSpool) ProcessPayload(payload []byte, topic string, version uint32) error {
+	shardID, err := ds.getShardID(payload)
+	if err != nil {
+		log.Warn("diskSpool.ProcessPayload: cannot get shardID from payload", "error", err)
+	}
+
+	recordBytes := encodeRecord(&record{
+		topic:   topic,
+		version: version,
+		payload: payload,
+	})
+	recordSize := uint64(len(recordBytes))
+
+	queue, err := ds.reserve(shardID, recordSize)
+	if err != nil {
+		return err
+	}
+
+	queue.mut.Lock()
+	seq := queue.nextSeq
+	err = writeFileSynced(queue.dir, seq, recordBytes)
+	if err == nil {
+		queue.nextSeq++
+	}
+	queue.mut.Unlock()
+
+	if err != nil {
+		ds.release(recordSize)
+		return err
+	}
+
+	select {
+	case queue.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (ds *diskSpool) reserve(shardID uint32, recordSize uint64) (*shardQueue, error) {
+	ds.mut.Lock()
+	defer ds.mut.Unlock()
+
+	if ds.closed {
+		return nil, ErrSpoolClosed
+	}
+
+	newSize := ds.sizeInBytes + recordSize
+	if ds.maxSizeInBytes > 0 && newSize > ds.maxSizeInBytes {
+		return nil, fmt.Errorf("%w, current size %d, max size %d", ErrSpoolFull, ds.sizeInBytes, ds.maxSizeInBytes)
+	}
+
+	queue, found := ds.queues[shardID]
+	if !found {
+		queue = ds.newShardQueue(shardID)
+		err := os.MkdirAll(queue.dir, dirPermissions)
+		if err != nil {
+			return nil, err
+		}
+		ds.startQueue(queue)
+	}
+
+	ds.sizeInBytes = newSize
+
+	return queue, nil
+}
+
+func (ds *diskSpool) release(recordSize uint64) {
+	ds.mut.Lock()
+	defer ds.mut.Unlock()
+
+	if recordSize > ds.sizeInBytes {
+		ds.sizeInBytes = 0
+		return
+	}
+
+	ds.sizeInBytes -= recordSize
+}
+
+func (ds *diskSpool) drain(queue *shardQueue) {
+	defer ds.wg.Done()
+
+	for {
+		allProcessed := ds.processPendingRecords(queue)
+
+		var retry <-chan time.Time
+		if !allProcessed {
+			retry = time.After(ds.retryDuration)
+		}
+
+		select {
+		case <-ds.ctx.Done():
+			return
+		case <-queue.notify:
+		case <-retry:
+		}
+	}
+}
+
+func (ds *diskSpool) processPendingRecords(queue *shardQueue) bool {
+	fileNames, err := listRecordFiles(queue.dir)
+	if err != nil {
+		log.Warn("diskSpool: cannot list spooled payloads", "shardID", queue.shardID, "error", err)
+		return false
+	}
+
+	for _, fileName := range fileNames {
+		if ds.ctx.Err() != nil {
+			return false
+		}
+
+		err = ds.processRecordFile(queue, fileName)
+		if err != nil {
+			log.Warn("diskSpool: cannot process spooled payload, will retry",
+				"shardID", queue.shardID, "file", fileName, "error", err)
+			return false
+		}
+	}
+
+	return true
+}
+
+func (ds *diskSpool) processRecordFile(queue *shardQueue, fileName string) error {
+	filePath := filepath.Join(queue.dir, fileName)
+	recordBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	rec, err := decodeRecord(recordBytes)
+	if err != nil {
+		log.Error("diskSpool: moving aside invalid spooled payload", "file", filePath, "error", err)
+		err = os.Rename(filePath, filePath+invalidFileExt)
+		if err != nil {
+			return err
+		}
+
+		ds.release(uint64(len(recordBytes)))
+		return nil
+	}
+
+	err = ds.handler.ProcessPayload(rec.payload, rec.topic, rec.version)
+	if err != nil && !ds.moveToDeadLetter(queue, fileName, rec, err) {
+		return err
+	}
+
+	err = os.Remove(filePath)
+	if err != nil {
+		return err
+	}
+
+	ds.release(uint64(len(recordBytes)))
+
+	return nil
+}
+
+// moveToDeadLetter counts the failed attempts of the oldest payload of the queue and, after max attempts, saves it in
+// the dead-letter handler. It returns true if the payload was saved, so it can be removed from the spool. The attempts
+// interrupted by the shutdown of the indexer are not counted
+func (ds *diskSpool) moveToDeadLetter(queue *shardQueue, fileName string, rec *record, processingErr error) bool {
+	if errors.Is(processingErr, context.Canceled) {
+		return false
+	}
+
+	if queue.failedFile != fileName {
+		queue.failedFile = fileName
+		queue.failedAttempts = 0
+	}
+	queue.failedAttempts++
+
+	if ds.maxAttempts == 0 || queue.failedAttempts < ds.maxAttempts {
+		return false
+	}
+	if check.IfNil(ds.deadLetterHandler) {
+		if queue.failedAttempts == ds.maxAttempts {
+			log.Error("diskSpool: the payload cannot be indexed and blocks its shard, it is retried until it is indexed "+
+				"because the dead-letter store is disabled", "shardID", queue.shardID, "file", fileName,
+				"attempts", queue.failedAttempts, "error", processingErr)
+		}
+		return false
+	}
+
+	err := ds.deadLetterHandler.SavePayload(rec.payload, rec.topic, rec.version, processingErr)
+	if err != nil {
+		log.Error("diskSpool: cannot move the payload to the dead-letter store, will retry",
+			"shardID", queue.shardID, "file", fileName, "error", err)
+		return false
+	}
+
+	log.Error("diskSpool: moved the payload that cannot be indexed to the dead-letter store",
+		"shardID", queue.shardID, "file", fileName, "attempts", queue.failedAttempts, "error", processingErr)
+	queue.failedFile = ""
+	queue.failedAttempts = 0
+
+	return true
+}
+
+func (ds *diskSpool) getShardID(payload []byte) (uint32, error) {
+	shard := &outport.Shard{}
+	err := ds.marshaller.Unmarshal(shard, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	return shard.ShardID, nil
+}
+
+// SizeInBytes returns the total size of the payloads that are waiting to be drained
+func (ds *diskSpool) SizeInBytes() uint64 {
+	ds.mut.Lock()
+	defer ds.mut.Unlock()
+
+	return ds.sizeInBytes
+}
+
+// Close will stop draining the spool and will close the wrapped payload handler. Payloads that were not drained
+// yet remain on disk and will be processed after a restart
+func (ds *diskSpool) Close() error {
+	ds.mut.Lock()
+	ds.closed = true
+	ds.mut.Unlock()
+
+	ds.cancel()
+	ds.wg.Wait()
+
+	return ds.handler.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ds *diskSpool) IsInterfaceNil() bool {
+	return ds == nil
+}
+
+func recordFileName(seq uint64) string {
+	return fmt.Sprintf("%020d%s", seq, recordFileExt)
+}
+
+func parseRecordFileName(name string) (uint64, bool) {
+	if !strings.HasSuffix(name, recordFileExt) {
+		return 0, false
+	}
+
+	seq, err := strconv.ParseUint(strings.TrimSuffix(name, recordFileExt), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return seq, true
+}
+
+func listRecordFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fileNames := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		_, ok := parseRecordFileName(entry.Name())
+		if !ok {
+			continue
+		}
+		fileNames = append(fileNames, entry.Name())
+	}
+
+	// file names are zero padded so the lexicographic order is the same as the sequence order
+	sort.Strings(fileNames)
+
+	return fileNames, nil
+}
+
+func writeFileSynced(dir string, seq uint64, content []byte) error {
+	finalPath := filepath.Join(dir, recordFileName(seq))
+	tmpPath := finalPath + tmpFileExt
+
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, filePermissions)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(content)
+	if err == nil {
+		err = file.Sync()
+	}
+	errClose := file.Close()
+	if err == nil {
+		err = errClose
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	err = os.Rename(tmpPath, finalPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	err = d.Sync()
+	errClose := d.Close()
+	if err != nil {
+		return err
+	}
+
+	return errClose
+}
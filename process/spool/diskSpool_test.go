@@ -0,0 +1,293 @@
+package spool
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockArgsDiskSpool(t *testing.T) ArgsDiskSpool {
+	return ArgsDiskSpool{
+		Path:           t.TempDir(),
+		RetryDuration:  10 * time.Millisecond,
+		Marshaller:     &mock.MarshalizerMock{},
+		PayloadHandler: &mock.PayloadHandlerStub{},
+	}
+}
+
+func shardPayload(shardID uint32, nonce int) []byte {
+	return []byte(fmt.Sprintf(`{"shardID":%d,"nonce":%d}`, shardID, nonce))
+}
+
+func TestNewDiskSpool(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsDiskSpool(t)
+	args.Path = ""
+	ds, err := NewDiskSpool(args)
+	require.Nil(t, ds)
+	require.Equal(t, ErrEmptySpoolPath, err)
+
+	args = createMockArgsDiskSpool(t)
+	args.Marshaller = nil
+	ds, err = NewDiskSpool(args)
+	require.Nil(t, ds)
+	require.Equal(t, dataindexer.ErrNilMarshalizer, err)
+
+	args = createMockArgsDiskSpool(t)
+	args.PayloadHandler = nil
+	ds, err = NewDiskSpool(args)
+	require.Nil(t, ds)
+	require.Equal(t, ErrNilPayloadHandler, err)
+
+	args = createMockArgsDiskSpool(t)
+	ds, err = NewDiskSpool(args)
+	require.Nil(t, err)
+	require.False(t, ds.IsInterfaceNil())
+	require.Nil(t, ds.Close())
+}
+
+func TestDiskSpool_ProcessPayloadShouldDrainInOrderPerShard(t *testing.T) {
+	t.Parallel()
+
+	mut := sync.Mutex{}
+	received := make(map[string][]string)
+	wg := sync.WaitGroup{}
+	numPayloads := 10
+	wg.Add(2 * numPayloads)
+
+	args := createMockArgsDiskSpool(t)
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			require.Equal(t, outport.TopicSaveBlock, topic)
+			require.Equal(t, uint32(1), version)
+
+			mut.Lock()
+			received[string(payload[:12])] = append(received[string(payload[:12])], string(payload))
+			mut.Unlock()
+			wg.Done()
+			return nil
+		},
+	}
+	ds, _ := NewDiskSpool(args)
+
+	for i := 0; i < numPayloads; i++ {
+		require.Nil(t, ds.ProcessPayload(shardPayload(0, i), outport.TopicSaveBlock, 1))
+		require.Nil(t, ds.ProcessPayload(shardPayload(1, i), outport.TopicSaveBlock, 1))
+	}
+
+	wg.Wait()
+	require.Nil(t, ds.Close())
+
+	for shardID := uint32(0); shardID < 2; shardID++ {
+		expected := make([]string, 0, numPayloads)
+		for i := 0; i < numPayloads; i++ {
+			expected = append(expected, string(shardPayload(shardID, i)))
+		}
+		require.Equal(t, expected, received[string(shardPayload(shardID, 0)[:12])])
+	}
+	require.Equal(t, uint64(0), ds.SizeInBytes())
+}
+
+func TestDiskSpool_ShouldRetryFailedPayloads(t *testing.T) {
+	t.Parallel()
+
+	numCalls := 0
+	done := make(chan struct{})
+	args := createMockArgsDiskSpool(t)
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			numCalls++
+			if numCalls < 3 {
+				return errors.New("local error")
+			}
+
+			close(done)
+			return nil
+		},
+	}
+	ds, _ := NewDiskSpool(args)
+
+	require.Nil(t, ds.ProcessPayload(shardPayload(2, 1), outport.TopicSaveBlock, 1))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "payload was not retried")
+	}
+	require.Nil(t, ds.Close())
+	require.Equal(t, 3, numCalls)
+}
+
+func TestDiskSpool_ShouldMoveToDeadLetterAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	errProcess := errors.New("local error")
+	mut := sync.Mutex{}
+	numCalls := 0
+	processed := make([]string, 0)
+	done := make(chan struct{})
+	args := createMockArgsDiskSpool(t)
+	args.MaxAttempts = 3
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			mut.Lock()
+			defer mut.Unlock()
+
+			if string(payload) == string(shardPayload(2, 1)) {
+				numCalls++
+				return errProcess
+			}
+
+			processed = append(processed, string(payload))
+			close(done)
+			return nil
+		},
+	}
+	var deadLetterPayload []byte
+	args.DeadLetterHandler = &mock.DeadLetterHandlerStub{
+		SavePayloadCalled: func(payload []byte, topic string, version uint32, processingErr error) error {
+			require.Equal(t, outport.TopicSaveBlock, topic)
+			require.Equal(t, uint32(1), version)
+			require.Equal(t, errProcess, processingErr)
+
+			mut.Lock()
+			deadLetterPayload = payload
+			mut.Unlock()
+			return nil
+		},
+	}
+	ds, _ := NewDiskSpool(args)
+
+	require.Nil(t, ds.ProcessPayload(shardPayload(2, 1), outport.TopicSaveBlock, 1))
+	require.Nil(t, ds.ProcessPayload(shardPayload(2, 2), outport.TopicSaveBlock, 1))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "the next payload was not processed")
+	}
+	require.Nil(t, ds.Close())
+
+	mut.Lock()
+	defer mut.Unlock()
+	require.Equal(t, 3, numCalls)
+	require.Equal(t, shardPayload(2, 1), deadLetterPayload)
+	require.Equal(t, []string{string(shardPayload(2, 2))}, processed)
+	require.Equal(t, uint64(0), ds.SizeInBytes())
+}
+
+func TestDiskSpool_WithoutDeadLetterHandlerShouldRetryAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	numCalls := 0
+	done := make(chan struct{})
+	args := createMockArgsDiskSpool(t)
+	args.MaxAttempts = 2
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			numCalls++
+			if numCalls < 5 {
+				return errors.New("local error")
+			}
+
+			close(done)
+			return nil
+		},
+	}
+	ds, _ := NewDiskSpool(args)
+
+	require.Nil(t, ds.ProcessPayload(shardPayload(2, 1), outport.TopicSaveBlock, 1))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "payload was not retried")
+	}
+	require.Nil(t, ds.Close())
+	require.Equal(t, 5, numCalls)
+}
+
+func TestDiskSpool_ShouldResumeAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsDiskSpool(t)
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			return errors.New("elastic is down")
+		},
+	}
+	ds, _ := NewDiskSpool(args)
+	require.Nil(t, ds.ProcessPayload(shardPayload(0, 1), outport.TopicSaveBlock, 1))
+	require.Nil(t, ds.ProcessPayload(shardPayload(0, 2), outport.TopicRevertIndexedBlock, 2))
+	require.Nil(t, ds.Close())
+	require.Equal(t, ErrSpoolClosed, ds.ProcessPayload(shardPayload(0, 3), outport.TopicSaveBlock, 1))
+
+	received := make([]string, 0)
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			received = append(received, fmt.Sprintf("%s|%s|%d", payload, topic, version))
+			wg.Done()
+			return nil
+		},
+	}
+	ds, err := NewDiskSpool(args)
+	require.Nil(t, err)
+	require.Nil(t, ds.ProcessPayload(shardPayload(0, 3), outport.TopicSaveBlock, 1))
+
+	wg.Wait()
+	require.Nil(t, ds.Close())
+	require.Equal(t, []string{
+		fmt.Sprintf("%s|%s|1", shardPayload(0, 1), outport.TopicSaveBlock),
+		fmt.Sprintf("%s|%s|2", shardPayload(0, 2), outport.TopicRevertIndexedBlock),
+		fmt.Sprintf("%s|%s|1", shardPayload(0, 3), outport.TopicSaveBlock),
+	}, received)
+}
+
+func TestDiskSpool_ProcessPayloadShouldErrWhenFull(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsDiskSpool(t)
+	args.MaxSizeInBytes = 100
+	args.PayloadHandler = &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			return errors.New("elastic is down")
+		},
+	}
+	ds, _ := NewDiskSpool(args)
+	defer func() {
+		_ = ds.Close()
+	}()
+
+	payload := make([]byte, 60)
+	require.Nil(t, ds.ProcessPayload(payload, outport.TopicSaveBlock, 1))
+
+	err := ds.ProcessPayload(payload, outport.TopicSaveBlock, 1)
+	require.True(t, errors.Is(err, ErrSpoolFull))
+}
+
+func TestRecord_EncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	rec := &record{
+		topic:   outport.TopicSaveAccounts,
+		version: 3,
+		payload: []byte("payload"),
+	}
+
+	decoded, err := decodeRecord(encodeRecord(rec))
+	require.Nil(t, err)
+	require.Equal(t, rec, decoded)
+
+	_, err = decodeRecord([]byte{0, 0, 0, 1, 0, 10, 'a'})
+	require.True(t, errors.Is(err, ErrInvalidRecord))
+}
@@ -0,0 +1,18 @@
+package spool
+
+import "errors"
+
+// ErrNilPayloadHandler signals that a nil payload handler has been provided
+var ErrNilPayloadHandler = errors.New("nil payload handler")
+
+// ErrEmptySpoolPath signals that an empty spool path has been provided
+var ErrEmptySpoolPath = errors.New("empty spool path")
+
+// ErrSpoolFull signals that the spool has reached the configured size cap
+var ErrSpoolFull = errors.New("spool is full")
+
+// ErrSpoolClosed signals that the spool was closed
+var ErrSpoolClosed = errors.New("spool is closed")
+
+// ErrInvalidRecord signals that a spooled record could not be decoded
+var ErrInvalidRecord = errors.New("invalid spool record")
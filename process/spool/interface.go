@@ -0,0 +1,7 @@
+package spool
+
+// DeadLetterHandler defines the behavior of a component that saves the payloads that cannot be indexed
+type DeadLetterHandler interface {
+	SavePayload(payload []byte, topic string, version uint32, processingErr error) error
+	IsInterfaceNil() bool
+}
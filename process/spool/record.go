@@ -0,0 +1,53 @@
+package spool
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	versionLen     = 4
+	topicLenLen    = 2
+	recordFileExt  = ".rec"
+	tmpFileExt     = ".tmp"
+	invalidFileExt = ".invalid"
+)
+
+type record struct {
+	topic   string
+	version uint32
+	payload []byte
+}
+
+// encodeRecord will serialize the provided record in the format: version(4 bytes) | topic length(2 bytes) | topic | payload
+func encodeRecord(r *record) []byte {
+	buff := make([]byte, versionLen+topicLenLen+len(r.topic)+len(r.payload))
+	binary.BigEndian.PutUint32(buff[:versionLen], r.version)
+	binary.BigEndian.PutUint16(buff[versionLen:versionLen+topicLenLen], uint16(len(r.topic)))
+
+	offset := versionLen + topicLenLen
+	copy(buff[offset:], r.topic)
+	copy(buff[offset+len(r.topic):], r.payload)
+
+	return buff
+}
+
+func decodeRecord(buff []byte) (*record, error) {
+	if len(buff) < versionLen+topicLenLen {
+		return nil, fmt.Errorf("%w: record too short, length %d", ErrInvalidRecord, len(buff))
+	}
+
+	version := binary.BigEndian.Uint32(buff[:versionLen])
+	topicLen := int(binary.BigEndian.Uint16(buff[versionLen : versionLen+topicLenLen]))
+
+	offset := versionLen + topicLenLen
+	if len(buff) < offset+topicLen {
+		return nil, fmt.Errorf("%w: topic length %d exceeds record length %d", ErrInvalidRecord, topicLen, len(buff))
+	}
+
+	return &record{
+		version: version,
+		topic:   string(buff[offset : offset+topicLen]),
+		payload: buff[offset+topicLen:],
+	}, nil
+}
@@ -0,0 +1,136 @@
+package sqlproc
+
+import (
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/tags"
+)
+
+var accountsColumns = []string{
+	"address", "nonce", "balance", "balance_num", "user_name", "owner_address", "developer_rewards", "shard_id", "timestamp_ms",
+}
+
+var accountsESDTColumns = []string{
+	"address", "token", "token_nonce", "identifier", "balance", "balance_num", "properties", "frozen", "type",
+	"current_owner", "shard_id", "timestamp_ms",
+}
+
+var accountsHistoryColumns = []string{
+	"address", "token", "token_nonce", "timestamp_ms", "balance", "is_sender", "is_smart_contract", "shard_id",
+}
+
+// SaveAccounts will prepare and save the provided accounts
+func (sp *sqlProcessor) SaveAccounts(accountsData *outport.Accounts) error {
+	accounts := make([]*data.Account, 0, len(accountsData.AlteredAccounts))
+	for _, account := range accountsData.AlteredAccounts {
+		accounts = append(accounts, &data.Account{
+			UserAccount: account,
+			IsSender:    false,
+		})
+	}
+
+	return sp.withTransaction(func(tx *sqlTx) error {
+		return sp.saveAccounts(tx, accounts, accountsData.ShardID, accountsData.BlockTimestampMs)
+	})
+}
+
+func (sp *sqlProcessor) saveAlteredAccounts(
+	tx *sqlTx,
+	coreAlteredAccounts map[string]*alteredAccount.AlteredAccount,
+	shardID uint32,
+	timestampMs uint64,
+) error {
+	regularAccounts, accountsESDT := sp.accountsProc.GetAccounts(coreAlteredAccounts)
+
+	err := sp.saveAccounts(tx, regularAccounts, shardID, timestampMs)
+	if err != nil {
+		return err
+	}
+
+	return sp.saveAccountsESDT(tx, accountsESDT, shardID, timestampMs)
+}
+
+// saveAccounts saves the balances of the accounts, unless a newer balance is already saved, and their history
+func (sp *sqlProcessor) saveAccounts(tx *sqlTx, accounts []*data.Account, shardID uint32, timestampMs uint64) error {
+	accountsMap := sp.accountsProc.PrepareRegularAccountsMap(accounts, shardID, timestampMs)
+
+	if sp.isIndexEnabled(dataindexer.AccountsIndex) {
+		query := upsertQuery(accountsTable, accountsColumns, []string{"address"}, accountsColumns[1:], accountsTable+".timestamp_ms <= excluded.timestamp_ms")
+		for _, account := range accountsMap {
+			err := tx.exec(query,
+				account.Address, account.Nonce, account.Balance, account.BalanceNum, account.UserName, account.Owner,
+				account.DeveloperRewards, account.ShardID, account.TimestampMs,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !sp.isIndexEnabled(dataindexer.AccountsHistoryIndex) {
+		return nil
+	}
+
+	return sp.saveAccountsHistory(tx, accountsMap, shardID, timestampMs)
+}
+
+// saveAccountsESDT saves the ESDT balances, with the type and the current owner of their token read from the tokens
+// table. A zero balance removes the row, unless a newer balance is already saved
+func (sp *sqlProcessor) saveAccountsESDT(tx *sqlTx, accountsESDT []*data.AccountESDT, shardID uint32, timestampMs uint64) error {
+	accountsESDTMap, tokensData := sp.accountsProc.PrepareAccountsMapESDT(accountsESDT, tags.NewTagsCount(), shardID, timestampMs)
+	if !check.IfNil(tokensData) && tokensData.Len() > 0 {
+		err := addTypeAndOwnerFromTokensTable(tx, tokensData)
+		if err != nil {
+			return err
+		}
+		tokensData.PutTypeAndOwnerInAccountsESDT(accountsESDTMap)
+	}
+
+	if sp.isIndexEnabled(dataindexer.AccountsESDTIndex) {
+		query := upsertQuery(accountsESDTTable, accountsESDTColumns, []string{"address", "token", "token_nonce"}, accountsESDTColumns[3:],
+			accountsESDTTable+".timestamp_ms <= excluded.timestamp_ms")
+		queryDelete := `DELETE FROM ` + accountsESDTTable + ` WHERE address = ? AND token = ? AND token_nonce = ? AND timestamp_ms <= ?`
+		for _, account := range accountsESDTMap {
+			var err error
+			if account.Balance == "0" || account.Balance == "" {
+				err = tx.exec(queryDelete, account.Address, account.TokenName, account.TokenNonce, account.TimestampMs)
+			} else {
+				err = tx.exec(query,
+					account.Address, account.TokenName, account.TokenNonce, account.TokenIdentifier, account.Balance,
+					account.BalanceNum, account.Properties, account.Frozen, account.Type, account.CurrentOwner,
+					account.ShardID, account.TimestampMs,
+				)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !sp.isIndexEnabled(dataindexer.AccountsESDTHistoryIndex) {
+		return nil
+	}
+
+	return sp.saveAccountsHistory(tx, accountsESDTMap, shardID, timestampMs)
+}
+
+// saveAccountsHistory saves the balances in the history table, the EGLD balances having an empty token
+func (sp *sqlProcessor) saveAccountsHistory(tx *sqlTx, accountsMap map[string]*data.AccountInfo, shardID uint32, timestampMs uint64) error {
+	history := sp.accountsProc.PrepareAccountsHistory(accountsMap, shardID, timestampMs)
+
+	query := upsertQuery(accountsHistoryTable, accountsHistoryColumns, accountsHistoryColumns[:4], accountsHistoryColumns[4:], "")
+	for _, entry := range history {
+		err := tx.exec(query,
+			entry.Address, entry.Token, entry.TokenNonce, entry.TimestampMs, entry.Balance, entry.IsSender,
+			entry.IsSmartContract, entry.ShardID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
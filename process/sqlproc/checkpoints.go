@@ -0,0 +1,221 @@
+package sqlproc
+
+import (
+	"encoding/hex"
+
+	coreData "github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+)
+
+var shardBlockInfosColumns = []string{"id", "shard_id", "header_hash", "nonce", "round", "timestamp_ms"}
+
+// SavePendingCommit does nothing, as the SQL backend does not save commit records
+func (sp *sqlProcessor) SavePendingCommit(_ coreData.HeaderHandler, _ []byte) error {
+	return nil
+}
+
+// SaveCommit does nothing. The rows are not stamped with commit ids, so the SQL backend does not save commit records
+func (sp *sqlProcessor) SaveCommit(_ coreData.HeaderHandler, _ []byte, _ uint64) error {
+	return nil
+}
+
+// SaveCheckpoint will save the provided block as the last block that was successfully indexed for its shard. The
+// checkpoint only moves forward, a block with a lower nonce than the one of the checkpoint does not change it
+func (sp *sqlProcessor) SaveCheckpoint(header coreData.HeaderHandler, headerHash []byte, timestampMs uint64) error {
+	if !sp.isIndexEnabled(dataindexer.ValuesIndex) {
+		return nil
+	}
+
+	shardID := header.GetShardID()
+	query := upsertQuery(shardBlockInfosTable, shardBlockInfosColumns, []string{"id"}, shardBlockInfosColumns[1:],
+		shardBlockInfosTable+".nonce < excluded.nonce")
+
+	return sp.withTransaction(func(tx *sqlTx) error {
+		return tx.exec(query, elasticproc.CheckpointKey(shardID), shardID, hex.EncodeToString(headerHash), header.GetNonce(), header.GetRound(), timestampMs)
+	})
+}
+
+// RevertCheckpoint will move the checkpoint of the shard of the provided block to the previous block, if the checkpoint
+// points to the reverted block
+func (sp *sqlProcessor) RevertCheckpoint(header coreData.HeaderHandler, headerHash []byte) error {
+	if !sp.isIndexEnabled(dataindexer.ValuesIndex) || header.GetNonce() == 0 {
+		return nil
+	}
+
+	shardID := header.GetShardID()
+	previousHash := hex.EncodeToString(header.GetPrevHash())
+	return sp.withTransaction(func(tx *sqlTx) error {
+		var round, timestampMs uint64
+		rows, err := tx.query(`SELECT round, timestamp_ms FROM `+blocksTable+` WHERE hash = ?`, previousHash)
+		if err != nil {
+			return err
+		}
+		if rows.Next() {
+			err = rows.Scan(&round, &timestampMs)
+		}
+		errClose := rows.Close()
+		if err != nil {
+			return err
+		}
+		if errClose != nil {
+			return errClose
+		}
+
+		return tx.exec(`UPDATE `+shardBlockInfosTable+` SET header_hash = ?, nonce = ?, round = ?, timestamp_ms = ? WHERE id = ? AND header_hash = ?`,
+			previousHash, header.GetNonce()-1, round, timestampMs, elasticproc.CheckpointKey(shardID), hex.EncodeToString(headerHash))
+	})
+}
+
+// GetCheckpoints returns the last indexed block of every shard
+func (sp *sqlProcessor) GetCheckpoints() (map[uint32]*data.ShardBlockInfo, error) {
+	checkpoints := make(map[uint32]*data.ShardBlockInfo)
+	if !sp.isIndexEnabled(dataindexer.ValuesIndex) {
+		return checkpoints, nil
+	}
+
+	err := sp.withTransaction(func(tx *sqlTx) error {
+		rows, err := tx.query(`SELECT id, shard_id, header_hash, nonce, round, timestamp_ms FROM ` + shardBlockInfosTable)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		for rows.Next() {
+			checkpoint := &data.ShardBlockInfo{}
+			err = rows.Scan(&checkpoint.Key, &checkpoint.ShardID, &checkpoint.HeaderHash, &checkpoint.Nonce, &checkpoint.Round, &checkpoint.TimestampMs)
+			if err != nil {
+				return err
+			}
+			if checkpoint.Key == elasticproc.CheckpointKey(checkpoint.ShardID) {
+				checkpoints[checkpoint.ShardID] = checkpoint
+			}
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return checkpoints, nil
+}
+
+// SaveFinalizedBlock will mark the provided block as final and will move forward the finalized block watermark
+// of the block's shard
+func (sp *sqlProcessor) SaveFinalizedBlock(finalizedBlock *outport.FinalizedBlock) error {
+	if !sp.isIndexEnabled(dataindexer.BlockIndex) {
+		return nil
+	}
+
+	headerHash := hex.EncodeToString(finalizedBlock.HeaderHash)
+	return sp.withTransaction(func(tx *sqlTx) error {
+		rows, err := tx.query(`SELECT nonce, round, timestamp_ms FROM `+blocksTable+` WHERE hash = ?`, headerHash)
+		if err != nil {
+			return err
+		}
+
+		info := &data.ShardBlockInfo{
+			Key:        elasticproc.FinalizedBlockKey(finalizedBlock.ShardID),
+			ShardID:    finalizedBlock.ShardID,
+			HeaderHash: headerHash,
+		}
+		found := rows.Next()
+		if found {
+			err = rows.Scan(&info.Nonce, &info.Round, &info.TimestampMs)
+		}
+		errClose := rows.Close()
+		if err != nil {
+			return err
+		}
+		if errClose != nil {
+			return errClose
+		}
+		if !found {
+			log.Debug("sqlProcessor.SaveFinalizedBlock: block not indexed", "shardID", finalizedBlock.ShardID, "hash", headerHash)
+			return nil
+		}
+
+		err = tx.exec(`UPDATE `+blocksTable+` SET is_final = ? WHERE hash = ?`, true, headerHash)
+		if err != nil {
+			return err
+		}
+
+		if !sp.isIndexEnabled(dataindexer.ValuesIndex) {
+			return nil
+		}
+
+		query := upsertQuery(shardBlockInfosTable, shardBlockInfosColumns, []string{"id"}, shardBlockInfosColumns[1:],
+			shardBlockInfosTable+".nonce < excluded.nonce")
+		return tx.exec(query, info.Key, info.ShardID, info.HeaderHash, info.Nonce, info.Round, info.TimestampMs)
+	})
+}
+
+// SaveNonceGaps will remove the closed nonce gaps of a shard and will save the new ones
+func (sp *sqlProcessor) SaveNonceGaps(_ uint32, removedGaps []*data.NonceGap, addedGaps []*data.NonceGap) error {
+	if !sp.isIndexEnabled(dataindexer.NonceGapsIndex) {
+		return nil
+	}
+	if len(removedGaps) == 0 && len(addedGaps) == 0 {
+		return nil
+	}
+
+	columns := []string{"shard_id", "from_nonce", "to_nonce", "detected_at_ms"}
+	query := upsertQuery(nonceGapsTable, columns, columns[:3], columns[3:], "")
+
+	return sp.withTransaction(func(tx *sqlTx) error {
+		for _, gap := range removedGaps {
+			err := tx.exec(`DELETE FROM `+nonceGapsTable+` WHERE shard_id = ? AND from_nonce = ? AND to_nonce = ?`, gap.ShardID, gap.FromNonce, gap.ToNonce)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, gap := range addedGaps {
+			err := tx.exec(query, gap.ShardID, gap.FromNonce, gap.ToNonce, gap.DetectedAtMs)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetNonceGaps returns all the saved nonce gaps
+func (sp *sqlProcessor) GetNonceGaps() ([]*data.NonceGap, error) {
+	gaps := make([]*data.NonceGap, 0)
+	if !sp.isIndexEnabled(dataindexer.NonceGapsIndex) {
+		return gaps, nil
+	}
+
+	err := sp.withTransaction(func(tx *sqlTx) error {
+		rows, err := tx.query(`SELECT shard_id, from_nonce, to_nonce, detected_at_ms FROM ` + nonceGapsTable + ` ORDER BY shard_id, from_nonce`)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		for rows.Next() {
+			gap := &data.NonceGap{}
+			err = rows.Scan(&gap.ShardID, &gap.FromNonce, &gap.ToNonce, &gap.DetectedAtMs)
+			if err != nil {
+				return err
+			}
+			gaps = append(gaps, gap)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return gaps, nil
+}
@@ -0,0 +1,72 @@
+package sqlproc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PostgresDialect is the name of the PostgreSQL dialect
+	PostgresDialect = "postgres"
+	// SQLiteDialect is the name of the SQLite dialect
+	SQLiteDialect = "sqlite"
+)
+
+// dialect holds the differences between the supported databases. The queries are written with "?" placeholders and
+// the types from the schema are shared, except for the binary columns
+type dialect struct {
+	name             string
+	blobType         string
+	numberedBindVars bool
+}
+
+func newDialect(name string) (*dialect, error) {
+	switch name {
+	case PostgresDialect:
+		return &dialect{
+			name:             PostgresDialect,
+			blobType:         "BYTEA",
+			numberedBindVars: true,
+		}, nil
+	case SQLiteDialect:
+		return &dialect{
+			name:     SQLiteDialect,
+			blobType: "BLOB",
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDialect, name)
+	}
+}
+
+// rebind replaces the "?" placeholders of the query with "$1", "$2"... for the databases that need numbered placeholders
+func (d *dialect) rebind(query string) string {
+	if !d.numberedBindVars {
+		return query
+	}
+
+	builder := strings.Builder{}
+	builder.Grow(len(query) + 16)
+	position := 0
+	for _, char := range query {
+		if char != '?' {
+			builder.WriteRune(char)
+			continue
+		}
+
+		position++
+		builder.WriteByte('$')
+		builder.WriteString(strconv.Itoa(position))
+	}
+
+	return builder.String()
+}
+
+// placeholders returns "?, ?, ..." with the provided number of placeholders
+func placeholders(count int) string {
+	if count <= 0 {
+		return ""
+	}
+
+	return strings.Repeat("?, ", count-1) + "?"
+}
@@ -0,0 +1,9 @@
+package sqlproc
+
+import "errors"
+
+// ErrNilDatabase signals that a nil database handle has been provided
+var ErrNilDatabase = errors.New("nil database")
+
+// ErrInvalidDialect signals that an unknown SQL dialect has been provided
+var ErrInvalidDialect = errors.New("invalid SQL dialect")
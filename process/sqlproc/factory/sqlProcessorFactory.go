@@ -0,0 +1,107 @@
+package factory
+
+import (
+	"context"
+
+	"database/sql"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/hashing"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-es-indexer-go/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/accounts"
+	blockProc "github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/block"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/logsevents"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/miniblocks"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/transactions"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/sqlproc"
+)
+
+// ArgSQLProcessorFactory holds all the components that are needed to create a SQL processor
+type ArgSQLProcessorFactory struct {
+	Context                  context.Context
+	DB                       *sql.DB
+	Dialect                  string
+	Marshalizer              marshal.Marshalizer
+	Hasher                   hashing.Hasher
+	AddressPubkeyConverter   core.PubkeyConverter
+	ValidatorPubkeyConverter core.PubkeyConverter
+	EnabledIndexes           []string
+	Version                  string
+	Denomination             int
+	ImportDB                 bool
+	EnableEpochsConfig       config.EnableEpochsConfig
+}
+
+// CreateSQLProcessor will create a new instance of the SQL processor, with the same data processors as the elastic processor
+func CreateSQLProcessor(arguments ArgSQLProcessorFactory) (dataindexer.ElasticProcessor, error) {
+	enabledIndexesMap := make(map[string]struct{})
+	for _, index := range arguments.EnabledIndexes {
+		enabledIndexesMap[index] = struct{}{}
+	}
+	if len(enabledIndexesMap) == 0 {
+		return nil, dataindexer.ErrEmptyEnabledIndexes
+	}
+
+	balanceConverter, err := converters.NewBalanceConverter(arguments.Denomination)
+	if err != nil {
+		return nil, err
+	}
+
+	accountsProc, err := accounts.NewAccountsProcessor(
+		arguments.AddressPubkeyConverter,
+		balanceConverter,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	blockProcHandler, err := blockProc.NewBlockProcessor(arguments.Hasher, arguments.Marshalizer, arguments.ValidatorPubkeyConverter)
+	if err != nil {
+		return nil, err
+	}
+
+	miniblocksProc, err := miniblocks.NewMiniblocksProcessor(arguments.Hasher, arguments.Marshalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	argsTxsProc := &transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: arguments.AddressPubkeyConverter,
+		Hasher:                 arguments.Hasher,
+		Marshalizer:            arguments.Marshalizer,
+		BalanceConverter:       balanceConverter,
+		EnableEpochsConfig:     arguments.EnableEpochsConfig,
+	}
+	txsProc, err := transactions.NewTransactionsProcessor(argsTxsProc)
+	if err != nil {
+		return nil, err
+	}
+
+	argsLogsAndEventsProc := logsevents.ArgsLogsAndEventsProcessor{
+		PubKeyConverter:  arguments.AddressPubkeyConverter,
+		Marshalizer:      arguments.Marshalizer,
+		BalanceConverter: balanceConverter,
+		Hasher:           arguments.Hasher,
+	}
+	logsAndEventsProc, err := logsevents.NewLogsAndEventsProcessor(argsLogsAndEventsProc)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlproc.NewSQLProcessor(sqlproc.ArgsSQLProcessor{
+		Context:           arguments.Context,
+		DB:                arguments.DB,
+		Dialect:           arguments.Dialect,
+		ImportDB:          arguments.ImportDB,
+		EnabledIndexes:    enabledIndexesMap,
+		TransactionsProc:  txsProc,
+		AccountsProc:      accountsProc,
+		BlockProc:         blockProcHandler,
+		MiniblocksProc:    miniblocksProc,
+		LogsAndEventsProc: logsAndEventsProc,
+		Version:           arguments.Version,
+	})
+}
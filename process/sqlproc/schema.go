@@ -0,0 +1,265 @@
+package sqlproc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const (
+	blocksTable            = "blocks"
+	miniblocksTable        = "miniblocks"
+	transactionsTable      = "transactions"
+	scResultsTable         = "sc_results"
+	receiptsTable          = "receipts"
+	tokenTransfersTable    = "token_transfers"
+	logsTable              = "logs"
+	eventsTable            = "events"
+	accountsTable          = "accounts"
+	accountsESDTTable      = "accounts_esdt"
+	accountsHistoryTable   = "accounts_history"
+	tokensTable            = "tokens"
+	shardBlockInfosTable   = "shard_block_infos"
+	nonceGapsTable         = "nonce_gaps"
+	indexerInfoTable       = "indexer_info"
+	indexerVersionInfoName = "indexer-version"
+)
+
+// schemaStatements returns the statements that create the tables and the indexes, if they do not exist. The big
+// numbers (values, fees and balances) are stored as decimal strings, like in the Elasticsearch documents, next to
+// their approximate numeric value
+func schemaStatements(d *dialect) []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS ` + blocksTable + ` (
+			hash TEXT PRIMARY KEY,
+			nonce BIGINT NOT NULL,
+			round BIGINT NOT NULL,
+			epoch BIGINT NOT NULL,
+			shard_id BIGINT NOT NULL,
+			proposer BIGINT NOT NULL,
+			proposer_bls_key TEXT,
+			tx_count BIGINT NOT NULL,
+			notarized_txs_count BIGINT NOT NULL,
+			size BIGINT NOT NULL,
+			size_txs BIGINT NOT NULL,
+			timestamp_ms BIGINT NOT NULL,
+			prev_hash TEXT,
+			state_root_hash TEXT,
+			accumulated_fees TEXT,
+			developer_fees TEXT,
+			epoch_start_block BOOLEAN NOT NULL,
+			gas_provided BIGINT NOT NULL,
+			gas_refunded BIGINT NOT NULL,
+			gas_penalized BIGINT NOT NULL,
+			max_gas_limit BIGINT NOT NULL,
+			is_final BOOLEAN NOT NULL DEFAULT FALSE
+		)`,
+		`CREATE INDEX IF NOT EXISTS blocks_shard_nonce_idx ON ` + blocksTable + ` (shard_id, nonce)`,
+		`CREATE TABLE IF NOT EXISTS ` + miniblocksTable + ` (
+			hash TEXT PRIMARY KEY,
+			sender_shard BIGINT NOT NULL,
+			receiver_shard BIGINT NOT NULL,
+			sender_block_hash TEXT,
+			receiver_block_hash TEXT,
+			type TEXT NOT NULL,
+			processing_type_on_source TEXT,
+			processing_type_on_destination TEXT,
+			timestamp_ms BIGINT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS ` + transactionsTable + ` (
+			hash TEXT PRIMARY KEY,
+			miniblock_hash TEXT,
+			nonce BIGINT NOT NULL,
+			round BIGINT NOT NULL,
+			epoch BIGINT NOT NULL,
+			value TEXT,
+			value_num DOUBLE PRECISION,
+			sender TEXT NOT NULL,
+			receiver TEXT NOT NULL,
+			sender_shard BIGINT NOT NULL,
+			receiver_shard BIGINT NOT NULL,
+			gas_price BIGINT NOT NULL,
+			gas_limit BIGINT NOT NULL,
+			gas_used BIGINT NOT NULL,
+			fee TEXT,
+			fee_num DOUBLE PRECISION,
+			initial_paid_fee TEXT,
+			had_refund BOOLEAN NOT NULL DEFAULT FALSE,
+			data ` + d.blobType + `,
+			signature TEXT,
+			timestamp_ms BIGINT NOT NULL,
+			status TEXT,
+			type TEXT,
+			operation TEXT,
+			function TEXT,
+			version BIGINT NOT NULL,
+			is_sc_call BOOLEAN NOT NULL,
+			is_relayed BOOLEAN NOT NULL,
+			relayer TEXT,
+			guardian TEXT,
+			has_sc_results BOOLEAN NOT NULL,
+			has_logs BOOLEAN NOT NULL,
+			error_event BOOLEAN NOT NULL,
+			completed_event BOOLEAN NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS transactions_sender_idx ON ` + transactionsTable + ` (sender, timestamp_ms)`,
+		`CREATE INDEX IF NOT EXISTS transactions_receiver_idx ON ` + transactionsTable + ` (receiver, timestamp_ms)`,
+		`CREATE INDEX IF NOT EXISTS transactions_miniblock_idx ON ` + transactionsTable + ` (miniblock_hash)`,
+		`CREATE TABLE IF NOT EXISTS ` + scResultsTable + ` (
+			hash TEXT PRIMARY KEY,
+			miniblock_hash TEXT,
+			nonce BIGINT NOT NULL,
+			epoch BIGINT NOT NULL,
+			value TEXT,
+			value_num DOUBLE PRECISION,
+			sender TEXT NOT NULL,
+			receiver TEXT NOT NULL,
+			sender_shard BIGINT NOT NULL,
+			receiver_shard BIGINT NOT NULL,
+			gas_price BIGINT NOT NULL,
+			gas_limit BIGINT NOT NULL,
+			data ` + d.blobType + `,
+			prev_tx_hash TEXT,
+			original_tx_hash TEXT,
+			original_sender TEXT,
+			relayer TEXT,
+			relayed_value TEXT,
+			call_type TEXT,
+			return_message TEXT,
+			timestamp_ms BIGINT NOT NULL,
+			status TEXT,
+			type TEXT,
+			operation TEXT,
+			function TEXT,
+			is_relayed BOOLEAN NOT NULL,
+			can_be_ignored BOOLEAN NOT NULL,
+			has_logs BOOLEAN NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS sc_results_original_tx_idx ON ` + scResultsTable + ` (original_tx_hash)`,
+		`CREATE TABLE IF NOT EXISTS ` + receiptsTable + ` (
+			hash TEXT PRIMARY KEY,
+			tx_hash TEXT NOT NULL,
+			value TEXT,
+			sender TEXT,
+			data TEXT,
+			timestamp_ms BIGINT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS ` + tokenTransfersTable + ` (
+			hash TEXT NOT NULL,
+			position BIGINT NOT NULL,
+			token TEXT NOT NULL,
+			value TEXT,
+			value_num DOUBLE PRECISION,
+			receiver TEXT,
+			PRIMARY KEY (hash, position)
+		)`,
+		`CREATE INDEX IF NOT EXISTS token_transfers_token_idx ON ` + tokenTransfersTable + ` (token)`,
+		`CREATE TABLE IF NOT EXISTS ` + logsTable + ` (
+			id TEXT PRIMARY KEY,
+			original_tx_hash TEXT,
+			address TEXT,
+			timestamp_ms BIGINT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS ` + eventsTable + ` (
+			id TEXT PRIMARY KEY,
+			tx_hash TEXT NOT NULL,
+			original_tx_hash TEXT,
+			log_address TEXT,
+			address TEXT,
+			identifier TEXT,
+			data TEXT,
+			additional_data TEXT,
+			topics TEXT,
+			event_order BIGINT NOT NULL,
+			tx_order BIGINT NOT NULL,
+			shard_id BIGINT NOT NULL,
+			timestamp_ms BIGINT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS events_tx_hash_idx ON ` + eventsTable + ` (tx_hash)`,
+		`CREATE INDEX IF NOT EXISTS events_shard_timestamp_idx ON ` + eventsTable + ` (shard_id, timestamp_ms)`,
+		`CREATE TABLE IF NOT EXISTS ` + accountsTable + ` (
+			address TEXT PRIMARY KEY,
+			nonce BIGINT NOT NULL,
+			balance TEXT,
+			balance_num DOUBLE PRECISION,
+			user_name TEXT,
+			owner_address TEXT,
+			developer_rewards TEXT,
+			shard_id BIGINT NOT NULL,
+			timestamp_ms BIGINT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS ` + accountsESDTTable + ` (
+			address TEXT NOT NULL,
+			token TEXT NOT NULL,
+			token_nonce BIGINT NOT NULL,
+			identifier TEXT NOT NULL,
+			balance TEXT,
+			balance_num DOUBLE PRECISION,
+			properties TEXT,
+			frozen BOOLEAN NOT NULL,
+			type TEXT,
+			current_owner TEXT,
+			shard_id BIGINT NOT NULL,
+			timestamp_ms BIGINT NOT NULL,
+			PRIMARY KEY (address, token, token_nonce)
+		)`,
+		`CREATE INDEX IF NOT EXISTS accounts_esdt_token_idx ON ` + accountsESDTTable + ` (token)`,
+		`CREATE INDEX IF NOT EXISTS accounts_esdt_shard_timestamp_idx ON ` + accountsESDTTable + ` (shard_id, timestamp_ms)`,
+		`CREATE TABLE IF NOT EXISTS ` + accountsHistoryTable + ` (
+			address TEXT NOT NULL,
+			token TEXT NOT NULL,
+			token_nonce BIGINT NOT NULL,
+			timestamp_ms BIGINT NOT NULL,
+			balance TEXT,
+			is_sender BOOLEAN NOT NULL,
+			is_smart_contract BOOLEAN NOT NULL,
+			shard_id BIGINT NOT NULL,
+			PRIMARY KEY (address, token, token_nonce, timestamp_ms)
+		)`,
+		`CREATE INDEX IF NOT EXISTS accounts_history_shard_timestamp_idx ON ` + accountsHistoryTable + ` (shard_id, timestamp_ms)`,
+		`CREATE TABLE IF NOT EXISTS ` + tokensTable + ` (
+			identifier TEXT PRIMARY KEY,
+			token TEXT NOT NULL,
+			name TEXT,
+			ticker TEXT,
+			issuer TEXT,
+			current_owner TEXT,
+			creator TEXT,
+			num_decimals BIGINT NOT NULL,
+			type TEXT,
+			nonce BIGINT NOT NULL,
+			timestamp_ms BIGINT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS tokens_token_idx ON ` + tokensTable + ` (token)`,
+		`CREATE TABLE IF NOT EXISTS ` + shardBlockInfosTable + ` (
+			id TEXT PRIMARY KEY,
+			shard_id BIGINT NOT NULL,
+			header_hash TEXT NOT NULL,
+			nonce BIGINT NOT NULL,
+			round BIGINT NOT NULL,
+			timestamp_ms BIGINT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS ` + nonceGapsTable + ` (
+			shard_id BIGINT NOT NULL,
+			from_nonce BIGINT NOT NULL,
+			to_nonce BIGINT NOT NULL,
+			detected_at_ms BIGINT NOT NULL,
+			PRIMARY KEY (shard_id, from_nonce, to_nonce)
+		)`,
+		`CREATE TABLE IF NOT EXISTS ` + indexerInfoTable + ` (
+			name TEXT PRIMARY KEY,
+			value TEXT
+		)`,
+	}
+}
+
+func createSchema(ctx context.Context, db *sql.DB, d *dialect) error {
+	for _, statement := range schemaStatements(d) {
+		_, err := db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("%w while creating the schema", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,421 @@
+package sqlproc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	coreData "github.com/multiversx/mx-chain-core-go/data"
+	"github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc"
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+var log = logger.GetOrCreate("indexer/process/sqlproc")
+
+// maxValuesInClause is the maximum number of values of an "IN (...)" clause, well below the bind variables limits
+const maxValuesInClause = 500
+
+// ArgsSQLProcessor holds all dependencies required by the sqlProcessor in order to create new instances
+type ArgsSQLProcessor struct {
+	Context           context.Context
+	DB                *sql.DB
+	Dialect           string
+	ImportDB          bool
+	EnabledIndexes    map[string]struct{}
+	TransactionsProc  elasticproc.DBTransactionsHandler
+	AccountsProc      elasticproc.DBAccountHandler
+	BlockProc         elasticproc.DBBlockHandler
+	MiniblocksProc    elasticproc.DBMiniblocksHandler
+	LogsAndEventsProc elasticproc.DBLogsAndEventsHandler
+	Version           string
+}
+
+type sqlProcessor struct {
+	ctx               context.Context
+	db                *sql.DB
+	dialect           *dialect
+	enabledIndexes    map[string]struct{}
+	transactionsProc  elasticproc.DBTransactionsHandler
+	accountsProc      elasticproc.DBAccountHandler
+	blockProc         elasticproc.DBBlockHandler
+	miniblocksProc    elasticproc.DBMiniblocksHandler
+	logsAndEventsProc elasticproc.DBLogsAndEventsHandler
+
+	mutex    sync.RWMutex
+	importDB bool
+}
+
+// NewSQLProcessor will create a processor that stores the indexed data in the normalized tables of a relational
+// database. The data is prepared by the same processors as for Elasticsearch, and the tables are created if they
+// do not exist. The data of each call is written in a single database transaction, which is rolled back when the
+// provided context is cancelled. A nil context is never cancelled
+func NewSQLProcessor(args ArgsSQLProcessor) (*sqlProcessor, error) {
+	err := checkArguments(args)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := newDialect(args.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := args.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	sp := &sqlProcessor{
+		ctx:               ctx,
+		db:                args.DB,
+		dialect:           d,
+		enabledIndexes:    args.EnabledIndexes,
+		transactionsProc:  args.TransactionsProc,
+		accountsProc:      args.AccountsProc,
+		blockProc:         args.BlockProc,
+		miniblocksProc:    args.MiniblocksProc,
+		logsAndEventsProc: args.LogsAndEventsProc,
+		importDB:          args.ImportDB,
+	}
+
+	err = createSchema(ctx, args.DB, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sp.saveVersion(args.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return sp, nil
+}
+
+func checkArguments(args ArgsSQLProcessor) error {
+	if args.DB == nil {
+		return ErrNilDatabase
+	}
+	if args.EnabledIndexes == nil {
+		return dataindexer.ErrNilEnabledIndexesMap
+	}
+	if check.IfNilReflect(args.TransactionsProc) {
+		return dataindexer.ErrNilTransactionsHandler
+	}
+	if check.IfNilReflect(args.AccountsProc) {
+		return dataindexer.ErrNilAccountsHandler
+	}
+	if check.IfNilReflect(args.BlockProc) {
+		return dataindexer.ErrNilBlockHandler
+	}
+	if check.IfNilReflect(args.MiniblocksProc) {
+		return dataindexer.ErrNilMiniblocksHandler
+	}
+	if check.IfNilReflect(args.LogsAndEventsProc) {
+		return dataindexer.ErrNilLogsAndEventsHandler
+	}
+
+	return nil
+}
+
+func (sp *sqlProcessor) saveVersion(version string) error {
+	if version == "" {
+		log.Debug("sqlProcessor: indexer version is empty")
+		return nil
+	}
+
+	return sp.withTransaction(func(tx *sqlTx) error {
+		return tx.exec(upsertQuery(indexerInfoTable, []string{"name", "value"}, []string{"name"}, []string{"value"}, ""),
+			indexerVersionInfoName, version)
+	})
+}
+
+// SaveHeader will prepare and save the block
+func (sp *sqlProcessor) SaveHeader(outportBlockWithHeader *outport.OutportBlockWithHeader) error {
+	if !sp.isIndexEnabled(dataindexer.BlockIndex) {
+		return nil
+	}
+
+	dbBlock, err := sp.blockProc.PrepareBlockForDB(outportBlockWithHeader)
+	if err != nil {
+		return err
+	}
+
+	columns := []string{
+		"hash", "nonce", "round", "epoch", "shard_id", "proposer", "proposer_bls_key", "tx_count", "notarized_txs_count",
+		"size", "size_txs", "timestamp_ms", "prev_hash", "state_root_hash", "accumulated_fees", "developer_fees",
+		"epoch_start_block", "gas_provided", "gas_refunded", "gas_penalized", "max_gas_limit",
+	}
+	query := upsertQuery(blocksTable, columns, []string{"hash"}, columns[1:], "")
+
+	return sp.withTransaction(func(tx *sqlTx) error {
+		return tx.exec(query,
+			dbBlock.Hash, dbBlock.Nonce, dbBlock.Round, dbBlock.Epoch, dbBlock.ShardID, dbBlock.Proposer, dbBlock.ProposerBlsKey,
+			dbBlock.TxCount, dbBlock.NotarizedTxsCount, dbBlock.Size, dbBlock.SizeTxs, dbBlock.TimestampMs, dbBlock.PrevHash,
+			dbBlock.StateRootHash, dbBlock.AccumulatedFees, dbBlock.DeveloperFees, dbBlock.EpochStartBlock, dbBlock.GasProvided,
+			dbBlock.GasRefunded, dbBlock.GasPenalized, dbBlock.MaxGasLimit,
+		)
+	})
+}
+
+// RemoveHeader will remove the block
+func (sp *sqlProcessor) RemoveHeader(header coreData.HeaderHandler) error {
+	headerHash, err := sp.blockProc.ComputeHeaderHash(header)
+	if err != nil {
+		return err
+	}
+
+	return sp.withTransaction(func(tx *sqlTx) error {
+		return tx.deleteIn(blocksTable, "hash", []string{hex.EncodeToString(headerHash)})
+	})
+}
+
+// RemoveMiniblocks will remove all the miniblocks of the block
+func (sp *sqlProcessor) RemoveMiniblocks(header coreData.HeaderHandler, body *block.Body) error {
+	encodedMiniblocksHashes := sp.miniblocksProc.GetMiniblocksHashesHexEncoded(header, body)
+	if len(encodedMiniblocksHashes) == 0 {
+		return nil
+	}
+
+	return sp.withTransaction(func(tx *sqlTx) error {
+		return tx.deleteIn(miniblocksTable, "hash", encodedMiniblocksHashes)
+	})
+}
+
+// RemoveTransactions will remove the transactions and the smart contract results of the block, together with their
+// receipts, token transfers and logs, and the events of the block
+func (sp *sqlProcessor) RemoveTransactions(header coreData.HeaderHandler, body *block.Body, timestampMs uint64) error {
+	encodedTxsHashes, encodedScrsHashes := sp.transactionsProc.GetHexEncodedHashesForRemove(header, body)
+	allHashes := append(append(make([]string, 0, len(encodedTxsHashes)+len(encodedScrsHashes)), encodedTxsHashes...), encodedScrsHashes...)
+
+	return sp.withTransaction(func(tx *sqlTx) error {
+		err := tx.deleteIn(transactionsTable, "hash", encodedTxsHashes)
+		if err != nil {
+			return err
+		}
+
+		err = tx.deleteIn(receiptsTable, "tx_hash", encodedTxsHashes)
+		if err != nil {
+			return err
+		}
+
+		err = tx.deleteIn(scResultsTable, "hash", encodedScrsHashes)
+		if err != nil {
+			return err
+		}
+
+		err = tx.deleteIn(tokenTransfersTable, "hash", allHashes)
+		if err != nil {
+			return err
+		}
+
+		err = tx.deleteIn(logsTable, "id", allHashes)
+		if err != nil {
+			return err
+		}
+
+		return tx.exec(`DELETE FROM `+eventsTable+` WHERE shard_id = ? AND timestamp_ms = ?`, header.GetShardID(), timestampMs)
+	})
+}
+
+// RemoveAccountsESDT will remove the ESDT balances and the ESDT balances history written at the provided timestamp
+func (sp *sqlProcessor) RemoveAccountsESDT(shardID uint32, timestampMs uint64) error {
+	return sp.withTransaction(func(tx *sqlTx) error {
+		err := tx.exec(`DELETE FROM `+accountsESDTTable+` WHERE shard_id = ? AND timestamp_ms = ?`, shardID, timestampMs)
+		if err != nil {
+			return err
+		}
+
+		return tx.exec(`DELETE FROM `+accountsHistoryTable+` WHERE shard_id = ? AND timestamp_ms = ? AND token <> ''`, shardID, timestampMs)
+	})
+}
+
+// SaveMiniblocks will prepare and save the miniblocks. A cross-shard miniblock is saved by both shards, each of them
+// setting its own block hash and processing type
+func (sp *sqlProcessor) SaveMiniblocks(header coreData.HeaderHandler, miniBlocks []*block.MiniBlock, timestampMS uint64) error {
+	if !sp.isIndexEnabled(dataindexer.MiniblocksIndex) {
+		return nil
+	}
+
+	mbs := sp.miniblocksProc.PrepareDBMiniblocks(header, miniBlocks, timestampMS)
+	if len(mbs) == 0 {
+		return nil
+	}
+
+	columns := []string{
+		"hash", "sender_shard", "receiver_shard", "sender_block_hash", "receiver_block_hash", "type",
+		"processing_type_on_source", "processing_type_on_destination", "timestamp_ms",
+	}
+	querySource := upsertQuery(miniblocksTable, columns, []string{"hash"}, []string{"sender_block_hash", "processing_type_on_source"}, "")
+	queryDestination := upsertQuery(miniblocksTable, columns, []string{"hash"}, []string{"receiver_block_hash", "processing_type_on_destination"}, "")
+
+	shardID := header.GetShardID()
+	return sp.withTransaction(func(tx *sqlTx) error {
+		for _, mb := range mbs {
+			query := queryDestination
+			notProcessedOnSource := shardID == mb.SenderShardID && mb.ProcessingTypeOnDestination != block.Processed.String()
+			if notProcessedOnSource {
+				query = querySource
+			}
+
+			err := tx.exec(query,
+				mb.Hash, mb.SenderShardID, mb.ReceiverShardID, mb.SenderBlockHash, mb.ReceiverBlockHash, mb.Type,
+				mb.ProcessingTypeOnSource, mb.ProcessingTypeOnDestination, mb.TimestampMs,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// SaveValidatorsRating does nothing, the validators ratings are not stored by the SQL backend
+func (sp *sqlProcessor) SaveValidatorsRating(_ *outport.ValidatorsRating) error {
+	return nil
+}
+
+// SaveRoundsInfo does nothing, the rounds are not stored by the SQL backend
+func (sp *sqlProcessor) SaveRoundsInfo(_ *outport.RoundsInfo) error {
+	return nil
+}
+
+// SaveShardValidatorsPubKeys does nothing, the validators public keys are not stored by the SQL backend
+func (sp *sqlProcessor) SaveShardValidatorsPubKeys(_ *outport.ValidatorsPubKeys) error {
+	return nil
+}
+
+// SetOutportConfig will set the outport config
+func (sp *sqlProcessor) SetOutportConfig(cfg outport.OutportConfig) error {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	sp.importDB = cfg.IsInImportDBMode
+
+	return nil
+}
+
+func (sp *sqlProcessor) isImportDB() bool {
+	sp.mutex.RLock()
+	defer sp.mutex.RUnlock()
+
+	return sp.importDB
+}
+
+func (sp *sqlProcessor) isIndexEnabled(index string) bool {
+	_, isEnabled := sp.enabledIndexes[index]
+	return isEnabled
+}
+
+// withTransaction runs the handler in a database transaction, which is committed only if the handler succeeds
+func (sp *sqlProcessor) withTransaction(handler func(tx *sqlTx) error) error {
+	dbTx, err := sp.db.BeginTx(sp.ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = handler(&sqlTx{ctx: sp.ctx, tx: dbTx, dialect: sp.dialect})
+	if err != nil {
+		errRollback := dbTx.Rollback()
+		if errRollback != nil {
+			log.Warn("sqlProcessor: cannot roll back the transaction", "error", errRollback)
+		}
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+// Close will close the database
+func (sp *sqlProcessor) Close() error {
+	return sp.db.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sp *sqlProcessor) IsInterfaceNil() bool {
+	return sp == nil
+}
+
+type sqlTx struct {
+	ctx     context.Context
+	tx      *sql.Tx
+	dialect *dialect
+}
+
+func (st *sqlTx) exec(query string, args ...interface{}) error {
+	_, err := st.tx.ExecContext(st.ctx, st.dialect.rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("%w while executing %s", err, firstWords(query))
+	}
+
+	return nil
+}
+
+func (st *sqlTx) query(query string, args ...interface{}) (*sql.Rows, error) {
+	return st.tx.QueryContext(st.ctx, st.dialect.rebind(query), args...)
+}
+
+// deleteIn deletes the rows whose column holds one of the provided values
+func (st *sqlTx) deleteIn(table string, column string, values []string) error {
+	return forEachChunk(values, func(chunk []interface{}) error {
+		return st.exec(fmt.Sprintf(`DELETE FROM %s WHERE %s IN (%s)`, table, column, placeholders(len(chunk))), chunk...)
+	})
+}
+
+func forEachChunk(values []string, handler func(chunk []interface{}) error) error {
+	for start := 0; start < len(values); start += maxValuesInClause {
+		end := start + maxValuesInClause
+		if end > len(values) {
+			end = len(values)
+		}
+
+		chunk := make([]interface{}, 0, end-start)
+		for _, value := range values[start:end] {
+			chunk = append(chunk, value)
+		}
+
+		err := handler(chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// upsertQuery returns an insert statement that updates the provided columns when a row with the same key exists,
+// only if the optional condition holds. Without any column to update, the existing row is kept
+func upsertQuery(table string, columns []string, keyColumns []string, updateColumns []string, condition string) string {
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO `,
+		table, strings.Join(columns, ", "), placeholders(len(columns)), strings.Join(keyColumns, ", "))
+	if len(updateColumns) == 0 {
+		return query + "NOTHING"
+	}
+
+	assignments := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		assignments = append(assignments, fmt.Sprintf("%s = excluded.%s", column, column))
+	}
+	query += "UPDATE SET " + strings.Join(assignments, ", ")
+	if condition != "" {
+		query += " WHERE " + condition
+	}
+
+	return query
+}
+
+func firstWords(query string) string {
+	words := strings.Fields(query)
+	if len(words) > 3 {
+		words = words[:3]
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,498 @@
+package sqlproc
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	dataBlock "github.com/multiversx/mx-chain-core-go/data/block"
+	"github.com/multiversx/mx-chain-core-go/data/esdt"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/accounts"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/block"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/logsevents"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/miniblocks"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/transactions"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+const testNumOfShards = 3
+
+func openTestDatabase(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "indexer.db"))
+	require.Nil(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return db
+}
+
+func createMockSQLProcessorArgs(db *sql.DB) ArgsSQLProcessor {
+	balanceConverter, _ := converters.NewBalanceConverter(18)
+	pubKeyConverter := mock.NewPubkeyConverterMock(32)
+
+	acp, _ := accounts.NewAccountsProcessor(pubKeyConverter, balanceConverter)
+	bp, _ := block.NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{}, pubKeyConverter)
+	mp, _ := miniblocks.NewMiniblocksProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
+	tp, _ := transactions.NewTransactionsProcessor(&transactions.ArgsTransactionProcessor{
+		AddressPubkeyConverter: pubKeyConverter,
+		Hasher:                 &mock.HasherMock{},
+		Marshalizer:            &mock.MarshalizerMock{},
+		BalanceConverter:       balanceConverter,
+	})
+	lp, _ := logsevents.NewLogsAndEventsProcessor(logsevents.ArgsLogsAndEventsProcessor{
+		PubKeyConverter:  pubKeyConverter,
+		Marshalizer:      &mock.MarshalizerMock{},
+		BalanceConverter: balanceConverter,
+		Hasher:           &mock.HasherMock{},
+	})
+
+	enabledIndexes := make(map[string]struct{})
+	for _, index := range []string{
+		dataindexer.BlockIndex, dataindexer.MiniblocksIndex, dataindexer.TransactionsIndex, dataindexer.ScResultsIndex,
+		dataindexer.ReceiptsIndex, dataindexer.LogsIndex, dataindexer.EventsIndex, dataindexer.AccountsIndex,
+		dataindexer.AccountsHistoryIndex, dataindexer.AccountsESDTIndex, dataindexer.AccountsESDTHistoryIndex,
+		dataindexer.TokensIndex, dataindexer.ValuesIndex, dataindexer.NonceGapsIndex,
+	} {
+		enabledIndexes[index] = struct{}{}
+	}
+
+	return ArgsSQLProcessor{
+		DB:                db,
+		Dialect:           SQLiteDialect,
+		EnabledIndexes:    enabledIndexes,
+		TransactionsProc:  tp,
+		AccountsProc:      acp,
+		BlockProc:         bp,
+		MiniblocksProc:    mp,
+		LogsAndEventsProc: lp,
+		Version:           "v1.0.0",
+	}
+}
+
+func createOutportBlockWithHeader(
+	body *dataBlock.Body,
+	header *dataBlock.Header,
+	pool *outport.TransactionPool,
+	coreAlteredAccounts map[string]*alteredAccount.AlteredAccount,
+) *outport.OutportBlockWithHeader {
+	return &outport.OutportBlockWithHeader{
+		OutportBlock: &outport.OutportBlock{
+			BlockData: &outport.BlockData{
+				Body:        body,
+				TimestampMs: header.GetTimeStamp() * 1000,
+			},
+			TransactionPool:      pool,
+			AlteredAccounts:      coreAlteredAccounts,
+			NumberOfShards:       testNumOfShards,
+			ShardID:              header.GetShardID(),
+			HeaderGasConsumption: &outport.HeaderGasConsumption{},
+		},
+		Header: header,
+	}
+}
+
+func countRows(t *testing.T, db *sql.DB, query string, args ...interface{}) int {
+	count := 0
+	err := db.QueryRow(query, args...).Scan(&count)
+	require.Nil(t, err)
+
+	return count
+}
+
+func TestNewSQLProcessor(t *testing.T) {
+	t.Parallel()
+
+	db := openTestDatabase(t)
+
+	args := createMockSQLProcessorArgs(db)
+	args.DB = nil
+	sp, err := NewSQLProcessor(args)
+	require.Nil(t, sp)
+	require.Equal(t, ErrNilDatabase, err)
+
+	args = createMockSQLProcessorArgs(db)
+	args.EnabledIndexes = nil
+	_, err = NewSQLProcessor(args)
+	require.Equal(t, dataindexer.ErrNilEnabledIndexesMap, err)
+
+	args = createMockSQLProcessorArgs(db)
+	args.TransactionsProc = nil
+	_, err = NewSQLProcessor(args)
+	require.Equal(t, dataindexer.ErrNilTransactionsHandler, err)
+
+	args = createMockSQLProcessorArgs(db)
+	args.LogsAndEventsProc = nil
+	_, err = NewSQLProcessor(args)
+	require.Equal(t, dataindexer.ErrNilLogsAndEventsHandler, err)
+
+	args = createMockSQLProcessorArgs(db)
+	args.Dialect = "oracle"
+	_, err = NewSQLProcessor(args)
+	require.True(t, errors.Is(err, ErrInvalidDialect))
+
+	sp, err = NewSQLProcessor(createMockSQLProcessorArgs(db))
+	require.Nil(t, err)
+	require.False(t, sp.IsInterfaceNil())
+
+	// the schema is created only once
+	_, err = NewSQLProcessor(createMockSQLProcessorArgs(db))
+	require.Nil(t, err)
+
+	version := ""
+	err = db.QueryRow(`SELECT value FROM indexer_info WHERE name = ?`, indexerVersionInfoName).Scan(&version)
+	require.Nil(t, err)
+	require.Equal(t, "v1.0.0", version)
+}
+
+func TestDialect_Rebind(t *testing.T) {
+	t.Parallel()
+
+	d, _ := newDialect(PostgresDialect)
+	require.Equal(t, "UPDATE t SET a = $1 WHERE b = $2", d.rebind("UPDATE t SET a = ? WHERE b = ?"))
+
+	d, _ = newDialect(SQLiteDialect)
+	require.Equal(t, "UPDATE t SET a = ? WHERE b = ?", d.rebind("UPDATE t SET a = ? WHERE b = ?"))
+
+	require.Equal(t,
+		"INSERT INTO t (a, b) VALUES (?, ?) ON CONFLICT (a) DO UPDATE SET b = excluded.b WHERE t.b < excluded.b",
+		upsertQuery("t", []string{"a", "b"}, []string{"a"}, []string{"b"}, "t.b < excluded.b"),
+	)
+	require.Equal(t, "INSERT INTO t (a) VALUES (?) ON CONFLICT (a) DO NOTHING", upsertQuery("t", []string{"a"}, []string{"a"}, nil, ""))
+}
+
+func TestSQLProcessor_SaveHeaderFinalizeAndRemove(t *testing.T) {
+	t.Parallel()
+
+	db := openTestDatabase(t)
+	sp, _ := NewSQLProcessor(createMockSQLProcessorArgs(db))
+
+	header := &dataBlock.Header{Nonce: 10, Round: 11, ShardID: 1, TimeStamp: 5040}
+	headerHash, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, header)
+	obh := createOutportBlockWithHeader(&dataBlock.Body{}, header, &outport.TransactionPool{}, nil)
+	obh.BlockData.HeaderHash = headerHash
+
+	err := sp.SaveHeader(obh)
+	require.Nil(t, err)
+	// saving the block again replaces it
+	err = sp.SaveHeader(obh)
+	require.Nil(t, err)
+	require.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM blocks WHERE hash = ? AND nonce = 10 AND shard_id = 1 AND NOT is_final`, hex.EncodeToString(headerHash)))
+
+	err = sp.SaveFinalizedBlock(&outport.FinalizedBlock{ShardID: 1, HeaderHash: headerHash})
+	require.Nil(t, err)
+	require.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM blocks WHERE is_final`))
+	require.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM shard_block_infos WHERE id = 'finalized-block-1' AND nonce = 10`))
+
+	// a block which is not indexed is ignored
+	err = sp.SaveFinalizedBlock(&outport.FinalizedBlock{ShardID: 1, HeaderHash: []byte("missing")})
+	require.Nil(t, err)
+
+	err = sp.RemoveHeader(header)
+	require.Nil(t, err)
+	require.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM blocks`))
+}
+
+func TestSQLProcessor_SaveMiniblocks(t *testing.T) {
+	t.Parallel()
+
+	db := openTestDatabase(t)
+	sp, _ := NewSQLProcessor(createMockSQLProcessorArgs(db))
+
+	miniBlocks := []*dataBlock.MiniBlock{
+		{SenderShardID: 0, ReceiverShardID: 1, TxHashes: [][]byte{[]byte("tx1")}},
+	}
+	header := &dataBlock.Header{ShardID: 0, Nonce: 1, MiniBlockHeaders: []dataBlock.MiniBlockHeader{{}}}
+	err := sp.SaveMiniblocks(header, miniBlocks, 1000)
+	require.Nil(t, err)
+
+	destinationHeader := &dataBlock.Header{ShardID: 1, Nonce: 2}
+	err = sp.SaveMiniblocks(destinationHeader, miniBlocks, 2000)
+	require.Nil(t, err)
+
+	senderBlockHash, receiverBlockHash := "", ""
+	err = db.QueryRow(`SELECT sender_block_hash, receiver_block_hash FROM miniblocks`).Scan(&senderBlockHash, &receiverBlockHash)
+	require.Nil(t, err)
+	require.NotEmpty(t, senderBlockHash)
+	require.NotEmpty(t, receiverBlockHash)
+	require.NotEqual(t, senderBlockHash, receiverBlockHash)
+
+	err = sp.RemoveMiniblocks(header, &dataBlock.Body{MiniBlocks: miniBlocks})
+	require.Nil(t, err)
+	require.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM miniblocks`))
+}
+
+func TestSQLProcessor_SaveTransactionsAndRevert(t *testing.T) {
+	t.Parallel()
+
+	db := openTestDatabase(t)
+	sp, _ := NewSQLProcessor(createMockSQLProcessorArgs(db))
+
+	txHash := []byte("hash")
+	header := &dataBlock.Header{Round: 50, TimeStamp: 5040}
+	body := &dataBlock.Body{
+		MiniBlocks: dataBlock.MiniBlockSlice{
+			{
+				Type:            dataBlock.TxBlock,
+				SenderShardID:   0,
+				ReceiverShardID: 0,
+				TxHashes:        [][]byte{txHash},
+			},
+		},
+	}
+	sender := []byte("11111111111111111111111111111111")
+	receiver := []byte("22222222222222222222222222222222")
+	pool := &outport.TransactionPool{
+		Transactions: map[string]*outport.TxInfo{
+			hex.EncodeToString(txHash): {
+				Transaction: &transaction.Transaction{
+					Nonce:    1,
+					SndAddr:  sender,
+					RcvAddr:  receiver,
+					GasLimit: 70000,
+					GasPrice: 1000000000,
+					Data:     []byte("transfer"),
+					Value:    big.NewInt(1234),
+				},
+				FeeInfo: &outport.FeeInfo{
+					GasUsed:        62000,
+					Fee:            big.NewInt(62000000000000),
+					InitialPaidFee: big.NewInt(62080000000000),
+				},
+			},
+		},
+		Logs: []*outport.LogData{
+			{
+				TxHash: hex.EncodeToString(txHash),
+				Log: &transaction.Log{
+					Address: receiver,
+					Events: []*transaction.Event{
+						{Address: receiver, Identifier: []byte("writeLog"), Topics: [][]byte{[]byte("topic")}},
+					},
+				},
+			},
+		},
+	}
+	coreAlteredAccounts := map[string]*alteredAccount.AlteredAccount{
+		hex.EncodeToString(sender): {
+			Address: hex.EncodeToString(sender),
+			Balance: "1000",
+			Nonce:   2,
+			AdditionalData: &alteredAccount.AdditionalAccountData{
+				IsSender: true,
+			},
+		},
+	}
+
+	err := sp.SaveTransactions(createOutportBlockWithHeader(body, header, pool, coreAlteredAccounts))
+	require.Nil(t, err)
+
+	fee, status := "", ""
+	var data []byte
+	var gasUsed uint64
+	err = db.QueryRow(`SELECT fee, status, data, gas_used FROM transactions WHERE hash = ?`, hex.EncodeToString(txHash)).Scan(&fee, &status, &data, &gasUsed)
+	require.Nil(t, err)
+	require.Equal(t, "62000000000000", fee)
+	require.Equal(t, transaction.TxStatusSuccess.String(), status)
+	require.Equal(t, []byte("transfer"), data)
+	require.Equal(t, uint64(62000), gasUsed)
+
+	topics := ""
+	err = db.QueryRow(`SELECT e.topics FROM logs l JOIN events e ON e.tx_hash = l.id WHERE l.id = ?`, hex.EncodeToString(txHash)).Scan(&topics)
+	require.Nil(t, err)
+	require.JSONEq(t, `["746f706963"]`, topics)
+
+	balance := ""
+	err = db.QueryRow(`SELECT balance FROM accounts WHERE address = ?`, hex.EncodeToString(sender)).Scan(&balance)
+	require.Nil(t, err)
+	require.Equal(t, "1000", balance)
+	require.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM accounts_history WHERE address = ? AND token = ''`, hex.EncodeToString(sender)))
+
+	header.MiniBlockHeaders = []dataBlock.MiniBlockHeader{{}}
+	err = sp.RemoveTransactions(header, body, 5040000)
+	require.Nil(t, err)
+	require.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM transactions`))
+	require.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM logs`))
+	require.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM events`))
+}
+
+func TestSQLProcessor_SaveTransactionsRefund(t *testing.T) {
+	t.Parallel()
+
+	db := openTestDatabase(t)
+	sp, _ := NewSQLProcessor(createMockSQLProcessorArgs(db))
+
+	_, err := db.Exec(`INSERT INTO transactions (hash, nonce, round, epoch, sender, receiver, sender_shard, receiver_shard,
+		gas_price, gas_limit, gas_used, fee, fee_num, initial_paid_fee, timestamp_ms, version, is_sc_call, is_relayed,
+		has_sc_results, has_logs, error_event, completed_event) VALUES ('tx', 0, 0, 0, 'a', 'b', 0, 1, 1, 100, 100, '1000', 1, '1000', 0, 1,
+		false, false, false, false, false, false)`)
+	require.Nil(t, err)
+
+	err = sp.withTransaction(func(tx *sqlTx) error {
+		return saveTransactionsFeeData(tx, map[string]*data.FeeData{
+			"tx": {Fee: "300", FeeNum: 0.3, GasRefunded: 30},
+		})
+	})
+	require.Nil(t, err)
+
+	fee := ""
+	var gasUsed uint64
+	hadRefund := false
+	err = db.QueryRow(`SELECT fee, gas_used, had_refund FROM transactions WHERE hash = 'tx'`).Scan(&fee, &gasUsed, &hadRefund)
+	require.Nil(t, err)
+	require.Equal(t, "700", fee)
+	require.Equal(t, uint64(70), gasUsed)
+	require.True(t, hadRefund)
+}
+
+func TestSQLProcessor_TokenTypeOfAccountsESDT(t *testing.T) {
+	t.Parallel()
+
+	address := hex.EncodeToString([]byte("33333333333333333333333333333333"))
+	esdtData := &esdt.ESDigitalToken{
+		TokenMetaData: &esdt.MetaData{
+			Creator: []byte("creator"),
+		},
+	}
+	esdtDataBytes, _ := json.Marshal(esdtData)
+
+	issuePool := &outport.TransactionPool{
+		Logs: []*outport.LogData{
+			{
+				TxHash: hex.EncodeToString([]byte("h1")),
+				Log: &transaction.Log{
+					Address: []byte(address),
+					Events: []*transaction.Event{
+						{
+							Address:    []byte("issuer"),
+							Identifier: []byte("issueSemiFungible"),
+							Topics:     [][]byte{[]byte("SEMI-abcd"), []byte("SEMI-token"), []byte("SEM"), []byte(core.SemiFungibleESDT)},
+						},
+					},
+				},
+			},
+		},
+	}
+	createPool := &outport.TransactionPool{
+		Logs: []*outport.LogData{
+			{
+				TxHash: hex.EncodeToString([]byte("h2")),
+				Log: &transaction.Log{
+					Address: []byte(address),
+					Events: []*transaction.Event{
+						{
+							Address:    []byte(address),
+							Identifier: []byte(core.BuiltInFunctionESDTNFTCreate),
+							Topics:     [][]byte{[]byte("SEMI-abcd"), big.NewInt(2).Bytes(), big.NewInt(1).Bytes(), esdtDataBytes},
+						},
+					},
+				},
+			},
+		},
+	}
+	coreAlteredAccounts := map[string]*alteredAccount.AlteredAccount{
+		address: {
+			Address: address,
+			Balance: "1000",
+			Tokens: []*alteredAccount.AccountTokenData{
+				{
+					Identifier: "SEMI-abcd",
+					Balance:    "1000",
+					Nonce:      2,
+					Properties: "3032",
+				},
+			},
+		},
+	}
+	metaHeader := &dataBlock.Header{Round: 50, ShardID: core.MetachainShardId, TimeStamp: 5040}
+	shardHeader := &dataBlock.Header{Round: 51, ShardID: 1, TimeStamp: 5600}
+
+	t.Run("issued before created", func(t *testing.T) {
+		t.Parallel()
+
+		db := openTestDatabase(t)
+		sp, _ := NewSQLProcessor(createMockSQLProcessorArgs(db))
+
+		err := sp.SaveTransactions(createOutportBlockWithHeader(&dataBlock.Body{}, metaHeader, issuePool, map[string]*alteredAccount.AlteredAccount{}))
+		require.Nil(t, err)
+		err = sp.SaveTransactions(createOutportBlockWithHeader(&dataBlock.Body{}, shardHeader, createPool, coreAlteredAccounts))
+		require.Nil(t, err)
+
+		tokenType := ""
+		err = db.QueryRow(`SELECT type FROM accounts_esdt WHERE address = ? AND token = 'SEMI-abcd' AND token_nonce = 2`, address).Scan(&tokenType)
+		require.Nil(t, err)
+		require.Equal(t, core.SemiFungibleESDT, tokenType)
+
+		err = db.QueryRow(`SELECT type FROM tokens WHERE identifier = 'SEMI-abcd-02'`).Scan(&tokenType)
+		require.Nil(t, err)
+		require.Equal(t, core.SemiFungibleESDT, tokenType)
+	})
+
+	t.Run("created before issued", func(t *testing.T) {
+		t.Parallel()
+
+		db := openTestDatabase(t)
+		sp, _ := NewSQLProcessor(createMockSQLProcessorArgs(db))
+
+		err := sp.SaveTransactions(createOutportBlockWithHeader(&dataBlock.Body{}, shardHeader, createPool, coreAlteredAccounts))
+		require.Nil(t, err)
+		require.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM accounts_esdt WHERE type IS NULL OR type = ''`))
+
+		err = sp.SaveTransactions(createOutportBlockWithHeader(&dataBlock.Body{}, metaHeader, issuePool, map[string]*alteredAccount.AlteredAccount{}))
+		require.Nil(t, err)
+		require.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM accounts_esdt a JOIN tokens t ON t.identifier = a.token WHERE a.type = ? AND t.type = ?`,
+			core.SemiFungibleESDT, core.SemiFungibleESDT))
+		require.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM tokens WHERE identifier = 'SEMI-abcd-02' AND type = ?`, core.SemiFungibleESDT))
+
+		err = sp.RemoveAccountsESDT(1, 5600000)
+		require.Nil(t, err)
+		require.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM accounts_esdt`))
+		require.Equal(t, 0, countRows(t, db, `SELECT COUNT(*) FROM accounts_history WHERE token <> ''`))
+	})
+}
+
+func TestSQLProcessor_CheckpointsAndNonceGaps(t *testing.T) {
+	t.Parallel()
+
+	db := openTestDatabase(t)
+	sp, _ := NewSQLProcessor(createMockSQLProcessorArgs(db))
+
+	err := sp.SaveCheckpoint(&dataBlock.Header{ShardID: 1, Nonce: 5, Round: 6}, []byte("h5"), 1000)
+	require.Nil(t, err)
+	err = sp.SaveCheckpoint(&dataBlock.Header{ShardID: 1, Nonce: 6, Round: 7}, []byte("h6"), 2000)
+	require.Nil(t, err)
+	// a block indexed again, with a lower nonce, does not move the checkpoint backwards
+	err = sp.SaveCheckpoint(&dataBlock.Header{ShardID: 1, Nonce: 4, Round: 5}, []byte("h4"), 500)
+	require.Nil(t, err)
+
+	checkpoints, err := sp.GetCheckpoints()
+	require.Nil(t, err)
+	require.Equal(t, map[uint32]*data.ShardBlockInfo{
+		1: {Key: "checkpoint-1", ShardID: 1, HeaderHash: hex.EncodeToString([]byte("h6")), Nonce: 6, Round: 7, TimestampMs: 2000},
+	}, checkpoints)
+
+	gap1 := &data.NonceGap{ShardID: 0, FromNonce: 3, ToNonce: 5, DetectedAtMs: 10}
+	gap2 := &data.NonceGap{ShardID: 0, FromNonce: 8, ToNonce: 8, DetectedAtMs: 20}
+	err = sp.SaveNonceGaps(0, nil, []*data.NonceGap{gap2, gap1})
+	require.Nil(t, err)
+	err = sp.SaveNonceGaps(0, []*data.NonceGap{gap2}, nil)
+	require.Nil(t, err)
+
+	gaps, err := sp.GetNonceGaps()
+	require.Nil(t, err)
+	require.Equal(t, []*data.NonceGap{gap1}, gaps)
+}
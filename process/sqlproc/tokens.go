@@ -0,0 +1,167 @@
+package sqlproc
+
+import (
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/alteredAccount"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+var tokensColumns = []string{
+	"identifier", "token", "name", "ticker", "issuer", "current_owner", "creator", "num_decimals", "type", "nonce", "timestamp_ms",
+}
+
+// saveTokens saves the issued tokens, their ownership transfers and their type changes. The type of an issued
+// token which is not fungible is then set on the ESDT balances and on the created tokens saved before the issue
+func (sp *sqlProcessor) saveTokens(tx *sqlTx, tokensData []*data.TokenInfo) error {
+	if len(tokensData) == 0 {
+		return nil
+	}
+
+	if sp.isIndexEnabled(dataindexer.TokensIndex) {
+		queryIssue := upsertQuery(tokensTable, tokensColumns, []string{"identifier"}, tokensColumns[1:], "")
+		queryTransferOwnership := upsertQuery(tokensTable, tokensColumns, []string{"identifier"}, []string{"current_owner"}, "")
+		queryChangeType := `UPDATE ` + tokensTable + ` SET type = ? WHERE identifier = ?`
+
+		for _, tokenData := range tokensData {
+			var err error
+			switch {
+			case tokenData.TransferOwnership:
+				err = saveToken(tx, queryTransferOwnership, tokenData.Token, tokenData)
+			case tokenData.ChangeToDynamic:
+				err = tx.exec(queryChangeType, tokenData.Type, tokenData.Token)
+			default:
+				err = saveToken(tx, queryIssue, tokenData.Token, tokenData)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := sp.addTokenType(tx, tokensData, accountsESDTTable, dataindexer.AccountsESDTIndex)
+	if err != nil {
+		return err
+	}
+
+	return sp.addTokenType(tx, tokensData, tokensTable, dataindexer.TokensIndex)
+}
+
+func saveToken(tx *sqlTx, query string, identifier string, tokenData *data.TokenInfo) error {
+	creator := ""
+	if tokenData.Data != nil {
+		creator = tokenData.Data.Creator
+	}
+
+	return tx.exec(query,
+		identifier, tokenData.Token, tokenData.Name, tokenData.Ticker, tokenData.Issuer, tokenData.CurrentOwner, creator,
+		tokenData.NumDecimals, tokenData.Type, tokenData.Nonce, tokenData.TimestampMs,
+	)
+}
+
+// addTokenType sets the type of the issued tokens on the rows of the provided table that were saved without a type
+func (sp *sqlProcessor) addTokenType(tx *sqlTx, tokensData []*data.TokenInfo, table string, index string) error {
+	if !sp.isIndexEnabled(index) {
+		return nil
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET type = ? WHERE token = ? AND (type IS NULL OR type = '')`, table)
+	for _, tokenData := range tokensData {
+		if tokenData.Type == core.FungibleESDT || tokenData.Type == "" {
+			continue
+		}
+
+		err := tx.exec(query, tokenData.Type, tokenData.Token)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveNFTCreateInfo saves the created tokens, with the type and the owner of their collection
+func (sp *sqlProcessor) saveNFTCreateInfo(tx *sqlTx, tokensData data.TokensHandler, coreAlteredAccounts map[string]*alteredAccount.AlteredAccount) error {
+	shouldSkip := !sp.isIndexEnabled(dataindexer.TokensIndex) || check.IfNil(tokensData) || tokensData.Len() == 0
+	if shouldSkip {
+		return nil
+	}
+
+	err := addTypeAndOwnerFromTokensTable(tx, tokensData)
+	if err != nil {
+		return err
+	}
+
+	tokens := tokensData.GetAllWithoutMetaESDT()
+	sp.accountsProc.PutTokenMedataDataInTokens(tokens, coreAlteredAccounts)
+
+	query := upsertQuery(tokensTable, tokensColumns, []string{"identifier"}, tokensColumns[1:], "")
+	for _, tokenData := range tokens {
+		err = saveToken(tx, query, tokenData.Identifier, tokenData)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeBurntNFTs removes the non-fungible tokens whose supply was burnt
+func (sp *sqlProcessor) removeBurntNFTs(tx *sqlTx, tokensSupply data.TokensHandler) error {
+	shouldSkip := !sp.isIndexEnabled(dataindexer.TokensIndex) || check.IfNil(tokensSupply) || tokensSupply.Len() == 0
+	if shouldSkip {
+		return nil
+	}
+
+	err := addTypeAndOwnerFromTokensTable(tx, tokensSupply)
+	if err != nil {
+		return err
+	}
+
+	identifiers := make([]string, 0, tokensSupply.Len())
+	for _, supplyData := range tokensSupply.GetAll() {
+		isNFT := supplyData.Type == core.NonFungibleESDT || supplyData.Type == core.NonFungibleESDTv2 || supplyData.Type == core.DynamicNFTESDT
+		if isNFT {
+			identifiers = append(identifiers, supplyData.Identifier)
+		}
+	}
+
+	return tx.deleteIn(tokensTable, "identifier", identifiers)
+}
+
+// addTypeAndOwnerFromTokensTable is the token-type lookup: the type and the current owner of the tokens are read
+// from the tokens table, as a multi-get response of the tokens index
+func addTypeAndOwnerFromTokensTable(tx *sqlTx, tokensData data.TokensHandler) error {
+	responseTokens := &data.ResponseTokens{}
+	err := forEachChunk(tokensData.GetAllTokens(), func(chunk []interface{}) error {
+		rows, errQuery := tx.query(fmt.Sprintf(`SELECT identifier, COALESCE(type, ''), COALESCE(current_owner, '') FROM %s WHERE identifier IN (%s)`,
+			tokensTable, placeholders(len(chunk))), chunk...)
+		if errQuery != nil {
+			return errQuery
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		for rows.Next() {
+			doc := data.ResponseTokenDB{Found: true}
+			errQuery = rows.Scan(&doc.ID, &doc.Source.Type, &doc.Source.CurrentOwner)
+			if errQuery != nil {
+				return errQuery
+			}
+			responseTokens.Docs = append(responseTokens.Docs, doc)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	tokensData.AddTypeAndOwnerFromResponse(responseTokens)
+
+	return nil
+}
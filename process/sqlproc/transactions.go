@@ -0,0 +1,356 @@
+package sqlproc
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/data"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+var transactionsColumns = []string{
+	"hash", "miniblock_hash", "nonce", "round", "epoch", "value", "value_num", "sender", "receiver", "sender_shard",
+	"receiver_shard", "gas_price", "gas_limit", "gas_used", "fee", "fee_num", "initial_paid_fee", "had_refund", "data",
+	"signature", "timestamp_ms", "status", "type", "operation", "function", "version", "is_sc_call", "is_relayed",
+	"relayer", "guardian", "has_sc_results", "has_logs", "error_event", "completed_event",
+}
+
+// the fee columns of a cross-shard transaction are written by the source shard, all the other columns by the destination shard
+var transactionsFeeColumns = map[string]struct{}{
+	"gas_used":         {},
+	"fee":              {},
+	"fee_num":          {},
+	"initial_paid_fee": {},
+	"had_refund":       {},
+}
+
+var scResultsColumns = []string{
+	"hash", "miniblock_hash", "nonce", "epoch", "value", "value_num", "sender", "receiver", "sender_shard", "receiver_shard",
+	"gas_price", "gas_limit", "data", "prev_tx_hash", "original_tx_hash", "original_sender", "relayer", "relayed_value",
+	"call_type", "return_message", "timestamp_ms", "status", "type", "operation", "function", "is_relayed", "can_be_ignored",
+	"has_logs",
+}
+
+// SaveTransactions will prepare and save the transactions, the smart contract results, the receipts, the logs and
+// the events of the block, the tokens that were issued, created or burnt, and the accounts altered by the block
+func (sp *sqlProcessor) SaveTransactions(obh *outport.OutportBlockWithHeader) error {
+	headerTimestamp := obh.Header.GetTimeStamp()
+	shardID := obh.Header.GetShardID()
+
+	miniBlocks := append(obh.BlockData.Body.MiniBlocks, obh.BlockData.IntraShardMiniBlocks...)
+	preparedResults := sp.transactionsProc.PrepareTransactionsForDatabase(miniBlocks, obh.Header, obh.TransactionPool, sp.isImportDB(), obh.NumberOfShards, obh.BlockData.TimestampMs)
+	logsData := sp.logsAndEventsProc.ExtractDataFromLogs(obh.TransactionPool.Logs, preparedResults, headerTimestamp, shardID, obh.NumberOfShards, obh.BlockData.TimestampMs)
+
+	return sp.withTransaction(func(tx *sqlTx) error {
+		err := sp.saveTransactions(tx, preparedResults.Transactions, logsData.TxHashStatusInfo, preparedResults.TxHashFee, shardID)
+		if err != nil {
+			return err
+		}
+
+		err = sp.saveScResults(tx, preparedResults.ScResults)
+		if err != nil {
+			return err
+		}
+
+		err = sp.saveReceipts(tx, preparedResults.Receipts)
+		if err != nil {
+			return err
+		}
+
+		err = sp.saveLogs(tx, logsData.DBLogs)
+		if err != nil {
+			return err
+		}
+
+		err = sp.saveEvents(tx, logsData.DBEvents)
+		if err != nil {
+			return err
+		}
+
+		err = sp.saveTokens(tx, logsData.TokensInfo)
+		if err != nil {
+			return err
+		}
+
+		err = sp.saveNFTCreateInfo(tx, logsData.Tokens, obh.AlteredAccounts)
+		if err != nil {
+			return err
+		}
+
+		err = sp.removeBurntNFTs(tx, logsData.TokensSupply)
+		if err != nil {
+			return err
+		}
+
+		return sp.saveAlteredAccounts(tx, obh.AlteredAccounts, shardID, obh.BlockData.TimestampMs)
+	})
+}
+
+func (sp *sqlProcessor) saveTransactions(
+	tx *sqlTx,
+	txs []*data.Transaction,
+	txHashStatusInfo map[string]*outport.StatusInfo,
+	txHashFee map[string]*data.FeeData,
+	selfShardID uint32,
+) error {
+	if !sp.isIndexEnabled(dataindexer.TransactionsIndex) {
+		return nil
+	}
+
+	sourceColumns := make([]string, 0, len(transactionsFeeColumns))
+	destinationColumns := make([]string, 0, len(transactionsColumns))
+	for _, column := range transactionsColumns[1:] {
+		_, isFeeColumn := transactionsFeeColumns[column]
+		if isFeeColumn {
+			sourceColumns = append(sourceColumns, column)
+			continue
+		}
+		destinationColumns = append(destinationColumns, column)
+	}
+
+	queryIntraShard := upsertQuery(transactionsTable, transactionsColumns, []string{"hash"}, transactionsColumns[1:], "")
+	querySource := upsertQuery(transactionsTable, transactionsColumns, []string{"hash"}, sourceColumns, "")
+	queryDestination := upsertQuery(transactionsTable, transactionsColumns, []string{"hash"}, destinationColumns, "")
+
+	for _, dbTx := range txs {
+		query := queryIntraShard
+		if dbTx.SenderShard != dbTx.ReceiverShard {
+			query = queryDestination
+			if dbTx.SenderShard == selfShardID {
+				query = querySource
+			}
+		}
+
+		err := tx.exec(query,
+			dbTx.Hash, dbTx.MBHash, dbTx.Nonce, dbTx.Round, dbTx.Epoch, dbTx.Value, dbTx.ValueNum, dbTx.Sender, dbTx.Receiver,
+			dbTx.SenderShard, dbTx.ReceiverShard, dbTx.GasPrice, dbTx.GasLimit, dbTx.GasUsed, dbTx.Fee, dbTx.FeeNum,
+			dbTx.InitialPaidFee, dbTx.HadRefund, dbTx.Data, dbTx.Signature, dbTx.TimestampMs, dbTx.Status, dbTx.Type,
+			dbTx.Operation, dbTx.Function, dbTx.Version, dbTx.IsScCall, dbTx.IsRelayed, dbTx.RelayedAddr,
+			dbTx.GuardianAddress, dbTx.HasSCR, dbTx.HasLogs, dbTx.ErrorEvent, dbTx.CompletedEvent,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = saveTokenTransfers(tx, dbTx.Hash, dbTx.Tokens, dbTx.ESDTValues, dbTx.ESDTValuesNum, dbTx.Receivers)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := saveTransactionsStatus(tx, txHashStatusInfo)
+	if err != nil {
+		return err
+	}
+
+	return saveTransactionsFeeData(tx, txHashFee)
+}
+
+// saveTransactionsStatus updates the status of the transactions, which can be set by the events of a later block
+func saveTransactionsStatus(tx *sqlTx, txHashStatusInfo map[string]*outport.StatusInfo) error {
+	query := `UPDATE ` + transactionsTable + ` SET status = CASE WHEN ? <> '' THEN ? ELSE status END, ` +
+		`completed_event = (completed_event OR ?), error_event = (error_event OR ?) WHERE hash = ?`
+	for txHash, statusInfo := range txHashStatusInfo {
+		err := tx.exec(query, statusInfo.Status, statusInfo.Status, statusInfo.CompletedEvent, statusInfo.ErrorEvent, txHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveTransactionsFeeData updates the fee of the already saved transactions, the same way as the Elasticsearch
+// update script: a refund is subtracted from the initially paid fee, otherwise the fee is replaced
+func saveTransactionsFeeData(tx *sqlTx, txHashFee map[string]*data.FeeData) error {
+	for txHash, feeData := range txHashFee {
+		if feeData.GasRefunded == 0 {
+			err := tx.exec(`UPDATE `+transactionsTable+` SET fee = ?, fee_num = ?, gas_used = ? WHERE hash = ?`,
+				feeData.Fee, feeData.FeeNum, feeData.GasUsed, txHash)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := applyRefund(tx, txHash, feeData)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyRefund(tx *sqlTx, txHash string, feeData *data.FeeData) error {
+	rows, err := tx.query(`SELECT fee, initial_paid_fee, had_refund, fee_num, gas_used FROM `+transactionsTable+` WHERE hash = ?`, txHash)
+	if err != nil {
+		return err
+	}
+
+	var fee, initialPaidFee sql.NullString
+	var hadRefund bool
+	var feeNum float64
+	var gasUsed uint64
+	found := rows.Next()
+	if found {
+		err = rows.Scan(&fee, &initialPaidFee, &hadRefund, &feeNum, &gasUsed)
+	}
+	errClose := rows.Close()
+	if err != nil {
+		return err
+	}
+	if errClose != nil {
+		return errClose
+	}
+	if !found || fee.String == "" || initialPaidFee.String == "" {
+		return nil
+	}
+
+	feeFromSource, ok := big.NewInt(0).SetString(initialPaidFee.String, 10)
+	if hadRefund {
+		feeFromSource, ok = big.NewInt(0).SetString(fee.String, 10)
+	}
+	refundFee, okRefund := big.NewInt(0).SetString(feeData.Fee, 10)
+	if !ok || !okRefund {
+		log.Warn("sqlProcessor: cannot apply the refund on an invalid fee", "hash", txHash, "fee", fee.String, "refund", feeData.Fee)
+		return nil
+	}
+
+	newFee := fee.String
+	if feeFromSource.Cmp(refundFee) > 0 {
+		newFee = big.NewInt(0).Sub(feeFromSource, refundFee).String()
+	}
+	if feeNum > feeData.FeeNum {
+		feeNum -= feeData.FeeNum
+	}
+	if gasUsed > feeData.GasRefunded {
+		gasUsed -= feeData.GasRefunded
+	}
+
+	return tx.exec(`UPDATE `+transactionsTable+` SET fee = ?, fee_num = ?, gas_used = ?, had_refund = ? WHERE hash = ?`,
+		newFee, feeNum, gasUsed, true, txHash)
+}
+
+func (sp *sqlProcessor) saveScResults(tx *sqlTx, scrs []*data.ScResult) error {
+	if !sp.isIndexEnabled(dataindexer.ScResultsIndex) {
+		return nil
+	}
+
+	query := upsertQuery(scResultsTable, scResultsColumns, []string{"hash"}, scResultsColumns[1:], "")
+	for _, scr := range scrs {
+		err := tx.exec(query,
+			scr.Hash, scr.MBHash, scr.Nonce, scr.Epoch, scr.Value, scr.ValueNum, scr.Sender, scr.Receiver, scr.SenderShard,
+			scr.ReceiverShard, scr.GasPrice, scr.GasLimit, scr.Data, scr.PrevTxHash, scr.OriginalTxHash, scr.OriginalSender,
+			scr.RelayerAddr, scr.RelayedValue, scr.CallType, scr.ReturnMessage, scr.TimestampMs, scr.Status, scr.Type,
+			scr.Operation, scr.Function, scr.IsRelayed, scr.CanBeIgnored, scr.HasLogs,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = saveTokenTransfers(tx, scr.Hash, scr.Tokens, scr.ESDTValues, scr.ESDTValuesNum, scr.Receivers)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveTokenTransfers replaces the tokens transferred by a transaction or by a smart contract result
+func saveTokenTransfers(tx *sqlTx, hash string, tokens []string, values []string, valuesNum []float64, receivers []string) error {
+	err := tx.exec(`DELETE FROM `+tokenTransfersTable+` WHERE hash = ?`, hash)
+	if err != nil {
+		return err
+	}
+
+	columns := []string{"hash", "position", "token", "value", "value_num", "receiver"}
+	query := upsertQuery(tokenTransfersTable, columns, []string{"hash", "position"}, columns[2:], "")
+	for idx, token := range tokens {
+		err = tx.exec(query, hash, idx, token, elementAt(values, idx), elementAt(valuesNum, idx), elementAt(receivers, idx))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func elementAt[T any](slice []T, idx int) T {
+	var value T
+	if idx < len(slice) {
+		value = slice[idx]
+	}
+
+	return value
+}
+
+func (sp *sqlProcessor) saveReceipts(tx *sqlTx, receipts []*data.Receipt) error {
+	if !sp.isIndexEnabled(dataindexer.ReceiptsIndex) {
+		return nil
+	}
+
+	columns := []string{"hash", "tx_hash", "value", "sender", "data", "timestamp_ms"}
+	query := upsertQuery(receiptsTable, columns, []string{"hash"}, columns[1:], "")
+	for _, receipt := range receipts {
+		err := tx.exec(query, receipt.Hash, receipt.TxHash, receipt.Value, receipt.Sender, receipt.Data, receipt.TimestampMs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sp *sqlProcessor) saveLogs(tx *sqlTx, logs []*data.Logs) error {
+	if !sp.isIndexEnabled(dataindexer.LogsIndex) {
+		return nil
+	}
+
+	columns := []string{"id", "original_tx_hash", "address", "timestamp_ms"}
+	query := upsertQuery(logsTable, columns, []string{"id"}, columns[1:], "")
+	for _, dbLog := range logs {
+		err := tx.exec(query, dbLog.ID, dbLog.OriginalTxHash, dbLog.Address, dbLog.TimestampMs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveEvents saves the events of the logs. The topics and the additional data are stored as JSON arrays
+func (sp *sqlProcessor) saveEvents(tx *sqlTx, events []*data.LogEvent) error {
+	if !sp.isIndexEnabled(dataindexer.EventsIndex) {
+		return nil
+	}
+
+	columns := []string{
+		"id", "tx_hash", "original_tx_hash", "log_address", "address", "identifier", "data", "additional_data", "topics",
+		"event_order", "tx_order", "shard_id", "timestamp_ms",
+	}
+	query := upsertQuery(eventsTable, columns, []string{"id"}, columns[1:], "")
+	for _, event := range events {
+		topics, err := json.Marshal(event.Topics)
+		if err != nil {
+			return err
+		}
+		additionalData, err := json.Marshal(event.AdditionalData)
+		if err != nil {
+			return err
+		}
+
+		err = tx.exec(query,
+			event.ID, event.TxHash, event.OriginalTxHash, event.LogAddress, event.Address, event.Identifier, event.Data,
+			string(additionalData), string(topics), event.Order, event.TxOrder, event.ShardID, event.TimestampMs,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
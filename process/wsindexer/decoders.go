@@ -0,0 +1,83 @@
+package wsindexer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrUnsupportedPayloadVersion signals that a payload was received with a version that has no decoder
+	ErrUnsupportedPayloadVersion = errors.New("unsupported payload version")
+
+	errDecoderAlreadyRegistered = errors.New("decoder already registered")
+	errNilDecodeFunc            = errors.New("nil decode function")
+)
+
+// DecodeFunc defines the function that decodes a payload into the data structure used for indexing
+type DecodeFunc func(payload []byte) (interface{}, error)
+
+type decoderKey struct {
+	topic   string
+	version uint32
+}
+
+type decoderRegistry struct {
+	mut      sync.RWMutex
+	decoders map[decoderKey]DecodeFunc
+}
+
+func newDecoderRegistry() *decoderRegistry {
+	return &decoderRegistry{
+		decoders: make(map[decoderKey]DecodeFunc),
+	}
+}
+
+// register will add the decoder of the payloads with the provided topic and version
+func (dr *decoderRegistry) register(topic string, version uint32, decode DecodeFunc) error {
+	if decode == nil {
+		return errNilDecodeFunc
+	}
+
+	dr.mut.Lock()
+	defer dr.mut.Unlock()
+
+	key := decoderKey{topic: topic, version: version}
+	_, found := dr.decoders[key]
+	if found {
+		return fmt.Errorf("%w for topic %s, version %d", errDecoderAlreadyRegistered, topic, version)
+	}
+
+	dr.decoders[key] = decode
+
+	return nil
+}
+
+// get returns the decoder of the payloads with the provided topic and version
+func (dr *decoderRegistry) get(topic string, version uint32) (DecodeFunc, error) {
+	dr.mut.RLock()
+	defer dr.mut.RUnlock()
+
+	decode, found := dr.decoders[decoderKey{topic: topic, version: version}]
+	if !found {
+		return nil, fmt.Errorf("%w %d for topic %s, supported versions: %v",
+			ErrUnsupportedPayloadVersion, version, topic, dr.getVersionsUnprotected(topic))
+	}
+
+	return decode, nil
+}
+
+func (dr *decoderRegistry) getVersionsUnprotected(topic string) []uint32 {
+	versions := make([]uint32, 0)
+	for key := range dr.decoders {
+		if key.topic == topic {
+			versions = append(versions, key.version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] < versions[j]
+	})
+
+	return versions
+}
@@ -0,0 +1,187 @@
+package wsindexer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	indexerCore "github.com/multiversx/mx-chain-es-indexer-go/core"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+)
+
+const defaultShardQueueSize = 100
+
+// ErrDispatcherClosed signals that the dispatcher was closed
+var ErrDispatcherClosed = errors.New("dispatcher closed")
+
+var errCannotGetShardID = errors.New("cannot get the shard ID from the payload")
+
+// ArgsDispatcher holds all the components needed to create a new instance of dispatcher
+type ArgsDispatcher struct {
+	Marshaller     marshal.Marshalizer
+	PayloadHandler indexerCore.PayloadHandler
+	QueueSize      int
+}
+
+type dispatchJob struct {
+	payload []byte
+	topic   string
+	version uint32
+	result  chan error
+}
+
+type shardWorker struct {
+	shardID uint32
+	jobs    chan *dispatchJob
+}
+
+type dispatcher struct {
+	marshaller marshal.Marshalizer
+	handler    indexerCore.PayloadHandler
+	queueSize  int
+
+	mut     sync.RWMutex
+	closed  bool
+	workers map[uint32]*shardWorker
+	wg      sync.WaitGroup
+
+	// the settings apply to all the shards, so they are never changed while a payload is processed
+	mutSettings sync.RWMutex
+}
+
+// NewDispatcher will create a new instance of dispatcher. The payloads are processed by one worker for each shard,
+// so the shards are processed in parallel while the payloads of a shard are processed strictly in the order they
+// were received. The metachain blocks are processed on their own worker as well and the shards do not wait for them,
+// so the token data the shard blocks read from the metachain is best-effort, see elasticproc.tokensLocker.
+// ProcessPayload returns only after the payload was processed, so a payload is acknowledged only after it was indexed
+func NewDispatcher(args ArgsDispatcher) (*dispatcher, error) {
+	if check.IfNil(args.Marshaller) {
+		return nil, dataindexer.ErrNilMarshalizer
+	}
+	if check.IfNil(args.PayloadHandler) {
+		return nil, errNilPayloadHandler
+	}
+
+	queueSize := args.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultShardQueueSize
+	}
+
+	return &dispatcher{
+		marshaller: args.Marshaller,
+		handler:    args.PayloadHandler,
+		queueSize:  queueSize,
+		workers:    make(map[uint32]*shardWorker),
+	}, nil
+}
+
+// ProcessPayload will pass the payload to the worker of its shard and will wait for it to be processed
+func (d *dispatcher) ProcessPayload(payload []byte, topic string, version uint32) error {
+	if topic == outport.TopicSettings {
+		d.mutSettings.Lock()
+		defer d.mutSettings.Unlock()
+
+		return d.handler.ProcessPayload(payload, topic, version)
+	}
+
+	shardID, err := d.getShardID(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errCannotGetShardID, err)
+	}
+
+	job := &dispatchJob{
+		payload: payload,
+		topic:   topic,
+		version: version,
+		result:  make(chan error, 1),
+	}
+
+	err = d.enqueue(shardID, job)
+	if err != nil {
+		return err
+	}
+
+	return <-job.result
+}
+
+func (d *dispatcher) enqueue(shardID uint32, job *dispatchJob) error {
+	d.mut.RLock()
+	worker, found := d.workers[shardID]
+	if found && !d.closed {
+		// the read lock is kept while enqueuing so the jobs channel is not closed in the meantime
+		worker.jobs <- job
+		d.mut.RUnlock()
+		return nil
+	}
+	d.mut.RUnlock()
+
+	d.mut.Lock()
+	if d.closed {
+		d.mut.Unlock()
+		return ErrDispatcherClosed
+	}
+
+	worker, found = d.workers[shardID]
+	if !found {
+		worker = &shardWorker{
+			shardID: shardID,
+			jobs:    make(chan *dispatchJob, d.queueSize),
+		}
+		d.workers[shardID] = worker
+		d.wg.Add(1)
+		go d.work(worker)
+	}
+	d.mut.Unlock()
+
+	// a new worker is not closed while there is a job that did not reach its queue
+	return d.enqueue(shardID, job)
+}
+
+func (d *dispatcher) work(worker *shardWorker) {
+	defer d.wg.Done()
+
+	for job := range worker.jobs {
+		job.result <- d.processJob(job)
+	}
+}
+
+func (d *dispatcher) processJob(job *dispatchJob) error {
+	d.mutSettings.RLock()
+	defer d.mutSettings.RUnlock()
+
+	return d.handler.ProcessPayload(job.payload, job.topic, job.version)
+}
+
+func (d *dispatcher) getShardID(payload []byte) (uint32, error) {
+	shard := &outport.Shard{}
+	err := d.marshaller.Unmarshal(shard, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	return shard.ShardID, nil
+}
+
+// Close will wait for the queued payloads to be processed and will close the wrapped payload handler
+func (d *dispatcher) Close() error {
+	d.mut.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, worker := range d.workers {
+			close(worker.jobs)
+		}
+	}
+	d.mut.Unlock()
+
+	d.wg.Wait()
+
+	return d.handler.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (d *dispatcher) IsInterfaceNil() bool {
+	return d == nil
+}
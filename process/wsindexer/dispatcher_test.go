@@ -0,0 +1,261 @@
+package wsindexer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/multiversx/mx-chain-es-indexer-go/process/dataindexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createPayload(shardID uint32, nonce uint64) []byte {
+	payload, _ := json.Marshal(&outport.BlockData{ShardID: shardID, HeaderHash: []byte(fmt.Sprintf("%d", nonce))})
+	return payload
+}
+
+func getNonceFromPayload(payload []byte) (uint32, uint64) {
+	blockData := &outport.BlockData{}
+	_ = json.Unmarshal(payload, blockData)
+
+	var nonce uint64
+	_, _ = fmt.Sscanf(string(blockData.HeaderHash), "%d", &nonce)
+
+	return blockData.ShardID, nonce
+}
+
+func TestNewDispatcher(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewDispatcher(ArgsDispatcher{PayloadHandler: &mock.PayloadHandlerStub{}})
+	require.Nil(t, d)
+	require.Equal(t, dataindexer.ErrNilMarshalizer, err)
+
+	d, err = NewDispatcher(ArgsDispatcher{Marshaller: &mock.MarshalizerMock{}})
+	require.Nil(t, d)
+	require.Equal(t, errNilPayloadHandler, err)
+
+	d, err = NewDispatcher(ArgsDispatcher{Marshaller: &mock.MarshalizerMock{}, PayloadHandler: &mock.PayloadHandlerStub{}})
+	require.Nil(t, err)
+	require.False(t, check.IfNil(d))
+	require.Equal(t, defaultShardQueueSize, d.queueSize)
+}
+
+func TestDispatcher_ProcessPayloadShouldReturnTheResult(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	d, _ := NewDispatcher(ArgsDispatcher{
+		Marshaller: &mock.MarshalizerMock{},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				return expectedErr
+			},
+		},
+	})
+
+	err := d.ProcessPayload(createPayload(0, 1), outport.TopicSaveBlock, 1)
+	require.Equal(t, expectedErr, err)
+
+	require.Nil(t, d.Close())
+	err = d.ProcessPayload(createPayload(0, 2), outport.TopicSaveBlock, 1)
+	require.Equal(t, ErrDispatcherClosed, err)
+}
+
+func TestDispatcher_ProcessPayloadWithoutShardIDShouldErr(t *testing.T) {
+	t.Parallel()
+
+	processed := false
+	d, _ := NewDispatcher(ArgsDispatcher{
+		Marshaller: &mock.MarshalizerMock{},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				processed = true
+				return nil
+			},
+		},
+	})
+
+	err := d.ProcessPayload([]byte("not a payload"), outport.TopicSaveBlock, 1)
+	require.True(t, errors.Is(err, errCannotGetShardID))
+	require.False(t, processed)
+	require.Nil(t, d.Close())
+}
+
+func TestDispatcher_ShouldKeepTheOrderWithinShard(t *testing.T) {
+	t.Parallel()
+
+	numShards := uint32(3)
+	numBlocks := uint64(50)
+
+	mut := sync.Mutex{}
+	processedNonces := make(map[uint32][]uint64)
+	d, _ := NewDispatcher(ArgsDispatcher{
+		Marshaller: &mock.MarshalizerMock{},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				shardID, nonce := getNonceFromPayload(payload)
+				mut.Lock()
+				processedNonces[shardID] = append(processedNonces[shardID], nonce)
+				mut.Unlock()
+				return nil
+			},
+		},
+		QueueSize: 2,
+	})
+
+	wg := sync.WaitGroup{}
+	for shardID := uint32(0); shardID < numShards; shardID++ {
+		wg.Add(1)
+		go func(shardID uint32) {
+			defer wg.Done()
+			for nonce := uint64(0); nonce < numBlocks; nonce++ {
+				topic := outport.TopicSaveBlock
+				if nonce%10 == 0 {
+					topic = outport.TopicRevertIndexedBlock
+				}
+				err := d.ProcessPayload(createPayload(shardID, nonce), topic, 1)
+				require.Nil(t, err)
+			}
+		}(shardID)
+	}
+	wg.Wait()
+	require.Nil(t, d.Close())
+
+	for shardID := uint32(0); shardID < numShards; shardID++ {
+		require.Len(t, processedNonces[shardID], int(numBlocks))
+		for idx, nonce := range processedNonces[shardID] {
+			require.Equal(t, uint64(idx), nonce)
+		}
+	}
+}
+
+func TestDispatcher_ShardsShouldBeProcessedInParallel(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	d, _ := NewDispatcher(ArgsDispatcher{
+		Marshaller: &mock.MarshalizerMock{},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				shardID, _ := getNonceFromPayload(payload)
+				if shardID == 0 {
+					<-release
+				}
+				return nil
+			},
+		},
+	})
+
+	go func() {
+		_ = d.ProcessPayload(createPayload(0, 1), outport.TopicSaveBlock, 1)
+	}()
+
+	// shard 1 is not blocked by the slow block of shard 0
+	err := d.ProcessPayload(createPayload(1, 1), outport.TopicSaveBlock, 1)
+	require.Nil(t, err)
+
+	close(release)
+	require.Nil(t, d.Close())
+}
+
+func TestDispatcher_MetachainBlocksShouldBeProcessedInParallelWithShardBlocks(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	d, _ := NewDispatcher(ArgsDispatcher{
+		Marshaller: &mock.MarshalizerMock{},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				shardID, _ := getNonceFromPayload(payload)
+				if shardID == core.MetachainShardId {
+					<-release
+				}
+				return nil
+			},
+		},
+	})
+
+	go func() {
+		_ = d.ProcessPayload(createPayload(core.MetachainShardId, 1), outport.TopicSaveBlock, 1)
+	}()
+
+	// the shard blocks are not blocked by a slow metachain block
+	err := d.ProcessPayload(createPayload(0, 1), outport.TopicSaveBlock, 1)
+	require.Nil(t, err)
+	err = d.ProcessPayload(createPayload(1, 1), outport.TopicRevertIndexedBlock, 1)
+	require.Nil(t, err)
+
+	close(release)
+	require.Nil(t, d.Close())
+}
+
+func TestDispatcher_SettingsShouldNotBeProcessedWithOtherPayloads(t *testing.T) {
+	t.Parallel()
+
+	numInProgress := int32(0)
+	settingsWithOthers := int32(0)
+	d, _ := NewDispatcher(ArgsDispatcher{
+		Marshaller: &mock.MarshalizerMock{},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				inProgress := atomic.AddInt32(&numInProgress, 1)
+				if topic == outport.TopicSettings && inProgress > 1 {
+					atomic.AddInt32(&settingsWithOthers, 1)
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&numInProgress, -1)
+				return nil
+			},
+		},
+	})
+
+	wg := sync.WaitGroup{}
+	for _, shardID := range []uint32{0, 1, core.MetachainShardId} {
+		wg.Add(1)
+		go func(shardID uint32) {
+			defer wg.Done()
+			for nonce := uint64(0); nonce < 20; nonce++ {
+				_ = d.ProcessPayload(createPayload(shardID, nonce), outport.TopicSaveBlock, 1)
+			}
+		}(shardID)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			_ = d.ProcessPayload(nil, outport.TopicSettings, 1)
+		}
+	}()
+	wg.Wait()
+	require.Nil(t, d.Close())
+
+	require.Equal(t, int32(0), atomic.LoadInt32(&settingsWithOthers))
+}
+
+func TestDispatcher_CloseShouldCloseTheHandler(t *testing.T) {
+	t.Parallel()
+
+	closeCalled := false
+	d, _ := NewDispatcher(ArgsDispatcher{
+		Marshaller: &mock.MarshalizerMock{},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			CloseCalled: func() error {
+				closeCalled = true
+				return nil
+			},
+		},
+	})
+
+	require.Nil(t, d.ProcessPayload(createPayload(0, 1), outport.TopicSaveBlock, 1))
+	require.Nil(t, d.Close())
+	require.True(t, closeCalled)
+}
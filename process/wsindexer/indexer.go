@@ -14,9 +14,12 @@ import (
 	logger "github.com/multiversx/mx-chain-logger-go"
 )
 
+const payloadVersion1 = 1
+
 var (
-	log               = logger.GetOrCreate("process/wsindexer")
-	errNilDataIndexer = errors.New("nil data indexer")
+	log                   = logger.GetOrCreate("process/wsindexer")
+	errNilDataIndexer     = errors.New("nil data indexer")
+	errWrongTypeAssertion = errors.New("wrong type assertion")
 )
 
 // ArgsIndexer holds all the components needed to create a new instance of indexer
@@ -30,7 +33,8 @@ type indexer struct {
 	marshaller    marshal.Marshalizer
 	di            DataIndexer
 	statusMetrics core.StatusMetricsHandler
-	actions       map[string]func(marshalledData []byte) error
+	actions       map[string]func(data interface{}) error
+	decoders      *decoderRegistry
 }
 
 // NewIndexer will create a new instance of *indexer
@@ -49,15 +53,21 @@ func NewIndexer(args ArgsIndexer) (*indexer, error) {
 		marshaller:    args.Marshaller,
 		di:            args.DataIndexer,
 		statusMetrics: args.StatusMetrics,
+		decoders:      newDecoderRegistry(),
 	}
 	payloadIndexer.initActionsMap()
 
+	err := payloadIndexer.registerDecoders()
+	if err != nil {
+		return nil, err
+	}
+
 	return payloadIndexer, nil
 }
 
 // GetOperationsMap returns the map with all the operations that will index data
 func (i *indexer) initActionsMap() {
-	i.actions = map[string]func(d []byte) error{
+	i.actions = map[string]func(data interface{}) error{
 		outport.TopicSaveBlock:             i.saveBlock,
 		outport.TopicRevertIndexedBlock:    i.revertIndexedBlock,
 		outport.TopicSaveRoundsInfo:        i.saveRounds,
@@ -69,25 +79,63 @@ func (i *indexer) initActionsMap() {
 	}
 }
 
-// ProcessPayload will proces the provided payload based on the topic
-func (i *indexer) ProcessPayload(payload []byte, topic string, version uint32) error {
-	if version != 1 {
-		log.Warn("received a payload with a different version", "version", version)
+// registerDecoders will register the decoders of all the supported payload versions. A newer or an older node outport
+// format is supported by registering its decoder, that converts the payload into the structure used for indexing
+func (i *indexer) registerDecoders() error {
+	decodersV1 := map[string]DecodeFunc{
+		outport.TopicSaveBlock:             i.newDecoder(func() interface{} { return &outport.OutportBlock{} }),
+		outport.TopicRevertIndexedBlock:    i.newDecoder(func() interface{} { return &outport.BlockData{} }),
+		outport.TopicSaveRoundsInfo:        i.newDecoder(func() interface{} { return &outport.RoundsInfo{} }),
+		outport.TopicSaveValidatorsRating:  i.newDecoder(func() interface{} { return &outport.ValidatorsRating{} }),
+		outport.TopicSaveValidatorsPubKeys: i.newDecoder(func() interface{} { return &outport.ValidatorsPubKeys{} }),
+		outport.TopicSaveAccounts:          i.newDecoder(func() interface{} { return &outport.Accounts{} }),
+		outport.TopicFinalizedBlock:        i.newDecoder(func() interface{} { return &outport.FinalizedBlock{} }),
+		outport.TopicSettings:              i.newDecoder(func() interface{} { return &outport.OutportConfig{} }),
+	}
+
+	for topic, decode := range decodersV1 {
+		err := i.decoders.register(topic, payloadVersion1, decode)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *indexer) newDecoder(createObject func() interface{}) DecodeFunc {
+	return func(payload []byte) (interface{}, error) {
+		obj := createObject()
+		err := i.marshaller.Unmarshal(obj, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		return obj, nil
 	}
+}
 
+// ProcessPayload will proces the provided payload based on the topic
+func (i *indexer) ProcessPayload(payload []byte, topic string, version uint32) error {
 	payloadTypeAction, ok := i.actions[topic]
 	if !ok {
 		log.Warn("invalid payload type", "topic", topic)
 		return nil
 	}
 
+	decode, err := i.decoders.get(topic, version)
+	if err != nil {
+		i.addVersionMetrics(topic, version, err)
+		return err
+	}
+
 	shardID, err := i.getShardID(payload)
 	if err != nil {
 		log.Warn("indexer.ProcessPayload: cannot get shardID from payload", "error", err)
 	}
 
 	start := time.Now()
-	err = payloadTypeAction(payload)
+	err = decodeAndProcess(decode, payloadTypeAction, payload)
 	duration := time.Since(start)
 
 	topicKey := fmt.Sprintf("%s_%d", topic, shardID)
@@ -97,82 +145,100 @@ func (i *indexer) ProcessPayload(payload []byte, topic string, version uint32) e
 		Topic:      topicKey,
 		Duration:   duration,
 	})
+	i.addVersionMetrics(topic, version, err)
 
 	return err
 }
 
-func (i *indexer) saveBlock(marshalledData []byte) error {
-	outportBlock := &outport.OutportBlock{}
-	err := i.marshaller.Unmarshal(outportBlock, marshalledData)
+func decodeAndProcess(decode DecodeFunc, action func(data interface{}) error, payload []byte) error {
+	decodedData, err := decode(payload)
 	if err != nil {
 		return err
 	}
 
+	return action(decodedData)
+}
+
+// addVersionMetrics will count the payloads of every topic by version, without a shard ID, so the node outport formats
+// that are still in use can be tracked during a network upgrade
+func (i *indexer) addVersionMetrics(topic string, version uint32, err error) {
+	i.statusMetrics.AddPayloadVersion(metrics.ArgsAddPayloadVersion{
+		Topic:    topic,
+		Version:  version,
+		GotError: err != nil,
+	})
+}
+
+func (i *indexer) saveBlock(decodedData interface{}) error {
+	outportBlock, ok := decodedData.(*outport.OutportBlock)
+	if !ok {
+		return fmt.Errorf("%w for topic %s", errWrongTypeAssertion, outport.TopicSaveBlock)
+	}
+
 	return i.di.SaveBlock(outportBlock)
 }
 
-func (i *indexer) revertIndexedBlock(marshalledData []byte) error {
-	blockData := &outport.BlockData{}
-	err := i.marshaller.Unmarshal(blockData, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) revertIndexedBlock(decodedData interface{}) error {
+	blockData, ok := decodedData.(*outport.BlockData)
+	if !ok {
+		return fmt.Errorf("%w for topic %s", errWrongTypeAssertion, outport.TopicRevertIndexedBlock)
 	}
 
 	return i.di.RevertIndexedBlock(blockData)
 }
 
-func (i *indexer) saveRounds(marshalledData []byte) error {
-	roundsInfo := &outport.RoundsInfo{}
-	err := i.marshaller.Unmarshal(roundsInfo, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) saveRounds(decodedData interface{}) error {
+	roundsInfo, ok := decodedData.(*outport.RoundsInfo)
+	if !ok {
+		return fmt.Errorf("%w for topic %s", errWrongTypeAssertion, outport.TopicSaveRoundsInfo)
 	}
 
 	return i.di.SaveRoundsInfo(roundsInfo)
 }
 
-func (i *indexer) saveValidatorsRating(marshalledData []byte) error {
-	ratingData := &outport.ValidatorsRating{}
-	err := i.marshaller.Unmarshal(ratingData, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) saveValidatorsRating(decodedData interface{}) error {
+	ratingData, ok := decodedData.(*outport.ValidatorsRating)
+	if !ok {
+		return fmt.Errorf("%w for topic %s", errWrongTypeAssertion, outport.TopicSaveValidatorsRating)
 	}
 
 	return i.di.SaveValidatorsRating(ratingData)
 }
 
-func (i *indexer) saveValidatorsPubKeys(marshalledData []byte) error {
-	validatorsPubKeys := &outport.ValidatorsPubKeys{}
-	err := i.marshaller.Unmarshal(validatorsPubKeys, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) saveValidatorsPubKeys(decodedData interface{}) error {
+	validatorsPubKeys, ok := decodedData.(*outport.ValidatorsPubKeys)
+	if !ok {
+		return fmt.Errorf("%w for topic %s", errWrongTypeAssertion, outport.TopicSaveValidatorsPubKeys)
 	}
 
 	return i.di.SaveValidatorsPubKeys(validatorsPubKeys)
 }
 
-func (i *indexer) saveAccounts(marshalledData []byte) error {
-	accounts := &outport.Accounts{}
-	err := i.marshaller.Unmarshal(accounts, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) saveAccounts(decodedData interface{}) error {
+	accounts, ok := decodedData.(*outport.Accounts)
+	if !ok {
+		return fmt.Errorf("%w for topic %s", errWrongTypeAssertion, outport.TopicSaveAccounts)
 	}
 
 	return i.di.SaveAccounts(accounts)
 }
 
-func (i *indexer) finalizedBlock(_ []byte) error {
-	return nil
+func (i *indexer) finalizedBlock(decodedData interface{}) error {
+	finalizedBlock, ok := decodedData.(*outport.FinalizedBlock)
+	if !ok {
+		return fmt.Errorf("%w for topic %s", errWrongTypeAssertion, outport.TopicFinalizedBlock)
+	}
+
+	return i.di.FinalizedBlock(finalizedBlock)
 }
 
-func (i *indexer) setSettings(marshalledData []byte) error {
-	settings := outport.OutportConfig{}
-	err := i.marshaller.Unmarshal(&settings, marshalledData)
-	if err != nil {
-		return err
+func (i *indexer) setSettings(decodedData interface{}) error {
+	settings, ok := decodedData.(*outport.OutportConfig)
+	if !ok {
+		return fmt.Errorf("%w for topic %s", errWrongTypeAssertion, outport.TopicSettings)
 	}
 
-	return i.di.SetCurrentSettings(settings)
+	return i.di.SetCurrentSettings(*settings)
 }
 
 // Close will close the indexer
@@ -0,0 +1,118 @@
+package wsindexer
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/metrics"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockArgsIndexer() ArgsIndexer {
+	return ArgsIndexer{
+		Marshaller:    &mock.MarshalizerMock{},
+		DataIndexer:   &mock.DataIndexerStub{},
+		StatusMetrics: metrics.NewStatusMetrics(),
+	}
+}
+
+func TestIndexer_ProcessPayloadShouldDecodeAndIndex(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	args := createMockArgsIndexer()
+	args.DataIndexer = &mock.DataIndexerStub{
+		FinalizedBlockCalled: func(finalizedBlock *outport.FinalizedBlock) error {
+			called = true
+			require.Equal(t, uint32(2), finalizedBlock.ShardID)
+			require.Equal(t, []byte("hash"), finalizedBlock.HeaderHash)
+			return nil
+		},
+	}
+	wsIndexer, err := NewIndexer(args)
+	require.Nil(t, err)
+
+	payload, _ := json.Marshal(&outport.FinalizedBlock{ShardID: 2, HeaderHash: []byte("hash")})
+	err = wsIndexer.ProcessPayload(payload, outport.TopicFinalizedBlock, 1)
+	require.Nil(t, err)
+	require.True(t, called)
+
+	allMetrics := args.StatusMetrics.GetMetrics()
+	require.Len(t, allMetrics, 1)
+	require.Equal(t, uint64(1), allMetrics["finalized_block_2"].OperationsCount)
+	require.Contains(t, args.StatusMetrics.GetMetricsForPrometheus(), `payload_versions{operation="operations_count",topic="finalized_block",version="1"} 1`)
+}
+
+func TestIndexer_ProcessPayloadUnsupportedVersionShouldErr(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsIndexer()
+	args.DataIndexer = &mock.DataIndexerStub{
+		SaveBlockCalled: func(outportBlock *outport.OutportBlock) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+	wsIndexer, _ := NewIndexer(args)
+
+	err := wsIndexer.ProcessPayload([]byte("{}"), outport.TopicSaveBlock, 2)
+	require.True(t, errors.Is(err, ErrUnsupportedPayloadVersion))
+	require.Contains(t, err.Error(), "unsupported payload version 2 for topic SaveBlock, supported versions: [1]")
+
+	prometheusMetrics := args.StatusMetrics.GetMetricsForPrometheus()
+	require.Contains(t, prometheusMetrics, `payload_versions{operation="operations_count",topic="save_block",version="2"} 1`)
+	require.Contains(t, prometheusMetrics, `payload_versions{operation="errors_count",topic="save_block",version="2"} 1`)
+}
+
+func TestIndexer_ProcessPayloadShouldUseTheDecoderOfTheVersion(t *testing.T) {
+	t.Parallel()
+
+	headerHashLengths := make([]uint64, 0)
+	args := createMockArgsIndexer()
+	args.DataIndexer = &mock.DataIndexerStub{
+		RevertIndexedBlockCalled: func(blockData *outport.BlockData) error {
+			headerHashLengths = append(headerHashLengths, uint64(len(blockData.HeaderHash)))
+			return nil
+		},
+	}
+	wsIndexer, _ := NewIndexer(args)
+
+	// a newer format, that is converted into the current structure
+	err := wsIndexer.decoders.register(outport.TopicRevertIndexedBlock, 2, func(payload []byte) (interface{}, error) {
+		return &outport.BlockData{HeaderHash: payload}, nil
+	})
+	require.Nil(t, err)
+
+	payloadV1, _ := json.Marshal(&outport.BlockData{HeaderHash: []byte("a")})
+	require.Nil(t, wsIndexer.ProcessPayload(payloadV1, outport.TopicRevertIndexedBlock, 1))
+	require.Nil(t, wsIndexer.ProcessPayload([]byte("abc"), outport.TopicRevertIndexedBlock, 2))
+	require.Equal(t, []uint64{1, 3}, headerHashLengths)
+}
+
+func TestIndexer_ProcessPayloadUnknownTopicShouldBeIgnored(t *testing.T) {
+	t.Parallel()
+
+	wsIndexer, _ := NewIndexer(createMockArgsIndexer())
+
+	err := wsIndexer.ProcessPayload([]byte("{}"), "unknown", 5)
+	require.Nil(t, err)
+}
+
+func TestDecoderRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	registry := newDecoderRegistry()
+	require.Equal(t, errNilDecodeFunc, registry.register("topic", 1, nil))
+
+	decode := func(payload []byte) (interface{}, error) { return nil, nil }
+	require.Nil(t, registry.register("topic", 1, decode))
+	require.True(t, errors.Is(registry.register("topic", 1, decode), errDecoderAlreadyRegistered))
+	require.Nil(t, registry.register("topic", 3, decode))
+
+	_, err := registry.get("topic", 2)
+	require.True(t, errors.Is(err, ErrUnsupportedPayloadVersion))
+	require.Contains(t, err.Error(), "supported versions: [1 3]")
+}
@@ -1,13 +1,24 @@
 package wsindexer
 
 import (
+	"github.com/multiversx/mx-chain-communication-go/websocket"
 	"github.com/multiversx/mx-chain-core-go/data/outport"
 )
 
 // WSClient defines what a websocket client should do
 type WSClient interface {
 	Send(message []byte, topic string) error
+	ProcessPayload(payload []byte, topic string, version uint32) error
 	Close() error
+	IsInterfaceNil() bool
+}
+
+// WSHost defines what a websocket host should do
+type WSHost interface {
+	Send(payload []byte, topic string) error
+	SetPayloadHandler(handler websocket.PayloadHandler) error
+	Close() error
+	IsInterfaceNil() bool
 }
 
 // DataIndexer dines what a data indexer should do
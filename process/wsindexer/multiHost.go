@@ -0,0 +1,121 @@
+package wsindexer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-es-indexer-go/core"
+)
+
+var (
+	errNoHosts           = errors.New("no websocket hosts provided")
+	errNilPayloadHandler = errors.New("nil payload handler")
+	errNilWebSocketHost  = errors.New("nil websocket host")
+)
+
+// ArgsMultiHost holds all the components needed to create a new instance of multiHost
+type ArgsMultiHost struct {
+	Hosts          []WSHost
+	PayloadHandler core.PayloadHandler
+}
+
+type multiHost struct {
+	hosts          []WSHost
+	payloadHandler core.PayloadHandler
+}
+
+// NewMultiHost will create a new instance of multiHost. All the provided hosts feed the same payload handler, every
+// host keeping its own connection. The hosts call the payload handler concurrently, the dispatcher in front of the
+// indexer processes the shards in parallel and the payloads of a shard in the order they were received
+func NewMultiHost(args ArgsMultiHost) (*multiHost, error) {
+	if len(args.Hosts) == 0 {
+		return nil, errNoHosts
+	}
+	if check.IfNil(args.PayloadHandler) {
+		return nil, errNilPayloadHandler
+	}
+
+	for idx, host := range args.Hosts {
+		if check.IfNil(host) {
+			return nil, fmt.Errorf("%w at index %d", errNilWebSocketHost, idx)
+		}
+	}
+
+	for _, host := range args.Hosts {
+		// the hosts close their payload handler when they are closed, so the shared one is wrapped and closed only once
+		err := host.SetPayloadHandler(&hostPayloadHandler{
+			handler: args.PayloadHandler,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &multiHost{
+		hosts:          args.Hosts,
+		payloadHandler: args.PayloadHandler,
+	}, nil
+}
+
+// Send will send the provided message to all the hosts
+func (mh *multiHost) Send(message []byte, topic string) error {
+	var lastErr error
+	for idx, host := range mh.hosts {
+		err := host.Send(message, topic)
+		if err != nil {
+			log.Debug("multiHost.Send: cannot send message", "host index", idx, "topic", topic, "error", err)
+			lastErr = fmt.Errorf("%w for host at index %d", err, idx)
+		}
+	}
+
+	return lastErr
+}
+
+// ProcessPayload will pass a payload that was not received on a websocket connection to the shared payload handler
+func (mh *multiHost) ProcessPayload(payload []byte, topic string, version uint32) error {
+	return mh.payloadHandler.ProcessPayload(payload, topic, version)
+}
+
+// Close will close all the hosts and then the shared payload handler
+func (mh *multiHost) Close() error {
+	var lastErr error
+	for idx, host := range mh.hosts {
+		err := host.Close()
+		if err != nil {
+			log.Warn("multiHost.Close: cannot close host", "host index", idx, "error", err)
+			lastErr = err
+		}
+	}
+
+	err := mh.payloadHandler.Close()
+	if err != nil {
+		return err
+	}
+
+	return lastErr
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (mh *multiHost) IsInterfaceNil() bool {
+	return mh == nil
+}
+
+type hostPayloadHandler struct {
+	handler core.PayloadHandler
+}
+
+// ProcessPayload will pass the payload to the shared payload handler
+func (hph *hostPayloadHandler) ProcessPayload(payload []byte, topic string, version uint32) error {
+	return hph.handler.ProcessPayload(payload, topic, version)
+}
+
+// Close does nothing, the shared payload handler is closed by the multiHost
+func (hph *hostPayloadHandler) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (hph *hostPayloadHandler) IsInterfaceNil() bool {
+	return hph == nil
+}
@@ -0,0 +1,221 @@
+package wsindexer
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-communication-go/websocket"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-core-go/data/outport"
+	"github.com/multiversx/mx-chain-es-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMultiHost(t *testing.T) {
+	t.Parallel()
+
+	mh, err := NewMultiHost(ArgsMultiHost{PayloadHandler: &mock.PayloadHandlerStub{}})
+	require.Nil(t, mh)
+	require.Equal(t, errNoHosts, err)
+
+	mh, err = NewMultiHost(ArgsMultiHost{Hosts: []WSHost{&mock.WSHostStub{}}})
+	require.Nil(t, mh)
+	require.Equal(t, errNilPayloadHandler, err)
+
+	mh, err = NewMultiHost(ArgsMultiHost{Hosts: []WSHost{&mock.WSHostStub{}, nil}, PayloadHandler: &mock.PayloadHandlerStub{}})
+	require.Nil(t, mh)
+	require.True(t, errors.Is(err, errNilWebSocketHost))
+
+	mh, err = NewMultiHost(ArgsMultiHost{Hosts: []WSHost{&mock.WSHostStub{}}, PayloadHandler: &mock.PayloadHandlerStub{}})
+	require.Nil(t, err)
+	require.False(t, check.IfNil(mh))
+}
+
+func TestMultiHost_AllHostsShouldFeedTheSamePayloadHandler(t *testing.T) {
+	t.Parallel()
+
+	processedTopics := make([]string, 0)
+	numCloseCalls := 0
+	payloadHandler := &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			processedTopics = append(processedTopics, topic)
+			return nil
+		},
+		CloseCalled: func() error {
+			numCloseCalls++
+			return nil
+		},
+	}
+
+	hostsHandlers := make([]websocket.PayloadHandler, 0)
+	createHost := func() *mock.WSHostStub {
+		return &mock.WSHostStub{
+			SetPayloadHandlerCalled: func(handler websocket.PayloadHandler) error {
+				hostsHandlers = append(hostsHandlers, handler)
+				return nil
+			},
+		}
+	}
+
+	mh, _ := NewMultiHost(ArgsMultiHost{
+		Hosts:          []WSHost{createHost(), createHost()},
+		PayloadHandler: payloadHandler,
+	})
+	require.Len(t, hostsHandlers, 2)
+
+	require.Nil(t, hostsHandlers[0].ProcessPayload(nil, "first", 1))
+	require.Nil(t, hostsHandlers[1].ProcessPayload(nil, "second", 1))
+	require.Equal(t, []string{"first", "second"}, processedTopics)
+
+	// a host closes its payload handler when it is closed
+	require.Nil(t, hostsHandlers[0].Close())
+	require.Nil(t, hostsHandlers[1].Close())
+	require.Equal(t, 0, numCloseCalls)
+
+	require.Nil(t, mh.Close())
+	require.Equal(t, 1, numCloseCalls)
+}
+
+func TestMultiHost_SendShouldSendToAllHosts(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	numSendCalls := 0
+	failingHost := &mock.WSHostStub{
+		SendCalled: func(payload []byte, topic string) error {
+			numSendCalls++
+			return expectedErr
+		},
+	}
+	host := &mock.WSHostStub{
+		SendCalled: func(payload []byte, topic string) error {
+			numSendCalls++
+			return nil
+		},
+	}
+
+	mh, _ := NewMultiHost(ArgsMultiHost{
+		Hosts:          []WSHost{failingHost, host},
+		PayloadHandler: &mock.PayloadHandlerStub{},
+	})
+
+	err := mh.Send([]byte("message"), "topic")
+	require.True(t, errors.Is(err, expectedErr))
+	require.Equal(t, 2, numSendCalls)
+}
+
+func TestMultiHost_ProcessPayloadShouldUseTheSharedPayloadHandler(t *testing.T) {
+	t.Parallel()
+
+	processed := 0
+	mh, _ := NewMultiHost(ArgsMultiHost{
+		Hosts: []WSHost{&mock.WSHostStub{}},
+		PayloadHandler: &mock.PayloadHandlerStub{
+			ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+				require.Equal(t, []byte("payload"), payload)
+				require.Equal(t, "topic", topic)
+				require.Equal(t, uint32(1), version)
+				processed++
+				return nil
+			},
+		},
+	})
+
+	err := mh.ProcessPayload([]byte("payload"), "topic", 1)
+	require.Nil(t, err)
+	require.Equal(t, 1, processed)
+}
+
+func TestMultiHost_ShardsShouldBeProcessedConcurrentlyAndInOrder(t *testing.T) {
+	t.Parallel()
+
+	numBlocks := uint64(10)
+	mut := sync.Mutex{}
+	processedNonces := make(map[uint32][]uint64)
+	numInProgress := make(map[uint32]int)
+	maxInProgress := make(map[uint32]int)
+
+	// the first block of a shard waits for the first block of the other shard, so the shards run at the same time
+	firstBlocks := &sync.WaitGroup{}
+	firstBlocks.Add(2)
+	firstBlocksStarted := make(chan struct{})
+	go func() {
+		firstBlocks.Wait()
+		close(firstBlocksStarted)
+	}()
+	timedOut := int32(0)
+
+	indexer := &mock.PayloadHandlerStub{
+		ProcessPayloadCalled: func(payload []byte, topic string, version uint32) error {
+			shardID, nonce := getNonceFromPayload(payload)
+
+			mut.Lock()
+			numInProgress[shardID]++
+			if numInProgress[shardID] > maxInProgress[shardID] {
+				maxInProgress[shardID] = numInProgress[shardID]
+			}
+			mut.Unlock()
+
+			if nonce == 1 {
+				firstBlocks.Done()
+				select {
+				case <-firstBlocksStarted:
+				case <-time.After(time.Second):
+					atomic.StoreInt32(&timedOut, 1)
+				}
+			}
+
+			mut.Lock()
+			processedNonces[shardID] = append(processedNonces[shardID], nonce)
+			numInProgress[shardID]--
+			mut.Unlock()
+
+			return nil
+		},
+	}
+	d, _ := NewDispatcher(ArgsDispatcher{
+		Marshaller:     &mock.MarshalizerMock{},
+		PayloadHandler: indexer,
+	})
+
+	hostsHandlers := make([]websocket.PayloadHandler, 0)
+	createHost := func() *mock.WSHostStub {
+		return &mock.WSHostStub{
+			SetPayloadHandlerCalled: func(handler websocket.PayloadHandler) error {
+				hostsHandlers = append(hostsHandlers, handler)
+				return nil
+			},
+		}
+	}
+
+	mh, _ := NewMultiHost(ArgsMultiHost{
+		Hosts:          []WSHost{createHost(), createHost()},
+		PayloadHandler: d,
+	})
+
+	wg := &sync.WaitGroup{}
+	for shardID, handler := range hostsHandlers {
+		wg.Add(1)
+		go func(shardID uint32, handler websocket.PayloadHandler) {
+			defer wg.Done()
+			for nonce := uint64(1); nonce <= numBlocks; nonce++ {
+				_ = handler.ProcessPayload(createPayload(shardID, nonce), outport.TopicSaveBlock, 1)
+			}
+		}(uint32(shardID), handler)
+	}
+	wg.Wait()
+	require.Nil(t, mh.Close())
+
+	require.Equal(t, int32(0), atomic.LoadInt32(&timedOut))
+	expectedNonces := make([]uint64, 0, numBlocks)
+	for nonce := uint64(1); nonce <= numBlocks; nonce++ {
+		expectedNonces = append(expectedNonces, nonce)
+	}
+	for shardID := uint32(0); shardID < 2; shardID++ {
+		require.Equal(t, expectedNonces, processedNonces[shardID])
+		require.Equal(t, 1, maxInProgress[shardID])
+	}
+}
@@ -24,6 +24,9 @@ var Blocks = Object{
 					"index": "false",
 					"type":  "keyword",
 				},
+				"commitId": Object{
+					"type": "keyword",
+				},
 				"developerFees": Object{
 					"index": "false",
 					"type":  "keyword",
@@ -144,6 +147,9 @@ var Blocks = Object{
 				"gasRefunded": Object{
 					"type": "double",
 				},
+				"isFinal": Object{
+					"type": "boolean",
+				},
 				"maxGasLimit": Object{
 					"type": "double",
 				},
@@ -0,0 +1,37 @@
+package indices
+
+// Commits will hold the configuration for the commits index
+var Commits = Object{
+	"index_patterns": Array{
+		"commits-*",
+	},
+	"template": Object{
+		"settings": Object{
+			"number_of_shards":   3,
+			"number_of_replicas": 0,
+		},
+		"mappings": Object{
+			"properties": Object{
+				"commitId": Object{
+					"type": "keyword",
+				},
+				"shardId": Object{
+					"type": "long",
+				},
+				"nonce": Object{
+					"type": "long",
+				},
+				"round": Object{
+					"type": "long",
+				},
+				"timestampMs": Object{
+					"type":   "date",
+					"format": "epoch_millis",
+				},
+				"pendingCommitIds": Object{
+					"type": "keyword",
+				},
+			},
+		},
+	},
+}
@@ -12,6 +12,9 @@ var Events = Object{
 		},
 		"mappings": Object{
 			"properties": Object{
+				"commitId": Object{
+					"type": "keyword",
+				},
 				"txHash": Object{
 					"type": "keyword",
 				},
@@ -0,0 +1,41 @@
+package indices
+
+import "fmt"
+
+// ISMRolloverPolicy will return the OpenSearch Index State Management policy that rolls over the provided index when
+// any of the non-empty conditions is met. The policy is attached to every new index of the alias
+func ISMRolloverPolicy(index string, minSize string, minIndexAge string) Object {
+	conditions := Object{}
+	if minSize != "" {
+		conditions["min_size"] = minSize
+	}
+	if minIndexAge != "" {
+		conditions["min_index_age"] = minIndexAge
+	}
+
+	return Object{
+		"policy": Object{
+			"description":   fmt.Sprintf("rollover policy for the %s index", index),
+			"default_state": "hot",
+			"states": Array{
+				Object{
+					"name": "hot",
+					"actions": Array{
+						Object{
+							"rollover": conditions,
+						},
+					},
+					"transitions": Array{},
+				},
+			},
+			"ism_template": Array{
+				Object{
+					"index_patterns": Array{
+						fmt.Sprintf("%s-*", index),
+					},
+					"priority": 100,
+				},
+			},
+		},
+	}
+}
@@ -15,6 +15,9 @@ var Logs = Object{
 				"address": Object{
 					"type": "keyword",
 				},
+				"commitId": Object{
+					"type": "keyword",
+				},
 				"events": Object{
 					"type": "nested",
 					"properties": Object{
@@ -12,6 +12,9 @@ var Miniblocks = Object{
 		},
 		"mappings": Object{
 			"properties": Object{
+				"commitId": Object{
+					"type": "keyword",
+				},
 				"procTypeD": Object{
 					"type": "keyword",
 				},
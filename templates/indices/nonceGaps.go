@@ -0,0 +1,31 @@
+package indices
+
+// NonceGaps will hold the configuration for the noncegaps index
+var NonceGaps = Object{
+	"index_patterns": Array{
+		"noncegaps-*",
+	},
+	"template": Object{
+		"settings": Object{
+			"number_of_shards":   1,
+			"number_of_replicas": 0,
+		},
+		"mappings": Object{
+			"properties": Object{
+				"shardId": Object{
+					"type": "long",
+				},
+				"fromNonce": Object{
+					"type": "long",
+				},
+				"toNonce": Object{
+					"type": "long",
+				},
+				"detectedAtMs": Object{
+					"type":   "date",
+					"format": "epoch_millis",
+				},
+			},
+		},
+	},
+}
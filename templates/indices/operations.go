@@ -31,6 +31,9 @@ var Operations = Object{
 					"index": "false",
 					"type":  "text",
 				},
+				"commitId": Object{
+					"type": "keyword",
+				},
 				"data": Object{
 					"type": "text",
 				},
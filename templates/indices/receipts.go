@@ -20,6 +20,9 @@ var Receipts = Object{
 		},
 		"mappings": Object{
 			"properties": Object{
+				"commitId": Object{
+					"type": "keyword",
+				},
 				"data": Object{
 					"type": "keyword",
 				},
@@ -27,6 +27,9 @@ var SCResults = Object{
 					"index": "false",
 					"type":  "text",
 				},
+				"commitId": Object{
+					"type": "keyword",
+				},
 				"data": Object{
 					"type": "text",
 				},
@@ -20,6 +20,9 @@ var Transactions = Object{
 		},
 		"mappings": Object{
 			"properties": Object{
+				"commitId": Object{
+					"type": "keyword",
+				},
 				"data": Object{
 					"type": "text",
 				},
@@ -18,6 +18,22 @@ var Values = Object{
 				"value": Object{
 					"type": "keyword",
 				},
+				"shardId": Object{
+					"type": "long",
+				},
+				"headerHash": Object{
+					"type": "keyword",
+				},
+				"nonce": Object{
+					"type": "long",
+				},
+				"round": Object{
+					"type": "long",
+				},
+				"timestampMs": Object{
+					"type":   "date",
+					"format": "epoch_millis",
+				},
 			},
 		},
 	},
@@ -4,7 +4,6 @@ import (
 	"math"
 	"time"
 
-	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
 	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
 	"github.com/multiversx/mx-chain-es-indexer-go/process/elasticproc/converters"
@@ -15,20 +14,22 @@ import (
 
 // CreateBalanceChecker will create a new instance of balanceChecker
 func CreateBalanceChecker(cfg *config.Config, repair bool) (*balanceChecker, error) {
-	esClient, err := esclient.NewElasticClient(elasticsearch.Config{
-		Addresses: []string{cfg.Elasticsearch.URL},
-		Username:  cfg.Elasticsearch.Username,
-		Password:  cfg.Elasticsearch.Password,
-		Logger:    &logging.CustomLogger{},
-		RetryBackoff: func(i int) time.Duration {
-			// A simple exponential delay
-			d := time.Duration(math.Exp2(float64(i))) * time.Second
-			log.Info("elastic: retry backoff", "attempt", i, "sleep duration", d)
-			return d
-		},
-		MaxRetries:    5,
-		RetryOnStatus: []int{429, 502, 503, 504},
-	})
+	esConfig, err := esclient.CreateElasticConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	esConfig.Logger = &logging.CustomLogger{}
+	esConfig.RetryBackoff = func(i int) time.Duration {
+		// A simple exponential delay
+		d := time.Duration(math.Exp2(float64(i))) * time.Second
+		log.Info("elastic: retry backoff", "attempt", i, "sleep duration", d)
+		return d
+	}
+	esConfig.MaxRetries = 5
+	esConfig.RetryOnStatus = []int{429, 502, 503, 504}
+
+	esClient, err := esclient.NewElasticClient(esConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -1,13 +1,21 @@
 package config
 
+import "github.com/multiversx/mx-chain-es-indexer-go/tools/common/connection"
+
 type Config struct {
 	Elasticsearch struct {
-		URL      string `json:"url"`
-		Username string `json:"username"`
-		Password string `json:"password"`
+		URL         string    `json:"url"`
+		Username    string    `json:"username"`
+		Password    string    `json:"password"`
+		APIKey      string    `json:"api-key"`
+		BearerToken string    `json:"bearer-token"`
+		TLS         TLSConfig `json:"tls"`
 	}
 	Proxy struct {
 		URL                         string `json:"url"`
 		MaxNumberOfParallelRequests int    `json:"parallel-requests"`
 	} `json:"proxy"`
 }
+
+// TLSConfig holds the certificates used by the HTTPS connections to the cluster
+type TLSConfig = connection.TLSConfig
@@ -0,0 +1,22 @@
+package esclient
+
+import (
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/tools/accounts-balance-checker/pkg/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/tools/common/connection"
+)
+
+// CreateElasticConfig returns the config of the client that connects to the Elasticsearch cluster. The requests are
+// authenticated with the bearer token, the API key or the username and the password, in this order
+func CreateElasticConfig(cfg *config.Config) (elasticsearch.Config, error) {
+	cluster := cfg.Elasticsearch
+
+	return connection.CreateElasticConfig(connection.ClusterConfig{
+		URL:         cluster.URL,
+		User:        cluster.Username,
+		Password:    cluster.Password,
+		APIKey:      cluster.APIKey,
+		BearerToken: cluster.BearerToken,
+		TLS:         cluster.TLS,
+	})
+}
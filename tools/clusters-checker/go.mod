@@ -5,6 +5,7 @@ go 1.17
 require (
 	github.com/elastic/go-elasticsearch/v7 v7.12.0
 	github.com/multiversx/mx-chain-core-go v1.1.30
+	github.com/multiversx/mx-chain-es-indexer-go/tools/common v0.0.0
 	github.com/multiversx/mx-chain-logger-go v1.0.11
 	github.com/pelletier/go-toml v1.9.3
 	github.com/tidwall/gjson v1.14.0
@@ -24,3 +25,5 @@ require (
 	golang.org/x/sys v0.2.0 // indirect
 	google.golang.org/protobuf v1.26.0 // indirect
 )
+
+replace github.com/multiversx/mx-chain-es-indexer-go/tools/common => ../common
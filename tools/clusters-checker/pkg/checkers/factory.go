@@ -7,27 +7,26 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/multiversx/mx-chain-es-indexer-go/tools/clusters-checker/pkg/client"
 	"github.com/multiversx/mx-chain-es-indexer-go/tools/clusters-checker/pkg/config"
 )
 
 // CreateClusterChecker will create a new instance of clusterChecker structure
 func CreateClusterChecker(cfg *config.Config, interval *Interval, logPrefix string, onlyIDs bool) (*clusterChecker, error) {
-	clientSource, err := client.NewElasticClient(elasticsearch.Config{
-		Addresses: []string{cfg.SourceCluster.URL},
-		Username:  cfg.SourceCluster.User,
-		Password:  cfg.SourceCluster.Password,
-	})
+	sourceConfig, err := client.CreateElasticConfig(cfg.SourceCluster)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create source client %s", err.Error())
+	}
+	clientSource, err := client.NewElasticClient(sourceConfig)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create source client %s", err.Error())
 	}
 
-	clientDestination, err := client.NewElasticClient(elasticsearch.Config{
-		Addresses: []string{cfg.DestinationCluster.URL},
-		Username:  cfg.DestinationCluster.User,
-		Password:  cfg.DestinationCluster.Password,
-	})
+	destinationConfig, err := client.CreateElasticConfig(cfg.DestinationCluster)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create destination client %s", err.Error())
+	}
+	clientDestination, err := client.NewElasticClient(destinationConfig)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create destination client %s", err.Error())
 	}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/tools/clusters-checker/pkg/config"
+	"github.com/multiversx/mx-chain-es-indexer-go/tools/common/connection"
+)
+
+// CreateElasticConfig returns the config of the client that connects to the provided cluster. The requests are
+// authenticated with the bearer token, the API key or the user and the password, in this order
+func CreateElasticConfig(cluster config.ClusterConfig) (elasticsearch.Config, error) {
+	return connection.CreateElasticConfig(connection.ClusterConfig{
+		URL:         cluster.URL,
+		User:        cluster.User,
+		Password:    cluster.Password,
+		APIKey:      cluster.APIKey,
+		BearerToken: cluster.BearerToken,
+		TLS:         cluster.TLS,
+	})
+}
@@ -1,17 +1,11 @@
 package config
 
+import "github.com/multiversx/mx-chain-es-indexer-go/tools/common/connection"
+
 type Config struct {
-	SourceCluster struct {
-		URL      string `toml:"url"`
-		User     string `toml:"user"`
-		Password string `toml:"password"`
-	} `toml:"source-cluster"`
-	DestinationCluster struct {
-		URL      string `toml:"url"`
-		User     string `toml:"user"`
-		Password string `toml:"password"`
-	} `toml:"destination-cluster"`
-	Compare struct {
+	SourceCluster      ClusterConfig `toml:"source-cluster"`
+	DestinationCluster ClusterConfig `toml:"destination-cluster"`
+	Compare            struct {
 		BlockchainStartTime  int64    `toml:"blockchain-start-time"`
 		NumParallelReads     int      `toml:"num-parallel-reads"`
 		IndicesWithTimestamp []string `toml:"indices-with-timestamp"`
@@ -24,3 +18,16 @@ type Config struct {
 		LogsPath             string `toml:"logs-path"`
 	} `toml:"logs"`
 }
+
+// ClusterConfig holds the connection details of a cluster
+type ClusterConfig struct {
+	URL         string    `toml:"url"`
+	User        string    `toml:"user"`
+	Password    string    `toml:"password"`
+	APIKey      string    `toml:"api-key"`
+	BearerToken string    `toml:"bearer-token"`
+	TLS         TLSConfig `toml:"tls"`
+}
+
+// TLSConfig holds the certificates used by the HTTPS connections to a cluster
+type TLSConfig = connection.TLSConfig
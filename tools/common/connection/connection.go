@@ -0,0 +1,94 @@
+package connection
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/elastic/go-elasticsearch/v7"
+)
+
+// ClusterConfig holds the connection details of a cluster
+type ClusterConfig struct {
+	URL         string
+	User        string
+	Password    string
+	APIKey      string
+	BearerToken string
+	TLS         TLSConfig
+}
+
+// TLSConfig holds the certificates used by the HTTPS connections to a cluster
+type TLSConfig struct {
+	CACertFile         string `toml:"ca-cert-file" json:"ca-cert-file"`
+	CertFile           string `toml:"cert-file" json:"cert-file"`
+	KeyFile            string `toml:"key-file" json:"key-file"`
+	InsecureSkipVerify bool   `toml:"insecure-skip-verify" json:"insecure-skip-verify"`
+}
+
+// CreateElasticConfig returns the config of the client that connects to the provided cluster. The requests are
+// authenticated with the bearer token, the API key or the user and the password, in this order
+func CreateElasticConfig(cluster ClusterConfig) (elasticsearch.Config, error) {
+	transport, err := NewHTTPTransport(cluster.TLS)
+	if err != nil {
+		return elasticsearch.Config{}, err
+	}
+
+	cfg := elasticsearch.Config{
+		Addresses: []string{cluster.URL},
+		Username:  cluster.User,
+		Password:  cluster.Password,
+		APIKey:    cluster.APIKey,
+		Transport: transport,
+	}
+	if cluster.BearerToken != "" {
+		cfg.Header = http.Header{"Authorization": []string{"Bearer " + cluster.BearerToken}}
+	}
+
+	return cfg, nil
+}
+
+// NewHTTPTransport returns a clone of http.DefaultTransport that uses the client certificate and trusts the certificate
+// authorities of the provided TLS config, besides the ones of the system
+func NewHTTPTransport(tlsCfg TLSConfig) (*http.Transport, error) {
+	tlsClientConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: tlsCfg.InsecureSkipVerify,
+	}
+
+	if tlsCfg.CACertFile != "" {
+		caCert, err := os.ReadFile(tlsCfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w while reading the CA bundle", err)
+		}
+
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificate found in the CA bundle %s", tlsCfg.CACertFile)
+		}
+		tlsClientConfig.RootCAs = rootCAs
+	}
+
+	if tlsCfg.CertFile != "" || tlsCfg.KeyFile != "" {
+		if tlsCfg.CertFile == "" || tlsCfg.KeyFile == "" {
+			return nil, errors.New("both the client certificate and the client key should be provided")
+		}
+
+		certificate, err := tls.LoadX509KeyPair(tlsCfg.CertFile, tlsCfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w while loading the client certificate", err)
+		}
+		tlsClientConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsClientConfig
+
+	return transport, nil
+}
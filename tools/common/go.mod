@@ -0,0 +1,5 @@
+module github.com/multiversx/mx-chain-es-indexer-go/tools/common
+
+go 1.17
+
+require github.com/elastic/go-elasticsearch/v7 v7.12.0
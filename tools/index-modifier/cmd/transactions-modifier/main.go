@@ -4,16 +4,22 @@ import (
 	"fmt"
 
 	"github.com/multiversx/mx-chain-es-indexer-go/tools/index-modifier/pkg/alterindex"
+	"github.com/multiversx/mx-chain-es-indexer-go/tools/index-modifier/pkg/client"
 	"github.com/multiversx/mx-chain-es-indexer-go/tools/index-modifier/pkg/modifiers"
 )
 
-const (
-	scrollClientAddress = ""
-	bulkClientAddress   = ""
+// the requests are authenticated with the bearer token, the API key or the user and the password, in this order
+var (
+	scrollCluster = client.ClusterConfig{
+		URL: "",
+	}
+	bulkCluster = client.ClusterConfig{
+		URL: "",
+	}
 )
 
 func main() {
-	indexModifier, err := alterindex.CreateIndexModifier(scrollClientAddress, bulkClientAddress)
+	indexModifier, err := alterindex.CreateIndexModifier(scrollCluster, bulkCluster)
 	if err != nil {
 		panic("cannot create index modifier: " + err.Error())
 	}
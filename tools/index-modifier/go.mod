@@ -6,6 +6,7 @@ require (
 	github.com/elastic/go-elasticsearch/v7 v7.12.0
 	github.com/multiversx/mx-chain-core-go v1.1.30
 	github.com/multiversx/mx-chain-es-indexer-go v1.3.7-0.20230110115720-a54a2d8aa20d
+	github.com/multiversx/mx-chain-es-indexer-go/tools/common v0.0.0
 	github.com/multiversx/mx-chain-logger-go v1.0.11
 	github.com/multiversx/mx-chain-vm-common-go v1.3.34
 	github.com/tidwall/gjson v1.14.0
@@ -23,3 +24,5 @@ require (
 	golang.org/x/sys v0.2.0 // indirect
 	google.golang.org/protobuf v1.26.0 // indirect
 )
+
+replace github.com/multiversx/mx-chain-es-indexer-go/tools/common => ../common
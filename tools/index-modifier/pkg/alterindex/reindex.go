@@ -30,21 +30,23 @@ func backOff(i int) time.Duration {
 	return d
 }
 
-// CreateIndexModifier will create a new instance of indexModifier
-func CreateIndexModifier(scrollClientAddress, bulkClientAddress string) (*indexModifier, error) {
-	cfg := elasticsearch.Config{
-		Addresses:     []string{scrollClientAddress},
-		MaxRetries:    0,
-		RetryBackoff:  backOff,
-		RetryOnStatus: []int{429, 502, 503, 504},
+// CreateIndexModifier will create a new instance of indexModifier. The documents are read from the scroll cluster and
+// written in the bulk cluster
+func CreateIndexModifier(scrollCluster, bulkCluster client.ClusterConfig) (*indexModifier, error) {
+	scrollConfig, err := createElasticConfig(scrollCluster)
+	if err != nil {
+		return nil, fmt.Errorf("%w while creating the scroll client config", err)
 	}
-	scrollClient, err := client.NewElasticClient(cfg)
+	scrollClient, err := client.NewElasticClient(scrollConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	cfg.Addresses = []string{bulkClientAddress}
-	bulkClient, err := indexerClient.NewElasticClient(cfg)
+	bulkConfig, err := createElasticConfig(bulkCluster)
+	if err != nil {
+		return nil, fmt.Errorf("%w while creating the bulk client config", err)
+	}
+	bulkClient, err := indexerClient.NewElasticClient(bulkConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -55,6 +57,19 @@ func CreateIndexModifier(scrollClientAddress, bulkClientAddress string) (*indexM
 	}, nil
 }
 
+func createElasticConfig(cluster client.ClusterConfig) (elasticsearch.Config, error) {
+	cfg, err := client.CreateElasticConfig(cluster)
+	if err != nil {
+		return elasticsearch.Config{}, err
+	}
+
+	cfg.MaxRetries = 0
+	cfg.RetryBackoff = backOff
+	cfg.RetryOnStatus = []int{429, 502, 503, 504}
+
+	return cfg, nil
+}
+
 // AlterIndex will alter provided index based on the modifier function
 func (im *indexModifier) AlterIndex(indexRead, indexWrite string, modifier func(responseBytes []byte) ([]*bytes.Buffer, error)) error {
 	count := 0
@@ -0,0 +1,15 @@
+package client
+
+import (
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/tools/common/connection"
+)
+
+// ClusterConfig holds the connection details of a cluster
+type ClusterConfig = connection.ClusterConfig
+
+// CreateElasticConfig returns the config of the client that connects to the provided cluster. The requests are
+// authenticated with the bearer token, the API key or the user and the password, in this order
+func CreateElasticConfig(cluster ClusterConfig) (elasticsearch.Config, error) {
+	return connection.CreateElasticConfig(cluster)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/multiversx/mx-chain-es-indexer-go/tools/common/connection"
+)
+
+// createElasticConfig returns the config of the client that connects to the cluster. The requests are authenticated with
+// the bearer token, the API key or the username and the password, in this order
+func createElasticConfig(cfg *config) (elasticsearch.Config, error) {
+	cluster := cfg.ClusterConfig
+
+	return connection.CreateElasticConfig(connection.ClusterConfig{
+		URL:         cluster.URL,
+		User:        cluster.Username,
+		Password:    cluster.Password,
+		APIKey:      cluster.APIKey,
+		BearerToken: cluster.BearerToken,
+		TLS:         cluster.TLS,
+	})
+}
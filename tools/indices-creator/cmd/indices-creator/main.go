@@ -7,9 +7,9 @@ import (
 	"os"
 	"path"
 
-	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/multiversx/mx-chain-es-indexer-go/client"
 	"github.com/multiversx/mx-chain-es-indexer-go/client/logging"
+	"github.com/multiversx/mx-chain-es-indexer-go/tools/common/connection"
 	"github.com/multiversx/mx-chain-es-indexer-go/tools/indexes-creator/reader"
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/pelletier/go-toml"
@@ -20,11 +20,14 @@ const configFileName = "cluster.toml"
 
 type config struct {
 	ClusterConfig struct {
-		URL            string   `toml:"url"`
-		Username       string   `toml:"username"`
-		Password       string   `toml:"password"`
-		UseKibana      bool     `toml:"use-kibana"`
-		EnabledIndices []string `toml:"enabled-indices"`
+		URL            string               `toml:"url"`
+		Username       string               `toml:"username"`
+		Password       string               `toml:"password"`
+		APIKey         string               `toml:"api-key"`
+		BearerToken    string               `toml:"bearer-token"`
+		UseKibana      bool                 `toml:"use-kibana"`
+		EnabledIndices []string             `toml:"enabled-indices"`
+		TLS            connection.TLSConfig `toml:"tls"`
 	} `toml:"config"`
 }
 
@@ -111,12 +114,13 @@ func createIndexesAndMappings(ctx *cli.Context) {
 }
 
 func createTemplates(cfg *config, indexesMappings map[string]*bytes.Buffer) error {
-	databaseClient, err := client.NewElasticClient(elasticsearch.Config{
-		Addresses: []string{cfg.ClusterConfig.URL},
-		Username:  cfg.ClusterConfig.Username,
-		Password:  cfg.ClusterConfig.Password,
-		Logger:    &logging.CustomLogger{},
-	})
+	esConfig, err := createElasticConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	esConfig.Logger = &logging.CustomLogger{}
+	databaseClient, err := client.NewElasticClient(esConfig)
 	if err != nil {
 		return err
 	}
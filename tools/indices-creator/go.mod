@@ -5,6 +5,7 @@ go 1.17
 require (
 	github.com/elastic/go-elasticsearch/v7 v7.12.0
 	github.com/multiversx/mx-chain-es-indexer-go v1.3.7-0.20230110115720-a54a2d8aa20d
+	github.com/multiversx/mx-chain-es-indexer-go/tools/common v0.0.0
 	github.com/multiversx/mx-chain-logger-go v1.0.11
 	github.com/pelletier/go-toml v1.9.3
 	github.com/urfave/cli v1.22.9
@@ -25,3 +26,5 @@ require (
 	golang.org/x/sys v0.2.0 // indirect
 	google.golang.org/protobuf v1.26.0 // indirect
 )
+
+replace github.com/multiversx/mx-chain-es-indexer-go/tools/common => ../common